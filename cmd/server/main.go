@@ -2,25 +2,36 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/tzDel/orchestragent-mcp/internal/adapters/mcp"
 	"github.com/tzDel/orchestragent-mcp/internal/application"
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+	"github.com/tzDel/orchestragent-mcp/internal/infrastructure/config"
+	"github.com/tzDel/orchestragent-mcp/internal/infrastructure/forge"
 	"github.com/tzDel/orchestragent-mcp/internal/infrastructure/git"
 	"github.com/tzDel/orchestragent-mcp/internal/infrastructure/persistence"
+	"github.com/tzDel/orchestragent-mcp/internal/infrastructure/process"
+	"github.com/tzDel/orchestragent-mcp/internal/infrastructure/workspace"
 )
 
 const databaseFileName = ".orchestragent-mcp.db"
 const defaultDatabaseDirectory = "."
 
 func main() {
-	repositoryPath, databaseDirectory := parseFlags()
+	process.RunSandboxInit()
 
-	databasePath, err := resolveDatabasePath(databaseDirectory)
+	serverConfig := loadConfiguration(parseFlags())
+
+	databasePath, err := resolveDatabasePath(serverConfig.DatabaseDir)
 	if err != nil {
 		log.Fatalf("failed to resolve database path: %v", err)
 	}
@@ -28,24 +39,42 @@ func main() {
 	sessionRepository, cleanup := initializeSessionRepository(databasePath)
 	defer cleanup()
 
-	server := initializeMCPServer(repositoryPath, sessionRepository)
-	startMCPServer(server, repositoryPath)
+	server := initializeMCPServer(serverConfig, sessionRepository)
+	startMCPServer(server, serverConfig.RepoRoot)
 }
 
-func parseFlags() (string, string) {
-	repositoryPath := flag.String("repo", resolveCurrentWorkingDirectory(), "path to git repository (defaults to current directory)")
-	databaseDirectory := flag.String("db", defaultDatabaseDirectory, "directory where SQLite database should be created (defaults to current working directory)")
+func parseFlags() config.Overrides {
+	configPath := flag.String("config", "", "path to a YAML config file (disables config file discovery)")
+	repositoryPath := flag.String("repo", "", "path to git repository (defaults to current directory)")
+	databaseDirectory := flag.String("db", "", "directory where SQLite database should be created (defaults to current working directory)")
+	baseBranch := flag.String("base-branch", "", "branch sessions are created from and compared against (defaults to main)")
+	worktreeDirectory := flag.String("worktree-dir", "", "directory for session worktrees, relative to the repository root (defaults to .worktrees)")
+	exportDirectory := flag.String("export-dir", "", "directory export_session and import_session are confined to, relative to the repository root (defaults to .exports)")
+	testCommand := flag.String("test-command", "", "command exec_in_session always allows, with exactly its own arguments (e.g. \"go test ./...\")")
 	flag.Parse()
 
-	return *repositoryPath, *databaseDirectory
+	return config.Overrides{
+		ConfigPath:  *configPath,
+		RepoRoot:    *repositoryPath,
+		DatabaseDir: *databaseDirectory,
+		BaseBranch:  *baseBranch,
+		WorktreeDir: *worktreeDirectory,
+		ExportDir:   *exportDirectory,
+		TestCommand: *testCommand,
+	}
 }
 
-func resolveCurrentWorkingDirectory() string {
-	currentWorkingDirectory, err := os.Getwd()
+func loadConfiguration(overrides config.Overrides) *config.Config {
+	serverConfig, err := config.Load(overrides)
 	if err != nil {
-		log.Fatalf("failed to resolve current working directory: %v", err)
+		log.Fatalf("failed to load configuration: %v", err)
 	}
-	return currentWorkingDirectory
+
+	for _, loadedFile := range serverConfig.LoadedFiles {
+		fmt.Fprintf(os.Stderr, "Loaded configuration from %s\n", loadedFile)
+	}
+
+	return serverConfig
 }
 
 func initializeSessionRepository(databasePath string) (*persistence.SQLiteSessionRepository, func()) {
@@ -63,15 +92,77 @@ func initializeSessionRepository(databasePath string) (*persistence.SQLiteSessio
 	return sessionRepository, cleanup
 }
 
-func initializeMCPServer(repositoryPath string, sessionRepository *persistence.SQLiteSessionRepository) *mcp.MCPServer {
-	gitOperations := git.NewGitClient(repositoryPath)
-	baseBranch := "main"
+func initializeMCPServer(serverConfig *config.Config, sessionRepository *persistence.SQLiteSessionRepository) *mcp.MCPServer {
+	gitOperations := git.NewGitClient(serverConfig.RepoRoot)
+	ensureBaseBranchExists(gitOperations, serverConfig.BaseBranch)
+	forgeClient := initializeForge(serverConfig)
+	worktreeFiles := workspace.NewFileSystem()
+	sessionArchives := workspace.NewSessionArchiveStore(serverConfig.ExportDir)
+	commandPolicy := initializeCommandPolicy(serverConfig)
+	commandRunner := process.NewRunner(initializeSandbox(serverConfig, commandPolicy))
 
-	createWorktreeUseCase := application.NewCreateWorktreeUseCase(gitOperations, sessionRepository, repositoryPath)
-	removeSessionUseCase := application.NewRemoveSessionUseCase(gitOperations, sessionRepository, baseBranch)
-	getSessionsUseCase := application.NewGetSessionsUseCase(gitOperations, sessionRepository, baseBranch)
+	createWorktreeUseCase := application.NewCreateWorktreeUseCase(gitOperations, sessionRepository, serverConfig.WorktreeDir, serverConfig.BaseBranch)
+	removeSessionUseCase := application.NewRemoveSessionUseCase(gitOperations, sessionRepository, serverConfig.BaseBranch)
+	getSessionsUseCase := application.NewGetSessionsUseCase(gitOperations, sessionRepository, serverConfig.BaseBranch, serverConfig.StaleBehindCommits)
+	getSessionDiffUseCase := application.NewGetSessionDiffUseCase(gitOperations, sessionRepository, serverConfig.BaseBranch)
+	getSessionCommitsUseCase := application.NewGetSessionCommitsUseCase(gitOperations, sessionRepository, serverConfig.BaseBranch)
+	mergeSessionUseCase := application.NewMergeSessionUseCase(gitOperations, sessionRepository, serverConfig.BaseBranch)
+	checkMergeUseCase := application.NewCheckMergeUseCase(gitOperations, sessionRepository, serverConfig.BaseBranch)
+	syncSessionUseCase := application.NewSyncSessionUseCase(gitOperations, sessionRepository, serverConfig.BaseBranch)
+	getSessionOverlapsUseCase := application.NewGetSessionOverlapsUseCase(gitOperations, sessionRepository, serverConfig.BaseBranch)
+	createCheckpointUseCase := application.NewCreateCheckpointUseCase(gitOperations, sessionRepository)
+	listCheckpointsUseCase := application.NewListCheckpointsUseCase(gitOperations, sessionRepository)
+	restoreCheckpointUseCase := application.NewRestoreCheckpointUseCase(gitOperations, sessionRepository)
+	commitSessionUseCase := application.NewCommitSessionUseCase(gitOperations, sessionRepository, domain.CommitAuthor{
+		Name:  serverConfig.CommitAuthorName,
+		Email: serverConfig.CommitAuthorEmail,
+	})
+	publishSessionUseCase := application.NewPublishSessionUseCase(gitOperations, sessionRepository, forgeClient, serverConfig.Remote, serverConfig.BaseBranch)
+	exportSessionUseCase := application.NewExportSessionUseCase(gitOperations, sessionRepository, sessionArchives, serverConfig.BaseBranch)
+	importSessionUseCase := application.NewImportSessionUseCase(gitOperations, sessionRepository, sessionArchives, serverConfig.WorktreeDir)
+	forkSessionUseCase := application.NewForkSessionUseCase(gitOperations, sessionRepository, serverConfig.WorktreeDir, serverConfig.BaseBranch)
+	restackSessionsUseCase := application.NewRestackSessionsUseCase(gitOperations, sessionRepository, serverConfig.BaseBranch)
+	cherryPickUseCase := application.NewCherryPickUseCase(gitOperations, sessionRepository, serverConfig.BaseBranch)
+	compareSessionsUseCase := application.NewCompareSessionsUseCase(gitOperations, sessionRepository, serverConfig.BaseBranch)
+	readFileUseCase := application.NewReadFileUseCase(worktreeFiles, sessionRepository)
+	writeFileUseCase := application.NewWriteFileUseCase(worktreeFiles, sessionRepository)
+	listDirectoryUseCase := application.NewListDirectoryUseCase(worktreeFiles, sessionRepository)
+	deleteFileUseCase := application.NewDeleteFileUseCase(worktreeFiles, sessionRepository)
+	moveFileUseCase := application.NewMoveFileUseCase(worktreeFiles, sessionRepository)
+	searchSessionUseCase := application.NewSearchSessionUseCase(gitOperations, sessionRepository)
+	applyPatchUseCase := application.NewApplyPatchUseCase(gitOperations, sessionRepository)
+	execInSessionUseCase := application.NewExecInSessionUseCase(commandRunner, sessionRepository, commandPolicy)
 
-	server, err := mcp.NewMCPServer(createWorktreeUseCase, removeSessionUseCase, getSessionsUseCase)
+	server, err := mcp.NewMCPServer(mcp.UseCases{
+		CreateWorktree:     createWorktreeUseCase,
+		RemoveSession:      removeSessionUseCase,
+		GetSessions:        getSessionsUseCase,
+		GetSessionDiff:     getSessionDiffUseCase,
+		GetSessionCommits:  getSessionCommitsUseCase,
+		MergeSession:       mergeSessionUseCase,
+		CheckMerge:         checkMergeUseCase,
+		SyncSession:        syncSessionUseCase,
+		GetSessionOverlaps: getSessionOverlapsUseCase,
+		CreateCheckpoint:   createCheckpointUseCase,
+		ListCheckpoints:    listCheckpointsUseCase,
+		RestoreCheckpoint:  restoreCheckpointUseCase,
+		CommitSession:      commitSessionUseCase,
+		PublishSession:     publishSessionUseCase,
+		ExportSession:      exportSessionUseCase,
+		ImportSession:      importSessionUseCase,
+		ForkSession:        forkSessionUseCase,
+		RestackSessions:    restackSessionsUseCase,
+		CherryPick:         cherryPickUseCase,
+		CompareSessions:    compareSessionsUseCase,
+		ReadFile:           readFileUseCase,
+		WriteFile:          writeFileUseCase,
+		ListDirectory:      listDirectoryUseCase,
+		DeleteFile:         deleteFileUseCase,
+		MoveFile:           moveFileUseCase,
+		SearchSession:      searchSessionUseCase,
+		ApplyPatch:         applyPatchUseCase,
+		ExecInSession:      execInSessionUseCase,
+	})
 	if err != nil {
 		log.Fatalf("failed to initialize MCP server: %v", err)
 	}
@@ -79,6 +170,101 @@ func initializeMCPServer(repositoryPath string, sessionRepository *persistence.S
 	return server
 }
 
+// initializeForge returns the forge pull requests are opened on, or nil when
+// no forge repository is configured
+func initializeForge(serverConfig *config.Config) domain.Forge {
+	if serverConfig.ForgeRepository == "" {
+		return nil
+	}
+
+	forgeClient, err := forge.NewClient(serverConfig.ForgeURL, serverConfig.ForgeRepository, serverConfig.ForgeToken, nil)
+	if err != nil {
+		log.Fatalf("failed to initialize forge client: %v", err)
+	}
+	return forgeClient
+}
+
+// initializeCommandPolicy builds the exec_in_session policy from the exec
+// configuration and the test command. The server's own ORCHESTRAGENT_*
+// variables, which may hold the forge token, are always kept from commands.
+func initializeCommandPolicy(serverConfig *config.Config) *domain.CommandPolicy {
+	policy := &domain.CommandPolicy{
+		AllowedEnv:     serverConfig.Exec.AllowedEnv,
+		DeniedEnv:      append([]string{"ORCHESTRAGENT_*"}, serverConfig.Exec.DeniedEnv...),
+		Timeout:        time.Duration(serverConfig.Exec.TimeoutSeconds) * time.Second,
+		MaxOutputBytes: serverConfig.Exec.MaxOutputBytes,
+	}
+	for _, rule := range serverConfig.Exec.Allow {
+		commandRule, err := domain.NewCommandRule(rule.Command, rule.Args)
+		if err != nil {
+			log.Fatalf("invalid exec policy: %v", err)
+		}
+		policy.Rules = append(policy.Rules, commandRule)
+	}
+	if serverConfig.TestCommand != "" {
+		testCommandRule, err := newTestCommandRule(serverConfig.TestCommand)
+		if err != nil {
+			log.Fatalf("invalid test command: %v", err)
+		}
+		policy.Rules = append(policy.Rules, testCommandRule)
+	}
+	return policy
+}
+
+// newTestCommandRule allows the configured test command with exactly its own
+// arguments, taken literally and in order. The command is split on
+// whitespace; it never runs through a shell.
+func newTestCommandRule(testCommand string) (domain.CommandRule, error) {
+	fields := strings.Fields(testCommand)
+	if len(fields) == 0 {
+		return domain.CommandRule{}, errors.New("test command must not be empty")
+	}
+
+	argPatterns := make([]string, 0, len(fields)-1)
+	for _, argument := range fields[1:] {
+		argPatterns = append(argPatterns, regexp.QuoteMeta(argument))
+	}
+	rule, err := domain.NewCommandRule(fields[0], argPatterns)
+	if err != nil {
+		return domain.CommandRule{}, err
+	}
+	rule.ExactArgs = true
+	return rule, nil
+}
+
+// initializeSandbox picks the isolation for exec_in_session. In auto mode a
+// missing namespace sandbox is reported once and commands run unisolated;
+// without any allowed command there is nothing to isolate.
+func initializeSandbox(serverConfig *config.Config, commandPolicy *domain.CommandPolicy) process.Sandbox {
+	sandboxConfig := serverConfig.Exec.Sandbox
+	if sandboxConfig.Mode == config.SandboxModeOff || !commandPolicy.Enabled() {
+		return process.NoSandbox{}
+	}
+
+	sandbox, err := process.NewNamespaceSandbox(process.SandboxOptions{
+		ReadOnlyPaths: sandboxConfig.ReadOnlyPaths,
+		WritablePaths: sandboxConfig.WritablePaths,
+	})
+	if err == nil {
+		return sandbox
+	}
+	if sandboxConfig.Mode == config.SandboxModeNamespaces {
+		log.Fatalf("failed to initialize command sandbox: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "Warning: commands run without isolation: %v\n", err)
+	return process.NoSandbox{}
+}
+
+func ensureBaseBranchExists(gitOperations *git.GitClient, baseBranch string) {
+	exists, err := gitOperations.BranchExists(context.Background(), baseBranch)
+	if err != nil {
+		log.Fatalf("failed to check base branch %q: %v", baseBranch, err)
+	}
+	if !exists {
+		log.Fatalf("base branch %q does not exist in the repository; set baseBranch in the config file, ORCHESTRAGENT_BASE_BRANCH or -base-branch", baseBranch)
+	}
+}
+
 func startMCPServer(server *mcp.MCPServer, repositoryPath string) {
 	fmt.Fprintf(os.Stderr, "Starting MCP server for repository: %s\n", repositoryPath)
 
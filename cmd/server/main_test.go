@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
 )
 
 func TestResolveDatabasePath_WhenDatabaseDirectoryNotProvided_UsesDefaultDirectory(t *testing.T) {
@@ -68,3 +70,40 @@ func TestResolveDatabasePath_WhenCustomDirectoryProvided_CreatesDatabaseInThatDi
 		t.Fatalf("database path = %s, expected %s", databasePath, expectedDatabasePath)
 	}
 }
+
+func TestNewTestCommandRule_AllowsOnlyItsOwnArguments(t *testing.T) {
+	// arrange
+	testCases := []struct {
+		name        string
+		testCommand string
+		args        []string
+		allowed     bool
+	}{
+		{"own arguments", "go test ./...", []string{"test", "./..."}, true},
+		{"arguments taken literally", "go test ./...", []string{"test", "./a.."}, false},
+		{"other arguments", "go test ./...", []string{"run", "./..."}, false},
+		{"reordered arguments", "go test ./...", []string{"./...", "test"}, false},
+		{"repeated arguments", "go test ./...", []string{"test", "test"}, false},
+		{"missing arguments", "go test ./...", []string{"test"}, false},
+		{"bare command", "make", []string{"clean"}, false},
+		{"bare command without arguments", "make", nil, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			rule, err := newTestCommandRule(testCase.testCommand)
+			if err != nil {
+				t.Fatalf("newTestCommandRule() error: %v", err)
+			}
+			policy := &domain.CommandPolicy{Rules: []domain.CommandRule{rule}}
+
+			// act
+			checkErr := policy.Check(rule.Command, testCase.args)
+
+			// assert
+			if (checkErr == nil) != testCase.allowed {
+				t.Errorf("Check(%q) error = %v, want allowed %v", testCase.args, checkErr, testCase.allowed)
+			}
+		})
+	}
+}
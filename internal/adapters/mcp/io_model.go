@@ -6,14 +6,19 @@ import (
 )
 
 type CreateWorktreeArgs struct {
-	SessionID string `json:"sessionId" jsonschema:"required" jsonschema_description:"The unique identifier for the session"`
+	SessionID       string `json:"sessionId" jsonschema:"required" jsonschema_description:"The unique identifier for the session"`
+	BaseRef         string `json:"baseRef,omitempty" jsonschema_description:"Branch, tag or commit SHA to start the session from (defaults to the configured base branch)"`
+	ParentSessionID string `json:"parentSessionId,omitempty" jsonschema_description:"Stack the session on this session's branch instead of a base ref"`
 }
 
 type CreateWorktreeOutput struct {
-	SessionID    string `json:"sessionId"`
-	WorktreePath string `json:"worktreePath"`
-	BranchName   string `json:"branchName"`
-	Status       string `json:"status"`
+	SessionID       string `json:"sessionId"`
+	WorktreePath    string `json:"worktreePath"`
+	BranchName      string `json:"branchName"`
+	BaseRef         string `json:"baseRef"`
+	BaseCommit      string `json:"baseCommit"`
+	Status          string `json:"status"`
+	ParentSessionID string `json:"parentSessionId,omitempty"`
 }
 
 type RemoveSessionArgs struct {
@@ -31,24 +36,575 @@ type RemoveSessionOutput struct {
 }
 
 type GetSessionsArgs struct {
+	BehindMoreThan *int `json:"behindMoreThan,omitempty" jsonschema_description:"Only list sessions more than this many commits behind their base"`
+	StaleOnly      bool `json:"staleOnly,omitempty" jsonschema_description:"Only list sessions further behind their base than the configured stale threshold"`
+	CheckMergeable bool `json:"checkMergeable,omitempty" jsonschema_description:"Dry-run merge each unmerged session into its base to report mergeable"`
 }
 
 type GetSessionsOutput struct {
-	Sessions []SessionOutput `json:"sessions"`
+	Sessions  []SessionOutput `json:"sessions"`
+	StackTree string          `json:"stackTree,omitempty" jsonschema_description:"Tree of stacked sessions, rooted at the bottom of each stack"`
 }
 
 type SessionOutput struct {
+	SessionID       string              `json:"sessionId"`
+	WorktreePath    string              `json:"worktreePath"`
+	BranchName      string              `json:"branchName"`
+	BaseRef         string              `json:"baseRef"`
+	BaseCommit      string              `json:"baseCommit,omitempty"`
+	Status          string              `json:"status"`
+	LinesAdded      int                 `json:"linesAdded"`
+	LinesRemoved    int                 `json:"linesRemoved"`
+	FilesChanged    int                 `json:"filesChanged"`
+	Files           []FileChangeOutput  `json:"files"`
+	Breakdown       DiffBreakdownOutput `json:"breakdown" jsonschema_description:"Committed, staged, unstaged and untracked work measured separately"`
+	Mergeable       *bool               `json:"mergeable,omitempty" jsonschema_description:"Whether the committed work merges cleanly into the base; only set with checkMergeable, and omitted for merged sessions or when the check fails"`
+	Ahead           int                 `json:"ahead" jsonschema_description:"Commits on the session branch that are not on its base"`
+	Behind          int                 `json:"behind" jsonschema_description:"Commits on the base that are not on the session branch"`
+	LastCommitAt    string              `json:"lastCommitAt,omitempty" jsonschema_description:"Commit time of the session branch tip"`
+	Stale           bool                `json:"stale" jsonschema_description:"Whether an unmerged session is further behind its base than the configured threshold"`
+	Checkpoints     []CheckpointOutput  `json:"checkpoints"`
+	PullRequest     *PullRequestOutput  `json:"pullRequest,omitempty"`
+	ParentSessionID string              `json:"parentSessionId,omitempty" jsonschema_description:"Session this one was forked from"`
+	Errors          []string            `json:"errors,omitempty" jsonschema_description:"What could not be measured for this session; the affected fields are zero"`
+}
+
+type DiffBreakdownOutput struct {
+	Committed DiffContributionOutput `json:"committed"`
+	Staged    DiffContributionOutput `json:"staged"`
+	Unstaged  DiffContributionOutput `json:"unstaged"`
+	Untracked DiffContributionOutput `json:"untracked"`
+}
+
+type DiffContributionOutput struct {
+	LinesAdded   int `json:"linesAdded"`
+	LinesRemoved int `json:"linesRemoved"`
+	FilesChanged int `json:"filesChanged"`
+}
+
+type FileChangeOutput struct {
+	Path         string `json:"path"`
+	OldPath      string `json:"oldPath,omitempty"`
+	ChangeType   string `json:"changeType" jsonschema_description:"One of added, modified, deleted, renamed, copied, type_changed"`
+	LinesAdded   int    `json:"linesAdded"`
+	LinesRemoved int    `json:"linesRemoved"`
+	Binary       bool   `json:"binary"`
+	Untracked    bool   `json:"untracked,omitempty"`
+}
+
+type GetSessionDiffArgs struct {
+	SessionID       string   `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+	Paths           []string `json:"paths,omitempty" jsonschema_description:"Glob patterns relative to the repository root, e.g. internal/**/*.go (defaults to all files)"`
+	ContextLines    *int     `json:"contextLines,omitempty" jsonschema_description:"Unchanged lines shown around each change (default 3, max 100)"`
+	MaxBytesPerFile int      `json:"maxBytesPerFile,omitempty" jsonschema_description:"Truncate each file's diff after this many bytes (default 16384)"`
+	MaxBytesPerPage int      `json:"maxBytesPerPage,omitempty" jsonschema_description:"Stop adding files to a page after this many bytes (default 65536)"`
+	PageSize        int      `json:"pageSize,omitempty" jsonschema_description:"Maximum number of files per page (default 20, max 200)"`
+	Cursor          string   `json:"cursor,omitempty" jsonschema_description:"nextCursor from a previous call to continue paging"`
+}
+
+type GetSessionDiffOutput struct {
+	SessionID  string           `json:"sessionId"`
+	BaseRef    string           `json:"baseRef"`
+	Files      []FileDiffOutput `json:"files"`
+	TotalFiles int              `json:"totalFiles"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+}
+
+type FileDiffOutput struct {
+	Path      string `json:"path"`
+	Diff      string `json:"diff"`
+	Truncated bool   `json:"truncated"`
+	SizeBytes int    `json:"sizeBytes"`
+}
+
+type GetSessionCommitsArgs struct {
+	SessionID string `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+}
+
+type GetSessionCommitsOutput struct {
+	SessionID  string         `json:"sessionId"`
+	BranchName string         `json:"branchName"`
+	BaseRef    string         `json:"baseRef"`
+	Commits    []CommitOutput `json:"commits"`
+}
+
+type CommitOutput struct {
+	SHA            string                `json:"sha"`
+	ParentSHAs     []string              `json:"parentShas"`
+	AuthorName     string                `json:"authorName"`
+	AuthorEmail    string                `json:"authorEmail"`
+	AuthoredAt     string                `json:"authoredAt"`
+	CommitterName  string                `json:"committerName"`
+	CommitterEmail string                `json:"committerEmail"`
+	CommittedAt    string                `json:"committedAt"`
+	Subject        string                `json:"subject"`
+	Body           string                `json:"body"`
+	Trailers       []CommitTrailerOutput `json:"trailers"`
+	Files          []CommitFileOutput    `json:"files"`
+	LinesAdded     int                   `json:"linesAdded"`
+	LinesRemoved   int                   `json:"linesRemoved"`
+}
+
+type CommitTrailerOutput struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type CommitFileOutput struct {
+	Path         string `json:"path"`
+	OldPath      string `json:"oldPath,omitempty"`
+	LinesAdded   int    `json:"linesAdded"`
+	LinesRemoved int    `json:"linesRemoved"`
+	Binary       bool   `json:"binary"`
+}
+
+type MergeSessionArgs struct {
+	SessionID        string `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+	Strategy         string `json:"strategy,omitempty" jsonschema_description:"merge (default) records a merge commit, squash creates one commit, rebase replays the session onto the base then fast-forwards, ff-only fails unless the base can be fast-forwarded"`
+	Message          string `json:"message,omitempty" jsonschema_description:"Commit message for merge and squash strategies (defaults to one naming the session)"`
+	RemoveAfterMerge bool   `json:"removeAfterMerge,omitempty" jsonschema_description:"Remove the worktree, branch and session record after a successful merge"`
+}
+
+type MergeSessionOutput struct {
+	SessionID       string   `json:"sessionId"`
+	BaseRef         string   `json:"baseRef"`
+	Strategy        string   `json:"strategy"`
+	Merged          bool     `json:"merged"`
+	MergedCommit    string   `json:"mergedCommit,omitempty"`
+	ConflictedPaths []string `json:"conflictedPaths,omitempty"`
+	Status          string   `json:"status"`
+	Removed         bool     `json:"removed"`
+	RemovedAt       string   `json:"removedAt,omitempty"`
+}
+
+type CheckMergeArgs struct {
+	SessionID string `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+}
+
+type CheckMergeOutput struct {
+	SessionID string                `json:"sessionId"`
+	BaseRef   string                `json:"baseRef"`
+	Clean     bool                  `json:"clean"`
+	Conflicts []MergeConflictOutput `json:"conflicts"`
+}
+
+type MergeConflictOutput struct {
+	Path    string   `json:"path"`
+	Type    string   `json:"type"`
+	Message string   `json:"message"`
+	Hunks   []string `json:"hunks"`
+}
+
+type SyncSessionArgs struct {
+	SessionID string `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+	Strategy  string `json:"strategy,omitempty" jsonschema_description:"rebase (default) replays the session commits onto the base, merge records a merge commit from the base"`
+}
+
+type SyncSessionOutput struct {
+	SessionID          string   `json:"sessionId"`
+	BaseRef            string   `json:"baseRef"`
+	Strategy           string   `json:"strategy"`
+	Synced             bool     `json:"synced"`
+	PreviousBaseCommit string   `json:"previousBaseCommit,omitempty"`
+	BaseCommit         string   `json:"baseCommit"`
+	HeadCommit         string   `json:"headCommit,omitempty"`
+	ConflictedPaths    []string `json:"conflictedPaths,omitempty"`
+}
+
+type RestackSessionsArgs struct {
+	SessionID string `json:"sessionId,omitempty" jsonschema_description:"Only restack the sessions stacked above this one (defaults to every stack)"`
+}
+
+type RestackSessionsOutput struct {
+	Sessions  []RestackResultOutput `json:"sessions"`
+	StackTree string                `json:"stackTree,omitempty"`
+}
+
+type RestackResultOutput struct {
+	SessionID          string   `json:"sessionId"`
+	ParentSessionID    string   `json:"parentSessionId"`
+	BaseRef            string   `json:"baseRef"`
+	PreviousBaseCommit string   `json:"previousBaseCommit,omitempty"`
+	BaseCommit         string   `json:"baseCommit,omitempty"`
+	HeadCommit         string   `json:"headCommit,omitempty"`
+	Restacked          bool     `json:"restacked"`
+	ConflictedPaths    []string `json:"conflictedPaths,omitempty"`
+	SkippedReason      string   `json:"skippedReason,omitempty"`
+}
+
+type CherryPickArgs struct {
+	SourceSessionID string   `json:"sourceSessionId" jsonschema:"required" jsonschema_description:"Session the commits are taken from"`
+	TargetSessionID string   `json:"targetSessionId" jsonschema:"required" jsonschema_description:"Session whose branch the commits are applied to"`
+	Commits         []string `json:"commits,omitempty" jsonschema_description:"Full or abbreviated (at least 7 characters) SHAs of source commits; defaults to all of them"`
+}
+
+type CherryPickOutput struct {
+	SourceSessionID     string   `json:"sourceSessionId"`
+	TargetSessionID     string   `json:"targetSessionId"`
+	Applied             bool     `json:"applied"`
+	PickedCommits       []string `json:"pickedCommits"`
+	SkippedMergeCommits []string `json:"skippedMergeCommits,omitempty"`
+	HeadCommit          string   `json:"headCommit,omitempty"`
+	ConflictedPaths     []string `json:"conflictedPaths,omitempty"`
+}
+
+type CompareSessionsArgs struct {
+	LeftSessionID   string   `json:"leftSessionId" jsonschema:"required" jsonschema_description:"Session the diff starts from"`
+	RightSessionID  string   `json:"rightSessionId" jsonschema:"required" jsonschema_description:"Session the diff leads to"`
+	Paths           []string `json:"paths,omitempty" jsonschema_description:"Glob patterns relative to the repository root, e.g. internal/**/*.go (defaults to all files)"`
+	ContextLines    *int     `json:"contextLines,omitempty" jsonschema_description:"Unchanged lines shown around each change (default 3, max 100)"`
+	MaxBytesPerFile int      `json:"maxBytesPerFile,omitempty" jsonschema_description:"Truncate each file's diff after this many bytes (default 16384)"`
+	MaxBytesPerPage int      `json:"maxBytesPerPage,omitempty" jsonschema_description:"Stop adding files to a page after this many bytes (default 65536)"`
+	PageSize        int      `json:"pageSize,omitempty" jsonschema_description:"Maximum number of files per page (default 20, max 200)"`
+	Cursor          string   `json:"cursor,omitempty" jsonschema_description:"nextCursor from a previous call to continue paging"`
+}
+
+type CompareSessionsOutput struct {
+	LeftSessionID         string             `json:"leftSessionId"`
+	RightSessionID        string             `json:"rightSessionId"`
+	LeftHeadCommit        string             `json:"leftHeadCommit"`
+	RightHeadCommit       string             `json:"rightHeadCommit"`
+	LinesAdded            int                `json:"linesAdded"`
+	LinesRemoved          int                `json:"linesRemoved"`
+	FilesChanged          int                `json:"filesChanged"`
+	FileStats             []FileChangeOutput `json:"fileStats" jsonschema_description:"Lines that differ per file, reading from the left session to the right one"`
+	OnlyLeftFiles         []string           `json:"onlyLeftFiles" jsonschema_description:"Files committed on the left session that the right session's commits do not touch"`
+	OnlyRightFiles        []string           `json:"onlyRightFiles" jsonschema_description:"Files committed on the right session that the left session's commits do not touch"`
+	LeftUncommittedFiles  int                `json:"leftUncommittedFiles" jsonschema_description:"Uncommitted files in the left worktree, which are not compared"`
+	RightUncommittedFiles int                `json:"rightUncommittedFiles" jsonschema_description:"Uncommitted files in the right worktree, which are not compared"`
+	Files                 []FileDiffOutput   `json:"files"`
+	TotalFiles            int                `json:"totalFiles"`
+	NextCursor            string             `json:"nextCursor,omitempty"`
+}
+
+type ReadFileArgs struct {
+	SessionID string `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+	Path      string `json:"path" jsonschema:"required" jsonschema_description:"File path relative to the session worktree, using forward slashes"`
+	MaxBytes  int    `json:"maxBytes,omitempty" jsonschema_description:"Return at most this many bytes (default 262144, max 4194304)"`
+}
+
+type ReadFileOutput struct {
+	SessionID string `json:"sessionId"`
+	Path      string `json:"path"`
+	Content   string `json:"content"`
+	Encoding  string `json:"encoding" jsonschema_description:"utf-8 for text, base64 for binary content"`
+	SizeBytes int64  `json:"sizeBytes" jsonschema_description:"Size of the whole file"`
+	Truncated bool   `json:"truncated"`
+}
+
+type WriteFileArgs struct {
+	SessionID string `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+	Path      string `json:"path" jsonschema:"required" jsonschema_description:"File path relative to the session worktree, using forward slashes"`
+	Content   string `json:"content" jsonschema_description:"New file content; an empty string empties the file"`
+	Encoding  string `json:"encoding,omitempty" jsonschema_description:"utf-8 (default) or base64"`
+}
+
+type WriteFileOutput struct {
+	SessionID string `json:"sessionId"`
+	Path      string `json:"path"`
+	SizeBytes int    `json:"sizeBytes"`
+	Created   bool   `json:"created"`
+}
+
+type ListDirectoryArgs struct {
+	SessionID string `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+	Path      string `json:"path,omitempty" jsonschema_description:"Directory relative to the session worktree (defaults to the worktree root)"`
+}
+
+type ListDirectoryOutput struct {
+	SessionID string                 `json:"sessionId"`
+	Path      string                 `json:"path"`
+	Entries   []DirectoryEntryOutput `json:"entries"`
+}
+
+type DirectoryEntryOutput struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	Type       string `json:"type" jsonschema_description:"One of file, directory, symlink, other"`
+	SizeBytes  int64  `json:"sizeBytes"`
+	ModifiedAt string `json:"modifiedAt"`
+}
+
+type DeleteFileArgs struct {
+	SessionID string `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+	Path      string `json:"path" jsonschema:"required" jsonschema_description:"File path relative to the session worktree, using forward slashes"`
+}
+
+type DeleteFileOutput struct {
+	SessionID string `json:"sessionId"`
+	Path      string `json:"path"`
+}
+
+type MoveFileArgs struct {
+	SessionID       string `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+	SourcePath      string `json:"sourcePath" jsonschema:"required" jsonschema_description:"Path to move, relative to the session worktree"`
+	DestinationPath string `json:"destinationPath" jsonschema:"required" jsonschema_description:"New path, relative to the session worktree"`
+	Overwrite       bool   `json:"overwrite,omitempty" jsonschema_description:"Replace an existing destination file"`
+}
+
+type MoveFileOutput struct {
+	SessionID       string `json:"sessionId"`
+	SourcePath      string `json:"sourcePath"`
+	DestinationPath string `json:"destinationPath"`
+}
+
+type SearchSessionArgs struct {
+	SessionID        string   `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+	Pattern          string   `json:"pattern" jsonschema:"required" jsonschema_description:"POSIX extended regular expression, or plain text with literal=true"`
+	Literal          bool     `json:"literal,omitempty" jsonschema_description:"Match pattern as plain text"`
+	IgnoreCase       bool     `json:"ignoreCase,omitempty"`
+	Paths            []string `json:"paths,omitempty" jsonschema_description:"Glob patterns relative to the repository root, e.g. internal/**/*.go (defaults to all files)"`
+	IncludeUntracked bool     `json:"includeUntracked,omitempty" jsonschema_description:"Also search untracked files that are not ignored by .gitignore"`
+	ContextLines     *int     `json:"contextLines,omitempty" jsonschema_description:"Lines shown before and after each match (default 0, max 10)"`
+	MaxResults       int      `json:"maxResults,omitempty" jsonschema_description:"Maximum number of matches (default 100, max 1000)"`
+}
+
+type SearchSessionOutput struct {
+	SessionID string              `json:"sessionId"`
+	Matches   []SearchMatchOutput `json:"matches"`
+	Truncated bool                `json:"truncated" jsonschema_description:"Whether more matches exist than were returned"`
+}
+
+type SearchMatchOutput struct {
+	Path          string   `json:"path"`
+	LineNumber    int      `json:"lineNumber"`
+	Column        int      `json:"column" jsonschema_description:"1-based byte offset of the first match on the line"`
+	Line          string   `json:"line"`
+	ContextBefore []string `json:"contextBefore,omitempty"`
+	ContextAfter  []string `json:"contextAfter,omitempty"`
+}
+
+type ApplyPatchArgs struct {
+	SessionID string `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+	Patch     string `json:"patch" jsonschema:"required" jsonschema_description:"Unified diff, as produced by git diff, with paths relative to the repository root (max 4 MiB)"`
+	Check     bool   `json:"check,omitempty" jsonschema_description:"Only report whether and how the patch would apply, without changing files"`
+}
+
+type ApplyPatchOutput struct {
+	SessionID       string            `json:"sessionId"`
+	Check           bool              `json:"check"`
+	Applies         bool              `json:"applies" jsonschema_description:"Whether the patch applies, as is or with a three-way merge"`
+	Applied         bool              `json:"applied" jsonschema_description:"Whether the worktree was changed"`
+	ThreeWay        bool              `json:"threeWay" jsonschema_description:"Whether the patch only applies with a three-way merge"`
+	Files           []string          `json:"files"`
+	Hunks           []PatchHunkOutput `json:"hunks"`
+	ConflictedPaths []string          `json:"conflictedPaths,omitempty" jsonschema_description:"Files a three-way merge would leave conflicted; the patch is not applied"`
+	Errors          []string          `json:"errors,omitempty" jsonschema_description:"Why git could not apply the patch"`
+}
+
+type PatchHunkOutput struct {
+	Path    string `json:"path"`
+	Hunk    int    `json:"hunk" jsonschema_description:"1-based hunk number within the file"`
+	Header  string `json:"header,omitempty" jsonschema_description:"The @@ line of the hunk; empty for changes without hunks such as renames"`
+	Applies bool   `json:"applies" jsonschema_description:"Whether the hunk applies cleanly on its own"`
+}
+
+type ExecInSessionArgs struct {
+	SessionID      string            `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+	Command        string            `json:"command" jsonschema:"required" jsonschema_description:"Binary allowed by the server's exec policy, e.g. go or make; not run through a shell"`
+	Args           []string          `json:"args,omitempty" jsonschema_description:"Arguments passed to the command as is"`
+	Env            map[string]string `json:"env,omitempty" jsonschema_description:"Extra environment variables; only names the server's exec.allowedEnv allows"`
+	TimeoutSeconds int               `json:"timeoutSeconds,omitempty" jsonschema_description:"Shorter timeout than the server's limit"`
+}
+
+type ExecInSessionOutput struct {
+	SessionID       string   `json:"sessionId"`
+	Command         string   `json:"command"`
+	Args            []string `json:"args"`
+	ExitCode        int      `json:"exitCode" jsonschema_description:"Exit status, or -1 when the command was killed"`
+	Stdout          string   `json:"stdout"`
+	Stderr          string   `json:"stderr"`
+	StdoutTruncated bool     `json:"stdoutTruncated"`
+	StderrTruncated bool     `json:"stderrTruncated"`
+	TimedOut        bool     `json:"timedOut"`
+	DurationMs      int64    `json:"durationMs"`
+}
+
+type GetSessionOverlapsArgs struct {
+	TrialMerge bool `json:"trialMerge,omitempty" jsonschema_description:"Dry-run merge each overlapping pair of sessions to find real conflicts"`
+}
+
+type GetSessionOverlapsOutput struct {
+	SessionIDs []string               `json:"sessionIds"`
+	Overlaps   []SessionOverlapOutput `json:"overlaps"`
+	Paths      []PathOverlapOutput    `json:"paths"`
+	Errors     []SessionErrorOutput   `json:"errors,omitempty" jsonschema_description:"Sessions left out because their changes could not be read"`
+}
+
+type SessionErrorOutput struct {
+	SessionID string `json:"sessionId"`
+	Error     string `json:"error"`
+}
+
+type SessionOverlapOutput struct {
+	SessionA        string   `json:"sessionA"`
+	SessionB        string   `json:"sessionB"`
+	Paths           []string `json:"paths"`
+	Conflicting     *bool    `json:"conflicting,omitempty" jsonschema_description:"Whether the pair's committed work conflicts; only set when trialMerge ran"`
+	ConflictedPaths []string `json:"conflictedPaths,omitempty"`
+}
+
+type PathOverlapOutput struct {
+	Path       string   `json:"path"`
+	SessionIDs []string `json:"sessionIds"`
+}
+
+type CreateCheckpointArgs struct {
+	SessionID string `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+	Name      string `json:"name,omitempty" jsonschema_description:"Checkpoint name: lowercase letters, numbers and hyphens (defaults to checkpoint-<n>)"`
+	Message   string `json:"message,omitempty" jsonschema_description:"Description stored with the checkpoint"`
+}
+
+type CreateCheckpointOutput struct {
+	SessionID  string           `json:"sessionId"`
+	Checkpoint CheckpointOutput `json:"checkpoint"`
+}
+
+type ListCheckpointsArgs struct {
+	SessionID string `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+}
+
+type ListCheckpointsOutput struct {
+	SessionID   string             `json:"sessionId"`
+	Checkpoints []CheckpointOutput `json:"checkpoints"`
+}
+
+type RestoreCheckpointArgs struct {
+	SessionID string `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+	Name      string `json:"name" jsonschema:"required" jsonschema_description:"Name of the checkpoint to restore"`
+}
+
+type RestoreCheckpointOutput struct {
+	SessionID  string           `json:"sessionId"`
+	Checkpoint CheckpointOutput `json:"checkpoint"`
+	Backup     CheckpointOutput `json:"backup" jsonschema_description:"Checkpoint of the state before the restore; restore it to undo"`
+	RestoredAt string           `json:"restoredAt"`
+}
+
+type CheckpointOutput struct {
+	Name       string `json:"name"`
+	CommitSHA  string `json:"commitSha" jsonschema_description:"Snapshot commit holding the full working tree"`
+	HeadCommit string `json:"headCommit" jsonschema_description:"Commit the session branch pointed at when the checkpoint was taken"`
+	Message    string `json:"message"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+type CommitSessionArgs struct {
+	SessionID string   `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+	Message   string   `json:"message" jsonschema:"required" jsonschema_description:"Commit message"`
+	Paths     []string `json:"paths,omitempty" jsonschema_description:"Paths relative to the worktree to commit (defaults to every change, including untracked files)"`
+	Agent     string   `json:"agent,omitempty" jsonschema_description:"Agent recorded in the Agent trailer (defaults to the MCP client name)"`
+}
+
+type CommitSessionOutput struct {
+	SessionID  string       `json:"sessionId"`
+	BranchName string       `json:"branchName"`
+	Commit     CommitOutput `json:"commit"`
+}
+
+type PublishSessionArgs struct {
+	SessionID string `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+	Title     string `json:"title,omitempty" jsonschema_description:"Pull request title (defaults to the commit subject of a single-commit session)"`
+	Body      string `json:"body,omitempty" jsonschema_description:"Pull request description (defaults to the list of commits)"`
+	Draft     bool   `json:"draft,omitempty" jsonschema_description:"Open the pull request as a draft"`
+	Force     bool   `json:"force,omitempty" jsonschema_description:"Overwrite the remote branch with a lease, e.g. after sync_session rebased it"`
+}
+
+type PublishSessionOutput struct {
+	SessionID          string             `json:"sessionId"`
+	Remote             string             `json:"remote"`
+	BranchName         string             `json:"branchName"`
+	BaseRef            string             `json:"baseRef"`
+	HeadCommit         string             `json:"headCommit"`
+	PullRequest        *PullRequestOutput `json:"pullRequest,omitempty"`
+	PullRequestCreated bool               `json:"pullRequestCreated"`
+}
+
+type PullRequestOutput struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+}
+
+type ExportSessionArgs struct {
+	SessionID  string `json:"sessionId" jsonschema:"required" jsonschema_description:"Session identifier"`
+	Format     string `json:"format,omitempty" jsonschema_description:"bundle (default) keeps commits exactly; patches writes a format-patch series"`
+	OutputPath string `json:"outputPath" jsonschema:"required" jsonschema_description:"Directory to create for the export, relative to the server's export directory; it must not exist"`
+}
+
+type ExportSessionOutput struct {
+	SessionID        string   `json:"sessionId"`
+	Format           string   `json:"format"`
+	OutputPath       string   `json:"outputPath"`
+	ManifestPath     string   `json:"manifestPath"`
+	Files            []string `json:"files"`
+	BaseCommit       string   `json:"baseCommit,omitempty"`
+	HeadCommit       string   `json:"headCommit"`
+	Commits          int      `json:"commits"`
+	UncommittedFiles int      `json:"uncommittedFiles"`
+}
+
+type ImportSessionArgs struct {
+	InputPath string `json:"inputPath" jsonschema:"required" jsonschema_description:"Directory written by export_session, relative to the server's export directory or as returned in its outputPath"`
+	SessionID string `json:"sessionId,omitempty" jsonschema_description:"Import under this session ID instead of the exported one"`
+}
+
+type ImportSessionOutput struct {
 	SessionID    string `json:"sessionId"`
+	Format       string `json:"format"`
 	WorktreePath string `json:"worktreePath"`
 	BranchName   string `json:"branchName"`
-	Status       string `json:"status"`
-	LinesAdded   int    `json:"linesAdded"`
-	LinesRemoved int    `json:"linesRemoved"`
+	BaseRef      string `json:"baseRef"`
+	BaseCommit   string `json:"baseCommit,omitempty"`
+	HeadCommit   string `json:"headCommit"`
+	Commits      int    `json:"commits"`
+}
+
+type ForkSessionArgs struct {
+	SessionID          string `json:"sessionId" jsonschema:"required" jsonschema_description:"Identifier of the new session"`
+	ParentSessionID    string `json:"parentSessionId" jsonschema:"required" jsonschema_description:"Session whose branch tip the new session starts from"`
+	IncludeUncommitted bool   `json:"includeUncommitted,omitempty" jsonschema_description:"Also copy the parent's uncommitted and untracked files"`
+}
+
+type ForkSessionOutput struct {
+	SessionID        string `json:"sessionId"`
+	ParentSessionID  string `json:"parentSessionId"`
+	WorktreePath     string `json:"worktreePath"`
+	BranchName       string `json:"branchName"`
+	BaseRef          string `json:"baseRef"`
+	BaseCommit       string `json:"baseCommit,omitempty"`
+	HeadCommit       string `json:"headCommit"`
+	Status           string `json:"status"`
+	UncommittedFiles int    `json:"uncommittedFiles"`
 }
 
 type MCPServer struct {
-	mcpServer             *mcpsdk.Server
-	createWorktreeUseCase *application.CreateWorktreeUseCase
-	removeSessionUseCase  *application.RemoveSessionUseCase
-	getSessionsUseCase    *application.GetSessionsUseCase
+	mcpServer                 *mcpsdk.Server
+	createWorktreeUseCase     *application.CreateWorktreeUseCase
+	removeSessionUseCase      *application.RemoveSessionUseCase
+	getSessionsUseCase        *application.GetSessionsUseCase
+	getSessionDiffUseCase     *application.GetSessionDiffUseCase
+	getSessionCommitsUseCase  *application.GetSessionCommitsUseCase
+	mergeSessionUseCase       *application.MergeSessionUseCase
+	checkMergeUseCase         *application.CheckMergeUseCase
+	syncSessionUseCase        *application.SyncSessionUseCase
+	getSessionOverlapsUseCase *application.GetSessionOverlapsUseCase
+	createCheckpointUseCase   *application.CreateCheckpointUseCase
+	listCheckpointsUseCase    *application.ListCheckpointsUseCase
+	restoreCheckpointUseCase  *application.RestoreCheckpointUseCase
+	commitSessionUseCase      *application.CommitSessionUseCase
+	publishSessionUseCase     *application.PublishSessionUseCase
+	exportSessionUseCase      *application.ExportSessionUseCase
+	importSessionUseCase      *application.ImportSessionUseCase
+	forkSessionUseCase        *application.ForkSessionUseCase
+	restackSessionsUseCase    *application.RestackSessionsUseCase
+	cherryPickUseCase         *application.CherryPickUseCase
+	compareSessionsUseCase    *application.CompareSessionsUseCase
+	readFileUseCase           *application.ReadFileUseCase
+	writeFileUseCase          *application.WriteFileUseCase
+	listDirectoryUseCase      *application.ListDirectoryUseCase
+	deleteFileUseCase         *application.DeleteFileUseCase
+	moveFileUseCase           *application.MoveFileUseCase
+	searchSessionUseCase      *application.SearchSessionUseCase
+	applyPatchUseCase         *application.ApplyPatchUseCase
+	execInSessionUseCase      *application.ExecInSessionUseCase
 }
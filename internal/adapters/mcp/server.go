@@ -3,16 +3,45 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/tzDel/orchestragent-mcp/internal/application"
 )
 
-func NewMCPServer(
-	createWorktreeUseCase *application.CreateWorktreeUseCase,
-	removeSessionUseCase *application.RemoveSessionUseCase,
-	getSessionsUseCase *application.GetSessionsUseCase,
-) (*MCPServer, error) {
+// UseCases bundles the application use cases exposed as MCP tools
+type UseCases struct {
+	CreateWorktree     *application.CreateWorktreeUseCase
+	RemoveSession      *application.RemoveSessionUseCase
+	GetSessions        *application.GetSessionsUseCase
+	GetSessionDiff     *application.GetSessionDiffUseCase
+	GetSessionCommits  *application.GetSessionCommitsUseCase
+	MergeSession       *application.MergeSessionUseCase
+	CheckMerge         *application.CheckMergeUseCase
+	SyncSession        *application.SyncSessionUseCase
+	GetSessionOverlaps *application.GetSessionOverlapsUseCase
+	CreateCheckpoint   *application.CreateCheckpointUseCase
+	ListCheckpoints    *application.ListCheckpointsUseCase
+	RestoreCheckpoint  *application.RestoreCheckpointUseCase
+	CommitSession      *application.CommitSessionUseCase
+	PublishSession     *application.PublishSessionUseCase
+	ExportSession      *application.ExportSessionUseCase
+	ImportSession      *application.ImportSessionUseCase
+	ForkSession        *application.ForkSessionUseCase
+	RestackSessions    *application.RestackSessionsUseCase
+	CherryPick         *application.CherryPickUseCase
+	CompareSessions    *application.CompareSessionsUseCase
+	ReadFile           *application.ReadFileUseCase
+	WriteFile          *application.WriteFileUseCase
+	ListDirectory      *application.ListDirectoryUseCase
+	DeleteFile         *application.DeleteFileUseCase
+	MoveFile           *application.MoveFileUseCase
+	SearchSession      *application.SearchSessionUseCase
+	ApplyPatch         *application.ApplyPatchUseCase
+	ExecInSession      *application.ExecInSessionUseCase
+}
+
+func NewMCPServer(useCases UseCases) (*MCPServer, error) {
 	impl := &mcpsdk.Implementation{
 		Name:    "orchestragent-mcp",
 		Version: "0.1.0",
@@ -21,17 +50,42 @@ func NewMCPServer(
 	mcpServer := mcpsdk.NewServer(impl, nil)
 
 	server := &MCPServer{
-		mcpServer:             mcpServer,
-		createWorktreeUseCase: createWorktreeUseCase,
-		removeSessionUseCase:  removeSessionUseCase,
-		getSessionsUseCase:    getSessionsUseCase,
+		mcpServer:                 mcpServer,
+		createWorktreeUseCase:     useCases.CreateWorktree,
+		removeSessionUseCase:      useCases.RemoveSession,
+		getSessionsUseCase:        useCases.GetSessions,
+		getSessionDiffUseCase:     useCases.GetSessionDiff,
+		getSessionCommitsUseCase:  useCases.GetSessionCommits,
+		mergeSessionUseCase:       useCases.MergeSession,
+		checkMergeUseCase:         useCases.CheckMerge,
+		syncSessionUseCase:        useCases.SyncSession,
+		getSessionOverlapsUseCase: useCases.GetSessionOverlaps,
+		createCheckpointUseCase:   useCases.CreateCheckpoint,
+		listCheckpointsUseCase:    useCases.ListCheckpoints,
+		restoreCheckpointUseCase:  useCases.RestoreCheckpoint,
+		commitSessionUseCase:      useCases.CommitSession,
+		publishSessionUseCase:     useCases.PublishSession,
+		exportSessionUseCase:      useCases.ExportSession,
+		importSessionUseCase:      useCases.ImportSession,
+		forkSessionUseCase:        useCases.ForkSession,
+		restackSessionsUseCase:    useCases.RestackSessions,
+		cherryPickUseCase:         useCases.CherryPick,
+		compareSessionsUseCase:    useCases.CompareSessions,
+		readFileUseCase:           useCases.ReadFile,
+		writeFileUseCase:          useCases.WriteFile,
+		listDirectoryUseCase:      useCases.ListDirectory,
+		deleteFileUseCase:         useCases.DeleteFile,
+		moveFileUseCase:           useCases.MoveFile,
+		searchSessionUseCase:      useCases.SearchSession,
+		applyPatchUseCase:         useCases.ApplyPatch,
+		execInSessionUseCase:      useCases.ExecInSession,
 	}
 
 	mcpsdk.AddTool(
 		mcpServer,
 		&mcpsdk.Tool{
 			Name:        "create_worktree",
-			Description: "Creates an isolated git worktree for a specific session with its own branch",
+			Description: "Creates an isolated git worktree for a specific session with its own branch, optionally starting from a given branch, tag or commit, or stacked on another session's branch",
 		},
 		server.handleCreateWorktree,
 	)
@@ -54,6 +108,231 @@ func NewMCPServer(
 		server.handleGetSessions,
 	)
 
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "get_session_diff",
+			Description: "Returns the unified diff of a session against its base, filtered by path globs and paginated by file. Pass nextCursor back as cursor to fetch the next page.",
+		},
+		server.handleGetSessionDiff,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "get_session_commits",
+			Description: "Lists the commits on a session branch that are not yet on its base, newest first, with messages, trailers and per-file line counts",
+		},
+		server.handleGetSessionCommits,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "merge_session",
+			Description: "Merges a session branch into its base branch using merge, squash, rebase or ff-only, marks the session merged and optionally removes it. Conflicts are aborted and reported.",
+		},
+		server.handleMergeSession,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "check_merge",
+			Description: "Dry-runs a merge of a session's committed work into its base without touching any worktree, returning whether it is clean and the conflicting paths and hunks",
+		},
+		server.handleCheckMerge,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "sync_session",
+			Description: "Brings a session branch up to date with its base by rebase (default) or merge and records the new base commit. Refuses when the worktree has uncommitted changes; conflicts are aborted and reported.",
+		},
+		server.handleSyncSession,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "get_session_overlaps",
+			Description: "Compares the changed files of all unmerged sessions and reports which pairs touch the same paths. With trialMerge=true, overlapping pairs are dry-run merged to show which would actually conflict.",
+		},
+		server.handleGetSessionOverlaps,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "create_checkpoint",
+			Description: "Snapshots a session worktree, including uncommitted and untracked files, into a hidden ref without changing the worktree or its branch",
+		},
+		server.handleCreateCheckpoint,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "list_checkpoints",
+			Description: "Lists a session's checkpoints, oldest first",
+		},
+		server.handleListCheckpoints,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "restore_checkpoint",
+			Description: "Restores a session worktree and branch to a checkpoint, discarding all commits and file changes made since",
+		},
+		server.handleRestoreCheckpoint,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "commit_session",
+			Description: "Stages all or selected paths in a session worktree and commits them on the session branch with Session-Id and Agent trailers",
+		},
+		server.handleCommitSession,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "publish_session",
+			Description: "Pushes a session branch to the configured remote and opens a pull request into its base on the configured forge",
+		},
+		server.handlePublishSession,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "export_session",
+			Description: "Exports a session's commits as a git bundle or format-patch series, with a JSON manifest of its metadata, into a new directory",
+		},
+		server.handleExportSession,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "import_session",
+			Description: "Recreates a session's branch, worktree and record from a directory written by export_session",
+		},
+		server.handleImportSession,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "fork_session",
+			Description: "Creates a new session whose branch and worktree start at another session's branch tip, optionally with its uncommitted changes",
+		},
+		server.handleForkSession,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "restack_sessions",
+			Description: "Rebases stacked sessions onto the current tip of the session they build on, moving them onto its base once it is merged",
+		},
+		server.handleRestackSessions,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "cherry_pick",
+			Description: "Applies selected commits, or all commits, of one session onto another session's branch, aborting cleanly on conflicts",
+		},
+		server.handleCherryPick,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "compare_sessions",
+			Description: "Returns the diff between two session branches with per-file stats and the files only one of them touched, paginated by file like get_session_diff",
+		},
+		server.handleCompareSessions,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "read_file",
+			Description: "Reads a file inside a session worktree; binary content is returned base64 encoded",
+		},
+		server.handleReadFile,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "write_file",
+			Description: "Creates or replaces a file inside a session worktree, creating missing parent directories; .git cannot be written",
+		},
+		server.handleWriteFile,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "list_directory",
+			Description: "Lists the entries of a directory inside a session worktree",
+		},
+		server.handleListDirectory,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "delete_file",
+			Description: "Deletes a file or symlink inside a session worktree; directories and .git cannot be deleted",
+		},
+		server.handleDeleteFile,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "move_file",
+			Description: "Moves or renames a file or directory inside a session worktree",
+		},
+		server.handleMoveFile,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "search_session",
+			Description: "Searches the files of a session worktree for a regular expression or literal text with git grep, respecting .gitignore",
+		},
+		server.handleSearchSession,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "apply_patch",
+			Description: "Applies a unified diff to a session worktree with git apply, falling back to a three-way merge, and reports which hunks apply; check mode only validates",
+		},
+		server.handleApplyPatch,
+	)
+
+	mcpsdk.AddTool(
+		mcpServer,
+		&mcpsdk.Tool{
+			Name:        "exec_in_session",
+			Description: "Runs a command allowed by the server's exec policy, such as go test or make, in a session worktree and returns its exit code, stdout and stderr",
+		},
+		server.handleExecInSession,
+	)
+
 	return server, nil
 }
 
@@ -63,7 +342,9 @@ func (s *MCPServer) handleCreateWorktree(
 	args CreateWorktreeArgs,
 ) (*mcpsdk.CallToolResult, any, error) {
 	request := application.CreateWorktreeRequest{
-		SessionID: args.SessionID,
+		SessionID:       args.SessionID,
+		BaseRef:         args.BaseRef,
+		ParentSessionID: args.ParentSessionID,
 	}
 
 	response, err := s.createWorktreeUseCase.Execute(ctx, request)
@@ -73,13 +354,16 @@ func (s *MCPServer) handleCreateWorktree(
 	}
 
 	output := CreateWorktreeOutput{
-		SessionID:    response.SessionID,
-		WorktreePath: response.WorktreePath,
-		BranchName:   response.BranchName,
-		Status:       response.Status,
+		SessionID:       response.SessionID,
+		WorktreePath:    response.WorktreePath,
+		BranchName:      response.BranchName,
+		BaseRef:         response.BaseRef,
+		BaseCommit:      response.BaseCommit,
+		Status:          response.Status,
+		ParentSessionID: response.ParentSessionID,
 	}
 
-	message := fmt.Sprintf("Successfully created worktree for session '%s' at '%s' on branch '%s'", response.SessionID, response.WorktreePath, response.BranchName)
+	message := fmt.Sprintf("Successfully created worktree for session '%s' at '%s' on branch '%s' from '%s'", response.SessionID, response.WorktreePath, response.BranchName, response.BaseRef)
 	return newSuccessResult(message), output, nil
 }
 
@@ -134,7 +418,11 @@ func (s *MCPServer) handleGetSessions(
 	req *mcpsdk.CallToolRequest,
 	args GetSessionsArgs,
 ) (*mcpsdk.CallToolResult, any, error) {
-	request := application.GetSessionsRequest{}
+	request := application.GetSessionsRequest{
+		BehindMoreThan: args.BehindMoreThan,
+		StaleOnly:      args.StaleOnly,
+		CheckMergeable: args.CheckMergeable,
+	}
 
 	response, err := s.getSessionsUseCase.Execute(ctx, request)
 	if err != nil {
@@ -144,24 +432,1060 @@ func (s *MCPServer) handleGetSessions(
 
 	sessionOutputs := make([]SessionOutput, 0, len(response.Sessions))
 	for _, session := range response.Sessions {
-		sessionOutputs = append(sessionOutputs, SessionOutput{
+		sessionOutput := SessionOutput{
 			SessionID:    session.SessionID,
 			WorktreePath: session.WorktreePath,
 			BranchName:   session.BranchName,
+			BaseRef:      session.BaseRef,
+			BaseCommit:   session.BaseCommit,
 			Status:       session.Status,
 			LinesAdded:   session.LinesAdded,
 			LinesRemoved: session.LinesRemoved,
-		})
+			FilesChanged: session.FilesChanged,
+			Files:        buildFileChangeOutputs(session.Files),
+			Mergeable:    session.Mergeable,
+			Breakdown: DiffBreakdownOutput{
+				Committed: DiffContributionOutput(session.Breakdown.Committed),
+				Staged:    DiffContributionOutput(session.Breakdown.Staged),
+				Unstaged:  DiffContributionOutput(session.Breakdown.Unstaged),
+				Untracked: DiffContributionOutput(session.Breakdown.Untracked),
+			},
+			Ahead:           session.Ahead,
+			Behind:          session.Behind,
+			Stale:           session.Stale,
+			Checkpoints:     buildCheckpointOutputs(session.Checkpoints),
+			PullRequest:     buildPullRequestOutput(session.PullRequest),
+			ParentSessionID: session.ParentSessionID,
+			Errors:          session.Errors,
+		}
+		if session.LastCommitAt != nil {
+			sessionOutput.LastCommitAt = session.LastCommitAt.Format("2006-01-02T15:04:05Z07:00")
+		}
+		sessionOutputs = append(sessionOutputs, sessionOutput)
 	}
 
 	output := GetSessionsOutput{
-		Sessions: sessionOutputs,
+		Sessions:  sessionOutputs,
+		StackTree: response.StackTree,
 	}
 
 	message := fmt.Sprintf("Found %d session(s)", len(response.Sessions))
+	if response.StackTree != "" {
+		message += "\n\nStacks:\n" + response.StackTree
+	}
+	return newSuccessResult(message), output, nil
+}
+
+func buildFileChangeOutputs(files []application.FileChangeDTO) []FileChangeOutput {
+	fileOutputs := make([]FileChangeOutput, 0, len(files))
+	for _, file := range files {
+		fileOutputs = append(fileOutputs, FileChangeOutput{
+			Path:         file.Path,
+			OldPath:      file.OldPath,
+			ChangeType:   file.ChangeType,
+			LinesAdded:   file.LinesAdded,
+			LinesRemoved: file.LinesRemoved,
+			Binary:       file.Binary,
+			Untracked:    file.Untracked,
+		})
+	}
+	return fileOutputs
+}
+
+func (s *MCPServer) handleGetSessionDiff(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args GetSessionDiffArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.GetSessionDiffRequest{
+		SessionID:       args.SessionID,
+		PathGlobs:       args.Paths,
+		ContextLines:    args.ContextLines,
+		MaxBytesPerFile: args.MaxBytesPerFile,
+		MaxBytesPerPage: args.MaxBytesPerPage,
+		PageSize:        args.PageSize,
+		Cursor:          args.Cursor,
+	}
+
+	response, err := s.getSessionDiffUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to get session diff: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	fileOutputs := make([]FileDiffOutput, 0, len(response.Files))
+	for _, file := range response.Files {
+		fileOutputs = append(fileOutputs, FileDiffOutput{
+			Path:      file.Path,
+			Diff:      file.Diff,
+			Truncated: file.Truncated,
+			SizeBytes: file.SizeBytes,
+		})
+	}
+
+	output := GetSessionDiffOutput{
+		SessionID:  response.SessionID,
+		BaseRef:    response.BaseRef,
+		Files:      fileOutputs,
+		TotalFiles: response.TotalFiles,
+		NextCursor: response.NextCursor,
+	}
+
+	message := fmt.Sprintf("Showing %d of %d changed file(s) in session '%s'", len(response.Files), response.TotalFiles, response.SessionID)
+	if response.NextCursor != "" {
+		message += "; more files available via nextCursor"
+	}
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleGetSessionCommits(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args GetSessionCommitsArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.GetSessionCommitsRequest{
+		SessionID: args.SessionID,
+	}
+
+	response, err := s.getSessionCommitsUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to get session commits: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	commitOutputs := make([]CommitOutput, 0, len(response.Commits))
+	for _, commit := range response.Commits {
+		commitOutputs = append(commitOutputs, buildCommitOutput(commit))
+	}
+
+	output := GetSessionCommitsOutput{
+		SessionID:  response.SessionID,
+		BranchName: response.BranchName,
+		BaseRef:    response.BaseRef,
+		Commits:    commitOutputs,
+	}
+
+	message := fmt.Sprintf("Found %d commit(s) on '%s' since '%s'", len(response.Commits), response.BranchName, response.BaseRef)
+	return newSuccessResult(message), output, nil
+}
+
+func buildCommitOutput(commit application.CommitDTO) CommitOutput {
+	trailerOutputs := make([]CommitTrailerOutput, 0, len(commit.Trailers))
+	for _, trailer := range commit.Trailers {
+		trailerOutputs = append(trailerOutputs, CommitTrailerOutput(trailer))
+	}
+
+	fileOutputs := make([]CommitFileOutput, 0, len(commit.Files))
+	for _, file := range commit.Files {
+		fileOutputs = append(fileOutputs, CommitFileOutput(file))
+	}
+
+	return CommitOutput{
+		SHA:            commit.SHA,
+		ParentSHAs:     commit.ParentSHAs,
+		AuthorName:     commit.AuthorName,
+		AuthorEmail:    commit.AuthorEmail,
+		AuthoredAt:     commit.AuthoredAt.Format("2006-01-02T15:04:05Z07:00"),
+		CommitterName:  commit.CommitterName,
+		CommitterEmail: commit.CommitterEmail,
+		CommittedAt:    commit.CommittedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Subject:        commit.Subject,
+		Body:           commit.Body,
+		Trailers:       trailerOutputs,
+		Files:          fileOutputs,
+		LinesAdded:     commit.LinesAdded,
+		LinesRemoved:   commit.LinesRemoved,
+	}
+}
+
+func (s *MCPServer) handleMergeSession(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args MergeSessionArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.MergeSessionRequest{
+		SessionID:        args.SessionID,
+		Strategy:         args.Strategy,
+		Message:          args.Message,
+		RemoveAfterMerge: args.RemoveAfterMerge,
+	}
+
+	response, err := s.mergeSessionUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to merge session: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := MergeSessionOutput{
+		SessionID:       response.SessionID,
+		BaseRef:         response.BaseRef,
+		Strategy:        response.Strategy,
+		Merged:          response.Merged,
+		MergedCommit:    response.MergedCommit,
+		ConflictedPaths: response.ConflictedPaths,
+		Status:          response.Status,
+		Removed:         response.Removed,
+	}
+
+	if !response.RemovedAt.IsZero() {
+		output.RemovedAt = response.RemovedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	if !response.Merged {
+		message := fmt.Sprintf(
+			"CONFLICT: Session '%s' cannot be merged into '%s' with strategy '%s'; the merge was aborted.\n\nConflicting files:\n%s",
+			response.SessionID,
+			response.BaseRef,
+			response.Strategy,
+			strings.Join(response.ConflictedPaths, "\n"),
+		)
+		return &mcpsdk.CallToolResult{
+			Content: []mcpsdk.Content{newTextContent(message)},
+			IsError: false,
+		}, output, nil
+	}
+
+	message := fmt.Sprintf("Successfully merged session '%s' into '%s' (%s) at %s", response.SessionID, response.BaseRef, response.Strategy, response.MergedCommit)
+	if response.Removed {
+		message += "; session removed"
+	}
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleCheckMerge(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args CheckMergeArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.CheckMergeRequest{
+		SessionID: args.SessionID,
+	}
+
+	response, err := s.checkMergeUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to check merge: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	conflictOutputs := make([]MergeConflictOutput, 0, len(response.Conflicts))
+	for _, conflict := range response.Conflicts {
+		conflictOutputs = append(conflictOutputs, MergeConflictOutput(conflict))
+	}
+
+	output := CheckMergeOutput{
+		SessionID: response.SessionID,
+		BaseRef:   response.BaseRef,
+		Clean:     response.Clean,
+		Conflicts: conflictOutputs,
+	}
+
+	if !response.Clean {
+		paths := make([]string, 0, len(response.Conflicts))
+		for _, conflict := range response.Conflicts {
+			paths = append(paths, conflict.Path)
+		}
+		message := fmt.Sprintf("Session '%s' would conflict with '%s' in %d file(s):\n%s", response.SessionID, response.BaseRef, len(paths), strings.Join(paths, "\n"))
+		return newSuccessResult(message), output, nil
+	}
+
+	message := fmt.Sprintf("Session '%s' merges cleanly into '%s'", response.SessionID, response.BaseRef)
 	return newSuccessResult(message), output, nil
 }
 
+func (s *MCPServer) handleSyncSession(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args SyncSessionArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.SyncSessionRequest{
+		SessionID: args.SessionID,
+		Strategy:  args.Strategy,
+	}
+
+	response, err := s.syncSessionUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to sync session: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := SyncSessionOutput(*response)
+
+	if !response.Synced {
+		message := fmt.Sprintf(
+			"CONFLICT: Session '%s' cannot be synced with '%s' using %s; the %s was aborted.\n\nConflicting files:\n%s",
+			response.SessionID,
+			response.BaseRef,
+			response.Strategy,
+			response.Strategy,
+			strings.Join(response.ConflictedPaths, "\n"),
+		)
+		return &mcpsdk.CallToolResult{
+			Content: []mcpsdk.Content{newTextContent(message)},
+			IsError: false,
+		}, output, nil
+	}
+
+	message := fmt.Sprintf("Successfully synced session '%s' with '%s' at %s (%s)", response.SessionID, response.BaseRef, response.BaseCommit, response.Strategy)
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleGetSessionOverlaps(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args GetSessionOverlapsArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.GetSessionOverlapsRequest{
+		TrialMerge: args.TrialMerge,
+	}
+
+	response, err := s.getSessionOverlapsUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to get session overlaps: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	overlapOutputs := make([]SessionOverlapOutput, 0, len(response.Overlaps))
+	conflictingPairs := 0
+	for _, overlap := range response.Overlaps {
+		overlapOutputs = append(overlapOutputs, SessionOverlapOutput(overlap))
+		if overlap.Conflicting != nil && *overlap.Conflicting {
+			conflictingPairs++
+		}
+	}
+	pathOutputs := make([]PathOverlapOutput, 0, len(response.Paths))
+	for _, pathOverlap := range response.Paths {
+		pathOutputs = append(pathOutputs, PathOverlapOutput(pathOverlap))
+	}
+	var errorOutputs []SessionErrorOutput
+	for _, sessionError := range response.Errors {
+		errorOutputs = append(errorOutputs, SessionErrorOutput(sessionError))
+	}
+
+	output := GetSessionOverlapsOutput{
+		SessionIDs: response.SessionIDs,
+		Overlaps:   overlapOutputs,
+		Paths:      pathOutputs,
+		Errors:     errorOutputs,
+	}
+
+	message := fmt.Sprintf("Compared %d session(s): %d overlapping pair(s) on %d path(s)", len(response.SessionIDs), len(response.Overlaps), len(response.Paths))
+	if args.TrialMerge {
+		message += fmt.Sprintf(", %d of them conflicting", conflictingPairs)
+	}
+	if len(response.Errors) > 0 {
+		message += fmt.Sprintf("; skipped %d session(s) whose changes could not be read", len(response.Errors))
+	}
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleCreateCheckpoint(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args CreateCheckpointArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.CreateCheckpointRequest{
+		SessionID: args.SessionID,
+		Name:      args.Name,
+		Message:   args.Message,
+	}
+
+	response, err := s.createCheckpointUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to create checkpoint: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := CreateCheckpointOutput{
+		SessionID:  response.SessionID,
+		Checkpoint: buildCheckpointOutput(response.Checkpoint),
+	}
+
+	message := fmt.Sprintf("Created checkpoint '%s' for session '%s'", response.Checkpoint.Name, response.SessionID)
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleListCheckpoints(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args ListCheckpointsArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.ListCheckpointsRequest{
+		SessionID: args.SessionID,
+	}
+
+	response, err := s.listCheckpointsUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to list checkpoints: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := ListCheckpointsOutput{
+		SessionID:   response.SessionID,
+		Checkpoints: buildCheckpointOutputs(response.Checkpoints),
+	}
+
+	message := fmt.Sprintf("Found %d checkpoint(s) for session '%s'", len(response.Checkpoints), response.SessionID)
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleRestoreCheckpoint(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args RestoreCheckpointArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.RestoreCheckpointRequest{
+		SessionID: args.SessionID,
+		Name:      args.Name,
+	}
+
+	response, err := s.restoreCheckpointUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to restore checkpoint: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := RestoreCheckpointOutput{
+		SessionID:  response.SessionID,
+		Checkpoint: buildCheckpointOutput(response.Checkpoint),
+		Backup:     buildCheckpointOutput(response.Backup),
+		RestoredAt: response.RestoredAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	message := fmt.Sprintf("Restored session '%s' to checkpoint '%s'; the previous state is saved as '%s'", response.SessionID, response.Checkpoint.Name, response.Backup.Name)
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleCommitSession(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args CommitSessionArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	agent := args.Agent
+	if agent == "" {
+		agent = clientName(req)
+	}
+
+	request := application.CommitSessionRequest{
+		SessionID: args.SessionID,
+		Message:   args.Message,
+		Paths:     args.Paths,
+		Agent:     agent,
+	}
+
+	response, err := s.commitSessionUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to commit session: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := CommitSessionOutput{
+		SessionID:  response.SessionID,
+		BranchName: response.BranchName,
+		Commit:     buildCommitOutput(response.Commit),
+	}
+
+	message := fmt.Sprintf("Committed %s on '%s' (%d file(s), +%d -%d)",
+		response.Commit.SHA, response.BranchName, len(response.Commit.Files), response.Commit.LinesAdded, response.Commit.LinesRemoved)
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handlePublishSession(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args PublishSessionArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.PublishSessionRequest{
+		SessionID: args.SessionID,
+		Title:     args.Title,
+		Body:      args.Body,
+		Draft:     args.Draft,
+		Force:     args.Force,
+	}
+
+	response, err := s.publishSessionUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to publish session: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := PublishSessionOutput{
+		SessionID:          response.SessionID,
+		Remote:             response.Remote,
+		BranchName:         response.BranchName,
+		BaseRef:            response.BaseRef,
+		HeadCommit:         response.HeadCommit,
+		PullRequest:        buildPullRequestOutput(response.PullRequest),
+		PullRequestCreated: response.PullRequestCreated,
+	}
+
+	message := fmt.Sprintf("Pushed '%s' to '%s' at %s", response.BranchName, response.Remote, response.HeadCommit)
+	switch {
+	case response.PullRequestCreated:
+		message += fmt.Sprintf("; opened pull request #%d: %s", response.PullRequest.Number, response.PullRequest.URL)
+	case response.PullRequest != nil:
+		message += fmt.Sprintf("; updated pull request #%d: %s", response.PullRequest.Number, response.PullRequest.URL)
+	default:
+		message += "; no forge is configured, so no pull request was opened"
+	}
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleExportSession(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args ExportSessionArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.ExportSessionRequest{
+		SessionID:  args.SessionID,
+		Format:     args.Format,
+		OutputPath: args.OutputPath,
+	}
+
+	response, err := s.exportSessionUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to export session: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := ExportSessionOutput{
+		SessionID:        response.SessionID,
+		Format:           string(response.Format),
+		OutputPath:       response.OutputPath,
+		ManifestPath:     response.ManifestPath,
+		Files:            response.Files,
+		BaseCommit:       response.BaseCommit,
+		HeadCommit:       response.HeadCommit,
+		Commits:          response.Commits,
+		UncommittedFiles: response.UncommittedFiles,
+	}
+
+	message := fmt.Sprintf("Exported %d commit(s) of session '%s' as %s to '%s'", response.Commits, response.SessionID, response.Format, response.OutputPath)
+	if response.UncommittedFiles > 0 {
+		message += fmt.Sprintf("; %d uncommitted file(s) were not exported", response.UncommittedFiles)
+	}
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleImportSession(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args ImportSessionArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.ImportSessionRequest{
+		InputPath: args.InputPath,
+		SessionID: args.SessionID,
+	}
+
+	response, err := s.importSessionUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to import session: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := ImportSessionOutput{
+		SessionID:    response.SessionID,
+		Format:       string(response.Format),
+		WorktreePath: response.WorktreePath,
+		BranchName:   response.BranchName,
+		BaseRef:      response.BaseRef,
+		BaseCommit:   response.BaseCommit,
+		HeadCommit:   response.HeadCommit,
+		Commits:      response.Commits,
+	}
+
+	message := fmt.Sprintf("Imported session '%s' at '%s' on branch '%s'", response.SessionID, response.WorktreePath, response.BranchName)
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleForkSession(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args ForkSessionArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.ForkSessionRequest{
+		SessionID:          args.SessionID,
+		ParentSessionID:    args.ParentSessionID,
+		IncludeUncommitted: args.IncludeUncommitted,
+	}
+
+	response, err := s.forkSessionUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to fork session: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := ForkSessionOutput{
+		SessionID:        response.SessionID,
+		ParentSessionID:  response.ParentSessionID,
+		WorktreePath:     response.WorktreePath,
+		BranchName:       response.BranchName,
+		BaseRef:          response.BaseRef,
+		BaseCommit:       response.BaseCommit,
+		HeadCommit:       response.HeadCommit,
+		Status:           response.Status,
+		UncommittedFiles: response.UncommittedFiles,
+	}
+
+	message := fmt.Sprintf("Forked session '%s' from '%s' at %s", response.SessionID, response.ParentSessionID, response.HeadCommit)
+	if response.UncommittedFiles > 0 {
+		message += fmt.Sprintf(" with %d uncommitted file(s)", response.UncommittedFiles)
+	}
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleRestackSessions(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args RestackSessionsArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.RestackSessionsRequest{
+		SessionID: args.SessionID,
+	}
+
+	response, err := s.restackSessionsUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to restack sessions: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	results := make([]RestackResultOutput, 0, len(response.Sessions))
+	restacked, blocked := 0, 0
+	for _, result := range response.Sessions {
+		results = append(results, RestackResultOutput(result))
+		if result.Restacked {
+			restacked++
+		}
+		if result.SkippedReason != "" || len(result.ConflictedPaths) > 0 {
+			blocked++
+		}
+	}
+
+	output := RestackSessionsOutput{
+		Sessions:  results,
+		StackTree: response.StackTree,
+	}
+
+	message := fmt.Sprintf("Restacked %d of %d stacked session(s)", restacked, len(response.Sessions))
+	if blocked > 0 {
+		message += fmt.Sprintf("; %d could not be restacked", blocked)
+	}
+	if response.StackTree != "" {
+		message += "\n\n" + response.StackTree
+	}
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleCherryPick(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args CherryPickArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.CherryPickRequest{
+		SourceSessionID: args.SourceSessionID,
+		TargetSessionID: args.TargetSessionID,
+		Commits:         args.Commits,
+	}
+
+	response, err := s.cherryPickUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to cherry-pick: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := CherryPickOutput(*response)
+
+	if !response.Applied {
+		message := fmt.Sprintf(
+			"CONFLICT: Commits of session '%s' cannot be cherry-picked onto '%s'; the cherry-pick was aborted.\n\nConflicting files:\n%s",
+			response.SourceSessionID,
+			response.TargetSessionID,
+			strings.Join(response.ConflictedPaths, "\n"),
+		)
+		return &mcpsdk.CallToolResult{
+			Content: []mcpsdk.Content{newTextContent(message)},
+			IsError: false,
+		}, output, nil
+	}
+
+	message := fmt.Sprintf("Cherry-picked %d commit(s) from session '%s' onto '%s' at %s", len(response.PickedCommits), response.SourceSessionID, response.TargetSessionID, response.HeadCommit)
+	if len(response.SkippedMergeCommits) > 0 {
+		message += fmt.Sprintf("; skipped %d merge commit(s)", len(response.SkippedMergeCommits))
+	}
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleCompareSessions(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args CompareSessionsArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.CompareSessionsRequest{
+		LeftSessionID:   args.LeftSessionID,
+		RightSessionID:  args.RightSessionID,
+		PathGlobs:       args.Paths,
+		ContextLines:    args.ContextLines,
+		MaxBytesPerFile: args.MaxBytesPerFile,
+		MaxBytesPerPage: args.MaxBytesPerPage,
+		PageSize:        args.PageSize,
+		Cursor:          args.Cursor,
+	}
+
+	response, err := s.compareSessionsUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to compare sessions: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	fileStatOutputs := make([]FileChangeOutput, 0, len(response.FileStats))
+	for _, file := range response.FileStats {
+		fileStatOutputs = append(fileStatOutputs, FileChangeOutput(file))
+	}
+	fileOutputs := make([]FileDiffOutput, 0, len(response.Files))
+	for _, file := range response.Files {
+		fileOutputs = append(fileOutputs, FileDiffOutput(file))
+	}
+
+	output := CompareSessionsOutput{
+		LeftSessionID:         response.LeftSessionID,
+		RightSessionID:        response.RightSessionID,
+		LeftHeadCommit:        response.LeftHeadCommit,
+		RightHeadCommit:       response.RightHeadCommit,
+		LinesAdded:            response.LinesAdded,
+		LinesRemoved:          response.LinesRemoved,
+		FilesChanged:          response.FilesChanged,
+		FileStats:             fileStatOutputs,
+		OnlyLeftFiles:         response.OnlyLeftFiles,
+		OnlyRightFiles:        response.OnlyRightFiles,
+		LeftUncommittedFiles:  response.LeftUncommittedFiles,
+		RightUncommittedFiles: response.RightUncommittedFiles,
+		Files:                 fileOutputs,
+		TotalFiles:            response.TotalFiles,
+		NextCursor:            response.NextCursor,
+	}
+
+	message := fmt.Sprintf(
+		"Sessions '%s' and '%s' differ in %d file(s) (+%d -%d); %d file(s) only touched by '%s', %d only by '%s'",
+		response.LeftSessionID, response.RightSessionID, response.FilesChanged, response.LinesAdded, response.LinesRemoved,
+		len(response.OnlyLeftFiles), response.LeftSessionID, len(response.OnlyRightFiles), response.RightSessionID,
+	)
+	if response.LeftUncommittedFiles > 0 || response.RightUncommittedFiles > 0 {
+		message += fmt.Sprintf("; uncommitted work is not compared (%d and %d file(s))", response.LeftUncommittedFiles, response.RightUncommittedFiles)
+	}
+	if response.NextCursor != "" {
+		message += "; more files available via nextCursor"
+	}
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleReadFile(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args ReadFileArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.ReadFileRequest{
+		SessionID: args.SessionID,
+		Path:      args.Path,
+		MaxBytes:  args.MaxBytes,
+	}
+
+	response, err := s.readFileUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to read file: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := ReadFileOutput(*response)
+
+	message := fmt.Sprintf("Read '%s' (%d bytes) in session '%s'", response.Path, response.SizeBytes, response.SessionID)
+	if response.Truncated {
+		message += "; content truncated, raise maxBytes to read more"
+	}
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleWriteFile(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args WriteFileArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.WriteFileRequest{
+		SessionID: args.SessionID,
+		Path:      args.Path,
+		Content:   args.Content,
+		Encoding:  args.Encoding,
+	}
+
+	response, err := s.writeFileUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to write file: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := WriteFileOutput(*response)
+
+	verb := "Updated"
+	if response.Created {
+		verb = "Created"
+	}
+	message := fmt.Sprintf("%s '%s' (%d bytes) in session '%s'", verb, response.Path, response.SizeBytes, response.SessionID)
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleListDirectory(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args ListDirectoryArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.ListDirectoryRequest{
+		SessionID: args.SessionID,
+		Path:      args.Path,
+	}
+
+	response, err := s.listDirectoryUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to list directory: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	entryOutputs := make([]DirectoryEntryOutput, 0, len(response.Entries))
+	for _, entry := range response.Entries {
+		entryOutputs = append(entryOutputs, DirectoryEntryOutput{
+			Name:       entry.Name,
+			Path:       entry.Path,
+			Type:       entry.Type,
+			SizeBytes:  entry.SizeBytes,
+			ModifiedAt: entry.ModifiedAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+
+	output := ListDirectoryOutput{
+		SessionID: response.SessionID,
+		Path:      response.Path,
+		Entries:   entryOutputs,
+	}
+
+	message := fmt.Sprintf("Found %d entry(ies) in '%s' of session '%s'", len(response.Entries), response.Path, response.SessionID)
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleDeleteFile(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args DeleteFileArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.DeleteFileRequest{
+		SessionID: args.SessionID,
+		Path:      args.Path,
+	}
+
+	response, err := s.deleteFileUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to delete file: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := DeleteFileOutput(*response)
+
+	message := fmt.Sprintf("Deleted '%s' in session '%s'", response.Path, response.SessionID)
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleMoveFile(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args MoveFileArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.MoveFileRequest{
+		SessionID:       args.SessionID,
+		SourcePath:      args.SourcePath,
+		DestinationPath: args.DestinationPath,
+		Overwrite:       args.Overwrite,
+	}
+
+	response, err := s.moveFileUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to move file: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := MoveFileOutput(*response)
+
+	message := fmt.Sprintf("Moved '%s' to '%s' in session '%s'", response.SourcePath, response.DestinationPath, response.SessionID)
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleSearchSession(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args SearchSessionArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.SearchSessionRequest{
+		SessionID:        args.SessionID,
+		Pattern:          args.Pattern,
+		Literal:          args.Literal,
+		IgnoreCase:       args.IgnoreCase,
+		PathGlobs:        args.Paths,
+		IncludeUntracked: args.IncludeUntracked,
+		ContextLines:     args.ContextLines,
+		MaxResults:       args.MaxResults,
+	}
+
+	response, err := s.searchSessionUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to search session: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	matchOutputs := make([]SearchMatchOutput, 0, len(response.Matches))
+	for _, match := range response.Matches {
+		matchOutputs = append(matchOutputs, SearchMatchOutput(match))
+	}
+
+	output := SearchSessionOutput{
+		SessionID: response.SessionID,
+		Matches:   matchOutputs,
+		Truncated: response.Truncated,
+	}
+
+	message := fmt.Sprintf("Found %d match(es) in session '%s'", len(response.Matches), response.SessionID)
+	if response.Truncated {
+		message += "; more matches exist, narrow the search or raise maxResults"
+	}
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleApplyPatch(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args ApplyPatchArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.ApplyPatchRequest{
+		SessionID: args.SessionID,
+		Patch:     args.Patch,
+		Check:     args.Check,
+	}
+
+	response, err := s.applyPatchUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to apply patch: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := ApplyPatchOutput{
+		SessionID:       response.SessionID,
+		Check:           response.Check,
+		Applies:         response.Applies,
+		Applied:         response.Applied,
+		ThreeWay:        response.ThreeWay,
+		Files:           response.Files,
+		Hunks:           make([]PatchHunkOutput, 0, len(response.Hunks)),
+		ConflictedPaths: response.ConflictedPaths,
+		Errors:          response.Errors,
+	}
+	for _, hunk := range response.Hunks {
+		output.Hunks = append(output.Hunks, PatchHunkOutput(hunk))
+	}
+
+	if len(response.ConflictedPaths) > 0 {
+		message := fmt.Sprintf(
+			"CONFLICT: The patch only applies to session '%s' with conflicts; nothing was changed.\n\nConflicting files:\n%s",
+			response.SessionID,
+			strings.Join(response.ConflictedPaths, "\n"),
+		)
+		return &mcpsdk.CallToolResult{
+			Content: []mcpsdk.Content{newTextContent(message)},
+			IsError: false,
+		}, output, nil
+	}
+	if !response.Applies {
+		message := fmt.Sprintf(
+			"The patch does not apply to session '%s'; nothing was changed.\n\n%s",
+			response.SessionID,
+			strings.Join(response.Errors, "\n"),
+		)
+		return &mcpsdk.CallToolResult{
+			Content: []mcpsdk.Content{newTextContent(message)},
+			IsError: false,
+		}, output, nil
+	}
+
+	how := "cleanly"
+	if response.ThreeWay {
+		how = "with a three-way merge"
+	}
+	message := fmt.Sprintf("Applied patch to %d file(s) in session '%s' %s", len(response.Files), response.SessionID, how)
+	if response.Check {
+		message = fmt.Sprintf("Patch applies %s to %d file(s) in session '%s'", how, len(response.Files), response.SessionID)
+	}
+	return newSuccessResult(message), output, nil
+}
+
+func (s *MCPServer) handleExecInSession(
+	ctx context.Context,
+	req *mcpsdk.CallToolRequest,
+	args ExecInSessionArgs,
+) (*mcpsdk.CallToolResult, any, error) {
+	request := application.ExecInSessionRequest{
+		SessionID:      args.SessionID,
+		Command:        args.Command,
+		Args:           args.Args,
+		Env:            args.Env,
+		TimeoutSeconds: args.TimeoutSeconds,
+	}
+
+	response, err := s.execInSessionUseCase.Execute(ctx, request)
+	if err != nil {
+		message := fmt.Sprintf("Failed to run command: %v", err)
+		return newErrorResult(message), nil, err
+	}
+
+	output := ExecInSessionOutput(*response)
+
+	message := fmt.Sprintf("Command '%s' exited with status %d after %d ms", response.Command, response.ExitCode, response.DurationMs)
+	if response.TimedOut {
+		message = fmt.Sprintf("Command '%s' timed out after %d ms and was killed", response.Command, response.DurationMs)
+	}
+	return newSuccessResult(message), output, nil
+}
+
+func buildPullRequestOutput(pullRequest *application.PullRequestDTO) *PullRequestOutput {
+	if pullRequest == nil {
+		return nil
+	}
+	return &PullRequestOutput{Number: pullRequest.Number, URL: pullRequest.URL}
+}
+
+// clientName returns the name the MCP client reported when it connected, or
+// an empty string when it is unknown
+func clientName(req *mcpsdk.CallToolRequest) string {
+	if req == nil || req.Session == nil {
+		return ""
+	}
+	initializeParams := req.Session.InitializeParams()
+	if initializeParams == nil || initializeParams.ClientInfo == nil {
+		return ""
+	}
+	return initializeParams.ClientInfo.Name
+}
+
+func buildCheckpointOutput(checkpoint application.CheckpointDTO) CheckpointOutput {
+	return CheckpointOutput{
+		Name:       checkpoint.Name,
+		CommitSHA:  checkpoint.CommitSHA,
+		HeadCommit: checkpoint.HeadCommit,
+		Message:    checkpoint.Message,
+		CreatedAt:  checkpoint.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func buildCheckpointOutputs(checkpoints []application.CheckpointDTO) []CheckpointOutput {
+	checkpointOutputs := make([]CheckpointOutput, 0, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		checkpointOutputs = append(checkpointOutputs, buildCheckpointOutput(checkpoint))
+	}
+	return checkpointOutputs
+}
+
 func (s *MCPServer) Run(ctx context.Context) error {
 	return s.mcpServer.Run(ctx, &mcpsdk.StdioTransport{})
 }
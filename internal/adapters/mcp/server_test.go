@@ -2,15 +2,23 @@ package mcp
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/tzDel/orchestragent-mcp/internal/application"
 	"github.com/tzDel/orchestragent-mcp/internal/domain"
+	"github.com/tzDel/orchestragent-mcp/internal/infrastructure/forge"
 	"github.com/tzDel/orchestragent-mcp/internal/infrastructure/git"
 	"github.com/tzDel/orchestragent-mcp/internal/infrastructure/persistence"
+	"github.com/tzDel/orchestragent-mcp/internal/infrastructure/process"
+	"github.com/tzDel/orchestragent-mcp/internal/infrastructure/workspace"
 )
 
 func initializeGitRepo(repositoryPath string) error {
@@ -79,11 +87,74 @@ func setupMCPServer(t *testing.T) (*MCPServer, string, *persistence.InMemorySess
 
 	gitClient := git.NewGitClient(repositoryRoot)
 	sessionRepository := persistence.NewInMemorySessionRepository()
-	createWorktreeUseCase := application.NewCreateWorktreeUseCase(gitClient, sessionRepository, repositoryRoot)
+	worktreeFiles := workspace.NewFileSystem()
+	sessionArchives := workspace.NewSessionArchiveStore(filepath.Join(repositoryRoot, ".exports"))
+	commandRunner := process.NewRunner(process.NoSandbox{})
+	gitStatusRule, _ := domain.NewCommandRule("git", []string{"status", "--short"})
+	commandPolicy := &domain.CommandPolicy{
+		Rules:          []domain.CommandRule{gitStatusRule},
+		Timeout:        10 * time.Second,
+		MaxOutputBytes: 4096,
+	}
+	createWorktreeUseCase := application.NewCreateWorktreeUseCase(gitClient, sessionRepository, filepath.Join(repositoryRoot, ".worktrees"), "master")
 	removeSessionUseCase := application.NewRemoveSessionUseCase(gitClient, sessionRepository, "master")
-	getSessionsUseCase := application.NewGetSessionsUseCase(gitClient, sessionRepository, "master")
-
-	server, err := NewMCPServer(createWorktreeUseCase, removeSessionUseCase, getSessionsUseCase)
+	getSessionsUseCase := application.NewGetSessionsUseCase(gitClient, sessionRepository, "master", 50)
+	getSessionDiffUseCase := application.NewGetSessionDiffUseCase(gitClient, sessionRepository, "master")
+	getSessionCommitsUseCase := application.NewGetSessionCommitsUseCase(gitClient, sessionRepository, "master")
+	mergeSessionUseCase := application.NewMergeSessionUseCase(gitClient, sessionRepository, "master")
+	checkMergeUseCase := application.NewCheckMergeUseCase(gitClient, sessionRepository, "master")
+	syncSessionUseCase := application.NewSyncSessionUseCase(gitClient, sessionRepository, "master")
+	getSessionOverlapsUseCase := application.NewGetSessionOverlapsUseCase(gitClient, sessionRepository, "master")
+	createCheckpointUseCase := application.NewCreateCheckpointUseCase(gitClient, sessionRepository)
+	listCheckpointsUseCase := application.NewListCheckpointsUseCase(gitClient, sessionRepository)
+	restoreCheckpointUseCase := application.NewRestoreCheckpointUseCase(gitClient, sessionRepository)
+	commitSessionUseCase := application.NewCommitSessionUseCase(gitClient, sessionRepository, domain.CommitAuthor{})
+	publishSessionUseCase := application.NewPublishSessionUseCase(gitClient, sessionRepository, nil, "origin", "master")
+	exportSessionUseCase := application.NewExportSessionUseCase(gitClient, sessionRepository, sessionArchives, "master")
+	importSessionUseCase := application.NewImportSessionUseCase(gitClient, sessionRepository, sessionArchives, filepath.Join(repositoryRoot, ".worktrees"))
+	forkSessionUseCase := application.NewForkSessionUseCase(gitClient, sessionRepository, filepath.Join(repositoryRoot, ".worktrees"), "master")
+	restackSessionsUseCase := application.NewRestackSessionsUseCase(gitClient, sessionRepository, "master")
+	cherryPickUseCase := application.NewCherryPickUseCase(gitClient, sessionRepository, "master")
+	compareSessionsUseCase := application.NewCompareSessionsUseCase(gitClient, sessionRepository, "master")
+	readFileUseCase := application.NewReadFileUseCase(worktreeFiles, sessionRepository)
+	writeFileUseCase := application.NewWriteFileUseCase(worktreeFiles, sessionRepository)
+	listDirectoryUseCase := application.NewListDirectoryUseCase(worktreeFiles, sessionRepository)
+	deleteFileUseCase := application.NewDeleteFileUseCase(worktreeFiles, sessionRepository)
+	moveFileUseCase := application.NewMoveFileUseCase(worktreeFiles, sessionRepository)
+	searchSessionUseCase := application.NewSearchSessionUseCase(gitClient, sessionRepository)
+	applyPatchUseCase := application.NewApplyPatchUseCase(gitClient, sessionRepository)
+	execInSessionUseCase := application.NewExecInSessionUseCase(commandRunner, sessionRepository, commandPolicy)
+
+	server, err := NewMCPServer(UseCases{
+		CreateWorktree:     createWorktreeUseCase,
+		RemoveSession:      removeSessionUseCase,
+		GetSessions:        getSessionsUseCase,
+		GetSessionDiff:     getSessionDiffUseCase,
+		GetSessionCommits:  getSessionCommitsUseCase,
+		MergeSession:       mergeSessionUseCase,
+		CheckMerge:         checkMergeUseCase,
+		SyncSession:        syncSessionUseCase,
+		GetSessionOverlaps: getSessionOverlapsUseCase,
+		CreateCheckpoint:   createCheckpointUseCase,
+		ListCheckpoints:    listCheckpointsUseCase,
+		RestoreCheckpoint:  restoreCheckpointUseCase,
+		CommitSession:      commitSessionUseCase,
+		PublishSession:     publishSessionUseCase,
+		ExportSession:      exportSessionUseCase,
+		ImportSession:      importSessionUseCase,
+		ForkSession:        forkSessionUseCase,
+		RestackSessions:    restackSessionsUseCase,
+		CherryPick:         cherryPickUseCase,
+		CompareSessions:    compareSessionsUseCase,
+		ReadFile:           readFileUseCase,
+		WriteFile:          writeFileUseCase,
+		ListDirectory:      listDirectoryUseCase,
+		DeleteFile:         deleteFileUseCase,
+		MoveFile:           moveFileUseCase,
+		SearchSession:      searchSessionUseCase,
+		ApplyPatch:         applyPatchUseCase,
+		ExecInSession:      execInSessionUseCase,
+	})
 	if err != nil {
 		t.Fatalf("failed to create MCP server: %v", err)
 	}
@@ -150,6 +221,43 @@ func TestCreateWorktreeToolHandler_ValidInput_ReturnsSuccess(t *testing.T) {
 	}
 }
 
+func TestCreateWorktreeToolHandler_WithBaseRef_ReturnsBaseRef(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	tagCommand := exec.Command("git", "tag", "v1.0.0")
+	tagCommand.Dir = repositoryRoot
+	if err := tagCommand.Run(); err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+
+	ctx := context.Background()
+	args := CreateWorktreeArgs{
+		SessionID: "hotfix",
+		BaseRef:   "v1.0.0",
+	}
+
+	// act
+	result, output, err := server.handleCreateWorktree(ctx, nil, args)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+
+	response, ok := output.(CreateWorktreeOutput)
+	if !ok {
+		t.Fatalf("expected output to be CreateWorktreeOutput, got: %T", output)
+	}
+	if response.BaseRef != "v1.0.0" {
+		t.Errorf("expected base ref 'v1.0.0', got: %s", response.BaseRef)
+	}
+}
+
 func TestCreateWorktreeToolHandler_InvalidSessionID_ReturnsError(t *testing.T) {
 	// arrange
 	server, _, _, cleanup := setupMCPServer(t)
@@ -183,7 +291,7 @@ func TestCreateWorktreeToolHandler_DuplicateSession_ReturnsError(t *testing.T) {
 	}
 
 	sessionID, _ := domain.NewSessionID("copilot")
-	session, _ := domain.NewSession(sessionID, filepath.Join(repositoryRoot, ".worktrees", "copilot"))
+	session, _ := domain.NewSession(sessionID, filepath.Join(repositoryRoot, ".worktrees", "copilot"), "")
 	_ = sessionRepository.Save(ctx, session)
 
 	// act
@@ -378,3 +486,1087 @@ func TestRemoveSessionToolHandler_NonexistentSession_ReturnsError(t *testing.T)
 		t.Error("expected IsError to be true")
 	}
 }
+
+func TestGetSessionDiffToolHandler_WithChanges_ReturnsFileDiffs(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-test-session")
+	os.WriteFile(filepath.Join(worktreePath, "README.md"), []byte("# Changed"), 0644)
+	os.WriteFile(filepath.Join(worktreePath, "notes.txt"), []byte("untracked"), 0644)
+
+	args := GetSessionDiffArgs{SessionID: "test-session", Paths: []string{"*.md"}}
+
+	// act
+	result, output, err := server.handleGetSessionDiff(ctx, nil, args)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+
+	response, ok := output.(GetSessionDiffOutput)
+	if !ok {
+		t.Fatalf("expected output to be GetSessionDiffOutput, got: %T", output)
+	}
+	if response.TotalFiles != 1 {
+		t.Fatalf("expected 1 changed file, got %d", response.TotalFiles)
+	}
+	if response.Files[0].Path != "README.md" {
+		t.Errorf("expected path 'README.md', got: %s", response.Files[0].Path)
+	}
+	if response.NextCursor != "" {
+		t.Errorf("expected no next cursor, got: %s", response.NextCursor)
+	}
+}
+
+func TestGetSessionDiffToolHandler_NonexistentSession_ReturnsError(t *testing.T) {
+	// arrange
+	server, _, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	args := GetSessionDiffArgs{SessionID: "nonexistent"}
+
+	// act
+	result, _, err := server.handleGetSessionDiff(ctx, nil, args)
+
+	// assert
+	if err == nil {
+		t.Fatal("expected error for non-existent session")
+	}
+	if result != nil && !result.IsError {
+		t.Error("expected IsError to be true")
+	}
+}
+
+func TestGetSessionsToolHandler_CountsUntrackedFiles(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-test-session")
+	os.WriteFile(filepath.Join(worktreePath, "new-file.txt"), []byte("one\ntwo\n"), 0644)
+
+	// act
+	result, output, err := server.handleGetSessions(ctx, nil, GetSessionsArgs{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+
+	response, ok := output.(GetSessionsOutput)
+	if !ok {
+		t.Fatalf("expected output to be GetSessionsOutput, got: %T", output)
+	}
+	session := response.Sessions[0]
+	if session.LinesAdded != 2 {
+		t.Errorf("expected LinesAdded = 2, got %d", session.LinesAdded)
+	}
+	if session.Breakdown.Untracked.LinesAdded != 2 {
+		t.Errorf("expected untracked LinesAdded = 2, got %d", session.Breakdown.Untracked.LinesAdded)
+	}
+}
+
+func TestGetSessionsToolHandler_BehindMoreThan_FiltersByDivergence(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+	if err := createAndCommitFile(repositoryRoot, "upstream.txt", "upstream\n"); err != nil {
+		t.Fatalf("failed to commit on base: %v", err)
+	}
+
+	// act
+	behindMoreThan := 0
+	_, behindOutput, behindErr := server.handleGetSessions(ctx, nil, GetSessionsArgs{BehindMoreThan: &behindMoreThan})
+	_, staleOutput, staleErr := server.handleGetSessions(ctx, nil, GetSessionsArgs{StaleOnly: true})
+
+	// assert
+	if behindErr != nil || staleErr != nil {
+		t.Fatalf("expected no errors, got: %v, %v", behindErr, staleErr)
+	}
+	behindSessions := behindOutput.(GetSessionsOutput).Sessions
+	if len(behindSessions) != 1 {
+		t.Fatalf("expected 1 session behind the base, got %d", len(behindSessions))
+	}
+	if behindSessions[0].Behind != 1 || behindSessions[0].Ahead != 0 {
+		t.Errorf("expected ahead 0 behind 1, got ahead %d behind %d", behindSessions[0].Ahead, behindSessions[0].Behind)
+	}
+	if behindSessions[0].LastCommitAt == "" {
+		t.Error("expected LastCommitAt to be set")
+	}
+	if staleSessions := staleOutput.(GetSessionsOutput).Sessions; len(staleSessions) != 0 {
+		t.Errorf("expected no stale sessions below the threshold, got %d", len(staleSessions))
+	}
+}
+
+func TestGetSessionCommitsToolHandler_WithCommits_ReturnsCommitLog(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-test-session")
+	if err := createAndCommitFile(worktreePath, "feature.txt", "feature\n"); err != nil {
+		t.Fatalf("failed to commit in worktree: %v", err)
+	}
+
+	// act
+	result, output, err := server.handleGetSessionCommits(ctx, nil, GetSessionCommitsArgs{SessionID: "test-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+
+	response, ok := output.(GetSessionCommitsOutput)
+	if !ok {
+		t.Fatalf("expected output to be GetSessionCommitsOutput, got: %T", output)
+	}
+	if len(response.Commits) != 1 {
+		t.Fatalf("expected 1 commit, got %d", len(response.Commits))
+	}
+	if response.Commits[0].Files[0].Path != "feature.txt" {
+		t.Errorf("expected commit to touch 'feature.txt', got: %+v", response.Commits[0].Files)
+	}
+	if response.BaseRef != "master" {
+		t.Errorf("expected base ref 'master', got: %s", response.BaseRef)
+	}
+}
+
+func TestMergeSessionToolHandler_SquashAndRemove_MergesIntoBase(t *testing.T) {
+	// arrange
+	server, repositoryRoot, sessionRepository, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-test-session")
+	if err := createAndCommitFile(worktreePath, "feature.txt", "feature\n"); err != nil {
+		t.Fatalf("failed to commit in worktree: %v", err)
+	}
+
+	args := MergeSessionArgs{SessionID: "test-session", Strategy: "squash", RemoveAfterMerge: true}
+
+	// act
+	result, output, err := server.handleMergeSession(ctx, nil, args)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+
+	response, ok := output.(MergeSessionOutput)
+	if !ok {
+		t.Fatalf("expected output to be MergeSessionOutput, got: %T", output)
+	}
+	if !response.Merged || !response.Removed {
+		t.Errorf("expected merged and removed, got: %+v", response)
+	}
+	if _, err := os.Stat(filepath.Join(repositoryRoot, "feature.txt")); err != nil {
+		t.Errorf("expected feature.txt on the base checkout: %v", err)
+	}
+	if _, err := os.Stat(worktreePath); !os.IsNotExist(err) {
+		t.Error("expected worktree directory to be removed")
+	}
+	sessionID, _ := domain.NewSessionID("test-session")
+	if exists, _ := sessionRepository.Exists(ctx, sessionID); exists {
+		t.Error("expected session record to be deleted")
+	}
+}
+
+func TestMergeSessionToolHandler_Conflict_ReturnsConflictedPaths(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-test-session")
+	if err := createAndCommitFile(worktreePath, "README.md", "# Session"); err != nil {
+		t.Fatalf("failed to commit in worktree: %v", err)
+	}
+	if err := createAndCommitFile(repositoryRoot, "README.md", "# Upstream"); err != nil {
+		t.Fatalf("failed to commit on base: %v", err)
+	}
+
+	// act
+	result, output, err := server.handleMergeSession(ctx, nil, MergeSessionArgs{SessionID: "test-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false for a reported conflict")
+	}
+
+	response, ok := output.(MergeSessionOutput)
+	if !ok {
+		t.Fatalf("expected output to be MergeSessionOutput, got: %T", output)
+	}
+	if response.Merged {
+		t.Error("expected Merged to be false")
+	}
+	if len(response.ConflictedPaths) != 1 || response.ConflictedPaths[0] != "README.md" {
+		t.Errorf("expected conflict in README.md, got: %v", response.ConflictedPaths)
+	}
+	if response.Status != "open" {
+		t.Errorf("expected status 'open', got: %s", response.Status)
+	}
+}
+
+func TestCheckMergeToolHandler_Conflict_ReportsPathsWithoutMerging(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-test-session")
+	if err := createAndCommitFile(worktreePath, "README.md", "# Session"); err != nil {
+		t.Fatalf("failed to commit in worktree: %v", err)
+	}
+	if err := createAndCommitFile(repositoryRoot, "README.md", "# Upstream"); err != nil {
+		t.Fatalf("failed to commit on base: %v", err)
+	}
+
+	// act
+	result, output, err := server.handleCheckMerge(ctx, nil, CheckMergeArgs{SessionID: "test-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+
+	response, ok := output.(CheckMergeOutput)
+	if !ok {
+		t.Fatalf("expected output to be CheckMergeOutput, got: %T", output)
+	}
+	if response.Clean {
+		t.Error("expected Clean to be false")
+	}
+	if len(response.Conflicts) != 1 || response.Conflicts[0].Path != "README.md" {
+		t.Errorf("expected conflict in README.md, got: %+v", response.Conflicts)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(repositoryRoot, "README.md"))
+	if string(content) != "# Upstream" {
+		t.Errorf("expected base checkout to be untouched, got: %q", content)
+	}
+}
+
+func TestSyncSessionToolHandler_Rebase_RecordsNewBaseCommit(t *testing.T) {
+	// arrange
+	server, repositoryRoot, sessionRepository, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-test-session")
+	if err := createAndCommitFile(worktreePath, "feature.txt", "feature\n"); err != nil {
+		t.Fatalf("failed to commit in worktree: %v", err)
+	}
+	if err := createAndCommitFile(repositoryRoot, "upstream.txt", "upstream\n"); err != nil {
+		t.Fatalf("failed to commit on base: %v", err)
+	}
+
+	// act
+	result, output, err := server.handleSyncSession(ctx, nil, SyncSessionArgs{SessionID: "test-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+
+	response, ok := output.(SyncSessionOutput)
+	if !ok {
+		t.Fatalf("expected output to be SyncSessionOutput, got: %T", output)
+	}
+	if !response.Synced {
+		t.Fatalf("expected Synced to be true, got: %+v", response)
+	}
+	if response.BaseCommit == response.PreviousBaseCommit {
+		t.Error("expected base commit to move to the new base tip")
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "upstream.txt")); err != nil {
+		t.Errorf("expected upstream.txt in the session worktree: %v", err)
+	}
+
+	sessionID, _ := domain.NewSessionID("test-session")
+	session, _ := sessionRepository.FindByID(ctx, sessionID)
+	if session.BaseCommit() != response.BaseCommit {
+		t.Errorf("expected stored base commit %s, got: %s", response.BaseCommit, session.BaseCommit())
+	}
+}
+
+func TestSyncSessionToolHandler_UncommittedChanges_ReturnsError(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-test-session")
+	if err := os.WriteFile(filepath.Join(worktreePath, "scratch.txt"), []byte("wip\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	// act
+	result, _, err := server.handleSyncSession(ctx, nil, SyncSessionArgs{SessionID: "test-session", Strategy: "merge"})
+
+	// assert
+	if err == nil {
+		t.Error("expected error for uncommitted changes")
+	}
+	if !result.IsError {
+		t.Error("expected IsError to be true")
+	}
+}
+
+func TestGetSessionOverlapsToolHandler_TrialMerge_ReportsConflictingPair(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, sessionID := range []string{"session-a", "session-b", "session-c"} {
+		createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: sessionID})
+		if createResult.IsError {
+			t.Fatalf("failed to create worktree: %v", createResult.Content)
+		}
+	}
+
+	worktreesDirectory := filepath.Join(repositoryRoot, ".worktrees")
+	if err := createAndCommitFile(filepath.Join(worktreesDirectory, "orchestragent-session-a"), "README.md", "# From A"); err != nil {
+		t.Fatalf("failed to commit in worktree: %v", err)
+	}
+	if err := createAndCommitFile(filepath.Join(worktreesDirectory, "orchestragent-session-b"), "README.md", "# From B"); err != nil {
+		t.Fatalf("failed to commit in worktree: %v", err)
+	}
+	if err := createAndCommitFile(filepath.Join(worktreesDirectory, "orchestragent-session-c"), "other.txt", "other\n"); err != nil {
+		t.Fatalf("failed to commit in worktree: %v", err)
+	}
+
+	// act
+	result, output, err := server.handleGetSessionOverlaps(ctx, nil, GetSessionOverlapsArgs{TrialMerge: true})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+
+	response, ok := output.(GetSessionOverlapsOutput)
+	if !ok {
+		t.Fatalf("expected output to be GetSessionOverlapsOutput, got: %T", output)
+	}
+	if len(response.Overlaps) != 1 {
+		t.Fatalf("expected 1 overlapping pair, got: %+v", response.Overlaps)
+	}
+	overlap := response.Overlaps[0]
+	if overlap.SessionA != "session-a" || overlap.SessionB != "session-b" {
+		t.Errorf("expected session-a/session-b to overlap, got: %s/%s", overlap.SessionA, overlap.SessionB)
+	}
+	if overlap.Conflicting == nil || !*overlap.Conflicting {
+		t.Error("expected the pair to conflict")
+	}
+	if len(overlap.ConflictedPaths) != 1 || overlap.ConflictedPaths[0] != "README.md" {
+		t.Errorf("expected conflict in README.md, got: %v", overlap.ConflictedPaths)
+	}
+}
+
+func TestRestoreCheckpointToolHandler_AfterChanges_RestoresWorktree(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-test-session")
+	if err := os.WriteFile(filepath.Join(worktreePath, "draft.txt"), []byte("draft\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	checkpointResult, checkpointOutput, _ := server.handleCreateCheckpoint(ctx, nil, CreateCheckpointArgs{SessionID: "test-session", Name: "draft"})
+	if checkpointResult.IsError {
+		t.Fatalf("failed to create checkpoint: %v", checkpointResult.Content)
+	}
+	if checkpoint := checkpointOutput.(CreateCheckpointOutput).Checkpoint; checkpoint.Name != "draft" {
+		t.Fatalf("expected checkpoint named draft, got: %+v", checkpoint)
+	}
+
+	if err := createAndCommitFile(worktreePath, "draft.txt", "rewritten\n"); err != nil {
+		t.Fatalf("failed to commit in worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(worktreePath, "junk.txt"), []byte("junk\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	// act
+	result, output, err := server.handleRestoreCheckpoint(ctx, nil, RestoreCheckpointArgs{SessionID: "test-session", Name: "draft"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+	restoreOutput, ok := output.(RestoreCheckpointOutput)
+	if !ok {
+		t.Fatalf("expected output to be RestoreCheckpointOutput, got: %T", output)
+	}
+	if restoreOutput.Backup.Name != "before-restore-1" {
+		t.Errorf("expected backup before-restore-1, got: %+v", restoreOutput.Backup)
+	}
+	if content, _ := os.ReadFile(filepath.Join(worktreePath, "draft.txt")); string(content) != "draft\n" {
+		t.Errorf("expected draft.txt to be restored, got: %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "junk.txt")); !os.IsNotExist(err) {
+		t.Error("expected junk.txt created after the checkpoint to be removed")
+	}
+
+	_, sessionsOutput, _ := server.handleGetSessions(ctx, nil, GetSessionsArgs{})
+	sessions := sessionsOutput.(GetSessionsOutput).Sessions
+	if len(sessions) != 1 || len(sessions[0].Checkpoints) != 2 {
+		t.Errorf("expected the checkpoint and its backup in session metadata, got: %+v", sessions)
+	}
+}
+
+func TestCommitSessionToolHandler_WithChanges_CommitsWithTrailers(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-test-session")
+	if err := os.WriteFile(filepath.Join(worktreePath, "feature.txt"), []byte("one\ntwo\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	// act
+	result, output, err := server.handleCommitSession(ctx, nil, CommitSessionArgs{SessionID: "test-session", Message: "Add feature", Agent: "tester"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+
+	response, ok := output.(CommitSessionOutput)
+	if !ok {
+		t.Fatalf("expected output to be CommitSessionOutput, got: %T", output)
+	}
+	if response.Commit.SHA == "" || response.Commit.LinesAdded != 2 {
+		t.Errorf("expected a commit adding 2 lines, got: %+v", response.Commit)
+	}
+	trailers := map[string]string{}
+	for _, trailer := range response.Commit.Trailers {
+		trailers[trailer.Key] = trailer.Value
+	}
+	if trailers["Session-Id"] != "test-session" || trailers["Agent"] != "tester" {
+		t.Errorf("expected Session-Id and Agent trailers, got: %+v", response.Commit.Trailers)
+	}
+
+	emptyResult, _, emptyErr := server.handleCommitSession(ctx, nil, CommitSessionArgs{SessionID: "test-session", Message: "Nothing"})
+	if emptyErr == nil || !emptyResult.IsError {
+		t.Error("expected an error when there is nothing to commit")
+	}
+}
+
+func TestPublishSessionToolHandler_WithForge_PushesAndRecordsPullRequest(t *testing.T) {
+	// arrange
+	server, repositoryRoot, sessionRepository, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	remotePath := filepath.Join(t.TempDir(), "remote.git")
+	if output, err := exec.Command("git", "init", "--bare", remotePath).CombinedOutput(); err != nil {
+		t.Fatalf("failed to create remote: %v (%s)", err, output)
+	}
+	addRemoteCommand := exec.Command("git", "remote", "add", "origin", remotePath)
+	addRemoteCommand.Dir = repositoryRoot
+	if err := addRemoteCommand.Run(); err != nil {
+		t.Fatalf("failed to add remote: %v", err)
+	}
+
+	forgeServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusCreated)
+		writer.Write([]byte(`{"number": 9, "html_url": "https://forge.example.com/team/app/pull/9"}`))
+	}))
+	defer forgeServer.Close()
+
+	forgeClient, err := forge.NewClient(forgeServer.URL, "team/app", "token", forgeServer.Client())
+	if err != nil {
+		t.Fatalf("failed to create forge client: %v", err)
+	}
+	server.publishSessionUseCase = application.NewPublishSessionUseCase(git.NewGitClient(repositoryRoot), sessionRepository, forgeClient, "origin", "master")
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-test-session")
+	if err := createAndCommitFile(worktreePath, "feature.txt", "feature\n"); err != nil {
+		t.Fatalf("failed to commit in worktree: %v", err)
+	}
+
+	// act
+	result, output, err := server.handlePublishSession(ctx, nil, PublishSessionArgs{SessionID: "test-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+
+	response, ok := output.(PublishSessionOutput)
+	if !ok {
+		t.Fatalf("expected output to be PublishSessionOutput, got: %T", output)
+	}
+	if !response.PullRequestCreated || response.PullRequest == nil || response.PullRequest.Number != 9 {
+		t.Errorf("expected pull request 9 to be created, got: %+v", response)
+	}
+
+	remoteHeadCommand := exec.Command("git", "rev-parse", "orchestragent-test-session")
+	remoteHeadCommand.Dir = remotePath
+	remoteHead, err := remoteHeadCommand.Output()
+	if err != nil || string(remoteHead[:len(remoteHead)-1]) != response.HeadCommit {
+		t.Errorf("expected remote branch at %s, got: %s (%v)", response.HeadCommit, remoteHead, err)
+	}
+
+	sessionID, _ := domain.NewSessionID("test-session")
+	session, _ := sessionRepository.FindByID(ctx, sessionID)
+	if session.PullRequest().URL != "https://forge.example.com/team/app/pull/9" {
+		t.Errorf("expected pull request URL on the session, got: %+v", session.PullRequest())
+	}
+}
+
+func TestImportSessionToolHandler_ExportedBundle_RecreatesSession(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-test-session")
+	if err := createAndCommitFile(worktreePath, "feature.txt", "feature\n"); err != nil {
+		t.Fatalf("failed to commit in worktree: %v", err)
+	}
+
+	exportPath := "handoff/test-session"
+	exportResult, exportOutput, _ := server.handleExportSession(ctx, nil, ExportSessionArgs{SessionID: "test-session", OutputPath: exportPath})
+	if exportResult.IsError {
+		t.Fatalf("failed to export session: %v", exportResult.Content)
+	}
+	exported := exportOutput.(ExportSessionOutput)
+	if exported.Format != "bundle" || exported.Commits != 1 {
+		t.Fatalf("expected a one-commit bundle export, got: %+v", exported)
+	}
+	if exported.OutputPath != filepath.Join(repositoryRoot, ".exports", "handoff", "test-session") {
+		t.Fatalf("expected the export inside the export directory, got: %s", exported.OutputPath)
+	}
+
+	// act
+	result, output, err := server.handleImportSession(ctx, nil, ImportSessionArgs{InputPath: exportPath, SessionID: "imported-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+	imported, ok := output.(ImportSessionOutput)
+	if !ok {
+		t.Fatalf("expected output to be ImportSessionOutput, got: %T", output)
+	}
+	if imported.HeadCommit != exported.HeadCommit {
+		t.Errorf("expected head commit %s, got: %s", exported.HeadCommit, imported.HeadCommit)
+	}
+	if content, _ := os.ReadFile(filepath.Join(imported.WorktreePath, "feature.txt")); string(content) != "feature\n" {
+		t.Errorf("expected feature.txt in the imported worktree, got: %q", content)
+	}
+
+	_, sessionsOutput, _ := server.handleGetSessions(ctx, nil, GetSessionsArgs{})
+	if sessions := sessionsOutput.(GetSessionsOutput).Sessions; len(sessions) != 2 {
+		t.Errorf("expected both sessions to be listed, got: %+v", sessions)
+	}
+}
+
+func TestForkSessionToolHandler_IncludeUncommitted_CopiesParentWork(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "parent-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+
+	parentPath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-parent-session")
+	if err := createAndCommitFile(parentPath, "committed.txt", "committed\n"); err != nil {
+		t.Fatalf("failed to commit in worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(parentPath, "draft.txt"), []byte("draft\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	// act
+	result, output, err := server.handleForkSession(ctx, nil, ForkSessionArgs{SessionID: "child-session", ParentSessionID: "parent-session", IncludeUncommitted: true})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+	forkOutput, ok := output.(ForkSessionOutput)
+	if !ok {
+		t.Fatalf("expected output to be ForkSessionOutput, got: %T", output)
+	}
+	for _, name := range []string{"committed.txt", "draft.txt"} {
+		if _, err := os.Stat(filepath.Join(forkOutput.WorktreePath, name)); err != nil {
+			t.Errorf("expected %s in the forked worktree: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(parentPath, "draft.txt")); err != nil {
+		t.Errorf("expected the parent worktree to keep draft.txt: %v", err)
+	}
+
+	_, sessionsOutput, _ := server.handleGetSessions(ctx, nil, GetSessionsArgs{})
+	for _, session := range sessionsOutput.(GetSessionsOutput).Sessions {
+		if session.SessionID == "child-session" && session.ParentSessionID != "parent-session" {
+			t.Errorf("expected child-session to list parent-session as parent, got: %q", session.ParentSessionID)
+		}
+	}
+}
+
+func TestRestackSessionsToolHandler_ParentMovedOn_RebasesStackedSession(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "parent-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+	parentPath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-parent-session")
+	if err := createAndCommitFile(parentPath, "first.txt", "first\n"); err != nil {
+		t.Fatalf("failed to commit in parent worktree: %v", err)
+	}
+
+	stackResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "child-session", ParentSessionID: "parent-session"})
+	if stackResult.IsError {
+		t.Fatalf("failed to create stacked worktree: %v", stackResult.Content)
+	}
+	childPath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-child-session")
+	if err := createAndCommitFile(childPath, "child.txt", "child\n"); err != nil {
+		t.Fatalf("failed to commit in child worktree: %v", err)
+	}
+	if err := createAndCommitFile(parentPath, "second.txt", "second\n"); err != nil {
+		t.Fatalf("failed to commit in parent worktree: %v", err)
+	}
+
+	removeResult, _, _ := server.handleRemoveSession(ctx, nil, RemoveSessionArgs{SessionID: "parent-session"})
+	if !removeResult.IsError {
+		t.Fatal("expected removing a session with a stacked child to fail")
+	}
+
+	// act
+	result, output, err := server.handleRestackSessions(ctx, nil, RestackSessionsArgs{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+	restackOutput, ok := output.(RestackSessionsOutput)
+	if !ok {
+		t.Fatalf("expected output to be RestackSessionsOutput, got: %T", output)
+	}
+	if len(restackOutput.Sessions) != 1 || !restackOutput.Sessions[0].Restacked {
+		t.Fatalf("expected child-session to be restacked, got: %+v", restackOutput.Sessions)
+	}
+	for _, name := range []string{"second.txt", "child.txt"} {
+		if _, err := os.Stat(filepath.Join(childPath, name)); err != nil {
+			t.Errorf("expected %s in the restacked worktree: %v", name, err)
+		}
+	}
+
+	_, sessionsOutput, _ := server.handleGetSessions(ctx, nil, GetSessionsArgs{})
+	if tree := sessionsOutput.(GetSessionsOutput).StackTree; tree != "parent-session (on master)\n└── child-session" {
+		t.Errorf("unexpected stack tree: %q", tree)
+	}
+}
+
+func TestCherryPickToolHandler_AllCommits_AppliesToTargetSession(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, sessionID := range []string{"source-session", "target-session"} {
+		createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: sessionID})
+		if createResult.IsError {
+			t.Fatalf("failed to create worktree: %v", createResult.Content)
+		}
+	}
+	sourcePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-source-session")
+	targetPath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-target-session")
+	if err := createAndCommitFile(sourcePath, "helper.go", "package helper\n"); err != nil {
+		t.Fatalf("failed to commit in source worktree: %v", err)
+	}
+
+	// act
+	result, output, err := server.handleCherryPick(ctx, nil, CherryPickArgs{SourceSessionID: "source-session", TargetSessionID: "target-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+	cherryPickOutput, ok := output.(CherryPickOutput)
+	if !ok {
+		t.Fatalf("expected output to be CherryPickOutput, got: %T", output)
+	}
+	if !cherryPickOutput.Applied || len(cherryPickOutput.PickedCommits) != 1 {
+		t.Errorf("expected one applied commit, got: %+v", cherryPickOutput)
+	}
+	if content, _ := os.ReadFile(filepath.Join(targetPath, "helper.go")); string(content) != "package helper\n" {
+		t.Errorf("expected helper.go in the target worktree, got: %q", content)
+	}
+}
+
+func TestCompareSessionsToolHandler_TwoSessions_ReturnsDiffAndOneSidedFiles(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, sessionID := range []string{"left-session", "right-session"} {
+		createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: sessionID})
+		if createResult.IsError {
+			t.Fatalf("failed to create worktree: %v", createResult.Content)
+		}
+	}
+	leftPath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-left-session")
+	rightPath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-right-session")
+	if err := createAndCommitFile(leftPath, "solution.go", "package left\n"); err != nil {
+		t.Fatalf("failed to commit in left worktree: %v", err)
+	}
+	if err := createAndCommitFile(rightPath, "solution.go", "package right\n"); err != nil {
+		t.Fatalf("failed to commit in right worktree: %v", err)
+	}
+	if err := createAndCommitFile(rightPath, "extra.go", "package right\n"); err != nil {
+		t.Fatalf("failed to commit in right worktree: %v", err)
+	}
+
+	// act
+	result, output, err := server.handleCompareSessions(ctx, nil, CompareSessionsArgs{LeftSessionID: "left-session", RightSessionID: "right-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+	compareOutput, ok := output.(CompareSessionsOutput)
+	if !ok {
+		t.Fatalf("expected output to be CompareSessionsOutput, got: %T", output)
+	}
+	if compareOutput.FilesChanged != 2 || compareOutput.TotalFiles != 2 {
+		t.Errorf("expected two differing files, got: %+v", compareOutput.FileStats)
+	}
+	if len(compareOutput.OnlyLeftFiles) != 0 || len(compareOutput.OnlyRightFiles) != 1 || compareOutput.OnlyRightFiles[0] != "extra.go" {
+		t.Errorf("expected only extra.go to be one-sided, got left %v right %v", compareOutput.OnlyLeftFiles, compareOutput.OnlyRightFiles)
+	}
+	for _, file := range compareOutput.Files {
+		if file.Path == "solution.go" && !strings.Contains(file.Diff, "-package left\n+package right\n") {
+			t.Errorf("unexpected solution.go diff: %q", file.Diff)
+		}
+	}
+}
+
+func TestFileToolHandlers_SessionWorktree_WriteReadMoveDelete(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-test-session")
+
+	// act
+	writeResult, _, writeErr := server.handleWriteFile(ctx, nil, WriteFileArgs{SessionID: "test-session", Path: "pkg/new.go", Content: "package pkg\n"})
+	_, readOutput, readErr := server.handleReadFile(ctx, nil, ReadFileArgs{SessionID: "test-session", Path: "pkg/new.go"})
+	_, moveOutput, moveErr := server.handleMoveFile(ctx, nil, MoveFileArgs{SessionID: "test-session", SourcePath: "pkg/new.go", DestinationPath: "pkg/renamed.go"})
+	_, listOutput, listErr := server.handleListDirectory(ctx, nil, ListDirectoryArgs{SessionID: "test-session", Path: "pkg"})
+	_, _, deleteErr := server.handleDeleteFile(ctx, nil, DeleteFileArgs{SessionID: "test-session", Path: "pkg/renamed.go"})
+
+	// assert
+	for name, err := range map[string]error{"write": writeErr, "read": readErr, "move": moveErr, "list": listErr, "delete": deleteErr} {
+		if err != nil {
+			t.Fatalf("%s: expected no error, got: %v", name, err)
+		}
+	}
+	if writeResult.IsError {
+		t.Error("expected IsError to be false")
+	}
+	if content := readOutput.(ReadFileOutput).Content; content != "package pkg\n" {
+		t.Errorf("expected written content to be read back, got: %q", content)
+	}
+	if moveOutput.(MoveFileOutput).DestinationPath != "pkg/renamed.go" {
+		t.Errorf("unexpected move output: %+v", moveOutput)
+	}
+	if entries := listOutput.(ListDirectoryOutput).Entries; len(entries) != 1 || entries[0].Path != "pkg/renamed.go" {
+		t.Errorf("expected only pkg/renamed.go to be listed, got: %+v", entries)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "pkg", "renamed.go")); !os.IsNotExist(err) {
+		t.Error("expected pkg/renamed.go to be deleted")
+	}
+}
+
+func TestFileToolHandlers_EscapingOrGitPaths_AreRejected(t *testing.T) {
+	// arrange
+	server, _, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+
+	// act
+	readResult, _, readErr := server.handleReadFile(ctx, nil, ReadFileArgs{SessionID: "test-session", Path: "../../README.md"})
+	writeResult, _, writeErr := server.handleWriteFile(ctx, nil, WriteFileArgs{SessionID: "test-session", Path: ".git", Content: "gitdir: /tmp\n"})
+
+	// assert
+	if !errors.Is(readErr, domain.ErrPathOutsideWorktree) || !readResult.IsError {
+		t.Errorf("expected reading outside the worktree to fail, got: %v", readErr)
+	}
+	if !errors.Is(writeErr, domain.ErrProtectedPath) || !writeResult.IsError {
+		t.Errorf("expected writing .git to fail, got: %v", writeErr)
+	}
+}
+
+func TestSearchSessionToolHandler_Pattern_FindsTrackedAndUntrackedFiles(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-test-session")
+	if err := createAndCommitFile(worktreePath, "tracked.go", "package tracked\n\nfunc Handle() {}\n"); err != nil {
+		t.Fatalf("failed to commit in worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(worktreePath, "untracked.go"), []byte("package tracked\n\nfunc HandleMore() {}\n"), 0o644); err != nil {
+		t.Fatalf("failed to write untracked file: %v", err)
+	}
+	contextLines := 1
+
+	// act
+	result, output, err := server.handleSearchSession(ctx, nil, SearchSessionArgs{SessionID: "test-session", Pattern: "func Handle[A-Za-z]*", IncludeUntracked: true, ContextLines: &contextLines})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+	searchOutput, ok := output.(SearchSessionOutput)
+	if !ok {
+		t.Fatalf("expected output to be SearchSessionOutput, got: %T", output)
+	}
+	if len(searchOutput.Matches) != 2 || searchOutput.Matches[0].Path != "tracked.go" || searchOutput.Matches[1].Path != "untracked.go" {
+		t.Fatalf("expected matches in tracked.go and untracked.go, got: %+v", searchOutput.Matches)
+	}
+	if match := searchOutput.Matches[0]; match.LineNumber != 3 || len(match.ContextBefore) != 1 || match.ContextBefore[0] != "" {
+		t.Errorf("unexpected tracked.go match: %+v", match)
+	}
+}
+
+func TestApplyPatchToolHandler_CheckThenApply_ChangesWorktreeOnce(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-test-session")
+	if err := createAndCommitFile(worktreePath, "notes.txt", "one\ntwo\nthree\n"); err != nil {
+		t.Fatalf("failed to commit in worktree: %v", err)
+	}
+	patch := "--- a/notes.txt\n+++ b/notes.txt\n@@ -1,3 +1,3 @@\n one\n-two\n+TWO\n three\n"
+
+	// act
+	checkResult, checkOutput, checkErr := server.handleApplyPatch(ctx, nil, ApplyPatchArgs{SessionID: "test-session", Patch: patch, Check: true})
+	checkedContent, _ := os.ReadFile(filepath.Join(worktreePath, "notes.txt"))
+	applyResult, applyOutput, applyErr := server.handleApplyPatch(ctx, nil, ApplyPatchArgs{SessionID: "test-session", Patch: patch})
+	appliedContent, _ := os.ReadFile(filepath.Join(worktreePath, "notes.txt"))
+	againResult, againOutput, againErr := server.handleApplyPatch(ctx, nil, ApplyPatchArgs{SessionID: "test-session", Patch: patch})
+
+	// assert
+	if checkErr != nil || applyErr != nil || againErr != nil {
+		t.Fatalf("expected no errors, got: %v, %v, %v", checkErr, applyErr, againErr)
+	}
+	if checkResult.IsError || applyResult.IsError || againResult.IsError {
+		t.Error("expected IsError to be false")
+	}
+	checked := checkOutput.(ApplyPatchOutput)
+	if !checked.Applies || checked.Applied || string(checkedContent) != "one\ntwo\nthree\n" {
+		t.Errorf("expected check to leave notes.txt untouched, got: %+v and %q", checked, checkedContent)
+	}
+	applied := applyOutput.(ApplyPatchOutput)
+	if !applied.Applied || len(applied.Hunks) != 1 || !applied.Hunks[0].Applies || string(appliedContent) != "one\nTWO\nthree\n" {
+		t.Errorf("expected the patch to be applied, got: %+v and %q", applied, appliedContent)
+	}
+	again := againOutput.(ApplyPatchOutput)
+	if again.Applies || again.Applied || len(again.Errors) == 0 || again.Hunks[0].Applies {
+		t.Errorf("expected the patch not to apply a second time, got: %+v", again)
+	}
+}
+
+func TestExecInSessionToolHandler_AllowedCommand_RunsInWorktree(t *testing.T) {
+	// arrange
+	server, repositoryRoot, _, cleanup := setupMCPServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createResult, _, _ := server.handleCreateWorktree(ctx, nil, CreateWorktreeArgs{SessionID: "test-session"})
+	if createResult.IsError {
+		t.Fatalf("failed to create worktree: %v", createResult.Content)
+	}
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "orchestragent-test-session")
+	if err := os.WriteFile(filepath.Join(worktreePath, "new.txt"), []byte("new\n"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	// act
+	result, output, err := server.handleExecInSession(ctx, nil, ExecInSessionArgs{SessionID: "test-session", Command: "git", Args: []string{"status", "--short"}})
+	deniedResult, _, deniedErr := server.handleExecInSession(ctx, nil, ExecInSessionArgs{SessionID: "test-session", Command: "git", Args: []string{"clean", "-fdx"}})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Error("expected IsError to be false")
+	}
+	execOutput, ok := output.(ExecInSessionOutput)
+	if !ok {
+		t.Fatalf("expected output to be ExecInSessionOutput, got: %T", output)
+	}
+	if execOutput.ExitCode != 0 || execOutput.Stdout != "?? new.txt\n" {
+		t.Errorf("expected git status of the session worktree, got: %+v", execOutput)
+	}
+	if !errors.Is(deniedErr, domain.ErrCommandNotAllowed) || !deniedResult.IsError {
+		t.Errorf("expected git clean to be refused, got: %v", deniedErr)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "new.txt")); err != nil {
+		t.Errorf("expected new.txt to survive, got: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// MaxApplyPatchBytes caps the size of a patch passed to apply_patch
+const MaxApplyPatchBytes = 4 << 20
+
+type ApplyPatchRequest struct {
+	SessionID string
+	Patch     string
+	// Check only reports whether and how the patch would apply
+	Check bool
+}
+
+type PatchHunkDTO struct {
+	Path    string `json:"path"`
+	Hunk    int    `json:"hunk"`
+	Header  string `json:"header,omitempty"`
+	Applies bool   `json:"applies"`
+}
+
+type ApplyPatchResponse struct {
+	SessionID string `json:"sessionId"`
+	Check     bool   `json:"check"`
+	// Applies tells whether the patch applies, as is or with a three-way
+	// merge; Applied whether the worktree was changed
+	Applies         bool           `json:"applies"`
+	Applied         bool           `json:"applied"`
+	ThreeWay        bool           `json:"threeWay"`
+	Files           []string       `json:"files"`
+	Hunks           []PatchHunkDTO `json:"hunks"`
+	ConflictedPaths []string       `json:"conflictedPaths,omitempty"`
+	Errors          []string       `json:"errors,omitempty"`
+}
+
+type ApplyPatchUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+}
+
+func NewApplyPatchUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+) *ApplyPatchUseCase {
+	return &ApplyPatchUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+	}
+}
+
+// Execute applies a unified diff to the session worktree without committing
+// it. A patch that does not apply, or only with conflicts, is reported in
+// the response rather than as an error, and leaves the worktree unchanged.
+func (applyPatchUseCase *ApplyPatchUseCase) Execute(
+	ctx context.Context,
+	request ApplyPatchRequest,
+) (*ApplyPatchResponse, error) {
+	session, err := findSession(ctx, applyPatchUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !request.Check && session.Status() == domain.StatusMerged {
+		return nil, fmt.Errorf("session %s is already merged", session.ID())
+	}
+
+	if request.Patch == "" {
+		return nil, errors.New("patch must not be empty")
+	}
+	if len(request.Patch) > MaxApplyPatchBytes {
+		return nil, fmt.Errorf("patch is %d bytes, the limit is %d", len(request.Patch), MaxApplyPatchBytes)
+	}
+
+	result, err := applyPatchUseCase.gitOperations.ApplyPatch(ctx, session.WorktreePath(), request.Patch, request.Check)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply patch: %w", err)
+	}
+
+	hunks := make([]PatchHunkDTO, 0, len(result.Hunks))
+	for _, hunk := range result.Hunks {
+		hunks = append(hunks, PatchHunkDTO{
+			Path:    hunk.Path,
+			Hunk:    hunk.Number,
+			Header:  hunk.Header,
+			Applies: hunk.Applies,
+		})
+	}
+
+	return &ApplyPatchResponse{
+		SessionID:       session.ID().String(),
+		Check:           request.Check,
+		Applies:         result.Applies,
+		Applied:         result.Applied,
+		ThreeWay:        result.ThreeWay,
+		Files:           result.Files,
+		Hunks:           hunks,
+		ConflictedPaths: result.ConflictedPaths,
+		Errors:          result.Errors,
+	}, nil
+}
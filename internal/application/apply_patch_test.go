@@ -0,0 +1,80 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func TestApplyPatchUseCase_Execute_ReportsHunksOfSessionWorktree(t *testing.T) {
+	// arrange
+	var appliedIn string
+	var checkedOnly bool
+	gitOperations := &mockGitOperations{
+		applyPatchFunc: func(ctx context.Context, worktreePath string, patch string, checkOnly bool) (*domain.PatchResult, error) {
+			appliedIn, checkedOnly = worktreePath, checkOnly
+			return &domain.PatchResult{
+				Files: []string{"main.go"},
+				Hunks: []domain.PatchHunk{
+					{Path: "main.go", Number: 1, Header: "@@ -1,3 +1,3 @@", Applies: true},
+					{Path: "main.go", Number: 2, Header: "@@ -9,3 +9,4 @@", Applies: false},
+				},
+				Applies:  true,
+				ThreeWay: true,
+			}, nil
+		},
+	}
+	useCase := NewApplyPatchUseCase(gitOperations, setupFileSession(t, "test-session", false))
+
+	// act
+	response, err := useCase.Execute(context.Background(), ApplyPatchRequest{SessionID: "test-session", Patch: "--- a/main.go\n", Check: true})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if appliedIn != "/worktrees/test-session" || !checkedOnly {
+		t.Errorf("applied in %q with checkOnly %v, want a check of the session worktree", appliedIn, checkedOnly)
+	}
+	if !response.Check || !response.Applies || response.Applied || !response.ThreeWay {
+		t.Errorf("response = %+v, want a three-way check that was not applied", response)
+	}
+	if len(response.Hunks) != 2 || response.Hunks[1].Hunk != 2 || response.Hunks[1].Applies {
+		t.Errorf("hunks = %+v, want the second hunk failing", response.Hunks)
+	}
+}
+
+func TestApplyPatchUseCase_Execute_RejectsInvalidRequests(t *testing.T) {
+	testCases := []struct {
+		name    string
+		merged  bool
+		request ApplyPatchRequest
+	}{
+		{"empty patch", false, ApplyPatchRequest{SessionID: "test-session"}},
+		{"patch too large", false, ApplyPatchRequest{SessionID: "test-session", Patch: string(make([]byte, MaxApplyPatchBytes+1))}},
+		{"merged session", true, ApplyPatchRequest{SessionID: "test-session", Patch: "--- a/main.go\n"}},
+		{"unknown session", false, ApplyPatchRequest{SessionID: "other-session", Patch: "--- a/main.go\n"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// arrange
+			gitOperations := &mockGitOperations{
+				applyPatchFunc: func(ctx context.Context, worktreePath string, patch string, checkOnly bool) (*domain.PatchResult, error) {
+					t.Error("ApplyPatch should not be called")
+					return nil, nil
+				},
+			}
+			useCase := NewApplyPatchUseCase(gitOperations, setupFileSession(t, "test-session", testCase.merged))
+
+			// act
+			_, err := useCase.Execute(context.Background(), testCase.request)
+
+			// assert
+			if err == nil {
+				t.Fatal("Execute() expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type CheckMergeRequest struct {
+	SessionID string
+}
+
+type MergeConflictDTO struct {
+	Path    string   `json:"path"`
+	Type    string   `json:"type"`
+	Message string   `json:"message"`
+	Hunks   []string `json:"hunks"`
+}
+
+type CheckMergeResponse struct {
+	SessionID string             `json:"sessionId"`
+	BaseRef   string             `json:"baseRef"`
+	Clean     bool               `json:"clean"`
+	Conflicts []MergeConflictDTO `json:"conflicts"`
+}
+
+type CheckMergeUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+	baseBranch        string
+}
+
+func NewCheckMergeUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+	baseBranch string,
+) *CheckMergeUseCase {
+	return &CheckMergeUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+		baseBranch:        baseBranch,
+	}
+}
+
+// Execute dry-runs a merge of the session's committed work into the current
+// tip of its base ref. Uncommitted changes are not part of the check.
+func (checkMergeUseCase *CheckMergeUseCase) Execute(
+	ctx context.Context,
+	request CheckMergeRequest,
+) (*CheckMergeResponse, error) {
+	session, err := findSession(ctx, checkMergeUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	baseRef := baseRefFor(session, checkMergeUseCase.baseBranch)
+	mergeCheck, err := checkMergeUseCase.gitOperations.CheckMerge(ctx, baseRef, session.BranchName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to check merge: %w", err)
+	}
+
+	conflicts := make([]MergeConflictDTO, 0, len(mergeCheck.Conflicts))
+	for _, conflict := range mergeCheck.Conflicts {
+		hunks := conflict.Hunks
+		if hunks == nil {
+			hunks = []string{}
+		}
+		conflicts = append(conflicts, MergeConflictDTO{
+			Path:    conflict.Path,
+			Type:    conflict.Type,
+			Message: conflict.Message,
+			Hunks:   hunks,
+		})
+	}
+
+	return &CheckMergeResponse{
+		SessionID: session.ID().String(),
+		BaseRef:   baseRef,
+		Clean:     mergeCheck.Clean,
+		Conflicts: conflicts,
+	}, nil
+}
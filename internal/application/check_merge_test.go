@@ -0,0 +1,63 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func TestCheckMergeUseCase_Execute_SessionNotFound(t *testing.T) {
+	// arrange
+	useCase := NewCheckMergeUseCase(&mockGitOperations{}, newMockSessionRepository(), "main")
+
+	// act
+	_, err := useCase.Execute(context.Background(), CheckMergeRequest{SessionID: "nonexistent"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for non-existent session")
+	}
+}
+
+func TestCheckMergeUseCase_Execute_ReportsConflicts(t *testing.T) {
+	// arrange
+	var receivedBaseRef string
+	gitOperations := &mockGitOperations{
+		checkMergeFunc: func(ctx context.Context, baseRef string, sessionBranch string) (*domain.MergeCheck, error) {
+			receivedBaseRef = baseRef
+			return &domain.MergeCheck{
+				Clean: false,
+				Conflicts: []domain.MergeConflict{
+					{Path: "main.go", Type: "CONFLICT (contents)", Hunks: []string{"<<<<<<< main\n=======\n>>>>>>> session\n"}},
+					{Path: "gone.go", Type: "CONFLICT (modify/delete)"},
+				},
+			}, nil
+		},
+	}
+	sessionRepository := newMockSessionRepository()
+	sessionID, _ := domain.NewSessionID("test-session")
+	session, _ := domain.NewSession(sessionID, "/path/test-session", "")
+	sessionRepository.Save(context.Background(), session)
+	useCase := NewCheckMergeUseCase(gitOperations, sessionRepository, "main")
+
+	// act
+	response, err := useCase.Execute(context.Background(), CheckMergeRequest{SessionID: "test-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if receivedBaseRef != "main" {
+		t.Errorf("CheckMerge() baseRef = %q, want %q", receivedBaseRef, "main")
+	}
+	if response.Clean {
+		t.Error("Execute() Clean = true, want false")
+	}
+	if len(response.Conflicts) != 2 || len(response.Conflicts[0].Hunks) != 1 {
+		t.Fatalf("Conflicts = %+v, want 2 conflicts with one hunk in the first", response.Conflicts)
+	}
+	if response.Conflicts[1].Hunks == nil {
+		t.Error("Hunks should be an empty list rather than nil")
+	}
+}
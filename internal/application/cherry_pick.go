@@ -0,0 +1,182 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// minCommitPrefixLength is the shortest abbreviated SHA accepted when
+// selecting commits to cherry-pick
+const minCommitPrefixLength = 7
+
+type CherryPickRequest struct {
+	SourceSessionID string
+	TargetSessionID string
+	// Commits are full or abbreviated SHAs of commits on the source session;
+	// empty picks all of them
+	Commits []string
+}
+
+type CherryPickResponse struct {
+	SourceSessionID string `json:"sourceSessionId"`
+	TargetSessionID string `json:"targetSessionId"`
+	Applied         bool   `json:"applied"`
+	// PickedCommits are the source commits applied, oldest first
+	PickedCommits []string `json:"pickedCommits"`
+	// SkippedMergeCommits are merge commits left out when picking all commits
+	SkippedMergeCommits []string `json:"skippedMergeCommits,omitempty"`
+	HeadCommit          string   `json:"headCommit,omitempty"`
+	ConflictedPaths     []string `json:"conflictedPaths,omitempty"`
+}
+
+type CherryPickUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+	baseBranch        string
+}
+
+func NewCherryPickUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+	baseBranch string,
+) *CherryPickUseCase {
+	return &CherryPickUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+		baseBranch:        baseBranch,
+	}
+}
+
+// Execute applies commits of the source session onto the target session's
+// branch in the order they were made on the source. Requested commits must be
+// on the source session. The picks are all or nothing: on conflict every pick
+// is aborted and the conflicted paths returned.
+func (cherryPickUseCase *CherryPickUseCase) Execute(
+	ctx context.Context,
+	request CherryPickRequest,
+) (*CherryPickResponse, error) {
+	source, err := findSession(ctx, cherryPickUseCase.sessionRepository, request.SourceSessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source session: %w", err)
+	}
+	target, err := findSession(ctx, cherryPickUseCase.sessionRepository, request.TargetSessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target session: %w", err)
+	}
+	if err := cherryPickUseCase.ensurePickable(ctx, source, target); err != nil {
+		return nil, err
+	}
+
+	sourceCommits, err := cherryPickUseCase.gitOperations.GetCommits(ctx, baseRefFor(source, cherryPickUseCase.baseBranch), source.BranchName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source commits: %w", err)
+	}
+	picked, skippedMerges, err := selectCommitsToPick(sourceCommits, request.Commits)
+	if err != nil {
+		return nil, fmt.Errorf("%w on session %s", err, source.ID())
+	}
+	if len(picked) == 0 {
+		return nil, fmt.Errorf("session %s has no commits to cherry-pick", source.ID())
+	}
+
+	response := &CherryPickResponse{
+		SourceSessionID:     source.ID().String(),
+		TargetSessionID:     target.ID().String(),
+		PickedCommits:       picked,
+		SkippedMergeCommits: skippedMerges,
+	}
+
+	err = cherryPickUseCase.gitOperations.CherryPick(ctx, target.WorktreePath(), picked)
+	var conflictErr *domain.ConflictError
+	if errors.As(err, &conflictErr) {
+		response.ConflictedPaths = conflictErr.Paths
+		return response, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to cherry-pick: %w", err)
+	}
+
+	headCommit, err := cherryPickUseCase.gitOperations.ResolveCommit(ctx, target.BranchName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve target head: %w", err)
+	}
+	response.Applied = true
+	response.HeadCommit = headCommit
+	return response, nil
+}
+
+func (cherryPickUseCase *CherryPickUseCase) ensurePickable(ctx context.Context, source *domain.Session, target *domain.Session) error {
+	if source.ID() == target.ID() {
+		return fmt.Errorf("source and target session are both %s", source.ID())
+	}
+	if target.Status() == domain.StatusMerged {
+		return fmt.Errorf("session %s is already merged", target.ID())
+	}
+
+	hasUncommitted, fileCount, err := cherryPickUseCase.gitOperations.HasUncommittedChanges(ctx, target.WorktreePath())
+	if err != nil {
+		return fmt.Errorf("failed to check uncommitted changes: %w", err)
+	}
+	if hasUncommitted {
+		return fmt.Errorf("session %s has %d uncommitted files; commit or discard them before cherry-picking", target.ID(), fileCount)
+	}
+	return nil
+}
+
+// selectCommitsToPick resolves requested SHAs against the source commits,
+// which are listed newest first, and returns the full SHAs oldest first.
+// Picking all commits leaves merge commits out; requesting one is an error.
+func selectCommitsToPick(sourceCommits []domain.Commit, requested []string) ([]string, []string, error) {
+	selected := make(map[string]bool, len(requested))
+	for _, prefix := range requested {
+		sha, err := matchCommitPrefix(sourceCommits, prefix)
+		if err != nil {
+			return nil, nil, err
+		}
+		selected[sha] = true
+	}
+
+	picked := make([]string, 0, len(sourceCommits))
+	skippedMerges := make([]string, 0)
+	for index := len(sourceCommits) - 1; index >= 0; index-- {
+		commit := sourceCommits[index]
+		if len(requested) > 0 && !selected[commit.SHA] {
+			continue
+		}
+		if len(commit.ParentSHAs) > 1 {
+			if len(requested) > 0 {
+				return nil, nil, fmt.Errorf("commit %s is a merge commit and cannot be cherry-picked", commit.SHA)
+			}
+			skippedMerges = append(skippedMerges, commit.SHA)
+			continue
+		}
+		picked = append(picked, commit.SHA)
+	}
+	return picked, skippedMerges, nil
+}
+
+func matchCommitPrefix(commits []domain.Commit, prefix string) (string, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if len(prefix) < minCommitPrefixLength {
+		return "", fmt.Errorf("commit %q must be at least %d characters of a SHA", prefix, minCommitPrefixLength)
+	}
+
+	match := ""
+	for _, commit := range commits {
+		if !strings.HasPrefix(commit.SHA, prefix) {
+			continue
+		}
+		if match != "" {
+			return "", fmt.Errorf("commit %s is ambiguous", prefix)
+		}
+		match = commit.SHA
+	}
+	if match == "" {
+		return "", fmt.Errorf("commit %s not found", prefix)
+	}
+	return match, nil
+}
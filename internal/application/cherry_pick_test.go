@@ -0,0 +1,159 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+const (
+	firstSourceCommit  = "aaaaaaa111111111111111111111111111111111"
+	mergeSourceCommit  = "bbbbbbb222222222222222222222222222222222"
+	secondSourceCommit = "ccccccc333333333333333333333333333333333"
+)
+
+func setupCherryPickSessions(t *testing.T, gitOperations *mockGitOperations) (*CherryPickUseCase, *domain.Session) {
+	t.Helper()
+
+	sessionRepository := newMockSessionRepository()
+	var target *domain.Session
+	for _, id := range []string{"source", "target"} {
+		sessionID, _ := domain.NewSessionID(id)
+		session, _ := domain.NewSession(sessionID, "/worktrees/"+id, "")
+		sessionRepository.Save(context.Background(), session)
+		target = session
+	}
+
+	gitOperations.getCommitsFunc = func(ctx context.Context, baseRef string, sessionBranch string) ([]domain.Commit, error) {
+		return []domain.Commit{
+			{SHA: secondSourceCommit, ParentSHAs: []string{mergeSourceCommit}},
+			{SHA: mergeSourceCommit, ParentSHAs: []string{firstSourceCommit, "0123456789abcdef0123456789abcdef01234567"}},
+			{SHA: firstSourceCommit, ParentSHAs: []string{"0123456789abcdef0123456789abcdef01234567"}},
+		}, nil
+	}
+	return NewCherryPickUseCase(gitOperations, sessionRepository, "main"), target
+}
+
+func TestCherryPickUseCase_Execute_AllCommits_PicksOldestFirstWithoutMerges(t *testing.T) {
+	// arrange
+	var pickedInto string
+	var pickedCommits []string
+	gitOperations := &mockGitOperations{
+		cherryPickFunc: func(ctx context.Context, worktreePath string, commits []string) error {
+			pickedInto, pickedCommits = worktreePath, commits
+			return nil
+		},
+	}
+	useCase, target := setupCherryPickSessions(t, gitOperations)
+
+	// act
+	response, err := useCase.Execute(context.Background(), CherryPickRequest{SourceSessionID: "source", TargetSessionID: "target"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if pickedInto != target.WorktreePath() {
+		t.Errorf("picked into %q, want %q", pickedInto, target.WorktreePath())
+	}
+	if len(pickedCommits) != 2 || pickedCommits[0] != firstSourceCommit || pickedCommits[1] != secondSourceCommit {
+		t.Errorf("picked %v, want the two non-merge commits oldest first", pickedCommits)
+	}
+	if !response.Applied || len(response.SkippedMergeCommits) != 1 || response.SkippedMergeCommits[0] != mergeSourceCommit {
+		t.Errorf("response = %+v, want applied with the merge commit skipped", response)
+	}
+}
+
+func TestCherryPickUseCase_Execute_SelectedCommits_ResolvesAbbreviatedSHAs(t *testing.T) {
+	// arrange
+	var pickedCommits []string
+	gitOperations := &mockGitOperations{
+		cherryPickFunc: func(ctx context.Context, worktreePath string, commits []string) error {
+			pickedCommits = commits
+			return nil
+		},
+	}
+	useCase, _ := setupCherryPickSessions(t, gitOperations)
+
+	// act
+	_, err := useCase.Execute(context.Background(), CherryPickRequest{SourceSessionID: "source", TargetSessionID: "target", Commits: []string{"ccccccc3", "AAAAAAA"}})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if len(pickedCommits) != 2 || pickedCommits[0] != firstSourceCommit || pickedCommits[1] != secondSourceCommit {
+		t.Errorf("picked %v, want both commits in source order", pickedCommits)
+	}
+}
+
+func TestCherryPickUseCase_Execute_InvalidSelection_ReturnsError(t *testing.T) {
+	testCases := []struct {
+		name    string
+		commits []string
+	}{
+		{name: "not on source", commits: []string{"ddddddd"}},
+		{name: "too short", commits: []string{"aaaa"}},
+		{name: "merge commit", commits: []string{"bbbbbbb"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// arrange
+			picked := false
+			gitOperations := &mockGitOperations{
+				cherryPickFunc: func(ctx context.Context, worktreePath string, commits []string) error {
+					picked = true
+					return nil
+				},
+			}
+			useCase, _ := setupCherryPickSessions(t, gitOperations)
+
+			// act
+			_, err := useCase.Execute(context.Background(), CherryPickRequest{SourceSessionID: "source", TargetSessionID: "target", Commits: testCase.commits})
+
+			// assert
+			if err == nil {
+				t.Error("Execute() expected error")
+			}
+			if picked {
+				t.Error("Execute() should not cherry-pick an invalid selection")
+			}
+		})
+	}
+}
+
+func TestCherryPickUseCase_Execute_Conflict_ReportsPaths(t *testing.T) {
+	// arrange
+	gitOperations := &mockGitOperations{
+		cherryPickFunc: func(ctx context.Context, worktreePath string, commits []string) error {
+			return &domain.ConflictError{Operation: "cherry-pick", Paths: []string{"shared.go"}}
+		},
+	}
+	useCase, _ := setupCherryPickSessions(t, gitOperations)
+
+	// act
+	response, err := useCase.Execute(context.Background(), CherryPickRequest{SourceSessionID: "source", TargetSessionID: "target"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if response.Applied || len(response.ConflictedPaths) != 1 || response.ConflictedPaths[0] != "shared.go" {
+		t.Errorf("response = %+v, want a reported conflict", response)
+	}
+}
+
+func TestCherryPickUseCase_Execute_UnknownTarget_ReturnsError(t *testing.T) {
+	// arrange
+	useCase, _ := setupCherryPickSessions(t, &mockGitOperations{})
+
+	// act
+	_, err := useCase.Execute(context.Background(), CherryPickRequest{SourceSessionID: "source", TargetSessionID: "missing"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for an unknown target session")
+	}
+}
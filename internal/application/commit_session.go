@@ -0,0 +1,116 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+const (
+	sessionIDTrailerKey = "Session-Id"
+	agentTrailerKey     = "Agent"
+)
+
+type CommitSessionRequest struct {
+	SessionID string
+	Message   string
+	// Paths limits the commit to these worktree-relative paths; empty
+	// commits every change, including untracked files
+	Paths []string
+	// Agent names the agent the commit is made for, recorded in an Agent
+	// trailer when set
+	Agent string
+}
+
+type CommitSessionResponse struct {
+	SessionID  string    `json:"sessionId"`
+	BranchName string    `json:"branchName"`
+	Commit     CommitDTO `json:"commit"`
+}
+
+type CommitSessionUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+	author            domain.CommitAuthor
+}
+
+func NewCommitSessionUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+	author domain.CommitAuthor,
+) *CommitSessionUseCase {
+	return &CommitSessionUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+		author:            author,
+	}
+}
+
+// Execute commits the session's changes on its branch with the configured
+// author identity, tagging the commit with Session-Id and Agent trailers. It
+// refuses to create an empty commit.
+func (commitSessionUseCase *CommitSessionUseCase) Execute(
+	ctx context.Context,
+	request CommitSessionRequest,
+) (*CommitSessionResponse, error) {
+	session, err := findSession(ctx, commitSessionUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status() == domain.StatusMerged {
+		return nil, fmt.Errorf("session %s is already merged", session.ID())
+	}
+	if strings.TrimSpace(request.Message) == "" {
+		return nil, errors.New("commit message must not be empty")
+	}
+	paths, err := validateCommitPaths(request.Paths)
+	if err != nil {
+		return nil, err
+	}
+	// a line break would end the trailer and let the rest forge new ones
+	if strings.ContainsAny(request.Agent, "\r\n") {
+		return nil, fmt.Errorf("agent %q must not contain line breaks", request.Agent)
+	}
+
+	trailers := []domain.CommitTrailer{{Key: sessionIDTrailerKey, Value: session.ID().String()}}
+	if agent := strings.TrimSpace(request.Agent); agent != "" {
+		trailers = append(trailers, domain.CommitTrailer{Key: agentTrailerKey, Value: agent})
+	}
+
+	commit, err := commitSessionUseCase.gitOperations.Commit(ctx, session.WorktreePath(), domain.CommitOptions{
+		Paths:    paths,
+		Message:  request.Message,
+		Author:   commitSessionUseCase.author,
+		Trailers: trailers,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit session %s: %w", session.ID(), err)
+	}
+
+	return &CommitSessionResponse{
+		SessionID:  session.ID().String(),
+		BranchName: session.BranchName(),
+		Commit:     buildCommitDTO(*commit),
+	}, nil
+}
+
+// validateCommitPaths cleans the requested paths and rejects any that would
+// reach outside the worktree
+func validateCommitPaths(paths []string) ([]string, error) {
+	cleanedPaths := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if strings.TrimSpace(path) == "" {
+			return nil, errors.New("paths must not be empty")
+		}
+		cleanedPath := filepath.Clean(path)
+		if filepath.IsAbs(cleanedPath) || cleanedPath == ".." || strings.HasPrefix(cleanedPath, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("path %q must be relative to the session worktree", path)
+		}
+		cleanedPaths = append(cleanedPaths, filepath.ToSlash(cleanedPath))
+	}
+	return cleanedPaths, nil
+}
@@ -0,0 +1,115 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func setupCommitSessionTest(t *testing.T, gitOperations *mockGitOperations) *CommitSessionUseCase {
+	t.Helper()
+
+	sessionRepository := newMockSessionRepository()
+	sessionID, _ := domain.NewSessionID("test-session")
+	session, _ := domain.NewSession(sessionID, "/path/test-session", "")
+	sessionRepository.Save(context.Background(), session)
+
+	author := domain.CommitAuthor{Name: "Session Bot", Email: "bot@example.com"}
+	return NewCommitSessionUseCase(gitOperations, sessionRepository, author)
+}
+
+func TestCommitSessionUseCase_Execute_AddsTrailersAndAuthor(t *testing.T) {
+	// arrange
+	var receivedOptions domain.CommitOptions
+	gitOperations := &mockGitOperations{
+		commitFunc: func(ctx context.Context, worktreePath string, options domain.CommitOptions) (*domain.Commit, error) {
+			receivedOptions = options
+			return &domain.Commit{SHA: "abc123", Files: []domain.FileChange{{Path: "main.go", LinesAdded: 3}}}, nil
+		},
+	}
+	useCase := setupCommitSessionTest(t, gitOperations)
+
+	// act
+	response, err := useCase.Execute(context.Background(), CommitSessionRequest{
+		SessionID: "test-session",
+		Message:   "Add feature",
+		Paths:     []string{"./src/../main.go"},
+		Agent:     "coder",
+	})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if response.Commit.SHA != "abc123" || response.Commit.LinesAdded != 3 {
+		t.Errorf("Commit = %+v, want sha abc123 with 3 lines added", response.Commit)
+	}
+	if receivedOptions.Author.Name != "Session Bot" {
+		t.Errorf("Author = %+v, want the configured author", receivedOptions.Author)
+	}
+	if len(receivedOptions.Paths) != 1 || receivedOptions.Paths[0] != "main.go" {
+		t.Errorf("Paths = %v, want [main.go]", receivedOptions.Paths)
+	}
+	expectedTrailers := []domain.CommitTrailer{{Key: "Session-Id", Value: "test-session"}, {Key: "Agent", Value: "coder"}}
+	if len(receivedOptions.Trailers) != 2 || receivedOptions.Trailers[0] != expectedTrailers[0] || receivedOptions.Trailers[1] != expectedTrailers[1] {
+		t.Errorf("Trailers = %+v, want %+v", receivedOptions.Trailers, expectedTrailers)
+	}
+}
+
+func TestCommitSessionUseCase_Execute_NothingToCommit_ReturnsError(t *testing.T) {
+	// arrange
+	gitOperations := &mockGitOperations{
+		commitFunc: func(ctx context.Context, worktreePath string, options domain.CommitOptions) (*domain.Commit, error) {
+			return nil, domain.ErrNothingToCommit
+		},
+	}
+	useCase := setupCommitSessionTest(t, gitOperations)
+
+	// act
+	_, err := useCase.Execute(context.Background(), CommitSessionRequest{SessionID: "test-session", Message: "Nothing"})
+
+	// assert
+	if !errors.Is(err, domain.ErrNothingToCommit) {
+		t.Errorf("Execute() error = %v, want ErrNothingToCommit", err)
+	}
+}
+
+func TestCommitSessionUseCase_Execute_RejectsInvalidInput(t *testing.T) {
+	testCases := []struct {
+		name    string
+		request CommitSessionRequest
+	}{
+		{"empty message", CommitSessionRequest{SessionID: "test-session", Message: "  "}},
+		{"path outside worktree", CommitSessionRequest{SessionID: "test-session", Message: "Escape", Paths: []string{"../other/file.go"}}},
+		{"absolute path", CommitSessionRequest{SessionID: "test-session", Message: "Absolute", Paths: []string{"/etc/passwd"}}},
+		{"agent with trailer injection", CommitSessionRequest{SessionID: "test-session", Message: "Inject", Agent: "codex\nSigned-off-by: Someone <someone@example.com>"}},
+		{"agent with carriage return", CommitSessionRequest{SessionID: "test-session", Message: "Inject", Agent: "codex\rSession-Id: other"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// arrange
+			commitCalled := false
+			gitOperations := &mockGitOperations{
+				commitFunc: func(ctx context.Context, worktreePath string, options domain.CommitOptions) (*domain.Commit, error) {
+					commitCalled = true
+					return &domain.Commit{}, nil
+				},
+			}
+			useCase := setupCommitSessionTest(t, gitOperations)
+
+			// act
+			_, err := useCase.Execute(context.Background(), testCase.request)
+
+			// assert
+			if err == nil {
+				t.Error("Execute() expected error")
+			}
+			if commitCalled {
+				t.Error("Commit() should not run for invalid input")
+			}
+		})
+	}
+}
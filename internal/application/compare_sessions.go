@@ -0,0 +1,210 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type CompareSessionsRequest struct {
+	LeftSessionID  string
+	RightSessionID string
+	PathGlobs      []string
+	// ContextLines is the number of unchanged lines around each hunk; nil
+	// selects DefaultDiffContextLines
+	ContextLines    *int
+	MaxBytesPerFile int
+	MaxBytesPerPage int
+	PageSize        int
+	Cursor          string
+}
+
+type CompareSessionsResponse struct {
+	LeftSessionID   string `json:"leftSessionId"`
+	RightSessionID  string `json:"rightSessionId"`
+	LeftHeadCommit  string `json:"leftHeadCommit"`
+	RightHeadCommit string `json:"rightHeadCommit"`
+	LinesAdded      int    `json:"linesAdded"`
+	LinesRemoved    int    `json:"linesRemoved"`
+	FilesChanged    int    `json:"filesChanged"`
+	// FileStats counts the lines that differ between the two branch tips,
+	// reading from left to right
+	FileStats []FileChangeDTO `json:"fileStats"`
+	// OnlyLeftFiles and OnlyRightFiles are files committed on one session
+	// since its base that the other session's commits do not touch
+	OnlyLeftFiles  []string `json:"onlyLeftFiles"`
+	OnlyRightFiles []string `json:"onlyRightFiles"`
+	// LeftUncommittedFiles and RightUncommittedFiles count work left out of
+	// the comparison because it is not committed yet
+	LeftUncommittedFiles  int           `json:"leftUncommittedFiles"`
+	RightUncommittedFiles int           `json:"rightUncommittedFiles"`
+	Files                 []FileDiffDTO `json:"files"`
+	TotalFiles            int           `json:"totalFiles"`
+	NextCursor            string        `json:"nextCursor,omitempty"`
+}
+
+type CompareSessionsUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+	baseBranch        string
+}
+
+func NewCompareSessionsUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+	baseBranch string,
+) *CompareSessionsUseCase {
+	return &CompareSessionsUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+		baseBranch:        baseBranch,
+	}
+}
+
+// Execute diffs the committed work of two sessions against each other, so the
+// patch turns the left session's branch into the right one's. The diff is
+// paginated by file the same way as a session diff.
+func (compareSessionsUseCase *CompareSessionsUseCase) Execute(
+	ctx context.Context,
+	request CompareSessionsRequest,
+) (*CompareSessionsResponse, error) {
+	left, err := findSession(ctx, compareSessionsUseCase.sessionRepository, request.LeftSessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid left session: %w", err)
+	}
+	right, err := findSession(ctx, compareSessionsUseCase.sessionRepository, request.RightSessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid right session: %w", err)
+	}
+	if left.ID() == right.ID() {
+		return nil, fmt.Errorf("cannot compare session %s with itself", left.ID())
+	}
+
+	options, err := buildDiffOptions(request.PathGlobs, request.ContextLines)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := decodeDiffCursor(request.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison, err := compareSessionsUseCase.gitOperations.CompareRefs(ctx, left.BranchName(), right.BranchName(), options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare sessions: %w", err)
+	}
+	offset, err := cursor.offsetInto(comparison.Diffs)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CompareSessionsResponse{
+		LeftSessionID:  left.ID().String(),
+		RightSessionID: right.ID().String(),
+		FileStats:      buildFileChangeDTOs(comparison.Files),
+		TotalFiles:     len(comparison.Diffs),
+	}
+	stats := domain.NewGitDiffStats(comparison.Files)
+	response.LinesAdded = stats.LinesAdded
+	response.LinesRemoved = stats.LinesRemoved
+	response.FilesChanged = stats.FilesChanged
+
+	if err := compareSessionsUseCase.describeSides(ctx, left, right, options.PathGlobs, response); err != nil {
+		return nil, err
+	}
+
+	files, nextOffset := paginateFileDiffs(
+		comparison.Diffs,
+		offset,
+		clampPositive(request.PageSize, DefaultDiffPageSize, MaxDiffPageSize),
+		clampPositive(request.MaxBytesPerFile, DefaultDiffMaxBytesPerFile, 0),
+		clampPositive(request.MaxBytesPerPage, DefaultDiffMaxBytesPerPage, 0),
+	)
+	response.Files = files
+	if nextOffset < len(comparison.Diffs) {
+		response.NextCursor = encodeDiffCursor(comparison.Diffs, nextOffset)
+	}
+
+	return response, nil
+}
+
+// describeSides fills in each session's head commit, uncommitted file count
+// and the files only its own commits touched
+func (compareSessionsUseCase *CompareSessionsUseCase) describeSides(
+	ctx context.Context,
+	left *domain.Session,
+	right *domain.Session,
+	pathGlobs []string,
+	response *CompareSessionsResponse,
+) error {
+	leftSide, err := compareSessionsUseCase.describeSide(ctx, left, pathGlobs)
+	if err != nil {
+		return err
+	}
+	rightSide, err := compareSessionsUseCase.describeSide(ctx, right, pathGlobs)
+	if err != nil {
+		return err
+	}
+
+	response.LeftHeadCommit = leftSide.headCommit
+	response.RightHeadCommit = rightSide.headCommit
+	response.LeftUncommittedFiles = leftSide.uncommittedFiles
+	response.RightUncommittedFiles = rightSide.uncommittedFiles
+	response.OnlyLeftFiles = filesOnlyIn(leftSide.changes, rightSide.changes)
+	response.OnlyRightFiles = filesOnlyIn(rightSide.changes, leftSide.changes)
+	return nil
+}
+
+type comparedSide struct {
+	headCommit       string
+	uncommittedFiles int
+	changes          []domain.FileChange
+}
+
+func (compareSessionsUseCase *CompareSessionsUseCase) describeSide(
+	ctx context.Context,
+	session *domain.Session,
+	pathGlobs []string,
+) (*comparedSide, error) {
+	headCommit, err := compareSessionsUseCase.gitOperations.ResolveCommit(ctx, session.BranchName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve head of session %s: %w", session.ID(), err)
+	}
+
+	changes, err := compareSessionsUseCase.gitOperations.GetBranchChanges(ctx, diffBaseFor(session, compareSessionsUseCase.baseBranch), session.BranchName(), pathGlobs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changes of session %s: %w", session.ID(), err)
+	}
+
+	side := &comparedSide{headCommit: headCommit, changes: changes}
+	if session.Status() != domain.StatusMerged {
+		_, side.uncommittedFiles, err = compareSessionsUseCase.gitOperations.HasUncommittedChanges(ctx, session.WorktreePath())
+		if err != nil {
+			return nil, fmt.Errorf("failed to check uncommitted changes of session %s: %w", session.ID(), err)
+		}
+	}
+	return side, nil
+}
+
+// filesOnlyIn returns the sorted paths changed in changes that other does not
+// touch. A rename touches both its old and its new path.
+func filesOnlyIn(changes []domain.FileChange, other []domain.FileChange) []string {
+	touched := make(map[string]bool, 2*len(other))
+	for _, change := range other {
+		touched[change.Path] = true
+		if change.OldPath != "" {
+			touched[change.OldPath] = true
+		}
+	}
+
+	paths := make([]string, 0)
+	for _, change := range changes {
+		if !touched[change.Path] && (change.OldPath == "" || !touched[change.OldPath]) {
+			paths = append(paths, change.Path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
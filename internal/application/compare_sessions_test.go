@@ -0,0 +1,135 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func setupCompareSessions(t *testing.T, gitOperations *mockGitOperations) *CompareSessionsUseCase {
+	t.Helper()
+
+	sessionRepository := newMockSessionRepository()
+	for _, id := range []string{"left", "right"} {
+		sessionID, _ := domain.NewSessionID(id)
+		session, _ := domain.NewSession(sessionID, "/worktrees/"+id, "")
+		sessionRepository.Save(context.Background(), session)
+	}
+	return NewCompareSessionsUseCase(gitOperations, sessionRepository, "main")
+}
+
+func TestCompareSessionsUseCase_Execute_ReportsDiffStatsAndOneSidedFiles(t *testing.T) {
+	// arrange
+	var comparedFrom, comparedTo string
+	gitOperations := &mockGitOperations{
+		compareRefsFunc: func(ctx context.Context, fromRef string, toRef string, options domain.DiffOptions) (*domain.RefComparison, error) {
+			comparedFrom, comparedTo = fromRef, toRef
+			return &domain.RefComparison{
+				Diffs: []domain.FileDiff{{Path: "shared.go", Patch: "-left\n+right\n"}, {Path: "right.go", Patch: "+right\n"}},
+				Files: []domain.FileChange{
+					{Path: "shared.go", ChangeType: domain.FileChangeModified, LinesAdded: 1, LinesRemoved: 1},
+					{Path: "right.go", ChangeType: domain.FileChangeAdded, LinesAdded: 1},
+				},
+			}, nil
+		},
+		getBranchChangesFunc: func(ctx context.Context, baseRef string, branchName string, pathGlobs []string) ([]domain.FileChange, error) {
+			if branchName == "orchestragent-left" {
+				return []domain.FileChange{{Path: "shared.go"}, {Path: "left.go"}, {Path: "renamed.go", OldPath: "old.go"}}, nil
+			}
+			return []domain.FileChange{{Path: "shared.go"}, {Path: "right.go"}, {Path: "old.go"}}, nil
+		},
+		hasUncommittedChangesFunc: func(ctx context.Context, worktreePath string) (bool, int, error) {
+			if worktreePath == "/worktrees/right" {
+				return true, 2, nil
+			}
+			return false, 0, nil
+		},
+	}
+	useCase := setupCompareSessions(t, gitOperations)
+
+	// act
+	response, err := useCase.Execute(context.Background(), CompareSessionsRequest{LeftSessionID: "left", RightSessionID: "right"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if comparedFrom != "orchestragent-left" || comparedTo != "orchestragent-right" {
+		t.Errorf("compared %s..%s, want the left branch against the right branch", comparedFrom, comparedTo)
+	}
+	if response.LinesAdded != 2 || response.LinesRemoved != 1 || response.FilesChanged != 2 || len(response.FileStats) != 2 {
+		t.Errorf("stats = +%d -%d in %d files, want +2 -1 in 2 files", response.LinesAdded, response.LinesRemoved, response.FilesChanged)
+	}
+	if !reflect.DeepEqual(response.OnlyLeftFiles, []string{"left.go"}) {
+		t.Errorf("onlyLeftFiles = %v, want [left.go]", response.OnlyLeftFiles)
+	}
+	if !reflect.DeepEqual(response.OnlyRightFiles, []string{"right.go"}) {
+		t.Errorf("onlyRightFiles = %v, want [right.go]", response.OnlyRightFiles)
+	}
+	if response.LeftUncommittedFiles != 0 || response.RightUncommittedFiles != 2 {
+		t.Errorf("uncommitted = %d/%d, want 0/2", response.LeftUncommittedFiles, response.RightUncommittedFiles)
+	}
+	if response.TotalFiles != 2 || len(response.Files) != 2 || response.NextCursor != "" {
+		t.Errorf("files = %+v, want both diffs on one page", response.Files)
+	}
+}
+
+func TestCompareSessionsUseCase_Execute_PaginatesDiff(t *testing.T) {
+	// arrange
+	gitOperations := &mockGitOperations{
+		compareRefsFunc: func(ctx context.Context, fromRef string, toRef string, options domain.DiffOptions) (*domain.RefComparison, error) {
+			return &domain.RefComparison{
+				Diffs: []domain.FileDiff{{Path: "a.go", Patch: "+a\n"}, {Path: "b.go", Patch: "+b\n"}},
+				Files: []domain.FileChange{{Path: "a.go"}, {Path: "b.go"}},
+			}, nil
+		},
+	}
+	useCase := setupCompareSessions(t, gitOperations)
+
+	// act
+	firstPage, err := useCase.Execute(context.Background(), CompareSessionsRequest{LeftSessionID: "left", RightSessionID: "right", PageSize: 1})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	secondPage, err := useCase.Execute(context.Background(), CompareSessionsRequest{LeftSessionID: "left", RightSessionID: "right", PageSize: 1, Cursor: firstPage.NextCursor})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if len(firstPage.Files) != 1 || firstPage.Files[0].Path != "a.go" || firstPage.NextCursor == "" {
+		t.Errorf("first page = %+v, want a.go with a cursor", firstPage)
+	}
+	if len(secondPage.Files) != 1 || secondPage.Files[0].Path != "b.go" || secondPage.NextCursor != "" {
+		t.Errorf("second page = %+v, want b.go and no cursor", secondPage)
+	}
+}
+
+func TestCompareSessionsUseCase_Execute_SameSession_ReturnsError(t *testing.T) {
+	// arrange
+	useCase := setupCompareSessions(t, &mockGitOperations{})
+
+	// act
+	_, err := useCase.Execute(context.Background(), CompareSessionsRequest{LeftSessionID: "left", RightSessionID: "left"})
+
+	// assert
+	if err == nil {
+		t.Fatal("Execute() expected error comparing a session with itself")
+	}
+}
+
+func TestCompareSessionsUseCase_Execute_InvalidCursor_ReturnsError(t *testing.T) {
+	// arrange
+	useCase := setupCompareSessions(t, &mockGitOperations{})
+
+	// act
+	_, err := useCase.Execute(context.Background(), CompareSessionsRequest{LeftSessionID: "left", RightSessionID: "right", Cursor: "bogus"})
+
+	// assert
+	if !errors.Is(err, ErrInvalidDiffCursor) {
+		t.Errorf("Execute() error = %v, want ErrInvalidDiffCursor", err)
+	}
+}
@@ -0,0 +1,102 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type CreateCheckpointRequest struct {
+	SessionID string
+	// Name defaults to checkpoint-<n>, numbered after the existing ones
+	Name    string
+	Message string
+}
+
+type CreateCheckpointResponse struct {
+	SessionID  string        `json:"sessionId"`
+	Checkpoint CheckpointDTO `json:"checkpoint"`
+}
+
+type CreateCheckpointUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+}
+
+func NewCreateCheckpointUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+) *CreateCheckpointUseCase {
+	return &CreateCheckpointUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+	}
+}
+
+// Execute snapshots the session worktree, including uncommitted and
+// untracked files, without changing the worktree or its branch
+func (createCheckpointUseCase *CreateCheckpointUseCase) Execute(
+	ctx context.Context,
+	request CreateCheckpointRequest,
+) (*CreateCheckpointResponse, error) {
+	session, err := findSession(ctx, createCheckpointUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpointName, err := createCheckpointUseCase.resolveName(ctx, session, request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	message := request.Message
+	if strings.TrimSpace(message) == "" {
+		message = fmt.Sprintf("Checkpoint %s of session '%s'", checkpointName, session.ID())
+	}
+
+	checkpoint, err := createCheckpointUseCase.gitOperations.CreateCheckpoint(ctx, session.WorktreePath(), session.ID().CheckpointRef(checkpointName), message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint: %w", err)
+	}
+
+	return &CreateCheckpointResponse{
+		SessionID:  session.ID().String(),
+		Checkpoint: buildCheckpointDTO(*checkpoint),
+	}, nil
+}
+
+// resolveName validates a requested name, or picks the first free
+// checkpoint-<n> after the session's existing checkpoints
+func (createCheckpointUseCase *CreateCheckpointUseCase) resolveName(ctx context.Context, session *domain.Session, requested string) (domain.CheckpointName, error) {
+	if strings.TrimSpace(requested) != "" {
+		checkpointName, err := domain.NewCheckpointName(requested)
+		if err != nil {
+			return domain.CheckpointName{}, fmt.Errorf("invalid checkpoint name: %w", err)
+		}
+		return checkpointName, nil
+	}
+
+	existing, err := createCheckpointUseCase.gitOperations.ListCheckpoints(ctx, session.ID().CheckpointRefPrefix())
+	if err != nil {
+		return domain.CheckpointName{}, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	return firstFreeCheckpointName(existing, "checkpoint", len(existing)+1)
+}
+
+// firstFreeCheckpointName returns the first <stem>-<n> from start on that no
+// existing checkpoint uses
+func firstFreeCheckpointName(existing []domain.Checkpoint, stem string, start int) (domain.CheckpointName, error) {
+	taken := make(map[string]bool, len(existing))
+	for _, checkpoint := range existing {
+		taken[checkpoint.Name] = true
+	}
+
+	for number := start; ; number++ {
+		candidate := fmt.Sprintf("%s-%d", stem, number)
+		if !taken[candidate] {
+			return domain.NewCheckpointName(candidate)
+		}
+	}
+}
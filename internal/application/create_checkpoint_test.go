@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func setupCheckpointTest(t *testing.T) *mockSessionRepository {
+	t.Helper()
+
+	sessionRepository := newMockSessionRepository()
+	sessionID, _ := domain.NewSessionID("test-session")
+	session, _ := domain.NewSession(sessionID, "/path/test-session", "")
+	sessionRepository.Save(context.Background(), session)
+	return sessionRepository
+}
+
+func TestCreateCheckpointUseCase_Execute_NamesCheckpointAfterExistingOnes(t *testing.T) {
+	// arrange
+	var receivedRef, receivedMessage string
+	gitOperations := &mockGitOperations{
+		listCheckpointsFunc: func(ctx context.Context, refPrefix string) ([]domain.Checkpoint, error) {
+			return []domain.Checkpoint{{Name: "checkpoint-1"}, {Name: "checkpoint-3"}}, nil
+		},
+		createCheckpointFunc: func(ctx context.Context, worktreePath string, ref string, message string) (*domain.Checkpoint, error) {
+			receivedRef = ref
+			receivedMessage = message
+			return &domain.Checkpoint{Name: "checkpoint-4", Ref: ref, Message: message}, nil
+		},
+	}
+	useCase := NewCreateCheckpointUseCase(gitOperations, setupCheckpointTest(t))
+
+	// act
+	response, err := useCase.Execute(context.Background(), CreateCheckpointRequest{SessionID: "test-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if receivedRef != "refs/orchestragent/test-session/checkpoints/checkpoint-4" {
+		t.Errorf("CreateCheckpoint() ref = %q, want the next free checkpoint-<n>", receivedRef)
+	}
+	if receivedMessage == "" {
+		t.Error("CreateCheckpoint() message should default to one naming the checkpoint")
+	}
+	if response.Checkpoint.Name != "checkpoint-4" {
+		t.Errorf("Checkpoint.Name = %q, want %q", response.Checkpoint.Name, "checkpoint-4")
+	}
+}
+
+func TestCreateCheckpointUseCase_Execute_InvalidName(t *testing.T) {
+	// arrange
+	useCase := NewCreateCheckpointUseCase(&mockGitOperations{}, setupCheckpointTest(t))
+
+	// act
+	_, err := useCase.Execute(context.Background(), CreateCheckpointRequest{SessionID: "test-session", Name: "../escape"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for an invalid checkpoint name")
+	}
+}
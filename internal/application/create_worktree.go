@@ -4,38 +4,47 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/tzDel/orchestragent-mcp/internal/domain"
 )
 
 type CreateWorktreeRequest struct {
 	SessionID string
+	BaseRef   string
+	// ParentSessionID stacks the new session on the parent session's branch;
+	// it cannot be combined with BaseRef
+	ParentSessionID string
 }
 
 type CreateWorktreeResponse struct {
-	SessionID    string
-	WorktreePath string
-	BranchName   string
-	Status       string
+	SessionID       string
+	WorktreePath    string
+	BranchName      string
+	BaseRef         string
+	BaseCommit      string
+	Status          string
+	ParentSessionID string
 }
 
 type CreateWorktreeUseCase struct {
 	gitOperations     domain.GitOperations
 	sessionRepository domain.SessionRepository
-	repositoryRoot    string
 	worktreeDirectory string
+	baseBranch        string
 }
 
 func NewCreateWorktreeUseCase(
 	gitOperations domain.GitOperations,
 	sessionRepository domain.SessionRepository,
-	repositoryRoot string,
+	worktreeDirectory string,
+	baseBranch string,
 ) *CreateWorktreeUseCase {
 	return &CreateWorktreeUseCase{
 		gitOperations:     gitOperations,
 		sessionRepository: sessionRepository,
-		repositoryRoot:    repositoryRoot,
-		worktreeDirectory: filepath.Join(repositoryRoot, ".worktrees"),
+		worktreeDirectory: worktreeDirectory,
+		baseBranch:        baseBranch,
 	}
 }
 
@@ -53,13 +62,27 @@ func (createWorktreeUseCase *CreateWorktreeUseCase) Execute(ctx context.Context,
 		return nil, err
 	}
 
+	parent, err := createWorktreeUseCase.findParent(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	requestedBaseRef := request.BaseRef
+	if parent != nil {
+		requestedBaseRef = parent.BranchName()
+	}
+	baseRef, baseCommit, err := createWorktreeUseCase.resolveBaseRef(ctx, requestedBaseRef)
+	if err != nil {
+		return nil, err
+	}
+
 	worktreePath := createWorktreeUseCase.buildWorktreePath(sessionID)
 
-	if err := createWorktreeUseCase.createWorktreeAndBranch(ctx, worktreePath, sessionID.BranchName()); err != nil {
+	if err := createWorktreeUseCase.createWorktreeAndBranch(ctx, worktreePath, sessionID.BranchName(), baseCommit); err != nil {
 		return nil, err
 	}
 
-	session, err := createWorktreeUseCase.createAndSaveSession(ctx, sessionID, worktreePath)
+	session, err := createWorktreeUseCase.createAndSaveSession(ctx, sessionID, worktreePath, baseRef, baseCommit, parent)
 	if err != nil {
 		return nil, err
 	}
@@ -97,23 +120,74 @@ func (createWorktreeUseCase *CreateWorktreeUseCase) ensureBranchDoesNotExist(ctx
 	return nil
 }
 
+// findParent loads the session a new session is to be stacked on, or returns
+// nil when no parent was requested
+func (createWorktreeUseCase *CreateWorktreeUseCase) findParent(ctx context.Context, request CreateWorktreeRequest) (*domain.Session, error) {
+	if strings.TrimSpace(request.ParentSessionID) == "" {
+		return nil, nil
+	}
+	if strings.TrimSpace(request.BaseRef) != "" {
+		return nil, fmt.Errorf("base ref and parent session cannot both be set")
+	}
+
+	parent, err := findSession(ctx, createWorktreeUseCase.sessionRepository, request.ParentSessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parent session: %w", err)
+	}
+	if parent.Status() == domain.StatusMerged {
+		return nil, fmt.Errorf("parent session %s is already merged", parent.ID())
+	}
+	return parent, nil
+}
+
+// resolveBaseRef falls back to the configured base branch when no base ref is
+// requested and pins the commit the ref currently points at, so the session
+// is always measured against the state it was started from
+func (createWorktreeUseCase *CreateWorktreeUseCase) resolveBaseRef(ctx context.Context, requestedBaseRef string) (string, string, error) {
+	baseRef := strings.TrimSpace(requestedBaseRef)
+	if baseRef == "" {
+		baseRef = createWorktreeUseCase.baseBranch
+	}
+
+	if strings.HasPrefix(baseRef, "-") {
+		return "", "", fmt.Errorf("invalid base ref: %s", baseRef)
+	}
+
+	baseCommit, err := createWorktreeUseCase.gitOperations.ResolveCommit(ctx, baseRef)
+	if err != nil {
+		return "", "", fmt.Errorf("base ref not found: %s: %w", baseRef, err)
+	}
+
+	return baseRef, baseCommit, nil
+}
+
 func (createWorktreeUseCase *CreateWorktreeUseCase) buildWorktreePath(sessionID domain.SessionID) string {
 	return filepath.Join(createWorktreeUseCase.worktreeDirectory, sessionID.WorktreeDirName())
 }
 
-func (createWorktreeUseCase *CreateWorktreeUseCase) createWorktreeAndBranch(ctx context.Context, worktreePath string, branchName string) error {
-	if err := createWorktreeUseCase.gitOperations.CreateWorktree(ctx, worktreePath, branchName); err != nil {
+func (createWorktreeUseCase *CreateWorktreeUseCase) createWorktreeAndBranch(ctx context.Context, worktreePath string, branchName string, startPoint string) error {
+	if err := createWorktreeUseCase.gitOperations.CreateWorktree(ctx, worktreePath, branchName, startPoint); err != nil {
 		return fmt.Errorf("failed to create worktree: %w", err)
 	}
 	return nil
 }
 
-func (createWorktreeUseCase *CreateWorktreeUseCase) createAndSaveSession(ctx context.Context, sessionID domain.SessionID, worktreePath string) (*domain.Session, error) {
-	session, err := domain.NewSession(sessionID, worktreePath)
+func (createWorktreeUseCase *CreateWorktreeUseCase) createAndSaveSession(ctx context.Context, sessionID domain.SessionID, worktreePath string, baseRef string, baseCommit string, parent *domain.Session) (*domain.Session, error) {
+	session, err := domain.NewSession(sessionID, worktreePath, baseRef)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
+	if err := session.SetBaseCommit(baseCommit); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	if parent != nil {
+		if err := session.SetParentSessionID(parent.ID()); err != nil {
+			return nil, fmt.Errorf("failed to create session: %w", err)
+		}
+	}
+
 	if err := createWorktreeUseCase.sessionRepository.Save(ctx, session); err != nil {
 		return nil, fmt.Errorf("failed to save session: %w", err)
 	}
@@ -123,9 +197,12 @@ func (createWorktreeUseCase *CreateWorktreeUseCase) createAndSaveSession(ctx con
 
 func (createWorktreeUseCase *CreateWorktreeUseCase) buildResponse(session *domain.Session) *CreateWorktreeResponse {
 	return &CreateWorktreeResponse{
-		SessionID:    session.ID().String(),
-		WorktreePath: session.WorktreePath(),
-		BranchName:   session.BranchName(),
-		Status:       string(session.Status()),
+		SessionID:       session.ID().String(),
+		WorktreePath:    session.WorktreePath(),
+		BranchName:      session.BranchName(),
+		BaseRef:         session.BaseRef(),
+		BaseCommit:      session.BaseCommit(),
+		Status:          string(session.Status()),
+		ParentSessionID: session.ParentSessionID().String(),
 	}
 }
@@ -17,7 +17,7 @@ func setupCreateWorktreeUseCase(gitOps *mockGitOperations) (*CreateWorktreeUseCa
 	}
 
 	sessionRepository := newMockSessionRepository()
-	useCase := NewCreateWorktreeUseCase(gitOps, sessionRepository, testRepositoryRoot)
+	useCase := NewCreateWorktreeUseCase(gitOps, sessionRepository, filepath.Join(testRepositoryRoot, ".worktrees"), "main")
 	return useCase, sessionRepository
 }
 
@@ -71,7 +71,7 @@ func TestCreateWorktreeUseCase_Execute_SessionAlreadyExists(t *testing.T) {
 	createWorktreeUseCase, sessionRepository := setupCreateWorktreeUseCase(nil)
 
 	sessionID, _ := domain.NewSessionID("test-session")
-	session, _ := domain.NewSession(sessionID, "/path")
+	session, _ := domain.NewSession(sessionID, "/path", "")
 	sessionRepository.Save(context.Background(), session)
 
 	request := CreateWorktreeRequest{SessionID: "test-session"}
@@ -109,7 +109,7 @@ func TestCreateWorktreeUseCase_Execute_BranchAlreadyExists(t *testing.T) {
 func TestCreateWorktreeUseCase_Execute_GitOperationFails(t *testing.T) {
 	// arrange
 	gitOperations := &mockGitOperations{
-		createWorktreeFunc: func(ctx context.Context, path string, branch string) error {
+		createWorktreeFunc: func(ctx context.Context, path string, branch string, baseRef string) error {
 			return errors.New("git error")
 		},
 	}
@@ -126,6 +126,166 @@ func TestCreateWorktreeUseCase_Execute_GitOperationFails(t *testing.T) {
 	}
 }
 
+func TestCreateWorktreeUseCase_Execute_WithoutBaseRef_UsesConfiguredBaseBranch(t *testing.T) {
+	// arrange
+	mainCommit := "1111111111111111111111111111111111111111"
+	var resolvedRef, receivedStartPoint string
+	gitOperations := &mockGitOperations{
+		resolveCommitFunc: func(ctx context.Context, ref string) (string, error) {
+			resolvedRef = ref
+			return mainCommit, nil
+		},
+		createWorktreeFunc: func(ctx context.Context, path string, branch string, startPoint string) error {
+			receivedStartPoint = startPoint
+			return nil
+		},
+	}
+	createWorktreeUseCase, sessionRepository := setupCreateWorktreeUseCase(gitOperations)
+	request := CreateWorktreeRequest{SessionID: "test-session"}
+	ctx := context.Background()
+
+	// act
+	response, err := createWorktreeUseCase.Execute(ctx, request)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if resolvedRef != "main" {
+		t.Errorf("ResolveCommit() ref = %q, want %q", resolvedRef, "main")
+	}
+	if receivedStartPoint != mainCommit {
+		t.Errorf("CreateWorktree() start point = %q, want %q", receivedStartPoint, mainCommit)
+	}
+	if response.BaseRef != "main" {
+		t.Errorf("BaseRef = %q, want %q", response.BaseRef, "main")
+	}
+	if sessionRepository.sessions["test-session"].BaseRef() != "main" {
+		t.Errorf("saved session BaseRef = %q, want %q", sessionRepository.sessions["test-session"].BaseRef(), "main")
+	}
+}
+
+func TestCreateWorktreeUseCase_Execute_WithBaseRef_PinsBaseCommit(t *testing.T) {
+	// arrange
+	releaseCommit := "2222222222222222222222222222222222222222"
+	var receivedStartPoint string
+	gitOperations := &mockGitOperations{
+		resolveCommitFunc: func(ctx context.Context, ref string) (string, error) {
+			if ref != "release/1.2" {
+				return "", errors.New("unexpected ref")
+			}
+			return releaseCommit, nil
+		},
+		createWorktreeFunc: func(ctx context.Context, path string, branch string, startPoint string) error {
+			receivedStartPoint = startPoint
+			return nil
+		},
+	}
+	createWorktreeUseCase, sessionRepository := setupCreateWorktreeUseCase(gitOperations)
+	request := CreateWorktreeRequest{SessionID: "test-session", BaseRef: "release/1.2"}
+	ctx := context.Background()
+
+	// act
+	response, err := createWorktreeUseCase.Execute(ctx, request)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if receivedStartPoint != releaseCommit {
+		t.Errorf("CreateWorktree() start point = %q, want %q", receivedStartPoint, releaseCommit)
+	}
+	if response.BaseRef != "release/1.2" {
+		t.Errorf("BaseRef = %q, want %q", response.BaseRef, "release/1.2")
+	}
+	if response.BaseCommit != releaseCommit {
+		t.Errorf("BaseCommit = %q, want %q", response.BaseCommit, releaseCommit)
+	}
+	savedSession := sessionRepository.sessions["test-session"]
+	if savedSession.BaseRef() != "release/1.2" {
+		t.Errorf("saved session BaseRef = %q, want %q", savedSession.BaseRef(), "release/1.2")
+	}
+	if savedSession.BaseCommit() != releaseCommit {
+		t.Errorf("saved session BaseCommit = %q, want %q", savedSession.BaseCommit(), releaseCommit)
+	}
+}
+
+func TestCreateWorktreeUseCase_Execute_WithParentSession_StacksOnParentBranch(t *testing.T) {
+	// arrange
+	parentTip := "3333333333333333333333333333333333333333"
+	var resolvedRef string
+	gitOperations := &mockGitOperations{
+		resolveCommitFunc: func(ctx context.Context, ref string) (string, error) {
+			resolvedRef = ref
+			return parentTip, nil
+		},
+	}
+	createWorktreeUseCase, sessionRepository := setupCreateWorktreeUseCase(gitOperations)
+	parentID, _ := domain.NewSessionID("parent-session")
+	parent, _ := domain.NewSession(parentID, "/repo/root/.worktrees/orchestragent-parent-session", "main")
+	sessionRepository.Save(context.Background(), parent)
+	request := CreateWorktreeRequest{SessionID: "test-session", ParentSessionID: "parent-session"}
+
+	// act
+	response, err := createWorktreeUseCase.Execute(context.Background(), request)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if resolvedRef != parent.BranchName() || response.BaseRef != parent.BranchName() || response.BaseCommit != parentTip {
+		t.Errorf("response = %+v, want base %s at %s", response, parent.BranchName(), parentTip)
+	}
+	if savedSession := sessionRepository.sessions["test-session"]; !savedSession.IsStackedOn(parent) {
+		t.Error("saved session should be stacked on the parent session")
+	}
+}
+
+func TestCreateWorktreeUseCase_Execute_WithParentSessionAndBaseRef_ReturnsError(t *testing.T) {
+	// arrange
+	createWorktreeUseCase, sessionRepository := setupCreateWorktreeUseCase(nil)
+	parentID, _ := domain.NewSessionID("parent-session")
+	parent, _ := domain.NewSession(parentID, "/repo/root/.worktrees/orchestragent-parent-session", "main")
+	sessionRepository.Save(context.Background(), parent)
+	request := CreateWorktreeRequest{SessionID: "test-session", ParentSessionID: "parent-session", BaseRef: "main"}
+
+	// act
+	_, err := createWorktreeUseCase.Execute(context.Background(), request)
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error when both base ref and parent session are set")
+	}
+}
+
+func TestCreateWorktreeUseCase_Execute_UnknownBaseRef_ReturnsError(t *testing.T) {
+	// arrange
+	worktreeCreated := false
+	gitOperations := &mockGitOperations{
+		resolveCommitFunc: func(ctx context.Context, ref string) (string, error) {
+			return "", errors.New("unknown revision")
+		},
+		createWorktreeFunc: func(ctx context.Context, path string, branch string, baseRef string) error {
+			worktreeCreated = true
+			return nil
+		},
+	}
+	createWorktreeUseCase, _ := setupCreateWorktreeUseCase(gitOperations)
+	request := CreateWorktreeRequest{SessionID: "test-session", BaseRef: "v9.9.9"}
+	ctx := context.Background()
+
+	// act
+	_, err := createWorktreeUseCase.Execute(ctx, request)
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for unknown base ref")
+	}
+	if worktreeCreated {
+		t.Error("Execute() should not create a worktree for an unknown base ref")
+	}
+}
+
 func TestCreateWorktreeUseCase_ValidateSessionID_WithValidID_ReturnsSessionID(t *testing.T) {
 	// arrange
 	createWorktreeUseCase, _ := setupCreateWorktreeUseCase(nil)
@@ -176,7 +336,7 @@ func TestCreateWorktreeUseCase_EnsureSessionDoesNotExist_WhenSessionExists_Retur
 	// arrange
 	createWorktreeUseCase, sessionRepository := setupCreateWorktreeUseCase(nil)
 	sessionID, _ := domain.NewSessionID("test-session")
-	session, _ := domain.NewSession(sessionID, "/path")
+	session, _ := domain.NewSession(sessionID, "/path", "")
 	sessionRepository.Save(context.Background(), session)
 	ctx := context.Background()
 
@@ -253,7 +413,7 @@ func TestCreateWorktreeUseCase_CreateWorktreeAndBranch_Success_ReturnsNoError(t
 	ctx := context.Background()
 
 	// act
-	err := createWorktreeUseCase.createWorktreeAndBranch(ctx, worktreePath, branchName)
+	err := createWorktreeUseCase.createWorktreeAndBranch(ctx, worktreePath, branchName, "main")
 
 	// assert
 	if err != nil {
@@ -264,7 +424,7 @@ func TestCreateWorktreeUseCase_CreateWorktreeAndBranch_Success_ReturnsNoError(t
 func TestCreateWorktreeUseCase_CreateWorktreeAndBranch_GitOperationFails_ReturnsError(t *testing.T) {
 	// arrange
 	gitOperations := &mockGitOperations{
-		createWorktreeFunc: func(ctx context.Context, path string, branch string) error {
+		createWorktreeFunc: func(ctx context.Context, path string, branch string, baseRef string) error {
 			return errors.New("git error")
 		},
 	}
@@ -274,7 +434,7 @@ func TestCreateWorktreeUseCase_CreateWorktreeAndBranch_GitOperationFails_Returns
 	ctx := context.Background()
 
 	// act
-	err := createWorktreeUseCase.createWorktreeAndBranch(ctx, worktreePath, branchName)
+	err := createWorktreeUseCase.createWorktreeAndBranch(ctx, worktreePath, branchName, "main")
 
 	// assert
 	if err == nil {
@@ -291,7 +451,7 @@ func TestCreateWorktreeUseCase_CreateAndSaveSession_Success_ReturnsSession(t *te
 	ctx := context.Background()
 
 	// act
-	session, err := createWorktreeUseCase.createAndSaveSession(ctx, sessionID, worktreePath)
+	session, err := createWorktreeUseCase.createAndSaveSession(ctx, sessionID, worktreePath, "main", "0123456789abcdef0123456789abcdef01234567", nil)
 
 	// assert
 	if err != nil {
@@ -312,7 +472,7 @@ func TestCreateWorktreeUseCase_BuildResponse_ReturnsCorrectResponse(t *testing.T
 	expectedBranchName := "orchestragent-test-session"
 	expectedStatus := "open"
 	sessionID, _ := domain.NewSessionID(expectedSessionID)
-	session, _ := domain.NewSession(sessionID, "/repo/root/.worktrees/orchestragent-test-session")
+	session, _ := domain.NewSession(sessionID, "/repo/root/.worktrees/orchestragent-test-session", "")
 
 	// act
 	response := createWorktreeUseCase.buildResponse(session)
@@ -0,0 +1,55 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type DeleteFileRequest struct {
+	SessionID string
+	Path      string
+}
+
+type DeleteFileResponse struct {
+	SessionID string `json:"sessionId"`
+	Path      string `json:"path"`
+}
+
+type DeleteFileUseCase struct {
+	worktreeFiles     domain.WorktreeFiles
+	sessionRepository domain.SessionRepository
+}
+
+func NewDeleteFileUseCase(
+	worktreeFiles domain.WorktreeFiles,
+	sessionRepository domain.SessionRepository,
+) *DeleteFileUseCase {
+	return &DeleteFileUseCase{
+		worktreeFiles:     worktreeFiles,
+		sessionRepository: sessionRepository,
+	}
+}
+
+func (deleteFileUseCase *DeleteFileUseCase) Execute(
+	ctx context.Context,
+	request DeleteFileRequest,
+) (*DeleteFileResponse, error) {
+	session, err := findSession(ctx, deleteFileUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status() == domain.StatusMerged {
+		return nil, fmt.Errorf("session %s is already merged", session.ID())
+	}
+
+	if err := deleteFileUseCase.worktreeFiles.DeleteFile(ctx, session.WorktreePath(), request.Path); err != nil {
+		return nil, fmt.Errorf("failed to delete file: %w", err)
+	}
+
+	return &DeleteFileResponse{
+		SessionID: session.ID().String(),
+		Path:      request.Path,
+	}, nil
+}
@@ -0,0 +1,42 @@
+package application
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDeleteFileUseCase_Execute_DeletesInSessionWorktree(t *testing.T) {
+	// arrange
+	var deletedFrom, deletedPath string
+	worktreeFiles := &mockWorktreeFiles{
+		deleteFileFunc: func(ctx context.Context, worktreePath string, path string) error {
+			deletedFrom, deletedPath = worktreePath, path
+			return nil
+		},
+	}
+	useCase := NewDeleteFileUseCase(worktreeFiles, setupFileSession(t, "test-session", false))
+
+	// act
+	_, err := useCase.Execute(context.Background(), DeleteFileRequest{SessionID: "test-session", Path: "old.go"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if deletedFrom != "/worktrees/test-session" || deletedPath != "old.go" {
+		t.Errorf("deleted %s in %s, want old.go in the session worktree", deletedPath, deletedFrom)
+	}
+}
+
+func TestDeleteFileUseCase_Execute_MergedSession_ReturnsError(t *testing.T) {
+	// arrange
+	useCase := NewDeleteFileUseCase(&mockWorktreeFiles{}, setupFileSession(t, "test-session", true))
+
+	// act
+	_, err := useCase.Execute(context.Background(), DeleteFileRequest{SessionID: "test-session", Path: "old.go"})
+
+	// assert
+	if err == nil {
+		t.Fatal("Execute() expected error for a merged session")
+	}
+}
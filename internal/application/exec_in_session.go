@@ -0,0 +1,119 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type ExecInSessionRequest struct {
+	SessionID string
+	Command   string
+	Args      []string
+	// Env sets extra environment variables; names the policy does not allow
+	// callers to set are refused
+	Env map[string]string
+	// TimeoutSeconds shortens the policy's timeout; zero uses it as is
+	TimeoutSeconds int
+}
+
+type ExecInSessionResponse struct {
+	SessionID       string   `json:"sessionId"`
+	Command         string   `json:"command"`
+	Args            []string `json:"args"`
+	ExitCode        int      `json:"exitCode"`
+	Stdout          string   `json:"stdout"`
+	Stderr          string   `json:"stderr"`
+	StdoutTruncated bool     `json:"stdoutTruncated"`
+	StderrTruncated bool     `json:"stderrTruncated"`
+	TimedOut        bool     `json:"timedOut"`
+	DurationMs      int64    `json:"durationMs"`
+}
+
+type ExecInSessionUseCase struct {
+	commandRunner     domain.CommandRunner
+	sessionRepository domain.SessionRepository
+	policy            *domain.CommandPolicy
+}
+
+func NewExecInSessionUseCase(
+	commandRunner domain.CommandRunner,
+	sessionRepository domain.SessionRepository,
+	policy *domain.CommandPolicy,
+) *ExecInSessionUseCase {
+	return &ExecInSessionUseCase{
+		commandRunner:     commandRunner,
+		sessionRepository: sessionRepository,
+		policy:            policy,
+	}
+}
+
+// Execute runs an allowed command in the session worktree. A command that
+// runs but fails or times out is reported in the response, not as an error.
+func (execInSessionUseCase *ExecInSessionUseCase) Execute(
+	ctx context.Context,
+	request ExecInSessionRequest,
+) (*ExecInSessionResponse, error) {
+	session, err := findSession(ctx, execInSessionUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status() == domain.StatusMerged {
+		return nil, fmt.Errorf("session %s is already merged", session.ID())
+	}
+
+	policy := execInSessionUseCase.policy
+	if err := policy.Check(request.Command, request.Args); err != nil {
+		return nil, err
+	}
+	for name := range request.Env {
+		if name == "" || strings.ContainsAny(name, "=\x00") {
+			return nil, fmt.Errorf("invalid environment variable name %q", name)
+		}
+		if !policy.EnvSettable(name) {
+			return nil, fmt.Errorf("%w: environment variable %s", domain.ErrCommandNotAllowed, name)
+		}
+	}
+	if request.TimeoutSeconds < 0 {
+		return nil, errors.New("timeout must not be negative")
+	}
+
+	timeout := policy.Timeout
+	if requested := time.Duration(request.TimeoutSeconds) * time.Second; requested > 0 && requested < timeout {
+		timeout = requested
+	}
+
+	result, err := execInSessionUseCase.commandRunner.Run(ctx, domain.CommandSpec{
+		WorktreePath:   session.WorktreePath(),
+		Command:        request.Command,
+		Args:           request.Args,
+		Env:            request.Env,
+		DeniedEnv:      policy.DeniedEnv,
+		Timeout:        timeout,
+		MaxOutputBytes: policy.MaxOutputBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to run command: %w", err)
+	}
+
+	args := request.Args
+	if args == nil {
+		args = []string{}
+	}
+	return &ExecInSessionResponse{
+		SessionID:       session.ID().String(),
+		Command:         request.Command,
+		Args:            args,
+		ExitCode:        result.ExitCode,
+		Stdout:          strings.ToValidUTF8(string(result.Stdout), "�"),
+		Stderr:          strings.ToValidUTF8(string(result.Stderr), "�"),
+		StdoutTruncated: result.StdoutTruncated,
+		StderrTruncated: result.StderrTruncated,
+		TimedOut:        result.TimedOut,
+		DurationMs:      result.Duration.Milliseconds(),
+	}, nil
+}
@@ -0,0 +1,117 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func newTestCommandPolicy(t *testing.T) *domain.CommandPolicy {
+	t.Helper()
+
+	goRule, err := domain.NewCommandRule("go", []string{"test", `\./\.\.\.`})
+	if err != nil {
+		t.Fatalf("NewCommandRule() error: %v", err)
+	}
+	return &domain.CommandPolicy{
+		Rules:          []domain.CommandRule{goRule},
+		AllowedEnv:     []string{"CGO_ENABLED", "*_TOKEN", "LD_*", "GOFLAGS"},
+		DeniedEnv:      []string{"*_TOKEN"},
+		Timeout:        time.Minute,
+		MaxOutputBytes: 4096,
+	}
+}
+
+func TestExecInSessionUseCase_Execute_RunsAllowedCommandInWorktree(t *testing.T) {
+	// arrange
+	var ranSpec domain.CommandSpec
+	commandRunner := &mockCommandRunner{
+		runFunc: func(ctx context.Context, spec domain.CommandSpec) (*domain.CommandResult, error) {
+			ranSpec = spec
+			return &domain.CommandResult{ExitCode: 1, Stdout: []byte("FAIL\xff\n"), StderrTruncated: true, Duration: 1500 * time.Millisecond}, nil
+		},
+	}
+	useCase := NewExecInSessionUseCase(commandRunner, setupFileSession(t, "test-session", false), newTestCommandPolicy(t))
+
+	// act
+	response, err := useCase.Execute(context.Background(), ExecInSessionRequest{
+		SessionID:      "test-session",
+		Command:        "go",
+		Args:           []string{"test", "./..."},
+		Env:            map[string]string{"CGO_ENABLED": "0"},
+		TimeoutSeconds: 30,
+	})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if ranSpec.WorktreePath != "/worktrees/test-session" || ranSpec.Timeout != 30*time.Second || ranSpec.MaxOutputBytes != 4096 || len(ranSpec.DeniedEnv) != 1 {
+		t.Errorf("spec = %+v, want the session worktree with the requested timeout and the policy's limits", ranSpec)
+	}
+	if response.ExitCode != 1 || response.Stdout != "FAIL�\n" || !response.StderrTruncated || response.DurationMs != 1500 {
+		t.Errorf("response = %+v, want the failed run", response)
+	}
+}
+
+func TestExecInSessionUseCase_Execute_TimeoutIsCappedByPolicy(t *testing.T) {
+	// arrange
+	var ranTimeout time.Duration
+	commandRunner := &mockCommandRunner{
+		runFunc: func(ctx context.Context, spec domain.CommandSpec) (*domain.CommandResult, error) {
+			ranTimeout = spec.Timeout
+			return &domain.CommandResult{}, nil
+		},
+	}
+	useCase := NewExecInSessionUseCase(commandRunner, setupFileSession(t, "test-session", false), newTestCommandPolicy(t))
+
+	// act
+	_, err := useCase.Execute(context.Background(), ExecInSessionRequest{SessionID: "test-session", Command: "go", Args: []string{"test"}, TimeoutSeconds: 3600})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if ranTimeout != time.Minute {
+		t.Errorf("timeout = %v, want the policy's minute", ranTimeout)
+	}
+}
+
+func TestExecInSessionUseCase_Execute_RefusesWhatThePolicyDenies(t *testing.T) {
+	testCases := []struct {
+		name    string
+		request ExecInSessionRequest
+	}{
+		{"unknown command", ExecInSessionRequest{SessionID: "test-session", Command: "sh", Args: []string{"-c", "id"}}},
+		{"argument outside patterns", ExecInSessionRequest{SessionID: "test-session", Command: "go", Args: []string{"test", "-exec=sh"}}},
+		{"denied environment variable", ExecInSessionRequest{SessionID: "test-session", Command: "go", Args: []string{"test"}, Env: map[string]string{"FORGE_TOKEN": "x"}}},
+		{"environment variable not allowed", ExecInSessionRequest{SessionID: "test-session", Command: "go", Args: []string{"test"}, Env: map[string]string{"GOPROXY": "https://proxy.example.com"}}},
+		{"toolexec through GOFLAGS", ExecInSessionRequest{SessionID: "test-session", Command: "go", Args: []string{"test"}, Env: map[string]string{"GOFLAGS": "-toolexec=/tmp/evil"}}},
+		{"preloaded library", ExecInSessionRequest{SessionID: "test-session", Command: "go", Args: []string{"test"}, Env: map[string]string{"LD_PRELOAD": "/tmp/evil.so"}}},
+		{"replaced PATH", ExecInSessionRequest{SessionID: "test-session", Command: "go", Args: []string{"test"}, Env: map[string]string{"PATH": "/tmp/evil"}}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// arrange
+			commandRunner := &mockCommandRunner{
+				runFunc: func(ctx context.Context, spec domain.CommandSpec) (*domain.CommandResult, error) {
+					t.Error("Run should not be called")
+					return nil, nil
+				},
+			}
+			useCase := NewExecInSessionUseCase(commandRunner, setupFileSession(t, "test-session", false), newTestCommandPolicy(t))
+
+			// act
+			_, err := useCase.Execute(context.Background(), testCase.request)
+
+			// assert
+			if !errors.Is(err, domain.ErrCommandNotAllowed) {
+				t.Errorf("Execute() error = %v, want ErrCommandNotAllowed", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type ExportSessionRequest struct {
+	SessionID string
+	// Format is bundle or patches; empty means bundle
+	Format string
+	// OutputPath names the directory to create for the export inside the
+	// export directory; it must not exist
+	OutputPath string
+}
+
+type ExportSessionResponse struct {
+	SessionID    string       `json:"sessionId"`
+	Format       ExportFormat `json:"format"`
+	OutputPath   string       `json:"outputPath"`
+	ManifestPath string       `json:"manifestPath"`
+	// Files lists the bundle or patch files written next to the manifest
+	Files      []string `json:"files"`
+	BaseCommit string   `json:"baseCommit,omitempty"`
+	HeadCommit string   `json:"headCommit"`
+	Commits    int      `json:"commits"`
+	// UncommittedFiles counts work left out of the export because it was
+	// never committed
+	UncommittedFiles int `json:"uncommittedFiles"`
+}
+
+type ExportSessionUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+	sessionArchives   domain.SessionArchives
+	baseBranch        string
+}
+
+func NewExportSessionUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+	sessionArchives domain.SessionArchives,
+	baseBranch string,
+) *ExportSessionUseCase {
+	return &ExportSessionUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+		sessionArchives:   sessionArchives,
+		baseBranch:        baseBranch,
+	}
+}
+
+// Execute writes the session's commits since its base, as a bundle or a
+// patch series, together with a manifest of the session's metadata into a
+// new directory. Only committed work is exported. A failed export removes
+// the directory again.
+func (exportSessionUseCase *ExportSessionUseCase) Execute(
+	ctx context.Context,
+	request ExportSessionRequest,
+) (*ExportSessionResponse, error) {
+	session, err := findSession(ctx, exportSessionUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	format, err := parseExportFormat(request.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	diffBase := diffBaseFor(session, exportSessionUseCase.baseBranch)
+	commits, err := exportSessionUseCase.gitOperations.GetCommits(ctx, diffBase, session.BranchName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("session %s has no commits to export", session.ID())
+	}
+
+	_, uncommittedFiles, err := exportSessionUseCase.gitOperations.HasUncommittedChanges(ctx, session.WorktreePath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to check uncommitted changes: %w", err)
+	}
+
+	outputPath, err := exportSessionUseCase.sessionArchives.Create(ctx, request.OutputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	response, err := exportSessionUseCase.writeExport(ctx, session, format, request.OutputPath, outputPath, diffBase, commits)
+	if err != nil {
+		exportSessionUseCase.sessionArchives.Remove(ctx, request.OutputPath)
+		return nil, err
+	}
+	response.UncommittedFiles = uncommittedFiles
+	return response, nil
+}
+
+func (exportSessionUseCase *ExportSessionUseCase) writeExport(
+	ctx context.Context,
+	session *domain.Session,
+	format ExportFormat,
+	archiveName string,
+	outputPath string,
+	diffBase string,
+	commits []domain.Commit,
+) (*ExportSessionResponse, error) {
+	manifest := sessionManifest{
+		Version:     sessionArchiveVersion,
+		Format:      format,
+		SessionID:   session.ID().String(),
+		BranchName:  session.BranchName(),
+		BaseRef:     baseRefFor(session, exportSessionUseCase.baseBranch),
+		BaseCommit:  session.BaseCommit(),
+		HeadCommit:  commits[0].SHA,
+		Status:      string(session.Status()),
+		PullRequest: buildPullRequestDTO(session.PullRequest()),
+		Commits:     len(commits),
+		ExportedAt:  time.Now().UTC(),
+	}
+
+	var files []string
+	switch format {
+	case ExportFormatBundle:
+		bundlePath := filepath.Join(outputPath, sessionBundleName)
+		if err := exportSessionUseCase.gitOperations.CreateBundle(ctx, bundlePath, session.BranchName(), session.BaseCommit()); err != nil {
+			return nil, err
+		}
+		files = []string{bundlePath}
+	case ExportFormatPatches:
+		patchPaths, err := exportSessionUseCase.gitOperations.FormatPatches(ctx, filepath.Join(outputPath, sessionPatchesDirName), diffBase, session.BranchName())
+		if err != nil {
+			return nil, err
+		}
+		for _, patchPath := range patchPaths {
+			relativePath, err := filepath.Rel(outputPath, patchPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to record patch %s: %w", patchPath, err)
+			}
+			manifest.Patches = append(manifest.Patches, filepath.ToSlash(relativePath))
+		}
+		files = patchPaths
+	}
+
+	if err := writeSessionManifest(ctx, exportSessionUseCase.sessionArchives, archiveName, manifest); err != nil {
+		return nil, err
+	}
+
+	return &ExportSessionResponse{
+		SessionID:    manifest.SessionID,
+		Format:       format,
+		OutputPath:   outputPath,
+		ManifestPath: filepath.Join(outputPath, sessionManifestName),
+		Files:        files,
+		BaseCommit:   manifest.BaseCommit,
+		HeadCommit:   manifest.HeadCommit,
+		Commits:      manifest.Commits,
+	}, nil
+}
@@ -0,0 +1,125 @@
+package application
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+const exportTestBaseCommit = "0123456789abcdef0123456789abcdef01234567"
+
+func setupExportTest(t *testing.T, gitOperations *mockGitOperations, sessionArchives *mockSessionArchives) *ExportSessionUseCase {
+	t.Helper()
+
+	if gitOperations.getCommitsFunc == nil {
+		gitOperations.getCommitsFunc = func(ctx context.Context, baseRef string, sessionBranch string) ([]domain.Commit, error) {
+			return []domain.Commit{{SHA: "89abcdef0123456789abcdef0123456789abcdef", Subject: "Add feature"}}, nil
+		}
+	}
+
+	sessionRepository := newMockSessionRepository()
+	sessionID, _ := domain.NewSessionID("test-session")
+	session, _ := domain.NewSession(sessionID, "/path/test-session", "develop")
+	session.SetBaseCommit(exportTestBaseCommit)
+	sessionRepository.Save(context.Background(), session)
+
+	return NewExportSessionUseCase(gitOperations, sessionRepository, sessionArchives, "main")
+}
+
+func TestExportSessionUseCase_Execute_Bundle_WritesManifest(t *testing.T) {
+	// arrange
+	var excludedCommit string
+	gitOperations := &mockGitOperations{
+		createBundleFunc: func(ctx context.Context, bundlePath string, branchName string, excluded string) error {
+			excludedCommit = excluded
+			return nil
+		},
+	}
+	sessionArchives := newMockSessionArchives()
+	useCase := setupExportTest(t, gitOperations, sessionArchives)
+
+	// act
+	response, err := useCase.Execute(context.Background(), ExportSessionRequest{SessionID: "test-session", OutputPath: "export"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if response.Format != ExportFormatBundle || len(response.Files) != 1 || response.Files[0] != filepath.Join("/exports/export", sessionBundleName) {
+		t.Errorf("response = %+v, want one bundle file in the archive", response)
+	}
+	if excludedCommit != exportTestBaseCommit {
+		t.Errorf("CreateBundle() excluded %q, want the base commit", excludedCommit)
+	}
+
+	manifest, err := readSessionManifest(context.Background(), sessionArchives, "export")
+	if err != nil {
+		t.Fatalf("readSessionManifest() error: %v", err)
+	}
+	if manifest.SessionID != "test-session" || manifest.BaseRef != "develop" || manifest.HeadCommit != "89abcdef0123456789abcdef0123456789abcdef" {
+		t.Errorf("manifest = %+v, want the session metadata", manifest)
+	}
+}
+
+func TestExportSessionUseCase_Execute_Patches_RecordsRelativePaths(t *testing.T) {
+	// arrange
+	gitOperations := &mockGitOperations{
+		formatPatchesFunc: func(ctx context.Context, outputDirectory string, baseRef string, branchName string) ([]string, error) {
+			return []string{filepath.Join(outputDirectory, "0001-Add-feature.patch")}, nil
+		},
+	}
+	sessionArchives := newMockSessionArchives()
+	useCase := setupExportTest(t, gitOperations, sessionArchives)
+
+	// act
+	_, err := useCase.Execute(context.Background(), ExportSessionRequest{SessionID: "test-session", Format: "patches", OutputPath: "export"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	manifest, _ := readSessionManifest(context.Background(), sessionArchives, "export")
+	if len(manifest.Patches) != 1 || manifest.Patches[0] != "patches/0001-Add-feature.patch" {
+		t.Errorf("Patches = %v, want the patch relative to the export", manifest.Patches)
+	}
+}
+
+func TestExportSessionUseCase_Execute_ExistingOutputPath_ReturnsError(t *testing.T) {
+	// arrange
+	sessionArchives := newMockSessionArchives()
+	sessionArchives.Create(context.Background(), "export")
+	useCase := setupExportTest(t, &mockGitOperations{}, sessionArchives)
+
+	// act
+	_, err := useCase.Execute(context.Background(), ExportSessionRequest{SessionID: "test-session", OutputPath: "export"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for an existing output path")
+	}
+}
+
+func TestExportSessionUseCase_Execute_FailedExport_RemovesOutput(t *testing.T) {
+	// arrange
+	gitOperations := &mockGitOperations{
+		createBundleFunc: func(ctx context.Context, bundlePath string, branchName string, excluded string) error {
+			return os.ErrPermission
+		},
+	}
+	sessionArchives := newMockSessionArchives()
+	useCase := setupExportTest(t, gitOperations, sessionArchives)
+
+	// act
+	_, err := useCase.Execute(context.Background(), ExportSessionRequest{SessionID: "test-session", OutputPath: "export"})
+
+	// assert
+	if err == nil {
+		t.Fatal("Execute() expected error")
+	}
+	if len(sessionArchives.archives) != 0 || len(sessionArchives.removed) != 1 {
+		t.Errorf("archives = %v, removed = %v; want the output directory removed after a failed export", sessionArchives.archives, sessionArchives.removed)
+	}
+}
@@ -0,0 +1,163 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type ForkSessionRequest struct {
+	SessionID       string
+	ParentSessionID string
+	// IncludeUncommitted copies the parent's uncommitted and untracked files
+	// into the new worktree
+	IncludeUncommitted bool
+}
+
+type ForkSessionResponse struct {
+	SessionID        string `json:"sessionId"`
+	ParentSessionID  string `json:"parentSessionId"`
+	WorktreePath     string `json:"worktreePath"`
+	BranchName       string `json:"branchName"`
+	BaseRef          string `json:"baseRef"`
+	BaseCommit       string `json:"baseCommit,omitempty"`
+	HeadCommit       string `json:"headCommit"`
+	Status           string `json:"status"`
+	UncommittedFiles int    `json:"uncommittedFiles"`
+}
+
+type ForkSessionUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+	worktreeDirectory string
+	baseBranch        string
+}
+
+func NewForkSessionUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+	worktreeDirectory string,
+	baseBranch string,
+) *ForkSessionUseCase {
+	return &ForkSessionUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+		worktreeDirectory: worktreeDirectory,
+		baseBranch:        baseBranch,
+	}
+}
+
+// Execute creates a session whose branch starts at the parent session's
+// branch tip. The fork keeps the parent's base ref and base commit, so its
+// diff and merge cover the parent's work as well as its own. If anything
+// fails after the worktree was created, the worktree and branch are removed
+// again.
+func (forkSessionUseCase *ForkSessionUseCase) Execute(
+	ctx context.Context,
+	request ForkSessionRequest,
+) (*ForkSessionResponse, error) {
+	parent, err := findSession(ctx, forkSessionUseCase.sessionRepository, request.ParentSessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID, err := domain.NewSessionID(request.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+	if err := forkSessionUseCase.ensureSessionIsNew(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	uncommittedFiles := 0
+	if request.IncludeUncommitted {
+		_, uncommittedFiles, err = forkSessionUseCase.gitOperations.HasUncommittedChanges(ctx, parent.WorktreePath())
+		if err != nil {
+			return nil, fmt.Errorf("failed to check parent worktree: %w", err)
+		}
+	}
+
+	headCommit, err := forkSessionUseCase.gitOperations.ResolveCommit(ctx, parent.BranchName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve parent branch %s: %w", parent.BranchName(), err)
+	}
+
+	worktreePath := filepath.Join(forkSessionUseCase.worktreeDirectory, sessionID.WorktreeDirName())
+	if err := forkSessionUseCase.gitOperations.CreateWorktree(ctx, worktreePath, sessionID.BranchName(), headCommit); err != nil {
+		return nil, fmt.Errorf("failed to create worktree: %w", err)
+	}
+
+	session, err := forkSessionUseCase.populateAndSave(ctx, parent, sessionID, worktreePath, uncommittedFiles > 0)
+	if err != nil {
+		forkSessionUseCase.gitOperations.RemoveWorktree(ctx, worktreePath, true)
+		forkSessionUseCase.gitOperations.DeleteBranch(ctx, sessionID.BranchName(), true)
+		return nil, err
+	}
+
+	return &ForkSessionResponse{
+		SessionID:        session.ID().String(),
+		ParentSessionID:  parent.ID().String(),
+		WorktreePath:     session.WorktreePath(),
+		BranchName:       session.BranchName(),
+		BaseRef:          baseRefFor(session, forkSessionUseCase.baseBranch),
+		BaseCommit:       session.BaseCommit(),
+		HeadCommit:       headCommit,
+		Status:           string(session.Status()),
+		UncommittedFiles: uncommittedFiles,
+	}, nil
+}
+
+func (forkSessionUseCase *ForkSessionUseCase) ensureSessionIsNew(ctx context.Context, sessionID domain.SessionID) error {
+	exists, err := forkSessionUseCase.sessionRepository.Exists(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to check session existence: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("session already exists: %s", sessionID)
+	}
+
+	branchExists, err := forkSessionUseCase.gitOperations.BranchExists(ctx, sessionID.BranchName())
+	if err != nil {
+		return fmt.Errorf("failed to check branch existence: %w", err)
+	}
+	if branchExists {
+		return fmt.Errorf("branch already exists: %s", sessionID.BranchName())
+	}
+	return nil
+}
+
+// populateAndSave copies the parent's uncommitted work when requested and
+// records the fork with the parent's base and lineage
+func (forkSessionUseCase *ForkSessionUseCase) populateAndSave(
+	ctx context.Context,
+	parent *domain.Session,
+	sessionID domain.SessionID,
+	worktreePath string,
+	copyUncommitted bool,
+) (*domain.Session, error) {
+	if copyUncommitted {
+		if err := forkSessionUseCase.gitOperations.CopyWorktreeChanges(ctx, parent.WorktreePath(), worktreePath); err != nil {
+			return nil, fmt.Errorf("failed to copy uncommitted changes: %w", err)
+		}
+	}
+
+	session, err := domain.NewSession(sessionID, worktreePath, parent.BaseRef())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	if parent.BaseCommit() != "" {
+		if err := session.SetBaseCommit(parent.BaseCommit()); err != nil {
+			return nil, fmt.Errorf("failed to create session: %w", err)
+		}
+	}
+	if err := session.SetParentSessionID(parent.ID()); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	if err := forkSessionUseCase.sessionRepository.Save(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to save session: %w", err)
+	}
+	return session, nil
+}
@@ -0,0 +1,145 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func saveTestParentSession(t *testing.T, sessionRepository *mockSessionRepository) *domain.Session {
+	t.Helper()
+
+	parentID, _ := domain.NewSessionID("parent")
+	parent, _ := domain.NewSession(parentID, "/worktrees/orchestragent-parent", "develop")
+	parent.SetBaseCommit("0123456789abcdef0123456789abcdef01234567")
+	sessionRepository.Save(context.Background(), parent)
+	return parent
+}
+
+func TestForkSessionUseCase_Execute_StartsAtParentTipWithLineage(t *testing.T) {
+	// arrange
+	sessionRepository := newMockSessionRepository()
+	saveTestParentSession(t, sessionRepository)
+	var resolvedRef, worktreeStartPoint string
+	copied := false
+	gitOperations := &mockGitOperations{
+		resolveCommitFunc: func(ctx context.Context, ref string) (string, error) {
+			resolvedRef = ref
+			return "89abcdef0123456789abcdef0123456789abcdef", nil
+		},
+		createWorktreeFunc: func(ctx context.Context, path string, branch string, baseRef string) error {
+			worktreeStartPoint = baseRef
+			return nil
+		},
+		copyWorktreeChangesFunc: func(ctx context.Context, sourceWorktreePath string, targetWorktreePath string) error {
+			copied = true
+			return nil
+		},
+	}
+	useCase := NewForkSessionUseCase(gitOperations, sessionRepository, "/worktrees", "main")
+
+	// act
+	response, err := useCase.Execute(context.Background(), ForkSessionRequest{SessionID: "child", ParentSessionID: "parent"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if resolvedRef != "orchestragent-parent" || worktreeStartPoint != "89abcdef0123456789abcdef0123456789abcdef" {
+		t.Errorf("resolved %q and started worktree at %q, want the parent branch tip", resolvedRef, worktreeStartPoint)
+	}
+	if copied {
+		t.Error("expected uncommitted changes not to be copied unless requested")
+	}
+	if response.ParentSessionID != "parent" || response.BaseRef != "develop" || response.HeadCommit != worktreeStartPoint {
+		t.Errorf("response = %+v, want the parent's base and tip", response)
+	}
+	saved := sessionRepository.sessions["child"]
+	if saved == nil || saved.ParentSessionID().String() != "parent" || saved.BaseCommit() != "0123456789abcdef0123456789abcdef01234567" {
+		t.Errorf("saved session = %+v, want the parent's lineage and base commit", saved)
+	}
+}
+
+func TestForkSessionUseCase_Execute_IncludeUncommitted_CopiesChanges(t *testing.T) {
+	// arrange
+	sessionRepository := newMockSessionRepository()
+	parent := saveTestParentSession(t, sessionRepository)
+	var copiedFrom, copiedTo string
+	gitOperations := &mockGitOperations{
+		hasUncommittedChangesFunc: func(ctx context.Context, worktreePath string) (bool, int, error) {
+			return true, 3, nil
+		},
+		copyWorktreeChangesFunc: func(ctx context.Context, sourceWorktreePath string, targetWorktreePath string) error {
+			copiedFrom, copiedTo = sourceWorktreePath, targetWorktreePath
+			return nil
+		},
+	}
+	useCase := NewForkSessionUseCase(gitOperations, sessionRepository, "/worktrees", "main")
+
+	// act
+	response, err := useCase.Execute(context.Background(), ForkSessionRequest{SessionID: "child", ParentSessionID: "parent", IncludeUncommitted: true})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if copiedFrom != parent.WorktreePath() || copiedTo != response.WorktreePath {
+		t.Errorf("copied %q to %q, want parent worktree to %q", copiedFrom, copiedTo, response.WorktreePath)
+	}
+	if response.UncommittedFiles != 3 {
+		t.Errorf("UncommittedFiles = %d, want 3", response.UncommittedFiles)
+	}
+}
+
+func TestForkSessionUseCase_Execute_CopyFails_RemovesWorktreeAndBranch(t *testing.T) {
+	// arrange
+	sessionRepository := newMockSessionRepository()
+	saveTestParentSession(t, sessionRepository)
+	var removedWorktree, deletedBranch string
+	gitOperations := &mockGitOperations{
+		hasUncommittedChangesFunc: func(ctx context.Context, worktreePath string) (bool, int, error) {
+			return true, 1, nil
+		},
+		copyWorktreeChangesFunc: func(ctx context.Context, sourceWorktreePath string, targetWorktreePath string) error {
+			return errors.New("read-tree failed")
+		},
+		removeWorktreeFunc: func(ctx context.Context, path string, force bool) error {
+			removedWorktree = path
+			return nil
+		},
+		deleteBranchFunc: func(ctx context.Context, branchName string, force bool) error {
+			deletedBranch = branchName
+			return nil
+		},
+	}
+	useCase := NewForkSessionUseCase(gitOperations, sessionRepository, "/worktrees", "main")
+
+	// act
+	_, err := useCase.Execute(context.Background(), ForkSessionRequest{SessionID: "child", ParentSessionID: "parent", IncludeUncommitted: true})
+
+	// assert
+	if err == nil {
+		t.Fatal("Execute() expected error when copying fails")
+	}
+	if removedWorktree != "/worktrees/orchestragent-child" || deletedBranch != "orchestragent-child" {
+		t.Errorf("removed %q and deleted %q, want the fork cleaned up", removedWorktree, deletedBranch)
+	}
+	if _, saved := sessionRepository.sessions["child"]; saved {
+		t.Error("expected no session record for a failed fork")
+	}
+}
+
+func TestForkSessionUseCase_Execute_UnknownParent_ReturnsError(t *testing.T) {
+	// arrange
+	useCase := NewForkSessionUseCase(&mockGitOperations{}, newMockSessionRepository(), "/worktrees", "main")
+
+	// act
+	_, err := useCase.Execute(context.Background(), ForkSessionRequest{SessionID: "child", ParentSessionID: "missing"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for an unknown parent session")
+	}
+}
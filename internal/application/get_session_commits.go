@@ -0,0 +1,140 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type GetSessionCommitsRequest struct {
+	SessionID string
+}
+
+type CommitTrailerDTO struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type CommitFileDTO struct {
+	Path         string `json:"path"`
+	OldPath      string `json:"oldPath,omitempty"`
+	LinesAdded   int    `json:"linesAdded"`
+	LinesRemoved int    `json:"linesRemoved"`
+	Binary       bool   `json:"binary"`
+}
+
+type CommitDTO struct {
+	SHA            string             `json:"sha"`
+	ParentSHAs     []string           `json:"parentShas"`
+	AuthorName     string             `json:"authorName"`
+	AuthorEmail    string             `json:"authorEmail"`
+	AuthoredAt     time.Time          `json:"authoredAt"`
+	CommitterName  string             `json:"committerName"`
+	CommitterEmail string             `json:"committerEmail"`
+	CommittedAt    time.Time          `json:"committedAt"`
+	Subject        string             `json:"subject"`
+	Body           string             `json:"body"`
+	Trailers       []CommitTrailerDTO `json:"trailers"`
+	Files          []CommitFileDTO    `json:"files"`
+	LinesAdded     int                `json:"linesAdded"`
+	LinesRemoved   int                `json:"linesRemoved"`
+}
+
+type GetSessionCommitsResponse struct {
+	SessionID  string      `json:"sessionId"`
+	BranchName string      `json:"branchName"`
+	BaseRef    string      `json:"baseRef"`
+	Commits    []CommitDTO `json:"commits"`
+}
+
+type GetSessionCommitsUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+	baseBranch        string
+}
+
+func NewGetSessionCommitsUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+	baseBranch string,
+) *GetSessionCommitsUseCase {
+	return &GetSessionCommitsUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+		baseBranch:        baseBranch,
+	}
+}
+
+// Execute lists the commits on the session branch that are not yet on its
+// base ref, the same range remove_session counts as unmerged
+func (getSessionCommitsUseCase *GetSessionCommitsUseCase) Execute(
+	ctx context.Context,
+	request GetSessionCommitsRequest,
+) (*GetSessionCommitsResponse, error) {
+	session, err := findSession(ctx, getSessionCommitsUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	baseRef := baseRefFor(session, getSessionCommitsUseCase.baseBranch)
+	commits, err := getSessionCommitsUseCase.gitOperations.GetCommits(ctx, baseRef, session.BranchName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
+	}
+
+	commitDTOs := make([]CommitDTO, 0, len(commits))
+	for _, commit := range commits {
+		commitDTOs = append(commitDTOs, buildCommitDTO(commit))
+	}
+
+	return &GetSessionCommitsResponse{
+		SessionID:  session.ID().String(),
+		BranchName: session.BranchName(),
+		BaseRef:    baseRef,
+		Commits:    commitDTOs,
+	}, nil
+}
+
+func buildCommitDTO(commit domain.Commit) CommitDTO {
+	stats := domain.NewGitDiffStats(commit.Files)
+
+	trailers := make([]CommitTrailerDTO, 0, len(commit.Trailers))
+	for _, trailer := range commit.Trailers {
+		trailers = append(trailers, CommitTrailerDTO{Key: trailer.Key, Value: trailer.Value})
+	}
+
+	files := make([]CommitFileDTO, 0, len(commit.Files))
+	for _, file := range commit.Files {
+		files = append(files, CommitFileDTO{
+			Path:         file.Path,
+			OldPath:      file.OldPath,
+			LinesAdded:   file.LinesAdded,
+			LinesRemoved: file.LinesRemoved,
+			Binary:       file.Binary,
+		})
+	}
+
+	parentSHAs := commit.ParentSHAs
+	if parentSHAs == nil {
+		parentSHAs = []string{}
+	}
+
+	return CommitDTO{
+		SHA:            commit.SHA,
+		ParentSHAs:     parentSHAs,
+		AuthorName:     commit.AuthorName,
+		AuthorEmail:    commit.AuthorEmail,
+		AuthoredAt:     commit.AuthoredAt,
+		CommitterName:  commit.CommitterName,
+		CommitterEmail: commit.CommitterEmail,
+		CommittedAt:    commit.CommittedAt,
+		Subject:        commit.Subject,
+		Body:           commit.Body,
+		Trailers:       trailers,
+		Files:          files,
+		LinesAdded:     stats.LinesAdded,
+		LinesRemoved:   stats.LinesRemoved,
+	}
+}
@@ -0,0 +1,80 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func TestGetSessionCommitsUseCase_Execute_SessionNotFound(t *testing.T) {
+	// arrange
+	useCase := NewGetSessionCommitsUseCase(&mockGitOperations{}, newMockSessionRepository(), "main")
+
+	// act
+	_, err := useCase.Execute(context.Background(), GetSessionCommitsRequest{SessionID: "nonexistent"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for non-existent session")
+	}
+}
+
+func TestGetSessionCommitsUseCase_Execute_ListsCommitsSinceBaseRef(t *testing.T) {
+	// arrange
+	authoredAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var receivedBaseRef, receivedBranch string
+	gitOperations := &mockGitOperations{
+		getCommitsFunc: func(ctx context.Context, baseRef string, sessionBranch string) ([]domain.Commit, error) {
+			receivedBaseRef = baseRef
+			receivedBranch = sessionBranch
+			return []domain.Commit{
+				{
+					SHA:        "abc123",
+					AuthorName: "Agent",
+					AuthoredAt: authoredAt,
+					Subject:    "Add feature",
+					Body:       "Explains why.\n\nSigned-off-by: Agent <agent@example.com>",
+					Trailers:   []domain.CommitTrailer{{Key: "Signed-off-by", Value: "Agent <agent@example.com>"}},
+					Files: []domain.FileChange{
+						{Path: "feature.go", LinesAdded: 10, LinesRemoved: 2},
+						{Path: "feature_test.go", LinesAdded: 5},
+					},
+				},
+			}, nil
+		},
+	}
+	sessionRepository := newMockSessionRepository()
+	sessionID, _ := domain.NewSessionID("test-session")
+	session, _ := domain.NewSession(sessionID, "/path/test-session", "release/1.0")
+	sessionRepository.Save(context.Background(), session)
+	useCase := NewGetSessionCommitsUseCase(gitOperations, sessionRepository, "main")
+
+	// act
+	response, err := useCase.Execute(context.Background(), GetSessionCommitsRequest{SessionID: "test-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if receivedBaseRef != "release/1.0" {
+		t.Errorf("GetCommits() baseRef = %q, want %q", receivedBaseRef, "release/1.0")
+	}
+	if receivedBranch != session.BranchName() {
+		t.Errorf("GetCommits() branch = %q, want %q", receivedBranch, session.BranchName())
+	}
+	if len(response.Commits) != 1 {
+		t.Fatalf("Execute() returned %d commits, want 1", len(response.Commits))
+	}
+	commit := response.Commits[0]
+	if commit.LinesAdded != 15 || commit.LinesRemoved != 2 {
+		t.Errorf("commit totals = +%d -%d, want +15 -2", commit.LinesAdded, commit.LinesRemoved)
+	}
+	if len(commit.Trailers) != 1 || commit.Trailers[0].Key != "Signed-off-by" {
+		t.Errorf("Trailers = %+v, want Signed-off-by", commit.Trailers)
+	}
+	if !commit.AuthoredAt.Equal(authoredAt) {
+		t.Errorf("AuthoredAt = %v, want %v", commit.AuthoredAt, authoredAt)
+	}
+}
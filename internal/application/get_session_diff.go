@@ -0,0 +1,271 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+const (
+	DefaultDiffContextLines    = 3
+	MaxDiffContextLines        = 100
+	DefaultDiffPageSize        = 20
+	MaxDiffPageSize            = 200
+	DefaultDiffMaxBytesPerFile = 16 * 1024
+	DefaultDiffMaxBytesPerPage = 64 * 1024
+
+	diffCursorPrefix = "files:"
+)
+
+var ErrInvalidDiffCursor = errors.New("invalid diff cursor")
+
+type GetSessionDiffRequest struct {
+	SessionID string
+	PathGlobs []string
+	// ContextLines is the number of unchanged lines around each hunk; nil
+	// selects DefaultDiffContextLines
+	ContextLines    *int
+	MaxBytesPerFile int
+	MaxBytesPerPage int
+	PageSize        int
+	Cursor          string
+}
+
+type FileDiffDTO struct {
+	Path      string `json:"path"`
+	Diff      string `json:"diff"`
+	Truncated bool   `json:"truncated"`
+	SizeBytes int    `json:"sizeBytes"`
+}
+
+type GetSessionDiffResponse struct {
+	SessionID  string        `json:"sessionId"`
+	BaseRef    string        `json:"baseRef"`
+	Files      []FileDiffDTO `json:"files"`
+	TotalFiles int           `json:"totalFiles"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
+
+type GetSessionDiffUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+	baseBranch        string
+}
+
+func NewGetSessionDiffUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+	baseBranch string,
+) *GetSessionDiffUseCase {
+	return &GetSessionDiffUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+		baseBranch:        baseBranch,
+	}
+}
+
+func (getSessionDiffUseCase *GetSessionDiffUseCase) Execute(
+	ctx context.Context,
+	request GetSessionDiffRequest,
+) (*GetSessionDiffResponse, error) {
+	session, err := findSession(ctx, getSessionDiffUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := buildDiffOptions(request.PathGlobs, request.ContextLines)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := decodeDiffCursor(request.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	fileDiffs, err := getSessionDiffUseCase.gitOperations.GetDiff(ctx, session.WorktreePath(), diffBaseFor(session, getSessionDiffUseCase.baseBranch), options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff: %w", err)
+	}
+	offset, err := cursor.offsetInto(fileDiffs)
+	if err != nil {
+		return nil, err
+	}
+
+	files, nextOffset := paginateFileDiffs(
+		fileDiffs,
+		offset,
+		clampPositive(request.PageSize, DefaultDiffPageSize, MaxDiffPageSize),
+		clampPositive(request.MaxBytesPerFile, DefaultDiffMaxBytesPerFile, 0),
+		clampPositive(request.MaxBytesPerPage, DefaultDiffMaxBytesPerPage, 0),
+	)
+
+	response := &GetSessionDiffResponse{
+		SessionID:  session.ID().String(),
+		BaseRef:    baseRefFor(session, getSessionDiffUseCase.baseBranch),
+		Files:      files,
+		TotalFiles: len(fileDiffs),
+	}
+	if nextOffset < len(fileDiffs) {
+		response.NextCursor = encodeDiffCursor(fileDiffs, nextOffset)
+	}
+
+	return response, nil
+}
+
+// buildDiffOptions validates the path globs and context lines shared by the
+// diff tools
+func buildDiffOptions(pathGlobs []string, requestedContextLines *int) (domain.DiffOptions, error) {
+	contextLines := DefaultDiffContextLines
+	if requestedContextLines != nil {
+		contextLines = *requestedContextLines
+	}
+	if contextLines < 0 || contextLines > MaxDiffContextLines {
+		return domain.DiffOptions{}, fmt.Errorf("context lines must be between 0 and %d, got %d", MaxDiffContextLines, contextLines)
+	}
+
+	if err := validatePathGlobs(pathGlobs); err != nil {
+		return domain.DiffOptions{}, err
+	}
+
+	return domain.DiffOptions{
+		PathGlobs:    pathGlobs,
+		ContextLines: contextLines,
+	}, nil
+}
+
+// validatePathGlobs rejects empty globs and globs that would be read as git
+// pathspec magic instead of a plain glob
+func validatePathGlobs(pathGlobs []string) error {
+	for _, pathGlob := range pathGlobs {
+		if strings.TrimSpace(pathGlob) == "" {
+			return errors.New("path globs must not be empty")
+		}
+		if strings.HasPrefix(pathGlob, ":") {
+			return fmt.Errorf("path glob %q must not use pathspec magic", pathGlob)
+		}
+	}
+	return nil
+}
+
+// paginateFileDiffs returns the page of files starting at offset together with
+// the offset of the next page. A page always holds at least one file so that
+// a single oversized file cannot stall pagination.
+func paginateFileDiffs(fileDiffs []domain.FileDiff, offset int, pageSize int, maxBytesPerFile int, maxBytesPerPage int) ([]FileDiffDTO, int) {
+	files := make([]FileDiffDTO, 0)
+	pageBytes := 0
+
+	index := offset
+	for ; index < len(fileDiffs) && len(files) < pageSize; index++ {
+		file := truncateFileDiff(fileDiffs[index], maxBytesPerFile)
+		if len(files) > 0 && pageBytes+len(file.Diff) > maxBytesPerPage {
+			break
+		}
+		pageBytes += len(file.Diff)
+		files = append(files, file)
+	}
+
+	return files, index
+}
+
+func truncateFileDiff(fileDiff domain.FileDiff, maxBytes int) FileDiffDTO {
+	file := FileDiffDTO{
+		Path:      fileDiff.Path,
+		Diff:      fileDiff.Patch,
+		SizeBytes: len(fileDiff.Patch),
+	}
+	if len(fileDiff.Patch) <= maxBytes {
+		return file
+	}
+
+	cut := strings.LastIndexByte(fileDiff.Patch[:maxBytes], '\n')
+	if cut < 0 {
+		// no whole line fits; cut inside it without splitting a character
+		cut = maxBytes
+		for cut > 0 && !utf8.RuneStart(fileDiff.Patch[cut]) {
+			cut--
+		}
+	} else {
+		cut++
+	}
+	file.Diff = fileDiff.Patch[:cut]
+	file.Truncated = true
+	return file
+}
+
+// clampPositive returns value, or fallback when value is not positive, capped
+// at limit when limit is positive
+func clampPositive(value int, fallback int, limit int) int {
+	if value <= 0 {
+		value = fallback
+	}
+	if limit > 0 && value > limit {
+		value = limit
+	}
+	return value
+}
+
+// diffCursor is a file offset into a diff together with a hash of the diff's
+// file list. The diff is recomputed on every page, so a cursor taken before
+// files were added or removed would skip or repeat files and is rejected.
+type diffCursor struct {
+	offset   int
+	fileList string
+}
+
+func encodeDiffCursor(fileDiffs []domain.FileDiff, offset int) string {
+	cursor := diffCursorPrefix + strconv.Itoa(offset) + ":" + hashFileList(fileDiffs)
+	return base64.RawURLEncoding.EncodeToString([]byte(cursor))
+}
+
+// decodeDiffCursor parses cursor; the empty cursor starts at the first file
+func decodeDiffCursor(cursor string) (*diffCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), diffCursorPrefix) {
+		return nil, ErrInvalidDiffCursor
+	}
+
+	offsetText, fileList, found := strings.Cut(strings.TrimPrefix(string(decoded), diffCursorPrefix), ":")
+	offset, err := strconv.Atoi(offsetText)
+	if !found || err != nil || offset < 0 || fileList == "" {
+		return nil, ErrInvalidDiffCursor
+	}
+
+	return &diffCursor{offset: offset, fileList: fileList}, nil
+}
+
+// offsetInto returns where in fileDiffs the page starts, refusing a cursor
+// taken from a different file list
+func (cursor *diffCursor) offsetInto(fileDiffs []domain.FileDiff) (int, error) {
+	if cursor == nil {
+		return 0, nil
+	}
+	if cursor.fileList != hashFileList(fileDiffs) {
+		return 0, fmt.Errorf("%w: the changed files differ from when the cursor was issued; start again without a cursor", ErrInvalidDiffCursor)
+	}
+	if cursor.offset > len(fileDiffs) {
+		return 0, fmt.Errorf("%w: offset %d is past the last file", ErrInvalidDiffCursor, cursor.offset)
+	}
+	return cursor.offset, nil
+}
+
+func hashFileList(fileDiffs []domain.FileDiff) string {
+	hash := sha256.New()
+	for _, fileDiff := range fileDiffs {
+		hash.Write([]byte(fileDiff.Path))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:8])
+}
@@ -0,0 +1,268 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func saveDiffTestSession(t *testing.T, sessionRepository *mockSessionRepository) *domain.Session {
+	t.Helper()
+
+	sessionID, _ := domain.NewSessionID("test-session")
+	session, _ := domain.NewSession(sessionID, "/path/test-session", "")
+	sessionRepository.Save(context.Background(), session)
+	return session
+}
+
+func fileDiffsOfSize(count int, size int) []domain.FileDiff {
+	fileDiffs := make([]domain.FileDiff, 0, count)
+	for index := 0; index < count; index++ {
+		fileDiffs = append(fileDiffs, domain.FileDiff{
+			Path:  "file" + string(rune('a'+index)) + ".txt",
+			Patch: strings.Repeat("x", size-1) + "\n",
+		})
+	}
+	return fileDiffs
+}
+
+func TestGetSessionDiffUseCase_Execute_SessionNotFound(t *testing.T) {
+	// arrange
+	gitOperations := &mockGitOperations{}
+	sessionRepository := newMockSessionRepository()
+	useCase := NewGetSessionDiffUseCase(gitOperations, sessionRepository, "main")
+
+	// act
+	_, err := useCase.Execute(context.Background(), GetSessionDiffRequest{SessionID: "nonexistent"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for non-existent session")
+	}
+}
+
+func TestGetSessionDiffUseCase_Execute_PassesOptionsToGit(t *testing.T) {
+	// arrange
+	var receivedBaseRef string
+	var receivedOptions domain.DiffOptions
+	gitOperations := &mockGitOperations{
+		getDiffFunc: func(ctx context.Context, worktreePath string, baseRef string, options domain.DiffOptions) ([]domain.FileDiff, error) {
+			receivedBaseRef = baseRef
+			receivedOptions = options
+			return []domain.FileDiff{}, nil
+		},
+	}
+	sessionRepository := newMockSessionRepository()
+	saveDiffTestSession(t, sessionRepository)
+	useCase := NewGetSessionDiffUseCase(gitOperations, sessionRepository, "main")
+	contextLines := 0
+	request := GetSessionDiffRequest{
+		SessionID:    "test-session",
+		PathGlobs:    []string{"internal/**/*.go"},
+		ContextLines: &contextLines,
+	}
+
+	// act
+	_, err := useCase.Execute(context.Background(), request)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if receivedBaseRef != "main" {
+		t.Errorf("GetDiff() baseRef = %q, want %q", receivedBaseRef, "main")
+	}
+	if receivedOptions.ContextLines != 0 {
+		t.Errorf("GetDiff() ContextLines = %d, want 0", receivedOptions.ContextLines)
+	}
+	if len(receivedOptions.PathGlobs) != 1 || receivedOptions.PathGlobs[0] != "internal/**/*.go" {
+		t.Errorf("GetDiff() PathGlobs = %v, want [internal/**/*.go]", receivedOptions.PathGlobs)
+	}
+}
+
+func TestGetSessionDiffUseCase_Execute_InvalidContextLines(t *testing.T) {
+	// arrange
+	sessionRepository := newMockSessionRepository()
+	saveDiffTestSession(t, sessionRepository)
+	useCase := NewGetSessionDiffUseCase(&mockGitOperations{}, sessionRepository, "main")
+	contextLines := -1
+
+	// act
+	_, err := useCase.Execute(context.Background(), GetSessionDiffRequest{SessionID: "test-session", ContextLines: &contextLines})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for negative context lines")
+	}
+}
+
+func TestGetSessionDiffUseCase_Execute_PaginatesWithCursor(t *testing.T) {
+	// arrange
+	gitOperations := &mockGitOperations{
+		getDiffFunc: func(ctx context.Context, worktreePath string, baseRef string, options domain.DiffOptions) ([]domain.FileDiff, error) {
+			return fileDiffsOfSize(5, 10), nil
+		},
+	}
+	sessionRepository := newMockSessionRepository()
+	saveDiffTestSession(t, sessionRepository)
+	useCase := NewGetSessionDiffUseCase(gitOperations, sessionRepository, "main")
+	ctx := context.Background()
+
+	// act
+	firstPage, err := useCase.Execute(ctx, GetSessionDiffRequest{SessionID: "test-session", PageSize: 2})
+	if err != nil {
+		t.Fatalf("Execute() first page error: %v", err)
+	}
+	secondPage, err := useCase.Execute(ctx, GetSessionDiffRequest{SessionID: "test-session", PageSize: 2, Cursor: firstPage.NextCursor})
+	if err != nil {
+		t.Fatalf("Execute() second page error: %v", err)
+	}
+	lastPage, err := useCase.Execute(ctx, GetSessionDiffRequest{SessionID: "test-session", PageSize: 2, Cursor: secondPage.NextCursor})
+	if err != nil {
+		t.Fatalf("Execute() last page error: %v", err)
+	}
+
+	// assert
+	if firstPage.TotalFiles != 5 {
+		t.Errorf("TotalFiles = %d, want 5", firstPage.TotalFiles)
+	}
+	if len(firstPage.Files) != 2 || firstPage.Files[0].Path != "filea.txt" {
+		t.Errorf("first page = %+v, want files a and b", firstPage.Files)
+	}
+	if len(secondPage.Files) != 2 || secondPage.Files[0].Path != "filec.txt" {
+		t.Errorf("second page = %+v, want files c and d", secondPage.Files)
+	}
+	if len(lastPage.Files) != 1 || lastPage.Files[0].Path != "filee.txt" {
+		t.Errorf("last page = %+v, want file e", lastPage.Files)
+	}
+	if lastPage.NextCursor != "" {
+		t.Errorf("last page NextCursor = %q, want empty", lastPage.NextCursor)
+	}
+}
+
+func TestGetSessionDiffUseCase_Execute_StopsPageAtByteBudget(t *testing.T) {
+	// arrange
+	gitOperations := &mockGitOperations{
+		getDiffFunc: func(ctx context.Context, worktreePath string, baseRef string, options domain.DiffOptions) ([]domain.FileDiff, error) {
+			return fileDiffsOfSize(3, 100), nil
+		},
+	}
+	sessionRepository := newMockSessionRepository()
+	saveDiffTestSession(t, sessionRepository)
+	useCase := NewGetSessionDiffUseCase(gitOperations, sessionRepository, "main")
+
+	// act
+	response, err := useCase.Execute(context.Background(), GetSessionDiffRequest{SessionID: "test-session", MaxBytesPerPage: 250})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if len(response.Files) != 2 {
+		t.Errorf("Execute() returned %d files, want 2", len(response.Files))
+	}
+	if response.NextCursor == "" {
+		t.Error("Execute() expected a cursor for the remaining file")
+	}
+}
+
+func TestGetSessionDiffUseCase_Execute_TruncatesLargeFileAtLineBoundary(t *testing.T) {
+	// arrange
+	patch := "line one\nline two\nline three\n"
+	gitOperations := &mockGitOperations{
+		getDiffFunc: func(ctx context.Context, worktreePath string, baseRef string, options domain.DiffOptions) ([]domain.FileDiff, error) {
+			return []domain.FileDiff{{Path: "big.txt", Patch: patch}}, nil
+		},
+	}
+	sessionRepository := newMockSessionRepository()
+	saveDiffTestSession(t, sessionRepository)
+	useCase := NewGetSessionDiffUseCase(gitOperations, sessionRepository, "main")
+
+	// act
+	response, err := useCase.Execute(context.Background(), GetSessionDiffRequest{SessionID: "test-session", MaxBytesPerFile: 20})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	file := response.Files[0]
+	if !file.Truncated {
+		t.Error("expected file to be truncated")
+	}
+	if file.Diff != "line one\nline two\n" {
+		t.Errorf("Diff = %q, want the first two lines", file.Diff)
+	}
+	if file.SizeBytes != len(patch) {
+		t.Errorf("SizeBytes = %d, want %d", file.SizeBytes, len(patch))
+	}
+}
+
+func TestGetSessionDiffUseCase_Execute_TruncatesLongLineWithoutSplittingCharacters(t *testing.T) {
+	// arrange
+	patch := "+" + strings.Repeat("ä", 20) + "\n"
+	gitOperations := &mockGitOperations{
+		getDiffFunc: func(ctx context.Context, worktreePath string, baseRef string, options domain.DiffOptions) ([]domain.FileDiff, error) {
+			return []domain.FileDiff{{Path: "umlauts.txt", Patch: patch}}, nil
+		},
+	}
+	sessionRepository := newMockSessionRepository()
+	saveDiffTestSession(t, sessionRepository)
+	useCase := NewGetSessionDiffUseCase(gitOperations, sessionRepository, "main")
+
+	// act
+	response, err := useCase.Execute(context.Background(), GetSessionDiffRequest{SessionID: "test-session", MaxBytesPerFile: 10})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	file := response.Files[0]
+	if !file.Truncated || file.Diff != "+"+strings.Repeat("ä", 4) {
+		t.Errorf("Diff = %q, want the line cut before the character spanning the limit", file.Diff)
+	}
+}
+
+func TestGetSessionDiffUseCase_Execute_InvalidCursor(t *testing.T) {
+	// arrange
+	sessionRepository := newMockSessionRepository()
+	saveDiffTestSession(t, sessionRepository)
+	useCase := NewGetSessionDiffUseCase(&mockGitOperations{}, sessionRepository, "main")
+
+	// act
+	_, err := useCase.Execute(context.Background(), GetSessionDiffRequest{SessionID: "test-session", Cursor: "not-a-cursor"})
+
+	// assert
+	if !errors.Is(err, ErrInvalidDiffCursor) {
+		t.Errorf("Execute() error = %v, want ErrInvalidDiffCursor", err)
+	}
+}
+
+func TestGetSessionDiffUseCase_Execute_FilesChangedBetweenPages_RejectsCursor(t *testing.T) {
+	// arrange
+	fileDiffs := fileDiffsOfSize(4, 10)
+	gitOperations := &mockGitOperations{
+		getDiffFunc: func(ctx context.Context, worktreePath string, baseRef string, options domain.DiffOptions) ([]domain.FileDiff, error) {
+			return fileDiffs, nil
+		},
+	}
+	sessionRepository := newMockSessionRepository()
+	saveDiffTestSession(t, sessionRepository)
+	useCase := NewGetSessionDiffUseCase(gitOperations, sessionRepository, "main")
+	ctx := context.Background()
+	firstPage, err := useCase.Execute(ctx, GetSessionDiffRequest{SessionID: "test-session", PageSize: 2})
+	if err != nil {
+		t.Fatalf("Execute() first page error: %v", err)
+	}
+	fileDiffs = append([]domain.FileDiff{{Path: "added.txt", Patch: "+new\n"}}, fileDiffs...)
+
+	// act
+	_, err = useCase.Execute(ctx, GetSessionDiffRequest{SessionID: "test-session", PageSize: 2, Cursor: firstPage.NextCursor})
+
+	// assert
+	if !errors.Is(err, ErrInvalidDiffCursor) {
+		t.Errorf("Execute() error = %v, want ErrInvalidDiffCursor for a stale cursor", err)
+	}
+}
@@ -0,0 +1,198 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type GetSessionOverlapsRequest struct {
+	// TrialMerge dry-runs a merge of every overlapping pair of sessions to
+	// find out which overlaps are real conflicts
+	TrialMerge bool
+}
+
+type SessionOverlapDTO struct {
+	SessionA string   `json:"sessionA"`
+	SessionB string   `json:"sessionB"`
+	Paths    []string `json:"paths"`
+	// Conflicting is only set when a trial merge ran and succeeded
+	Conflicting     *bool    `json:"conflicting,omitempty"`
+	ConflictedPaths []string `json:"conflictedPaths,omitempty"`
+}
+
+type PathOverlapDTO struct {
+	Path       string   `json:"path"`
+	SessionIDs []string `json:"sessionIds"`
+}
+
+// SessionErrorDTO names a session that was left out of the comparison
+type SessionErrorDTO struct {
+	SessionID string `json:"sessionId"`
+	Error     string `json:"error"`
+}
+
+type GetSessionOverlapsResponse struct {
+	SessionIDs []string            `json:"sessionIds"`
+	Overlaps   []SessionOverlapDTO `json:"overlaps"`
+	Paths      []PathOverlapDTO    `json:"paths"`
+	Errors     []SessionErrorDTO   `json:"errors,omitempty"`
+}
+
+type GetSessionOverlapsUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+	baseBranch        string
+}
+
+func NewGetSessionOverlapsUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+	baseBranch string,
+) *GetSessionOverlapsUseCase {
+	return &GetSessionOverlapsUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+		baseBranch:        baseBranch,
+	}
+}
+
+// Execute compares the changed files of every unmerged session, including
+// uncommitted work, and reports the pairs that touch the same paths. Trial
+// merges only see committed work and only run for overlapping pairs, since
+// sessions without a common path cannot conflict.
+func (getSessionOverlapsUseCase *GetSessionOverlapsUseCase) Execute(
+	ctx context.Context,
+	request GetSessionOverlapsRequest,
+) (*GetSessionOverlapsResponse, error) {
+	sessions, err := getSessionOverlapsUseCase.sessionRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	openSessions := make([]*domain.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if session.Status() != domain.StatusMerged {
+			openSessions = append(openSessions, session)
+		}
+	}
+	sort.Slice(openSessions, func(i, j int) bool {
+		return openSessions[i].ID().String() < openSessions[j].ID().String()
+	})
+
+	// a session whose changes cannot be read, for example because its
+	// worktree is gone, is reported and skipped so the others still compare
+	comparedSessions := make([]*domain.Session, 0, len(openSessions))
+	changedPaths := make([]map[string]bool, 0, len(openSessions))
+	sessionIDs := make([]string, 0, len(openSessions))
+	var sessionErrors []SessionErrorDTO
+	for _, session := range openSessions {
+		paths, err := getSessionOverlapsUseCase.changedPaths(ctx, session)
+		if err != nil {
+			sessionErrors = append(sessionErrors, SessionErrorDTO{SessionID: session.ID().String(), Error: err.Error()})
+			continue
+		}
+		comparedSessions = append(comparedSessions, session)
+		changedPaths = append(changedPaths, paths)
+		sessionIDs = append(sessionIDs, session.ID().String())
+	}
+
+	overlaps := make([]SessionOverlapDTO, 0)
+	for first := 0; first < len(comparedSessions); first++ {
+		for second := first + 1; second < len(comparedSessions); second++ {
+			sharedPaths := intersectPaths(changedPaths[first], changedPaths[second])
+			if len(sharedPaths) == 0 {
+				continue
+			}
+
+			overlap := SessionOverlapDTO{
+				SessionA: sessionIDs[first],
+				SessionB: sessionIDs[second],
+				Paths:    sharedPaths,
+			}
+			if request.TrialMerge {
+				getSessionOverlapsUseCase.trialMerge(ctx, comparedSessions[first], comparedSessions[second], &overlap)
+			}
+			overlaps = append(overlaps, overlap)
+		}
+	}
+
+	return &GetSessionOverlapsResponse{
+		SessionIDs: sessionIDs,
+		Overlaps:   overlaps,
+		Paths:      buildPathOverlaps(sessionIDs, changedPaths),
+		Errors:     sessionErrors,
+	}, nil
+}
+
+// changedPaths returns every path a session touches; renames count for both
+// their old and new path
+func (getSessionOverlapsUseCase *GetSessionOverlapsUseCase) changedPaths(ctx context.Context, session *domain.Session) (map[string]bool, error) {
+	diffStats, err := getSessionOverlapsUseCase.gitOperations.GetDiffStats(ctx, session.WorktreePath(), diffBaseFor(session, getSessionOverlapsUseCase.baseBranch))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read changes of session %s: %w", session.ID(), err)
+	}
+
+	paths := make(map[string]bool, len(diffStats.Files))
+	for _, file := range diffStats.Files {
+		paths[file.Path] = true
+		if file.OldPath != "" {
+			paths[file.OldPath] = true
+		}
+	}
+	return paths, nil
+}
+
+// trialMerge dry-runs a merge of the two session branches. A failed check
+// leaves the overlap without a verdict rather than failing the whole report.
+func (getSessionOverlapsUseCase *GetSessionOverlapsUseCase) trialMerge(
+	ctx context.Context,
+	first *domain.Session,
+	second *domain.Session,
+	overlap *SessionOverlapDTO,
+) {
+	mergeCheck, err := getSessionOverlapsUseCase.gitOperations.CheckMerge(ctx, first.BranchName(), second.BranchName())
+	if err != nil {
+		return
+	}
+
+	conflicting := !mergeCheck.Clean
+	overlap.Conflicting = &conflicting
+	for _, conflict := range mergeCheck.Conflicts {
+		overlap.ConflictedPaths = append(overlap.ConflictedPaths, conflict.Path)
+	}
+}
+
+func intersectPaths(first map[string]bool, second map[string]bool) []string {
+	shared := make([]string, 0)
+	for path := range first {
+		if second[path] {
+			shared = append(shared, path)
+		}
+	}
+	sort.Strings(shared)
+	return shared
+}
+
+// buildPathOverlaps lists each path changed by more than one session
+func buildPathOverlaps(sessionIDs []string, changedPaths []map[string]bool) []PathOverlapDTO {
+	sessionsByPath := make(map[string][]string)
+	for index, paths := range changedPaths {
+		for path := range paths {
+			sessionsByPath[path] = append(sessionsByPath[path], sessionIDs[index])
+		}
+	}
+
+	pathOverlaps := make([]PathOverlapDTO, 0)
+	for path, pathSessionIDs := range sessionsByPath {
+		if len(pathSessionIDs) > 1 {
+			pathOverlaps = append(pathOverlaps, PathOverlapDTO{Path: path, SessionIDs: pathSessionIDs})
+		}
+	}
+	sort.Slice(pathOverlaps, func(i, j int) bool {
+		return pathOverlaps[i].Path < pathOverlaps[j].Path
+	})
+	return pathOverlaps
+}
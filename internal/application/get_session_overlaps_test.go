@@ -0,0 +1,159 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func setupOverlapTest(t *testing.T, gitOperations *mockGitOperations, changedFiles map[string][]domain.FileChange) *GetSessionOverlapsUseCase {
+	t.Helper()
+
+	sessionRepository := newMockSessionRepository()
+	for sessionName := range changedFiles {
+		sessionID, _ := domain.NewSessionID(sessionName)
+		session, _ := domain.NewSession(sessionID, "/path/"+sessionName, "")
+		sessionRepository.Save(context.Background(), session)
+	}
+
+	gitOperations.getDiffStatsFunc = func(ctx context.Context, worktreePath string, baseRef string) (*domain.GitDiffStats, error) {
+		return domain.NewGitDiffStats(changedFiles[strings.TrimPrefix(worktreePath, "/path/")]), nil
+	}
+
+	return NewGetSessionOverlapsUseCase(gitOperations, sessionRepository, "main")
+}
+
+func TestGetSessionOverlapsUseCase_Execute_ReportsSharedPaths(t *testing.T) {
+	// arrange
+	useCase := setupOverlapTest(t, &mockGitOperations{}, map[string][]domain.FileChange{
+		"alpha": {{Path: "api/handler.go"}, {Path: "README.md"}},
+		"beta":  {{Path: "api/handler.go"}, {Path: "docs/new.md", OldPath: "docs/old.md"}},
+		"gamma": {{Path: "docs/old.md"}},
+		"delta": {{Path: "web/app.ts"}},
+	})
+
+	// act
+	response, err := useCase.Execute(context.Background(), GetSessionOverlapsRequest{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if len(response.SessionIDs) != 4 {
+		t.Errorf("SessionIDs = %v, want 4 sessions", response.SessionIDs)
+	}
+	if len(response.Overlaps) != 2 {
+		t.Fatalf("Overlaps = %+v, want 2 pairs", response.Overlaps)
+	}
+	first, second := response.Overlaps[0], response.Overlaps[1]
+	if first.SessionA != "alpha" || first.SessionB != "beta" || len(first.Paths) != 1 || first.Paths[0] != "api/handler.go" {
+		t.Errorf("first overlap = %+v, want alpha/beta on api/handler.go", first)
+	}
+	if second.SessionA != "beta" || second.SessionB != "gamma" || len(second.Paths) != 1 || second.Paths[0] != "docs/old.md" {
+		t.Errorf("second overlap = %+v, want beta/gamma on the renamed docs/old.md", second)
+	}
+	if first.Conflicting != nil {
+		t.Error("Conflicting should be unset without a trial merge")
+	}
+	if len(response.Paths) != 2 || response.Paths[0].Path != "api/handler.go" {
+		t.Errorf("Paths = %+v, want api/handler.go and docs/old.md", response.Paths)
+	}
+}
+
+func TestGetSessionOverlapsUseCase_Execute_TrialMergesOverlappingPairs(t *testing.T) {
+	// arrange
+	var checkedPairs [][2]string
+	gitOperations := &mockGitOperations{
+		checkMergeFunc: func(ctx context.Context, baseRef string, sessionBranch string) (*domain.MergeCheck, error) {
+			checkedPairs = append(checkedPairs, [2]string{baseRef, sessionBranch})
+			return &domain.MergeCheck{Conflicts: []domain.MergeConflict{{Path: "shared.go"}}}, nil
+		},
+	}
+	useCase := setupOverlapTest(t, gitOperations, map[string][]domain.FileChange{
+		"alpha": {{Path: "shared.go"}},
+		"beta":  {{Path: "shared.go"}},
+		"gamma": {{Path: "other.go"}},
+	})
+
+	// act
+	response, err := useCase.Execute(context.Background(), GetSessionOverlapsRequest{TrialMerge: true})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if len(checkedPairs) != 1 {
+		t.Fatalf("CheckMerge() ran for %v, want only the overlapping pair", checkedPairs)
+	}
+	overlap := response.Overlaps[0]
+	if overlap.Conflicting == nil || !*overlap.Conflicting {
+		t.Error("Conflicting = false, want true")
+	}
+	if len(overlap.ConflictedPaths) != 1 || overlap.ConflictedPaths[0] != "shared.go" {
+		t.Errorf("ConflictedPaths = %v, want [shared.go]", overlap.ConflictedPaths)
+	}
+}
+
+func TestGetSessionOverlapsUseCase_Execute_SkipsMergedSessions(t *testing.T) {
+	// arrange
+	sessionRepository := newMockSessionRepository()
+	for _, sessionName := range []string{"alpha", "beta"} {
+		sessionID, _ := domain.NewSessionID(sessionName)
+		session, _ := domain.NewSession(sessionID, "/path/"+sessionName, "")
+		if sessionName == "beta" {
+			session.MarkMerged()
+		}
+		sessionRepository.Save(context.Background(), session)
+	}
+	gitOperations := &mockGitOperations{
+		getDiffStatsFunc: func(ctx context.Context, worktreePath string, baseRef string) (*domain.GitDiffStats, error) {
+			return domain.NewGitDiffStats([]domain.FileChange{{Path: "shared.go"}}), nil
+		},
+	}
+	useCase := NewGetSessionOverlapsUseCase(gitOperations, sessionRepository, "main")
+
+	// act
+	response, err := useCase.Execute(context.Background(), GetSessionOverlapsRequest{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if len(response.SessionIDs) != 1 || len(response.Overlaps) != 0 {
+		t.Errorf("response = %+v, want only the open session and no overlaps", response)
+	}
+}
+
+func TestGetSessionOverlapsUseCase_Execute_SkipsSessionWhoseChangesCannotBeRead(t *testing.T) {
+	// arrange
+	gitOperations := &mockGitOperations{}
+	useCase := setupOverlapTest(t, gitOperations, map[string][]domain.FileChange{
+		"alpha":  {{Path: "api/handler.go"}},
+		"beta":   {{Path: "api/handler.go"}},
+		"broken": {{Path: "api/handler.go"}},
+	})
+	readChanges := gitOperations.getDiffStatsFunc
+	gitOperations.getDiffStatsFunc = func(ctx context.Context, worktreePath string, baseRef string) (*domain.GitDiffStats, error) {
+		if worktreePath == "/path/broken" {
+			return nil, errors.New("worktree is missing")
+		}
+		return readChanges(ctx, worktreePath, baseRef)
+	}
+
+	// act
+	response, err := useCase.Execute(context.Background(), GetSessionOverlapsRequest{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if len(response.SessionIDs) != 2 || len(response.Overlaps) != 1 {
+		t.Errorf("response = %+v, want alpha and beta compared with one overlap", response)
+	}
+	if len(response.Errors) != 1 || response.Errors[0].SessionID != "broken" || !strings.Contains(response.Errors[0].Error, "worktree is missing") {
+		t.Errorf("Errors = %+v, want the broken session reported", response.Errors)
+	}
+}
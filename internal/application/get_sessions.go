@@ -2,41 +2,99 @@ package application
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/tzDel/orchestragent-mcp/internal/domain"
 )
 
 type GetSessionsRequest struct {
+	// BehindMoreThan, when set, keeps only sessions more than this many
+	// commits behind their base, the comparison the stale threshold uses
+	BehindMoreThan *int
+	// StaleOnly keeps only sessions further behind than the stale threshold
+	StaleOnly bool
+	// CheckMergeable dry-runs a merge of every unmerged session to fill in
+	// Mergeable, which is otherwise left unset
+	CheckMergeable bool
 }
 
 type SessionDTO struct {
-	SessionID    string `json:"sessionId"`
-	WorktreePath string `json:"worktreePath"`
-	BranchName   string `json:"branchName"`
-	Status       string `json:"status"`
+	SessionID       string           `json:"sessionId"`
+	WorktreePath    string           `json:"worktreePath"`
+	BranchName      string           `json:"branchName"`
+	BaseRef         string           `json:"baseRef"`
+	BaseCommit      string           `json:"baseCommit,omitempty"`
+	Status          string           `json:"status"`
+	LinesAdded      int              `json:"linesAdded"`
+	LinesRemoved    int              `json:"linesRemoved"`
+	FilesChanged    int              `json:"filesChanged"`
+	Files           []FileChangeDTO  `json:"files"`
+	Breakdown       DiffBreakdownDTO `json:"breakdown"`
+	Mergeable       *bool            `json:"mergeable,omitempty"`
+	Ahead           int              `json:"ahead"`
+	Behind          int              `json:"behind"`
+	LastCommitAt    *time.Time       `json:"lastCommitAt,omitempty"`
+	Stale           bool             `json:"stale"`
+	Checkpoints     []CheckpointDTO  `json:"checkpoints"`
+	PullRequest     *PullRequestDTO  `json:"pullRequest,omitempty"`
+	ParentSessionID string           `json:"parentSessionId,omitempty"`
+	// Errors lists what could not be measured for the session, such as its
+	// divergence from the base or its checkpoints, whose fields are then left
+	// at zero or empty
+	Errors []string `json:"errors,omitempty"`
+}
+
+type DiffBreakdownDTO struct {
+	Committed DiffContributionDTO `json:"committed"`
+	Staged    DiffContributionDTO `json:"staged"`
+	Unstaged  DiffContributionDTO `json:"unstaged"`
+	Untracked DiffContributionDTO `json:"untracked"`
+}
+
+type DiffContributionDTO struct {
+	LinesAdded   int `json:"linesAdded"`
+	LinesRemoved int `json:"linesRemoved"`
+	FilesChanged int `json:"filesChanged"`
+}
+
+type FileChangeDTO struct {
+	Path         string `json:"path"`
+	OldPath      string `json:"oldPath,omitempty"`
+	ChangeType   string `json:"changeType"`
 	LinesAdded   int    `json:"linesAdded"`
 	LinesRemoved int    `json:"linesRemoved"`
+	Binary       bool   `json:"binary"`
+	Untracked    bool   `json:"untracked,omitempty"`
 }
 
 type GetSessionsResponse struct {
 	Sessions []SessionDTO `json:"sessions"`
+	// StackTree draws the stacks among the listed sessions; it is empty when
+	// none of them are stacked on each other
+	StackTree string `json:"stackTree,omitempty"`
 }
 
 type GetSessionsUseCase struct {
 	gitOperations     domain.GitOperations
 	sessionRepository domain.SessionRepository
 	baseBranch        string
+	// staleBehindCommits is how far behind its base an open session may fall
+	// before it is reported as stale
+	staleBehindCommits int
 }
 
 func NewGetSessionsUseCase(
 	gitOperations domain.GitOperations,
 	sessionRepository domain.SessionRepository,
 	baseBranch string,
+	staleBehindCommits int,
 ) *GetSessionsUseCase {
 	return &GetSessionsUseCase{
-		gitOperations:     gitOperations,
-		sessionRepository: sessionRepository,
-		baseBranch:        baseBranch,
+		gitOperations:      gitOperations,
+		sessionRepository:  sessionRepository,
+		baseBranch:         baseBranch,
+		staleBehindCommits: staleBehindCommits,
 	}
 }
 
@@ -47,29 +105,131 @@ func (useCase *GetSessionsUseCase) Execute(ctx context.Context, request GetSessi
 	}
 
 	sessionDTOs := make([]SessionDTO, 0, len(sessions))
+	listedSessions := make([]*domain.Session, 0, len(sessions))
 	for _, session := range sessions {
-		diffStats, err := useCase.gitOperations.GetDiffStats(ctx, session.WorktreePath(), useCase.baseBranch)
+		var sessionErrors []string
+
+		// a session whose divergence is unknown is kept by the filters, so
+		// that a broken session is reported rather than hidden
+		divergence, err := useCase.gitOperations.GetDivergence(ctx, baseRefFor(session, useCase.baseBranch), session.BranchName())
+		if err != nil {
+			sessionErrors = append(sessionErrors, fmt.Sprintf("failed to compare with base: %v", err))
+			divergence = &domain.BranchDivergence{}
+		} else if !useCase.matchesFilters(session, divergence, request) {
+			continue
+		}
+
+		diffStats, err := useCase.gitOperations.GetDiffStats(ctx, session.WorktreePath(), diffBaseFor(session, useCase.baseBranch))
 		if err != nil {
-			// Continue with zero stats on error
-			diffStats = &domain.GitDiffStats{LinesAdded: 0, LinesRemoved: 0}
+			sessionErrors = append(sessionErrors, fmt.Sprintf("failed to get diff stats: %v", err))
+			diffStats = domain.NewGitDiffStats(nil)
 		}
 
 		dto := useCase.buildSessionDTO(session, diffStats)
+		if request.CheckMergeable {
+			dto.Mergeable = useCase.checkMergeable(ctx, session)
+		}
+		dto.Ahead = divergence.Ahead
+		dto.Behind = divergence.Behind
+		if !divergence.LastCommitAt.IsZero() {
+			dto.LastCommitAt = &divergence.LastCommitAt
+		}
+		dto.Stale = useCase.isStale(session, divergence)
+		dto.Checkpoints, err = useCase.listCheckpoints(ctx, session)
+		if err != nil {
+			sessionErrors = append(sessionErrors, err.Error())
+		}
+		dto.Errors = sessionErrors
 		sessionDTOs = append(sessionDTOs, dto)
+		listedSessions = append(listedSessions, session)
 	}
 
 	return &GetSessionsResponse{
-		Sessions: sessionDTOs,
+		Sessions:  sessionDTOs,
+		StackTree: renderStackTree(listedSessions, useCase.baseBranch),
 	}, nil
 }
 
+func (useCase *GetSessionsUseCase) matchesFilters(session *domain.Session, divergence *domain.BranchDivergence, request GetSessionsRequest) bool {
+	if request.BehindMoreThan != nil && divergence.Behind <= *request.BehindMoreThan {
+		return false
+	}
+	return !request.StaleOnly || useCase.isStale(session, divergence)
+}
+
+// isStale reports whether an unmerged session has fallen further behind its
+// base than the configured threshold
+func (useCase *GetSessionsUseCase) isStale(session *domain.Session, divergence *domain.BranchDivergence) bool {
+	return session.Status() != domain.StatusMerged && divergence.Behind > useCase.staleBehindCommits
+}
+
+// listCheckpoints returns the session's checkpoints. When they cannot be read
+// it returns an empty list together with the error.
+func (useCase *GetSessionsUseCase) listCheckpoints(ctx context.Context, session *domain.Session) ([]CheckpointDTO, error) {
+	checkpoints, err := useCase.gitOperations.ListCheckpoints(ctx, session.ID().CheckpointRefPrefix())
+	if err != nil {
+		return []CheckpointDTO{}, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	return buildCheckpointDTOs(checkpoints), nil
+}
+
+// checkMergeable dry-runs a merge of the session into its base ref. It
+// returns nil for merged sessions and when the check itself fails.
+func (useCase *GetSessionsUseCase) checkMergeable(ctx context.Context, session *domain.Session) *bool {
+	if session.Status() == domain.StatusMerged {
+		return nil
+	}
+
+	mergeCheck, err := useCase.gitOperations.CheckMerge(ctx, baseRefFor(session, useCase.baseBranch), session.BranchName())
+	if err != nil {
+		return nil
+	}
+	return &mergeCheck.Clean
+}
+
 func (useCase *GetSessionsUseCase) buildSessionDTO(session *domain.Session, diffStats *domain.GitDiffStats) SessionDTO {
 	return SessionDTO{
 		SessionID:    session.ID().String(),
 		WorktreePath: session.WorktreePath(),
 		BranchName:   session.BranchName(),
+		BaseRef:      baseRefFor(session, useCase.baseBranch),
+		BaseCommit:   session.BaseCommit(),
 		Status:       string(session.Status()),
 		LinesAdded:   diffStats.LinesAdded,
 		LinesRemoved: diffStats.LinesRemoved,
+		FilesChanged: diffStats.FilesChanged,
+		Files:        buildFileChangeDTOs(diffStats.Files),
+		Breakdown: DiffBreakdownDTO{
+			Committed: buildDiffContributionDTO(diffStats.Breakdown.Committed),
+			Staged:    buildDiffContributionDTO(diffStats.Breakdown.Staged),
+			Unstaged:  buildDiffContributionDTO(diffStats.Breakdown.Unstaged),
+			Untracked: buildDiffContributionDTO(diffStats.Breakdown.Untracked),
+		},
+		PullRequest:     buildPullRequestDTO(session.PullRequest()),
+		ParentSessionID: session.ParentSessionID().String(),
+	}
+}
+
+func buildDiffContributionDTO(contribution domain.DiffContribution) DiffContributionDTO {
+	return DiffContributionDTO{
+		LinesAdded:   contribution.LinesAdded,
+		LinesRemoved: contribution.LinesRemoved,
+		FilesChanged: contribution.FilesChanged,
+	}
+}
+
+func buildFileChangeDTOs(files []domain.FileChange) []FileChangeDTO {
+	fileDTOs := make([]FileChangeDTO, 0, len(files))
+	for _, file := range files {
+		fileDTOs = append(fileDTOs, FileChangeDTO{
+			Path:         file.Path,
+			OldPath:      file.OldPath,
+			ChangeType:   string(file.ChangeType),
+			LinesAdded:   file.LinesAdded,
+			LinesRemoved: file.LinesRemoved,
+			Binary:       file.Binary,
+			Untracked:    file.Untracked,
+		})
 	}
+	return fileDTOs
 }
@@ -2,7 +2,10 @@ package application
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/tzDel/orchestragent-mcp/internal/domain"
 )
@@ -13,7 +16,7 @@ func TestGetSessionsUseCase_NoSessions(t *testing.T) {
 	mockRepo := &MockSessionRepository{
 		sessions: make(map[string]*domain.Session),
 	}
-	useCase := NewGetSessionsUseCase(mockGitOps, mockRepo, "main")
+	useCase := NewGetSessionsUseCase(mockGitOps, mockRepo, "main", 50)
 	ctx := context.Background()
 	request := GetSessionsRequest{}
 
@@ -32,10 +35,10 @@ func TestGetSessionsUseCase_NoSessions(t *testing.T) {
 func TestGetSessionsUseCase_MultipleSessions(t *testing.T) {
 	// arrange
 	sessionID1, _ := domain.NewSessionID("session-one")
-	session1, _ := domain.NewSession(sessionID1, "/path/session-one")
+	session1, _ := domain.NewSession(sessionID1, "/path/session-one", "")
 
 	sessionID2, _ := domain.NewSessionID("session-two")
-	session2, _ := domain.NewSession(sessionID2, "/path/session-two")
+	session2, _ := domain.NewSession(sessionID2, "/path/session-two", "")
 
 	mockGitOps := &MockGitOperations{
 		diffStats: map[string]*domain.GitDiffStats{
@@ -57,7 +60,7 @@ func TestGetSessionsUseCase_MultipleSessions(t *testing.T) {
 		},
 	}
 
-	useCase := NewGetSessionsUseCase(mockGitOps, mockRepo, "main")
+	useCase := NewGetSessionsUseCase(mockGitOps, mockRepo, "main", 50)
 	ctx := context.Background()
 	request := GetSessionsRequest{}
 
@@ -103,10 +106,10 @@ func TestGetSessionsUseCase_MultipleSessions(t *testing.T) {
 func TestGetSessionsUseCase_GitStatsError_ContinuesWithOtherSessions(t *testing.T) {
 	// arrange
 	sessionID1, _ := domain.NewSessionID("session-one")
-	session1, _ := domain.NewSession(sessionID1, "/path/session-one")
+	session1, _ := domain.NewSession(sessionID1, "/path/session-one", "")
 
 	sessionID2, _ := domain.NewSessionID("session-two")
-	session2, _ := domain.NewSession(sessionID2, "/path/session-two")
+	session2, _ := domain.NewSession(sessionID2, "/path/session-two", "")
 
 	mockGitOps := &MockGitOperations{
 		diffStats: map[string]*domain.GitDiffStats{
@@ -125,7 +128,7 @@ func TestGetSessionsUseCase_GitStatsError_ContinuesWithOtherSessions(t *testing.
 		},
 	}
 
-	useCase := NewGetSessionsUseCase(mockGitOps, mockRepo, "main")
+	useCase := NewGetSessionsUseCase(mockGitOps, mockRepo, "main", 50)
 	ctx := context.Background()
 	request := GetSessionsRequest{}
 
@@ -156,3 +159,358 @@ func TestGetSessionsUseCase_GitStatsError_ContinuesWithOtherSessions(t *testing.
 		t.Errorf("Session two LinesAdded = %d, want 5", session2DTO.LinesAdded)
 	}
 }
+
+func TestGetSessionsUseCase_UsesEachSessionsBaseRef(t *testing.T) {
+	// arrange
+	legacySessionID, _ := domain.NewSessionID("legacy")
+	legacySession, _ := domain.NewSession(legacySessionID, "/path/legacy", "")
+
+	releaseSessionID, _ := domain.NewSessionID("release")
+	releaseSession, _ := domain.NewSession(releaseSessionID, "/path/release", "release/1.0")
+
+	receivedBaseRefs := make(map[string]string)
+	gitOperations := &mockGitOperations{
+		getDiffStatsFunc: func(ctx context.Context, worktreePath string, baseBranch string) (*domain.GitDiffStats, error) {
+			receivedBaseRefs[worktreePath] = baseBranch
+			return &domain.GitDiffStats{}, nil
+		},
+	}
+	sessionRepository := newMockSessionRepository()
+	sessionRepository.Save(context.Background(), legacySession)
+	sessionRepository.Save(context.Background(), releaseSession)
+
+	useCase := NewGetSessionsUseCase(gitOperations, sessionRepository, "main", 50)
+	ctx := context.Background()
+
+	// act
+	response, err := useCase.Execute(ctx, GetSessionsRequest{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if receivedBaseRefs["/path/legacy"] != "main" {
+		t.Errorf("legacy session compared against %q, want %q", receivedBaseRefs["/path/legacy"], "main")
+	}
+	if receivedBaseRefs["/path/release"] != "release/1.0" {
+		t.Errorf("release session compared against %q, want %q", receivedBaseRefs["/path/release"], "release/1.0")
+	}
+	for _, sessionDTO := range response.Sessions {
+		if sessionDTO.SessionID == "release" && sessionDTO.BaseRef != "release/1.0" {
+			t.Errorf("release session BaseRef = %q, want %q", sessionDTO.BaseRef, "release/1.0")
+		}
+	}
+}
+
+func TestGetSessionsUseCase_MeasuresAgainstPinnedBaseCommit(t *testing.T) {
+	// arrange
+	baseCommit := "3333333333333333333333333333333333333333"
+	sessionID, _ := domain.NewSessionID("pinned")
+	session, _ := domain.NewSession(sessionID, "/path/pinned", "main")
+	session.SetBaseCommit(baseCommit)
+
+	var receivedBase string
+	gitOperations := &mockGitOperations{
+		getDiffStatsFunc: func(ctx context.Context, worktreePath string, baseBranch string) (*domain.GitDiffStats, error) {
+			receivedBase = baseBranch
+			return &domain.GitDiffStats{}, nil
+		},
+	}
+	sessionRepository := newMockSessionRepository()
+	sessionRepository.Save(context.Background(), session)
+
+	useCase := NewGetSessionsUseCase(gitOperations, sessionRepository, "main", 50)
+	ctx := context.Background()
+
+	// act
+	response, err := useCase.Execute(ctx, GetSessionsRequest{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if receivedBase != baseCommit {
+		t.Errorf("GetDiffStats() base = %q, want pinned commit %q", receivedBase, baseCommit)
+	}
+	if response.Sessions[0].BaseCommit != baseCommit {
+		t.Errorf("BaseCommit = %q, want %q", response.Sessions[0].BaseCommit, baseCommit)
+	}
+}
+
+func TestGetSessionsUseCase_IncludesPerFileChanges(t *testing.T) {
+	// arrange
+	sessionID, _ := domain.NewSessionID("session-one")
+	session, _ := domain.NewSession(sessionID, "/path/session-one", "")
+
+	mockGitOps := &MockGitOperations{
+		diffStats: map[string]*domain.GitDiffStats{
+			"session-one": domain.NewGitDiffStats([]domain.FileChange{
+				{Path: "cmd/main.go", ChangeType: domain.FileChangeModified, LinesAdded: 2, LinesRemoved: 1},
+				{Path: "assets/logo.png", ChangeType: domain.FileChangeAdded, Binary: true},
+				{Path: "docs/new.md", OldPath: "docs/old.md", ChangeType: domain.FileChangeRenamed},
+			}),
+		},
+	}
+	mockRepo := &MockSessionRepository{
+		sessions: map[string]*domain.Session{"session-one": session},
+	}
+	useCase := NewGetSessionsUseCase(mockGitOps, mockRepo, "main", 50)
+
+	// act
+	response, err := useCase.Execute(context.Background(), GetSessionsRequest{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	sessionDTO := response.Sessions[0]
+	if sessionDTO.FilesChanged != 3 {
+		t.Errorf("FilesChanged = %d, want 3", sessionDTO.FilesChanged)
+	}
+	if len(sessionDTO.Files) != 3 {
+		t.Fatalf("Files has %d entries, want 3", len(sessionDTO.Files))
+	}
+	if !sessionDTO.Files[1].Binary || sessionDTO.Files[1].ChangeType != "added" {
+		t.Errorf("Files[1] = %+v, want added binary file", sessionDTO.Files[1])
+	}
+	if sessionDTO.Files[2].OldPath != "docs/old.md" || sessionDTO.Files[2].ChangeType != "renamed" {
+		t.Errorf("Files[2] = %+v, want rename from docs/old.md", sessionDTO.Files[2])
+	}
+}
+
+func TestGetSessionsUseCase_IncludesDiffBreakdown(t *testing.T) {
+	// arrange
+	sessionID, _ := domain.NewSessionID("session-one")
+	session, _ := domain.NewSession(sessionID, "/path/session-one", "")
+
+	stats := domain.NewGitDiffStats([]domain.FileChange{
+		{Path: "new.go", ChangeType: domain.FileChangeAdded, LinesAdded: 10, Untracked: true},
+	})
+	stats.Breakdown.Untracked = domain.DiffContribution{LinesAdded: 10, FilesChanged: 1}
+	stats.Breakdown.Staged = domain.DiffContribution{LinesAdded: 4, LinesRemoved: 2, FilesChanged: 1}
+
+	mockGitOps := &MockGitOperations{
+		diffStats: map[string]*domain.GitDiffStats{"session-one": stats},
+	}
+	mockRepo := &MockSessionRepository{
+		sessions: map[string]*domain.Session{"session-one": session},
+	}
+	useCase := NewGetSessionsUseCase(mockGitOps, mockRepo, "main", 50)
+
+	// act
+	response, err := useCase.Execute(context.Background(), GetSessionsRequest{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	sessionDTO := response.Sessions[0]
+	if sessionDTO.Breakdown.Untracked.LinesAdded != 10 {
+		t.Errorf("Breakdown.Untracked.LinesAdded = %d, want 10", sessionDTO.Breakdown.Untracked.LinesAdded)
+	}
+	if sessionDTO.Breakdown.Staged.LinesRemoved != 2 {
+		t.Errorf("Breakdown.Staged.LinesRemoved = %d, want 2", sessionDTO.Breakdown.Staged.LinesRemoved)
+	}
+	if !sessionDTO.Files[0].Untracked {
+		t.Error("Files[0].Untracked = false, want true")
+	}
+}
+
+func TestGetSessionsUseCase_ReportsMergeable(t *testing.T) {
+	// arrange
+	cleanSessionID, _ := domain.NewSessionID("clean")
+	cleanSession, _ := domain.NewSession(cleanSessionID, "/path/clean", "")
+	conflictedSessionID, _ := domain.NewSessionID("conflicted")
+	conflictedSession, _ := domain.NewSession(conflictedSessionID, "/path/conflicted", "")
+	mergedSessionID, _ := domain.NewSessionID("merged")
+	mergedSession, _ := domain.NewSession(mergedSessionID, "/path/merged", "")
+	mergedSession.MarkMerged()
+
+	var mergeChecks int
+	gitOperations := &mockGitOperations{
+		checkMergeFunc: func(ctx context.Context, baseRef string, sessionBranch string) (*domain.MergeCheck, error) {
+			mergeChecks++
+			return &domain.MergeCheck{Clean: sessionBranch == cleanSession.BranchName()}, nil
+		},
+	}
+	sessionRepository := newMockSessionRepository()
+	sessionRepository.Save(context.Background(), cleanSession)
+	sessionRepository.Save(context.Background(), conflictedSession)
+	sessionRepository.Save(context.Background(), mergedSession)
+	useCase := NewGetSessionsUseCase(gitOperations, sessionRepository, "main", 50)
+
+	// act
+	withoutCheck, withoutCheckErr := useCase.Execute(context.Background(), GetSessionsRequest{})
+	response, err := useCase.Execute(context.Background(), GetSessionsRequest{CheckMergeable: true})
+
+	// assert
+	if withoutCheckErr != nil || err != nil {
+		t.Fatalf("Execute() errors: %v, %v", withoutCheckErr, err)
+	}
+	for _, sessionDTO := range withoutCheck.Sessions {
+		if sessionDTO.Mergeable != nil {
+			t.Errorf("session %s reports mergeable without checkMergeable", sessionDTO.SessionID)
+		}
+	}
+	if mergeChecks != 2 {
+		t.Errorf("ran %d merge checks, want one per unmerged session and only when asked", mergeChecks)
+	}
+	for _, sessionDTO := range response.Sessions {
+		switch sessionDTO.SessionID {
+		case "clean":
+			if sessionDTO.Mergeable == nil || !*sessionDTO.Mergeable {
+				t.Error("clean session should be mergeable")
+			}
+		case "conflicted":
+			if sessionDTO.Mergeable == nil || *sessionDTO.Mergeable {
+				t.Error("conflicted session should not be mergeable")
+			}
+		case "merged":
+			if sessionDTO.Mergeable != nil {
+				t.Error("merged session should not report mergeable")
+			}
+		}
+	}
+}
+
+func TestGetSessionsUseCase_ReportsDivergenceAndFiltersStale(t *testing.T) {
+	// arrange
+	lastCommitAt := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	behindByBranch := make(map[string]int)
+
+	sessionRepository := newMockSessionRepository()
+	for sessionName, behind := range map[string]int{"fresh": 3, "stale": 80} {
+		sessionID, _ := domain.NewSessionID(sessionName)
+		session, _ := domain.NewSession(sessionID, "/path/"+sessionName, "")
+		sessionRepository.Save(context.Background(), session)
+		behindByBranch[session.BranchName()] = behind
+	}
+
+	gitOperations := &mockGitOperations{
+		getDivergenceFunc: func(ctx context.Context, baseRef string, sessionBranch string) (*domain.BranchDivergence, error) {
+			return &domain.BranchDivergence{Ahead: 2, Behind: behindByBranch[sessionBranch], LastCommitAt: lastCommitAt}, nil
+		},
+	}
+	useCase := NewGetSessionsUseCase(gitOperations, sessionRepository, "main", 50)
+	ctx := context.Background()
+	behindMoreThan := 80
+
+	// act
+	allSessions, allErr := useCase.Execute(ctx, GetSessionsRequest{})
+	staleSessions, staleErr := useCase.Execute(ctx, GetSessionsRequest{StaleOnly: true})
+	behindSessions, behindErr := useCase.Execute(ctx, GetSessionsRequest{BehindMoreThan: &behindMoreThan})
+
+	// assert
+	if allErr != nil || staleErr != nil || behindErr != nil {
+		t.Fatalf("Execute() errors: %v, %v, %v", allErr, staleErr, behindErr)
+	}
+	if len(allSessions.Sessions) != 2 {
+		t.Fatalf("Execute() returned %d sessions, want 2", len(allSessions.Sessions))
+	}
+	for _, sessionDTO := range allSessions.Sessions {
+		if sessionDTO.Ahead != 2 || sessionDTO.LastCommitAt == nil || !sessionDTO.LastCommitAt.Equal(lastCommitAt) {
+			t.Errorf("session %s = ahead %d last commit %v, want ahead 2 at %v", sessionDTO.SessionID, sessionDTO.Ahead, sessionDTO.LastCommitAt, lastCommitAt)
+		}
+		if wantStale := sessionDTO.SessionID == "stale"; sessionDTO.Stale != wantStale {
+			t.Errorf("session %s Stale = %v, want %v", sessionDTO.SessionID, sessionDTO.Stale, wantStale)
+		}
+	}
+	if len(staleSessions.Sessions) != 1 || staleSessions.Sessions[0].Behind != 80 {
+		t.Errorf("StaleOnly returned %+v, want only the session 80 commits behind", staleSessions.Sessions)
+	}
+	if len(behindSessions.Sessions) != 0 {
+		t.Errorf("BehindMoreThan=80 returned %d sessions, want 0 since 80 is not more than 80", len(behindSessions.Sessions))
+	}
+}
+
+func TestGetSessionsUseCase_DivergenceError_ReportedAndKeptByFilters(t *testing.T) {
+	// arrange
+	sessionID, _ := domain.NewSessionID("broken")
+	session, _ := domain.NewSession(sessionID, "/path/broken", "")
+	sessionRepository := newMockSessionRepository()
+	sessionRepository.Save(context.Background(), session)
+	gitOperations := &mockGitOperations{
+		getDivergenceFunc: func(ctx context.Context, baseRef string, sessionBranch string) (*domain.BranchDivergence, error) {
+			return nil, errors.New("unknown revision")
+		},
+	}
+	useCase := NewGetSessionsUseCase(gitOperations, sessionRepository, "main", 50)
+
+	// act
+	response, err := useCase.Execute(context.Background(), GetSessionsRequest{StaleOnly: true})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if len(response.Sessions) != 1 {
+		t.Fatalf("Execute() returned %d sessions, want the broken one", len(response.Sessions))
+	}
+	sessionDTO := response.Sessions[0]
+	if len(sessionDTO.Errors) != 1 || !strings.Contains(sessionDTO.Errors[0], "unknown revision") {
+		t.Errorf("Errors = %q, want the divergence failure", sessionDTO.Errors)
+	}
+	if sessionDTO.LastCommitAt != nil || sessionDTO.Stale {
+		t.Errorf("session = %+v, want no last commit and not stale", sessionDTO)
+	}
+}
+
+func TestGetSessionsUseCase_CheckpointError_ReportedPerSession(t *testing.T) {
+	// arrange
+	sessionID, _ := domain.NewSessionID("broken")
+	session, _ := domain.NewSession(sessionID, "/path/broken", "")
+	sessionRepository := newMockSessionRepository()
+	sessionRepository.Save(context.Background(), session)
+	gitOperations := &mockGitOperations{
+		listCheckpointsFunc: func(ctx context.Context, refPrefix string) ([]domain.Checkpoint, error) {
+			return nil, errors.New("bad object")
+		},
+	}
+	useCase := NewGetSessionsUseCase(gitOperations, sessionRepository, "main", 50)
+
+	// act
+	response, err := useCase.Execute(context.Background(), GetSessionsRequest{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	sessionDTO := response.Sessions[0]
+	if len(sessionDTO.Errors) != 1 || !strings.Contains(sessionDTO.Errors[0], "failed to list checkpoints: bad object") {
+		t.Errorf("Errors = %q, want the checkpoint failure", sessionDTO.Errors)
+	}
+	if sessionDTO.Checkpoints == nil || len(sessionDTO.Checkpoints) != 0 {
+		t.Errorf("Checkpoints = %v, want an empty list", sessionDTO.Checkpoints)
+	}
+}
+
+func TestGetSessionsUseCase_StackTree_DrawsOnlyFilteredSessions(t *testing.T) {
+	// arrange
+	sessionRepository := newMockSessionRepository()
+	parent, child, _ := saveTestStack(t, sessionRepository)
+	gitOperations := &mockGitOperations{
+		getDivergenceFunc: func(ctx context.Context, baseRef string, sessionBranch string) (*domain.BranchDivergence, error) {
+			if sessionBranch == parent.BranchName() {
+				return &domain.BranchDivergence{}, nil
+			}
+			return &domain.BranchDivergence{Behind: 80}, nil
+		},
+	}
+	useCase := NewGetSessionsUseCase(gitOperations, sessionRepository, "main", 50)
+	ctx := context.Background()
+
+	// act
+	allSessions, allErr := useCase.Execute(ctx, GetSessionsRequest{})
+	staleSessions, staleErr := useCase.Execute(ctx, GetSessionsRequest{StaleOnly: true})
+
+	// assert
+	if allErr != nil || staleErr != nil {
+		t.Fatalf("Execute() errors: %v, %v", allErr, staleErr)
+	}
+	if allSessions.StackTree != "parent (on main)\n└── child\n    └── grandchild" {
+		t.Errorf("StackTree = %q, want the whole stack", allSessions.StackTree)
+	}
+	if wantTree := "child (on " + child.BaseRef() + ")\n└── grandchild"; staleSessions.StackTree != wantTree {
+		t.Errorf("StaleOnly StackTree = %q, want %q without the filtered parent", staleSessions.StackTree, wantTree)
+	}
+}
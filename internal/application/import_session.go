@@ -0,0 +1,215 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type ImportSessionRequest struct {
+	// InputPath names a directory written by export_session inside the
+	// export directory
+	InputPath string
+	// SessionID imports the session under a different ID; empty keeps the
+	// exported one
+	SessionID string
+}
+
+type ImportSessionResponse struct {
+	SessionID    string       `json:"sessionId"`
+	Format       ExportFormat `json:"format"`
+	WorktreePath string       `json:"worktreePath"`
+	BranchName   string       `json:"branchName"`
+	BaseRef      string       `json:"baseRef"`
+	BaseCommit   string       `json:"baseCommit,omitempty"`
+	HeadCommit   string       `json:"headCommit"`
+	Commits      int          `json:"commits"`
+}
+
+type ImportSessionUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+	sessionArchives   domain.SessionArchives
+	worktreeDirectory string
+}
+
+func NewImportSessionUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+	sessionArchives domain.SessionArchives,
+	worktreeDirectory string,
+) *ImportSessionUseCase {
+	return &ImportSessionUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+		sessionArchives:   sessionArchives,
+		worktreeDirectory: worktreeDirectory,
+	}
+}
+
+// Execute recreates an exported session: its branch, a worktree for it and
+// its session record. The exported base commit must already be in this
+// repository. If anything fails after the worktree was created, the worktree
+// and branch are removed again.
+func (importSessionUseCase *ImportSessionUseCase) Execute(
+	ctx context.Context,
+	request ImportSessionRequest,
+) (*ImportSessionResponse, error) {
+	inputPath, err := importSessionUseCase.sessionArchives.Locate(ctx, request.InputPath)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := readSessionManifest(ctx, importSessionUseCase.sessionArchives, request.InputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionIDString := strings.TrimSpace(request.SessionID)
+	if sessionIDString == "" {
+		sessionIDString = manifest.SessionID
+	}
+	sessionID, err := domain.NewSessionID(sessionIDString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+	if err := importSessionUseCase.ensureSessionIsNew(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	worktreePath := filepath.Join(importSessionUseCase.worktreeDirectory, sessionID.WorktreeDirName())
+	if err := importSessionUseCase.restoreBranch(ctx, manifest, inputPath, worktreePath, sessionID.BranchName()); err != nil {
+		return nil, err
+	}
+
+	session, headCommit, err := importSessionUseCase.saveSession(ctx, manifest, sessionID, worktreePath)
+	if err != nil {
+		importSessionUseCase.gitOperations.RemoveWorktree(ctx, worktreePath, true)
+		importSessionUseCase.gitOperations.DeleteBranch(ctx, sessionID.BranchName(), true)
+		return nil, err
+	}
+
+	return &ImportSessionResponse{
+		SessionID:    session.ID().String(),
+		Format:       manifest.Format,
+		WorktreePath: session.WorktreePath(),
+		BranchName:   session.BranchName(),
+		BaseRef:      session.BaseRef(),
+		BaseCommit:   session.BaseCommit(),
+		HeadCommit:   headCommit,
+		Commits:      manifest.Commits,
+	}, nil
+}
+
+func (importSessionUseCase *ImportSessionUseCase) ensureSessionIsNew(ctx context.Context, sessionID domain.SessionID) error {
+	exists, err := importSessionUseCase.sessionRepository.Exists(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to check session existence: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("session already exists: %s", sessionID)
+	}
+
+	branchExists, err := importSessionUseCase.gitOperations.BranchExists(ctx, sessionID.BranchName())
+	if err != nil {
+		return fmt.Errorf("failed to check branch existence: %w", err)
+	}
+	if branchExists {
+		return fmt.Errorf("branch already exists: %s", sessionID.BranchName())
+	}
+	return nil
+}
+
+// restoreBranch creates the session branch and worktree from the exported
+// commits. A bundle restores the exact commits; patches are replayed onto
+// the base commit and get new SHAs.
+func (importSessionUseCase *ImportSessionUseCase) restoreBranch(
+	ctx context.Context,
+	manifest *sessionManifest,
+	inputPath string,
+	worktreePath string,
+	branchName string,
+) error {
+	gitOperations := importSessionUseCase.gitOperations
+
+	switch manifest.Format {
+	case ExportFormatBundle:
+		headCommit, err := gitOperations.FetchBundle(ctx, filepath.Join(inputPath, sessionBundleName), "refs/heads/"+manifest.BranchName)
+		if err != nil {
+			return err
+		}
+		if err := gitOperations.CreateWorktree(ctx, worktreePath, branchName, headCommit); err != nil {
+			return fmt.Errorf("failed to create worktree: %w", err)
+		}
+		return nil
+	case ExportFormatPatches:
+		if manifest.BaseCommit == "" {
+			return errors.New("patch exports need a base commit to apply onto")
+		}
+		if _, err := gitOperations.ResolveCommit(ctx, manifest.BaseCommit); err != nil {
+			return fmt.Errorf("base commit %s is not in this repository; fetch %s first: %w", manifest.BaseCommit, manifest.BaseRef, err)
+		}
+
+		patchPaths := make([]string, 0, len(manifest.Patches))
+		for _, patch := range manifest.Patches {
+			patchPath := filepath.Join(inputPath, filepath.FromSlash(patch))
+			if !strings.HasPrefix(patchPath, filepath.Clean(inputPath)+string(filepath.Separator)) {
+				return fmt.Errorf("patch %q lies outside the export directory", patch)
+			}
+			patchPaths = append(patchPaths, patchPath)
+		}
+
+		if err := gitOperations.CreateWorktree(ctx, worktreePath, branchName, manifest.BaseCommit); err != nil {
+			return fmt.Errorf("failed to create worktree: %w", err)
+		}
+		if err := gitOperations.ApplyPatches(ctx, worktreePath, patchPaths); err != nil {
+			gitOperations.RemoveWorktree(ctx, worktreePath, true)
+			gitOperations.DeleteBranch(ctx, branchName, true)
+			return err
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported export format %q", manifest.Format)
+	}
+}
+
+// saveSession records the imported session with the exported base and
+// review state. Merged sessions come back as open, since their work is not
+// merged into this repository.
+func (importSessionUseCase *ImportSessionUseCase) saveSession(
+	ctx context.Context,
+	manifest *sessionManifest,
+	sessionID domain.SessionID,
+	worktreePath string,
+) (*domain.Session, string, error) {
+	session, err := domain.NewSession(sessionID, worktreePath, manifest.BaseRef)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create session: %w", err)
+	}
+	if manifest.BaseCommit != "" {
+		if err := session.SetBaseCommit(manifest.BaseCommit); err != nil {
+			return nil, "", fmt.Errorf("failed to create session: %w", err)
+		}
+	}
+	if manifest.PullRequest != nil {
+		if err := session.SetPullRequest(domain.PullRequest{Number: manifest.PullRequest.Number, URL: manifest.PullRequest.URL}); err != nil {
+			return nil, "", fmt.Errorf("failed to create session: %w", err)
+		}
+	}
+	if domain.SessionStatus(manifest.Status) == domain.StatusReviewed {
+		session.MarkReviewed()
+	}
+
+	headCommit, err := importSessionUseCase.gitOperations.ResolveCommit(ctx, session.BranchName())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve imported branch: %w", err)
+	}
+
+	if err := importSessionUseCase.sessionRepository.Save(ctx, session); err != nil {
+		return nil, "", fmt.Errorf("failed to save session: %w", err)
+	}
+	return session, headCommit, nil
+}
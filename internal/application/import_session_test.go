@@ -0,0 +1,160 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func writeTestManifest(t *testing.T, manifest sessionManifest) *mockSessionArchives {
+	t.Helper()
+
+	sessionArchives := newMockSessionArchives()
+	sessionArchives.Create(context.Background(), "export")
+	manifest.Version = sessionArchiveVersion
+	manifest.ExportedAt = time.Now().UTC()
+	if err := writeSessionManifest(context.Background(), sessionArchives, "export", manifest); err != nil {
+		t.Fatalf("writeSessionManifest() error: %v", err)
+	}
+	return sessionArchives
+}
+
+func TestImportSessionUseCase_Execute_Bundle_RecreatesSession(t *testing.T) {
+	// arrange
+	sessionArchives := writeTestManifest(t, sessionManifest{
+		Format:      ExportFormatBundle,
+		SessionID:   "original",
+		BranchName:  "orchestragent-original",
+		BaseRef:     "develop",
+		BaseCommit:  "0123456789abcdef0123456789abcdef01234567",
+		Status:      "reviewed",
+		PullRequest: &PullRequestDTO{Number: 4, URL: "https://forge.example.com/pull/4"},
+		Commits:     2,
+	})
+	var fetchedRef, worktreeStartPoint string
+	gitOperations := &mockGitOperations{
+		fetchBundleFunc: func(ctx context.Context, bundlePath string, ref string) (string, error) {
+			fetchedRef = ref
+			return "89abcdef0123456789abcdef0123456789abcdef", nil
+		},
+		createWorktreeFunc: func(ctx context.Context, path string, branch string, baseRef string) error {
+			worktreeStartPoint = baseRef
+			return nil
+		},
+	}
+	sessionRepository := newMockSessionRepository()
+	useCase := NewImportSessionUseCase(gitOperations, sessionRepository, sessionArchives, "/worktrees")
+
+	// act
+	response, err := useCase.Execute(context.Background(), ImportSessionRequest{InputPath: "export", SessionID: "renamed"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if fetchedRef != "refs/heads/orchestragent-original" || worktreeStartPoint != "89abcdef0123456789abcdef0123456789abcdef" {
+		t.Errorf("fetched %q and started worktree at %q, want the bundled branch tip", fetchedRef, worktreeStartPoint)
+	}
+	if response.SessionID != "renamed" || response.BranchName != "orchestragent-renamed" {
+		t.Errorf("response = %+v, want the renamed session", response)
+	}
+	saved := sessionRepository.sessions["renamed"]
+	if saved == nil || saved.BaseRef() != "develop" || saved.Status() != domain.StatusReviewed || saved.PullRequest().Number != 4 {
+		t.Errorf("saved session = %+v, want the exported metadata", saved)
+	}
+}
+
+func TestImportSessionUseCase_Execute_ExistingSession_ReturnsError(t *testing.T) {
+	// arrange
+	sessionArchives := writeTestManifest(t, sessionManifest{Format: ExportFormatBundle, SessionID: "test-session", BranchName: "orchestragent-test-session"})
+	sessionRepository := newMockSessionRepository()
+	sessionID, _ := domain.NewSessionID("test-session")
+	session, _ := domain.NewSession(sessionID, "/path/test-session", "")
+	sessionRepository.Save(context.Background(), session)
+	useCase := NewImportSessionUseCase(&mockGitOperations{}, sessionRepository, sessionArchives, "/worktrees")
+
+	// act
+	_, err := useCase.Execute(context.Background(), ImportSessionRequest{InputPath: "export"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for an existing session")
+	}
+}
+
+func TestImportSessionUseCase_Execute_PatchesFailToApply_CleansUp(t *testing.T) {
+	// arrange
+	sessionArchives := writeTestManifest(t, sessionManifest{
+		Format:     ExportFormatPatches,
+		SessionID:  "test-session",
+		BranchName: "orchestragent-test-session",
+		BaseRef:    "main",
+		BaseCommit: "0123456789abcdef0123456789abcdef01234567",
+		Patches:    []string{"patches/0001-Add-feature.patch"},
+	})
+
+	var removedWorktree, deletedBranch string
+	gitOperations := &mockGitOperations{
+		applyPatchesFunc: func(ctx context.Context, worktreePath string, patchPaths []string) error {
+			return errors.New("patch does not apply")
+		},
+		removeWorktreeFunc: func(ctx context.Context, path string, force bool) error {
+			removedWorktree = path
+			return nil
+		},
+		deleteBranchFunc: func(ctx context.Context, branchName string, force bool) error {
+			deletedBranch = branchName
+			return nil
+		},
+	}
+	sessionRepository := newMockSessionRepository()
+	useCase := NewImportSessionUseCase(gitOperations, sessionRepository, sessionArchives, "/worktrees")
+
+	// act
+	_, err := useCase.Execute(context.Background(), ImportSessionRequest{InputPath: "export"})
+
+	// assert
+	if err == nil {
+		t.Fatal("Execute() expected error when patches do not apply")
+	}
+	if removedWorktree != filepath.Join("/worktrees", "orchestragent-test-session") || deletedBranch != "orchestragent-test-session" {
+		t.Errorf("removed %q and deleted %q, want the new worktree and branch cleaned up", removedWorktree, deletedBranch)
+	}
+	if len(sessionRepository.sessions) != 0 {
+		t.Error("no session should be saved when the import fails")
+	}
+}
+
+func TestImportSessionUseCase_Execute_PatchOutsideExport_ReturnsError(t *testing.T) {
+	// arrange
+	sessionArchives := writeTestManifest(t, sessionManifest{
+		Format:     ExportFormatPatches,
+		SessionID:  "test-session",
+		BranchName: "orchestragent-test-session",
+		BaseCommit: "0123456789abcdef0123456789abcdef01234567",
+		Patches:    []string{"../../etc/passwd"},
+	})
+	createWorktreeCalled := false
+	gitOperations := &mockGitOperations{
+		createWorktreeFunc: func(ctx context.Context, path string, branch string, baseRef string) error {
+			createWorktreeCalled = true
+			return nil
+		},
+	}
+	useCase := NewImportSessionUseCase(gitOperations, newMockSessionRepository(), sessionArchives, "/worktrees")
+
+	// act
+	_, err := useCase.Execute(context.Background(), ImportSessionRequest{InputPath: "export"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for a patch outside the export")
+	}
+	if createWorktreeCalled {
+		t.Error("CreateWorktree() should not run for an invalid manifest")
+	}
+}
@@ -0,0 +1,80 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type ListCheckpointsRequest struct {
+	SessionID string
+}
+
+type CheckpointDTO struct {
+	Name       string    `json:"name"`
+	CommitSHA  string    `json:"commitSha"`
+	HeadCommit string    `json:"headCommit"`
+	Message    string    `json:"message"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type ListCheckpointsResponse struct {
+	SessionID   string          `json:"sessionId"`
+	Checkpoints []CheckpointDTO `json:"checkpoints"`
+}
+
+type ListCheckpointsUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+}
+
+func NewListCheckpointsUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+) *ListCheckpointsUseCase {
+	return &ListCheckpointsUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+	}
+}
+
+// Execute lists the session's checkpoints, oldest first
+func (listCheckpointsUseCase *ListCheckpointsUseCase) Execute(
+	ctx context.Context,
+	request ListCheckpointsRequest,
+) (*ListCheckpointsResponse, error) {
+	session, err := findSession(ctx, listCheckpointsUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints, err := listCheckpointsUseCase.gitOperations.ListCheckpoints(ctx, session.ID().CheckpointRefPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	return &ListCheckpointsResponse{
+		SessionID:   session.ID().String(),
+		Checkpoints: buildCheckpointDTOs(checkpoints),
+	}, nil
+}
+
+func buildCheckpointDTO(checkpoint domain.Checkpoint) CheckpointDTO {
+	return CheckpointDTO{
+		Name:       checkpoint.Name,
+		CommitSHA:  checkpoint.SHA,
+		HeadCommit: checkpoint.HeadSHA,
+		Message:    checkpoint.Message,
+		CreatedAt:  checkpoint.CreatedAt,
+	}
+}
+
+func buildCheckpointDTOs(checkpoints []domain.Checkpoint) []CheckpointDTO {
+	checkpointDTOs := make([]CheckpointDTO, 0, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		checkpointDTOs = append(checkpointDTOs, buildCheckpointDTO(checkpoint))
+	}
+	return checkpointDTOs
+}
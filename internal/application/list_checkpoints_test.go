@@ -0,0 +1,49 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func TestListCheckpointsUseCase_Execute_SessionNotFound(t *testing.T) {
+	// arrange
+	useCase := NewListCheckpointsUseCase(&mockGitOperations{}, newMockSessionRepository())
+
+	// act
+	_, err := useCase.Execute(context.Background(), ListCheckpointsRequest{SessionID: "nonexistent"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for non-existent session")
+	}
+}
+
+func TestListCheckpointsUseCase_Execute_ListsSessionCheckpoints(t *testing.T) {
+	// arrange
+	createdAt := time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC)
+	var receivedPrefix string
+	gitOperations := &mockGitOperations{
+		listCheckpointsFunc: func(ctx context.Context, refPrefix string) ([]domain.Checkpoint, error) {
+			receivedPrefix = refPrefix
+			return []domain.Checkpoint{{Name: "checkpoint-1", SHA: "abc", HeadSHA: "def", Message: "Before tests", CreatedAt: createdAt}}, nil
+		},
+	}
+	useCase := NewListCheckpointsUseCase(gitOperations, setupCheckpointTest(t))
+
+	// act
+	response, err := useCase.Execute(context.Background(), ListCheckpointsRequest{SessionID: "test-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if receivedPrefix != "refs/orchestragent/test-session/checkpoints/" {
+		t.Errorf("ListCheckpoints() prefix = %q, want the session's checkpoint namespace", receivedPrefix)
+	}
+	if len(response.Checkpoints) != 1 || response.Checkpoints[0].HeadCommit != "def" || !response.Checkpoints[0].CreatedAt.Equal(createdAt) {
+		t.Errorf("Checkpoints = %+v, want the one checkpoint", response.Checkpoints)
+	}
+}
@@ -0,0 +1,80 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type ListDirectoryRequest struct {
+	SessionID string
+	// Path is the directory to list; empty lists the worktree root
+	Path string
+}
+
+type DirectoryEntryDTO struct {
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`
+	Type       string    `json:"type"`
+	SizeBytes  int64     `json:"sizeBytes"`
+	ModifiedAt time.Time `json:"modifiedAt"`
+}
+
+type ListDirectoryResponse struct {
+	SessionID string              `json:"sessionId"`
+	Path      string              `json:"path"`
+	Entries   []DirectoryEntryDTO `json:"entries"`
+}
+
+type ListDirectoryUseCase struct {
+	worktreeFiles     domain.WorktreeFiles
+	sessionRepository domain.SessionRepository
+}
+
+func NewListDirectoryUseCase(
+	worktreeFiles domain.WorktreeFiles,
+	sessionRepository domain.SessionRepository,
+) *ListDirectoryUseCase {
+	return &ListDirectoryUseCase{
+		worktreeFiles:     worktreeFiles,
+		sessionRepository: sessionRepository,
+	}
+}
+
+func (listDirectoryUseCase *ListDirectoryUseCase) Execute(
+	ctx context.Context,
+	request ListDirectoryRequest,
+) (*ListDirectoryResponse, error) {
+	session, err := findSession(ctx, listDirectoryUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := listDirectoryUseCase.worktreeFiles.ListDirectory(ctx, session.WorktreePath(), request.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directory: %w", err)
+	}
+
+	entryDTOs := make([]DirectoryEntryDTO, 0, len(entries))
+	for _, entry := range entries {
+		entryDTOs = append(entryDTOs, DirectoryEntryDTO{
+			Name:       entry.Name,
+			Path:       entry.Path,
+			Type:       string(entry.Type),
+			SizeBytes:  entry.SizeBytes,
+			ModifiedAt: entry.ModifiedAt,
+		})
+	}
+
+	path := request.Path
+	if path == "" {
+		path = "."
+	}
+	return &ListDirectoryResponse{
+		SessionID: session.ID().String(),
+		Path:      path,
+		Entries:   entryDTOs,
+	}, nil
+}
@@ -0,0 +1,37 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func TestListDirectoryUseCase_Execute_EmptyPath_ListsWorktreeRoot(t *testing.T) {
+	// arrange
+	var listedPath string
+	worktreeFiles := &mockWorktreeFiles{
+		listDirectoryFunc: func(ctx context.Context, worktreePath string, path string) ([]domain.DirectoryEntry, error) {
+			listedPath = path
+			return []domain.DirectoryEntry{
+				{Name: "README.md", Path: "README.md", Type: domain.DirectoryEntryFile, SizeBytes: 10},
+				{Name: "src", Path: "src", Type: domain.DirectoryEntryDirectory},
+			}, nil
+		},
+	}
+	useCase := NewListDirectoryUseCase(worktreeFiles, setupFileSession(t, "test-session", false))
+
+	// act
+	response, err := useCase.Execute(context.Background(), ListDirectoryRequest{SessionID: "test-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if listedPath != "" || response.Path != "." {
+		t.Errorf("listed %q reported as %q, want the root reported as .", listedPath, response.Path)
+	}
+	if len(response.Entries) != 2 || response.Entries[0].Type != "file" || response.Entries[1].Type != "directory" {
+		t.Errorf("entries = %+v, want README.md and src", response.Entries)
+	}
+}
@@ -0,0 +1,196 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type MergeSessionRequest struct {
+	SessionID string
+	// Strategy is one of merge, squash, rebase or ff-only; empty means merge
+	Strategy         string
+	Message          string
+	RemoveAfterMerge bool
+}
+
+type MergeSessionResponse struct {
+	SessionID       string    `json:"sessionId"`
+	BaseRef         string    `json:"baseRef"`
+	Strategy        string    `json:"strategy"`
+	Merged          bool      `json:"merged"`
+	MergedCommit    string    `json:"mergedCommit,omitempty"`
+	ConflictedPaths []string  `json:"conflictedPaths,omitempty"`
+	Status          string    `json:"status"`
+	Removed         bool      `json:"removed"`
+	RemovedAt       time.Time `json:"removedAt,omitempty"`
+}
+
+type MergeSessionUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+	baseBranch        string
+	// mergeMutex serializes merges so two sessions never update a base
+	// branch checkout at the same time
+	mergeMutex sync.Mutex
+}
+
+func NewMergeSessionUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+	baseBranch string,
+) *MergeSessionUseCase {
+	return &MergeSessionUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+		baseBranch:        baseBranch,
+	}
+}
+
+func (mergeSessionUseCase *MergeSessionUseCase) Execute(
+	ctx context.Context,
+	request MergeSessionRequest,
+) (*MergeSessionResponse, error) {
+	session, err := findSession(ctx, mergeSessionUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	strategy, err := mergeSessionUseCase.parseStrategy(request.Strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	mergeSessionUseCase.mergeMutex.Lock()
+	defer mergeSessionUseCase.mergeMutex.Unlock()
+
+	baseRef := baseRefFor(session, mergeSessionUseCase.baseBranch)
+	if err := mergeSessionUseCase.ensureMergeable(ctx, session, baseRef); err != nil {
+		return nil, err
+	}
+	if request.RemoveAfterMerge {
+		children, err := findStackedChildren(ctx, mergeSessionUseCase.sessionRepository, session)
+		if err != nil {
+			return nil, err
+		}
+		if len(children) > 0 {
+			return nil, fmt.Errorf("sessions %s are stacked on %s; merge without removeAfterMerge and restack them first", sessionIDList(children), session.ID())
+		}
+	}
+
+	response := &MergeSessionResponse{
+		SessionID: session.ID().String(),
+		BaseRef:   baseRef,
+		Strategy:  string(strategy),
+	}
+
+	options := domain.MergeOptions{
+		Strategy: strategy,
+		Message:  mergeSessionUseCase.buildMessage(ctx, session, baseRef, strategy, request.Message),
+	}
+	mergedCommit, err := mergeSessionUseCase.gitOperations.Merge(ctx, baseRef, session.BranchName(), session.WorktreePath(), options)
+	var conflictErr *domain.ConflictError
+	if errors.As(err, &conflictErr) {
+		response.ConflictedPaths = conflictErr.Paths
+		response.Status = string(session.Status())
+		return response, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge session: %w", err)
+	}
+
+	session.MarkMerged()
+	if err := mergeSessionUseCase.sessionRepository.Save(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to save session: %w", err)
+	}
+	response.Merged = true
+	response.MergedCommit = mergedCommit
+	response.Status = string(session.Status())
+
+	if request.RemoveAfterMerge {
+		if err := teardownSession(ctx, mergeSessionUseCase.gitOperations, mergeSessionUseCase.sessionRepository, session, true); err != nil {
+			return nil, fmt.Errorf("session merged but could not be removed: %w", err)
+		}
+		response.Removed = true
+		response.RemovedAt = time.Now()
+	}
+
+	return response, nil
+}
+
+func (mergeSessionUseCase *MergeSessionUseCase) parseStrategy(value string) (domain.MergeStrategy, error) {
+	if value == "" {
+		return domain.MergeStrategyMerge, nil
+	}
+	return domain.ParseMergeStrategy(value)
+}
+
+func (mergeSessionUseCase *MergeSessionUseCase) ensureMergeable(ctx context.Context, session *domain.Session, baseRef string) error {
+	if session.Status() == domain.StatusMerged {
+		return fmt.Errorf("session %s is already merged", session.ID())
+	}
+
+	isBranch, err := mergeSessionUseCase.gitOperations.BranchExists(ctx, baseRef)
+	if err != nil {
+		return fmt.Errorf("failed to check base branch: %w", err)
+	}
+	if !isBranch {
+		return fmt.Errorf("base ref %s is not a local branch and cannot be merged into", baseRef)
+	}
+
+	hasUncommitted, fileCount, err := mergeSessionUseCase.gitOperations.HasUncommittedChanges(ctx, session.WorktreePath())
+	if err != nil {
+		return fmt.Errorf("failed to check uncommitted changes: %w", err)
+	}
+	if hasUncommitted {
+		return fmt.Errorf("session has %d uncommitted files; commit or discard them before merging", fileCount)
+	}
+
+	// git reports success for a branch with nothing to merge, which would
+	// mark the session merged and let removeAfterMerge tear it down
+	commits, err := mergeSessionUseCase.gitOperations.GetCommits(ctx, baseRef, session.BranchName())
+	if err != nil {
+		return fmt.Errorf("failed to list session commits: %w", err)
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("session %s has no commits ahead of %s; nothing to merge", session.ID(), baseRef)
+	}
+
+	return nil
+}
+
+// buildMessage returns the caller's message, or a default naming the session.
+// Squash commits list the subjects of the commits they replace.
+func (mergeSessionUseCase *MergeSessionUseCase) buildMessage(
+	ctx context.Context,
+	session *domain.Session,
+	baseRef string,
+	strategy domain.MergeStrategy,
+	requested string,
+) string {
+	if strings.TrimSpace(requested) != "" {
+		return requested
+	}
+
+	switch strategy {
+	case domain.MergeStrategyMerge:
+		return fmt.Sprintf("Merge session '%s' into %s", session.ID(), baseRef)
+	case domain.MergeStrategySquash:
+		message := fmt.Sprintf("Squash session '%s' into %s", session.ID(), baseRef)
+		commits, err := mergeSessionUseCase.gitOperations.GetCommits(ctx, baseRef, session.BranchName())
+		if err != nil || len(commits) == 0 {
+			return message
+		}
+		subjects := make([]string, 0, len(commits))
+		for index := len(commits) - 1; index >= 0; index-- {
+			subjects = append(subjects, "* "+commits[index].Subject)
+		}
+		return message + "\n\n" + strings.Join(subjects, "\n")
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,229 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func setupMergeTest(t *testing.T, gitOperations *mockGitOperations) (*MergeSessionUseCase, *mockSessionRepository) {
+	t.Helper()
+
+	if gitOperations.branchExistsFunc == nil {
+		gitOperations.branchExistsFunc = func(ctx context.Context, branch string) (bool, error) {
+			return true, nil
+		}
+	}
+	if gitOperations.getCommitsFunc == nil {
+		gitOperations.getCommitsFunc = func(ctx context.Context, baseRef string, sessionBranch string) ([]domain.Commit, error) {
+			return []domain.Commit{{SHA: "0123456789abcdef0123456789abcdef01234567", Subject: "Add feature"}}, nil
+		}
+	}
+
+	sessionRepository := newMockSessionRepository()
+	sessionID, _ := domain.NewSessionID("test-session")
+	session, _ := domain.NewSession(sessionID, "/path/test-session", "")
+	sessionRepository.Save(context.Background(), session)
+
+	return NewMergeSessionUseCase(gitOperations, sessionRepository, "main"), sessionRepository
+}
+
+func TestMergeSessionUseCase_Execute_MarksSessionMerged(t *testing.T) {
+	// arrange
+	var receivedOptions domain.MergeOptions
+	var receivedBase string
+	gitOperations := &mockGitOperations{
+		mergeFunc: func(ctx context.Context, baseBranch string, sessionBranch string, sessionWorktreePath string, options domain.MergeOptions) (string, error) {
+			receivedBase = baseBranch
+			receivedOptions = options
+			return "1111111111111111111111111111111111111111", nil
+		},
+	}
+	useCase, sessionRepository := setupMergeTest(t, gitOperations)
+
+	// act
+	response, err := useCase.Execute(context.Background(), MergeSessionRequest{SessionID: "test-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if !response.Merged || response.Status != "merged" {
+		t.Errorf("response = %+v, want merged", response)
+	}
+	if receivedBase != "main" {
+		t.Errorf("Merge() base = %q, want %q", receivedBase, "main")
+	}
+	if receivedOptions.Strategy != domain.MergeStrategyMerge {
+		t.Errorf("Merge() strategy = %q, want default merge", receivedOptions.Strategy)
+	}
+	if !strings.Contains(receivedOptions.Message, "test-session") {
+		t.Errorf("Merge() message = %q, want it to name the session", receivedOptions.Message)
+	}
+	saved := sessionRepository.sessions["test-session"]
+	if saved.Status() != domain.StatusMerged {
+		t.Errorf("saved status = %s, want merged", saved.Status())
+	}
+}
+
+func TestMergeSessionUseCase_Execute_SquashMessageListsCommits(t *testing.T) {
+	// arrange
+	var receivedMessage string
+	gitOperations := &mockGitOperations{
+		getCommitsFunc: func(ctx context.Context, baseRef string, sessionBranch string) ([]domain.Commit, error) {
+			return []domain.Commit{{Subject: "Second"}, {Subject: "First"}}, nil
+		},
+		mergeFunc: func(ctx context.Context, baseBranch string, sessionBranch string, sessionWorktreePath string, options domain.MergeOptions) (string, error) {
+			receivedMessage = options.Message
+			return "1111111111111111111111111111111111111111", nil
+		},
+	}
+	useCase, _ := setupMergeTest(t, gitOperations)
+
+	// act
+	_, err := useCase.Execute(context.Background(), MergeSessionRequest{SessionID: "test-session", Strategy: "squash"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(receivedMessage, "* First\n* Second") {
+		t.Errorf("squash message = %q, want commit subjects oldest first", receivedMessage)
+	}
+}
+
+func TestMergeSessionUseCase_Execute_ConflictLeavesSessionOpen(t *testing.T) {
+	// arrange
+	gitOperations := &mockGitOperations{
+		mergeFunc: func(ctx context.Context, baseBranch string, sessionBranch string, sessionWorktreePath string, options domain.MergeOptions) (string, error) {
+			return "", &domain.ConflictError{Operation: "merge", Paths: []string{"README.md"}}
+		},
+	}
+	useCase, sessionRepository := setupMergeTest(t, gitOperations)
+
+	// act
+	response, err := useCase.Execute(context.Background(), MergeSessionRequest{SessionID: "test-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if response.Merged {
+		t.Error("Execute() Merged = true, want false on conflict")
+	}
+	if len(response.ConflictedPaths) != 1 || response.ConflictedPaths[0] != "README.md" {
+		t.Errorf("ConflictedPaths = %v, want [README.md]", response.ConflictedPaths)
+	}
+	if sessionRepository.sessions["test-session"].Status() != domain.StatusOpen {
+		t.Error("session should stay open after a conflict")
+	}
+}
+
+func TestMergeSessionUseCase_Execute_RemoveAfterMerge(t *testing.T) {
+	// arrange
+	var removedPath string
+	gitOperations := &mockGitOperations{
+		removeWorktreeFunc: func(ctx context.Context, path string, force bool) error {
+			removedPath = path
+			return nil
+		},
+	}
+	useCase, sessionRepository := setupMergeTest(t, gitOperations)
+
+	// act
+	response, err := useCase.Execute(context.Background(), MergeSessionRequest{SessionID: "test-session", RemoveAfterMerge: true})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if !response.Removed {
+		t.Error("Execute() Removed = false, want true")
+	}
+	if removedPath != "/path/test-session" {
+		t.Errorf("RemoveWorktree() path = %q, want %q", removedPath, "/path/test-session")
+	}
+	if _, exists := sessionRepository.sessions["test-session"]; exists {
+		t.Error("session record should be deleted")
+	}
+}
+
+func TestMergeSessionUseCase_Execute_RejectsUncommittedChanges(t *testing.T) {
+	// arrange
+	gitOperations := &mockGitOperations{
+		hasUncommittedChangesFunc: func(ctx context.Context, worktreePath string) (bool, int, error) {
+			return true, 2, nil
+		},
+	}
+	useCase, _ := setupMergeTest(t, gitOperations)
+
+	// act
+	_, err := useCase.Execute(context.Background(), MergeSessionRequest{SessionID: "test-session"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for uncommitted changes")
+	}
+}
+
+func TestMergeSessionUseCase_Execute_RejectsNonBranchBase(t *testing.T) {
+	// arrange
+	gitOperations := &mockGitOperations{
+		branchExistsFunc: func(ctx context.Context, branch string) (bool, error) {
+			return false, nil
+		},
+	}
+	useCase, _ := setupMergeTest(t, gitOperations)
+
+	// act
+	_, err := useCase.Execute(context.Background(), MergeSessionRequest{SessionID: "test-session"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error when the base ref is not a branch")
+	}
+}
+
+func TestMergeSessionUseCase_Execute_UnknownStrategy(t *testing.T) {
+	// arrange
+	useCase, _ := setupMergeTest(t, &mockGitOperations{})
+
+	// act
+	_, err := useCase.Execute(context.Background(), MergeSessionRequest{SessionID: "test-session", Strategy: "octopus"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for unknown strategy")
+	}
+}
+
+func TestMergeSessionUseCase_Execute_RejectsSessionWithoutCommits(t *testing.T) {
+	// arrange
+	mergeCalled := false
+	gitOperations := &mockGitOperations{
+		getCommitsFunc: func(ctx context.Context, baseRef string, sessionBranch string) ([]domain.Commit, error) {
+			return []domain.Commit{}, nil
+		},
+		mergeFunc: func(ctx context.Context, baseBranch string, sessionBranch string, sessionWorktreePath string, options domain.MergeOptions) (string, error) {
+			mergeCalled = true
+			return "", nil
+		},
+	}
+	useCase, sessionRepository := setupMergeTest(t, gitOperations)
+
+	// act
+	_, err := useCase.Execute(context.Background(), MergeSessionRequest{SessionID: "test-session", RemoveAfterMerge: true})
+
+	// assert
+	if err == nil || !strings.Contains(err.Error(), "nothing to merge") {
+		t.Errorf("Execute() error = %v, want nothing to merge", err)
+	}
+	if mergeCalled {
+		t.Error("Merge() should not be called for a session without commits")
+	}
+	if saved := sessionRepository.sessions["test-session"]; saved == nil || saved.Status() != domain.StatusOpen {
+		t.Errorf("session = %v, want it kept open", saved)
+	}
+}
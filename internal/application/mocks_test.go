@@ -4,18 +4,43 @@ import (
 	"context"
 	"errors"
 	"path/filepath"
+	"strings"
 
 	"github.com/tzDel/orchestragent-mcp/internal/domain"
 )
 
 type mockGitOperations struct {
-	createWorktreeFunc        func(ctx context.Context, path string, branch string) error
+	createWorktreeFunc        func(ctx context.Context, path string, branch string, baseRef string) error
 	removeWorktreeFunc        func(ctx context.Context, path string, force bool) error
 	branchExistsFunc          func(ctx context.Context, branch string) (bool, error)
+	resolveCommitFunc         func(ctx context.Context, ref string) (string, error)
 	hasUncommittedChangesFunc func(ctx context.Context, worktreePath string) (bool, int, error)
 	hasUnpushedCommitsFunc    func(ctx context.Context, baseBranch string, sessionBranch string) (int, error)
 	deleteBranchFunc          func(ctx context.Context, branchName string, force bool) error
 	getDiffStatsFunc          func(ctx context.Context, worktreePath string, baseBranch string) (*domain.GitDiffStats, error)
+	getDiffFunc               func(ctx context.Context, worktreePath string, baseRef string, options domain.DiffOptions) ([]domain.FileDiff, error)
+	getCommitsFunc            func(ctx context.Context, baseRef string, sessionBranch string) ([]domain.Commit, error)
+	mergeFunc                 func(ctx context.Context, baseBranch string, sessionBranch string, sessionWorktreePath string, options domain.MergeOptions) (string, error)
+	checkMergeFunc            func(ctx context.Context, baseRef string, sessionBranch string) (*domain.MergeCheck, error)
+	syncFunc                  func(ctx context.Context, worktreePath string, upstream string, options domain.MergeOptions) error
+	getDivergenceFunc         func(ctx context.Context, baseRef string, sessionBranch string) (*domain.BranchDivergence, error)
+	createCheckpointFunc      func(ctx context.Context, worktreePath string, ref string, message string) (*domain.Checkpoint, error)
+	listCheckpointsFunc       func(ctx context.Context, refPrefix string) ([]domain.Checkpoint, error)
+	restoreCheckpointFunc     func(ctx context.Context, worktreePath string, ref string, backupRef string) (*domain.Checkpoint, error)
+	deleteCheckpointsFunc     func(ctx context.Context, refPrefix string) error
+	commitFunc                func(ctx context.Context, worktreePath string, options domain.CommitOptions) (*domain.Commit, error)
+	pushFunc                  func(ctx context.Context, remote string, branchName string, force bool) error
+	createBundleFunc          func(ctx context.Context, bundlePath string, branchName string, excludedCommit string) error
+	formatPatchesFunc         func(ctx context.Context, outputDirectory string, baseRef string, branchName string) ([]string, error)
+	fetchBundleFunc           func(ctx context.Context, bundlePath string, ref string) (string, error)
+	applyPatchesFunc          func(ctx context.Context, worktreePath string, patchPaths []string) error
+	copyWorktreeChangesFunc   func(ctx context.Context, sourceWorktreePath string, targetWorktreePath string) error
+	rebaseOntoFunc            func(ctx context.Context, worktreePath string, newBase string, upstream string) error
+	cherryPickFunc            func(ctx context.Context, worktreePath string, commits []string) error
+	compareRefsFunc           func(ctx context.Context, fromRef string, toRef string, options domain.DiffOptions) (*domain.RefComparison, error)
+	getBranchChangesFunc      func(ctx context.Context, baseRef string, branchName string, pathGlobs []string) ([]domain.FileChange, error)
+	searchFunc                func(ctx context.Context, worktreePath string, options domain.SearchOptions) (*domain.SearchResult, error)
+	applyPatchFunc            func(ctx context.Context, worktreePath string, patch string, checkOnly bool) (*domain.PatchResult, error)
 }
 
 type MockGitOperations struct {
@@ -23,9 +48,9 @@ type MockGitOperations struct {
 	shouldFailForSession string
 }
 
-func (mock *mockGitOperations) CreateWorktree(ctx context.Context, path string, branch string) error {
+func (mock *mockGitOperations) CreateWorktree(ctx context.Context, path string, branch string, baseRef string) error {
 	if mock.createWorktreeFunc != nil {
-		return mock.createWorktreeFunc(ctx, path, branch)
+		return mock.createWorktreeFunc(ctx, path, branch, baseRef)
 	}
 	return nil
 }
@@ -44,6 +69,13 @@ func (mock *mockGitOperations) BranchExists(ctx context.Context, branch string)
 	return false, nil
 }
 
+func (mock *mockGitOperations) ResolveCommit(ctx context.Context, ref string) (string, error) {
+	if mock.resolveCommitFunc != nil {
+		return mock.resolveCommitFunc(ctx, ref)
+	}
+	return "0123456789abcdef0123456789abcdef01234567", nil
+}
+
 func (mock *mockGitOperations) HasUncommittedChanges(ctx context.Context, worktreePath string) (bool, int, error) {
 	if mock.hasUncommittedChangesFunc != nil {
 		return mock.hasUncommittedChangesFunc(ctx, worktreePath)
@@ -72,7 +104,168 @@ func (mock *mockGitOperations) GetDiffStats(ctx context.Context, worktreePath st
 	return &domain.GitDiffStats{LinesAdded: 0, LinesRemoved: 0}, nil
 }
 
-func (mock *MockGitOperations) CreateWorktree(ctx context.Context, path string, branch string) error {
+func (mock *mockGitOperations) GetDiff(ctx context.Context, worktreePath string, baseRef string, options domain.DiffOptions) ([]domain.FileDiff, error) {
+	if mock.getDiffFunc != nil {
+		return mock.getDiffFunc(ctx, worktreePath, baseRef, options)
+	}
+	return []domain.FileDiff{}, nil
+}
+
+func (mock *mockGitOperations) GetCommits(ctx context.Context, baseRef string, sessionBranch string) ([]domain.Commit, error) {
+	if mock.getCommitsFunc != nil {
+		return mock.getCommitsFunc(ctx, baseRef, sessionBranch)
+	}
+	return []domain.Commit{}, nil
+}
+
+func (mock *mockGitOperations) CompareRefs(ctx context.Context, fromRef string, toRef string, options domain.DiffOptions) (*domain.RefComparison, error) {
+	if mock.compareRefsFunc != nil {
+		return mock.compareRefsFunc(ctx, fromRef, toRef, options)
+	}
+	return &domain.RefComparison{Diffs: []domain.FileDiff{}, Files: []domain.FileChange{}}, nil
+}
+
+func (mock *mockGitOperations) GetBranchChanges(ctx context.Context, baseRef string, branchName string, pathGlobs []string) ([]domain.FileChange, error) {
+	if mock.getBranchChangesFunc != nil {
+		return mock.getBranchChangesFunc(ctx, baseRef, branchName, pathGlobs)
+	}
+	return []domain.FileChange{}, nil
+}
+
+func (mock *mockGitOperations) Search(ctx context.Context, worktreePath string, options domain.SearchOptions) (*domain.SearchResult, error) {
+	if mock.searchFunc != nil {
+		return mock.searchFunc(ctx, worktreePath, options)
+	}
+	return &domain.SearchResult{Matches: []domain.SearchMatch{}}, nil
+}
+
+func (mock *mockGitOperations) ApplyPatch(ctx context.Context, worktreePath string, patch string, checkOnly bool) (*domain.PatchResult, error) {
+	if mock.applyPatchFunc != nil {
+		return mock.applyPatchFunc(ctx, worktreePath, patch, checkOnly)
+	}
+	return &domain.PatchResult{Files: []string{}, Hunks: []domain.PatchHunk{}, Applies: true, Applied: !checkOnly}, nil
+}
+
+func (mock *mockGitOperations) Merge(ctx context.Context, baseBranch string, sessionBranch string, sessionWorktreePath string, options domain.MergeOptions) (string, error) {
+	if mock.mergeFunc != nil {
+		return mock.mergeFunc(ctx, baseBranch, sessionBranch, sessionWorktreePath, options)
+	}
+	return "0123456789abcdef0123456789abcdef01234567", nil
+}
+
+func (mock *mockGitOperations) CheckMerge(ctx context.Context, baseRef string, sessionBranch string) (*domain.MergeCheck, error) {
+	if mock.checkMergeFunc != nil {
+		return mock.checkMergeFunc(ctx, baseRef, sessionBranch)
+	}
+	return &domain.MergeCheck{Clean: true, Conflicts: []domain.MergeConflict{}}, nil
+}
+
+func (mock *mockGitOperations) Sync(ctx context.Context, worktreePath string, upstream string, options domain.MergeOptions) error {
+	if mock.syncFunc != nil {
+		return mock.syncFunc(ctx, worktreePath, upstream, options)
+	}
+	return nil
+}
+
+func (mock *mockGitOperations) GetDivergence(ctx context.Context, baseRef string, sessionBranch string) (*domain.BranchDivergence, error) {
+	if mock.getDivergenceFunc != nil {
+		return mock.getDivergenceFunc(ctx, baseRef, sessionBranch)
+	}
+	return &domain.BranchDivergence{}, nil
+}
+
+func (mock *mockGitOperations) CreateCheckpoint(ctx context.Context, worktreePath string, ref string, message string) (*domain.Checkpoint, error) {
+	if mock.createCheckpointFunc != nil {
+		return mock.createCheckpointFunc(ctx, worktreePath, ref, message)
+	}
+	return &domain.Checkpoint{Name: ref[strings.LastIndex(ref, "/")+1:], Ref: ref, Message: message}, nil
+}
+
+func (mock *mockGitOperations) ListCheckpoints(ctx context.Context, refPrefix string) ([]domain.Checkpoint, error) {
+	if mock.listCheckpointsFunc != nil {
+		return mock.listCheckpointsFunc(ctx, refPrefix)
+	}
+	return []domain.Checkpoint{}, nil
+}
+
+func (mock *mockGitOperations) RestoreCheckpoint(ctx context.Context, worktreePath string, ref string, backupRef string) (*domain.Checkpoint, error) {
+	if mock.restoreCheckpointFunc != nil {
+		return mock.restoreCheckpointFunc(ctx, worktreePath, ref, backupRef)
+	}
+	return &domain.Checkpoint{Ref: backupRef}, nil
+}
+
+func (mock *mockGitOperations) DeleteCheckpoints(ctx context.Context, refPrefix string) error {
+	if mock.deleteCheckpointsFunc != nil {
+		return mock.deleteCheckpointsFunc(ctx, refPrefix)
+	}
+	return nil
+}
+
+func (mock *mockGitOperations) Commit(ctx context.Context, worktreePath string, options domain.CommitOptions) (*domain.Commit, error) {
+	if mock.commitFunc != nil {
+		return mock.commitFunc(ctx, worktreePath, options)
+	}
+	return &domain.Commit{SHA: "0123456789abcdef0123456789abcdef01234567", Subject: options.Message, Trailers: options.Trailers}, nil
+}
+
+func (mock *mockGitOperations) Push(ctx context.Context, remote string, branchName string, force bool) error {
+	if mock.pushFunc != nil {
+		return mock.pushFunc(ctx, remote, branchName, force)
+	}
+	return nil
+}
+
+func (mock *mockGitOperations) CreateBundle(ctx context.Context, bundlePath string, branchName string, excludedCommit string) error {
+	if mock.createBundleFunc != nil {
+		return mock.createBundleFunc(ctx, bundlePath, branchName, excludedCommit)
+	}
+	return nil
+}
+
+func (mock *mockGitOperations) FormatPatches(ctx context.Context, outputDirectory string, baseRef string, branchName string) ([]string, error) {
+	if mock.formatPatchesFunc != nil {
+		return mock.formatPatchesFunc(ctx, outputDirectory, baseRef, branchName)
+	}
+	return []string{}, nil
+}
+
+func (mock *mockGitOperations) FetchBundle(ctx context.Context, bundlePath string, ref string) (string, error) {
+	if mock.fetchBundleFunc != nil {
+		return mock.fetchBundleFunc(ctx, bundlePath, ref)
+	}
+	return "0123456789abcdef0123456789abcdef01234567", nil
+}
+
+func (mock *mockGitOperations) ApplyPatches(ctx context.Context, worktreePath string, patchPaths []string) error {
+	if mock.applyPatchesFunc != nil {
+		return mock.applyPatchesFunc(ctx, worktreePath, patchPaths)
+	}
+	return nil
+}
+
+func (mock *mockGitOperations) CherryPick(ctx context.Context, worktreePath string, commits []string) error {
+	if mock.cherryPickFunc != nil {
+		return mock.cherryPickFunc(ctx, worktreePath, commits)
+	}
+	return nil
+}
+
+func (mock *mockGitOperations) RebaseOnto(ctx context.Context, worktreePath string, newBase string, upstream string) error {
+	if mock.rebaseOntoFunc != nil {
+		return mock.rebaseOntoFunc(ctx, worktreePath, newBase, upstream)
+	}
+	return nil
+}
+
+func (mock *mockGitOperations) CopyWorktreeChanges(ctx context.Context, sourceWorktreePath string, targetWorktreePath string) error {
+	if mock.copyWorktreeChangesFunc != nil {
+		return mock.copyWorktreeChangesFunc(ctx, sourceWorktreePath, targetWorktreePath)
+	}
+	return nil
+}
+
+func (mock *MockGitOperations) CreateWorktree(ctx context.Context, path string, branch string, baseRef string) error {
 	return nil
 }
 
@@ -84,6 +277,10 @@ func (mock *MockGitOperations) BranchExists(ctx context.Context, branch string)
 	return false, nil
 }
 
+func (mock *MockGitOperations) ResolveCommit(ctx context.Context, ref string) (string, error) {
+	return "0123456789abcdef0123456789abcdef01234567", nil
+}
+
 func (mock *MockGitOperations) HasUncommittedChanges(ctx context.Context, worktreePath string) (bool, int, error) {
 	return false, 0, nil
 }
@@ -112,6 +309,214 @@ func (mock *MockGitOperations) GetDiffStats(ctx context.Context, worktreePath st
 	return &domain.GitDiffStats{LinesAdded: 0, LinesRemoved: 0}, nil
 }
 
+func (mock *MockGitOperations) GetDiff(ctx context.Context, worktreePath string, baseRef string, options domain.DiffOptions) ([]domain.FileDiff, error) {
+	return []domain.FileDiff{}, nil
+}
+
+func (mock *MockGitOperations) GetCommits(ctx context.Context, baseRef string, sessionBranch string) ([]domain.Commit, error) {
+	return []domain.Commit{}, nil
+}
+
+func (mock *MockGitOperations) CompareRefs(ctx context.Context, fromRef string, toRef string, options domain.DiffOptions) (*domain.RefComparison, error) {
+	return &domain.RefComparison{Diffs: []domain.FileDiff{}, Files: []domain.FileChange{}}, nil
+}
+
+func (mock *MockGitOperations) GetBranchChanges(ctx context.Context, baseRef string, branchName string, pathGlobs []string) ([]domain.FileChange, error) {
+	return []domain.FileChange{}, nil
+}
+
+func (mock *MockGitOperations) Search(ctx context.Context, worktreePath string, options domain.SearchOptions) (*domain.SearchResult, error) {
+	return &domain.SearchResult{Matches: []domain.SearchMatch{}}, nil
+}
+
+func (mock *MockGitOperations) ApplyPatch(ctx context.Context, worktreePath string, patch string, checkOnly bool) (*domain.PatchResult, error) {
+	return &domain.PatchResult{Files: []string{}, Hunks: []domain.PatchHunk{}, Applies: true, Applied: !checkOnly}, nil
+}
+
+func (mock *MockGitOperations) Merge(ctx context.Context, baseBranch string, sessionBranch string, sessionWorktreePath string, options domain.MergeOptions) (string, error) {
+	return "0123456789abcdef0123456789abcdef01234567", nil
+}
+
+func (mock *MockGitOperations) CheckMerge(ctx context.Context, baseRef string, sessionBranch string) (*domain.MergeCheck, error) {
+	return &domain.MergeCheck{Clean: true, Conflicts: []domain.MergeConflict{}}, nil
+}
+
+func (mock *MockGitOperations) Sync(ctx context.Context, worktreePath string, upstream string, options domain.MergeOptions) error {
+	return nil
+}
+
+func (mock *MockGitOperations) GetDivergence(ctx context.Context, baseRef string, sessionBranch string) (*domain.BranchDivergence, error) {
+	return &domain.BranchDivergence{}, nil
+}
+
+func (mock *MockGitOperations) CreateCheckpoint(ctx context.Context, worktreePath string, ref string, message string) (*domain.Checkpoint, error) {
+	return &domain.Checkpoint{Ref: ref, Message: message}, nil
+}
+
+func (mock *MockGitOperations) ListCheckpoints(ctx context.Context, refPrefix string) ([]domain.Checkpoint, error) {
+	return []domain.Checkpoint{}, nil
+}
+
+func (mock *MockGitOperations) RestoreCheckpoint(ctx context.Context, worktreePath string, ref string, backupRef string) (*domain.Checkpoint, error) {
+	return &domain.Checkpoint{Ref: backupRef}, nil
+}
+
+func (mock *MockGitOperations) DeleteCheckpoints(ctx context.Context, refPrefix string) error {
+	return nil
+}
+
+func (mock *MockGitOperations) Commit(ctx context.Context, worktreePath string, options domain.CommitOptions) (*domain.Commit, error) {
+	return &domain.Commit{SHA: "0123456789abcdef0123456789abcdef01234567"}, nil
+}
+
+func (mock *MockGitOperations) Push(ctx context.Context, remote string, branchName string, force bool) error {
+	return nil
+}
+
+func (mock *MockGitOperations) CreateBundle(ctx context.Context, bundlePath string, branchName string, excludedCommit string) error {
+	return nil
+}
+
+func (mock *MockGitOperations) FormatPatches(ctx context.Context, outputDirectory string, baseRef string, branchName string) ([]string, error) {
+	return []string{}, nil
+}
+
+func (mock *MockGitOperations) FetchBundle(ctx context.Context, bundlePath string, ref string) (string, error) {
+	return "0123456789abcdef0123456789abcdef01234567", nil
+}
+
+func (mock *MockGitOperations) ApplyPatches(ctx context.Context, worktreePath string, patchPaths []string) error {
+	return nil
+}
+
+func (mock *MockGitOperations) CherryPick(ctx context.Context, worktreePath string, commits []string) error {
+	return nil
+}
+
+func (mock *MockGitOperations) RebaseOnto(ctx context.Context, worktreePath string, newBase string, upstream string) error {
+	return nil
+}
+
+func (mock *MockGitOperations) CopyWorktreeChanges(ctx context.Context, sourceWorktreePath string, targetWorktreePath string) error {
+	return nil
+}
+
+type mockForge struct {
+	createPullRequestFunc func(ctx context.Context, request domain.NewPullRequest) (*domain.PullRequest, error)
+}
+
+func (mock *mockForge) CreatePullRequest(ctx context.Context, request domain.NewPullRequest) (*domain.PullRequest, error) {
+	if mock.createPullRequestFunc != nil {
+		return mock.createPullRequestFunc(ctx, request)
+	}
+	return &domain.PullRequest{Number: 1, URL: "https://forge.example.com/pull/1"}, nil
+}
+
+type mockWorktreeFiles struct {
+	readFileFunc      func(ctx context.Context, worktreePath string, path string, maxBytes int) (*domain.FileContent, error)
+	writeFileFunc     func(ctx context.Context, worktreePath string, path string, data []byte) (bool, error)
+	listDirectoryFunc func(ctx context.Context, worktreePath string, path string) ([]domain.DirectoryEntry, error)
+	deleteFileFunc    func(ctx context.Context, worktreePath string, path string) error
+	moveFileFunc      func(ctx context.Context, worktreePath string, sourcePath string, destinationPath string, overwrite bool) error
+}
+
+func (mock *mockWorktreeFiles) ReadFile(ctx context.Context, worktreePath string, path string, maxBytes int) (*domain.FileContent, error) {
+	if mock.readFileFunc != nil {
+		return mock.readFileFunc(ctx, worktreePath, path, maxBytes)
+	}
+	return &domain.FileContent{Data: []byte{}}, nil
+}
+
+func (mock *mockWorktreeFiles) WriteFile(ctx context.Context, worktreePath string, path string, data []byte) (bool, error) {
+	if mock.writeFileFunc != nil {
+		return mock.writeFileFunc(ctx, worktreePath, path, data)
+	}
+	return true, nil
+}
+
+func (mock *mockWorktreeFiles) ListDirectory(ctx context.Context, worktreePath string, path string) ([]domain.DirectoryEntry, error) {
+	if mock.listDirectoryFunc != nil {
+		return mock.listDirectoryFunc(ctx, worktreePath, path)
+	}
+	return []domain.DirectoryEntry{}, nil
+}
+
+func (mock *mockWorktreeFiles) DeleteFile(ctx context.Context, worktreePath string, path string) error {
+	if mock.deleteFileFunc != nil {
+		return mock.deleteFileFunc(ctx, worktreePath, path)
+	}
+	return nil
+}
+
+func (mock *mockWorktreeFiles) MoveFile(ctx context.Context, worktreePath string, sourcePath string, destinationPath string, overwrite bool) error {
+	if mock.moveFileFunc != nil {
+		return mock.moveFileFunc(ctx, worktreePath, sourcePath, destinationPath, overwrite)
+	}
+	return nil
+}
+
+type mockCommandRunner struct {
+	runFunc func(ctx context.Context, spec domain.CommandSpec) (*domain.CommandResult, error)
+}
+
+func (mock *mockCommandRunner) Run(ctx context.Context, spec domain.CommandSpec) (*domain.CommandResult, error) {
+	if mock.runFunc != nil {
+		return mock.runFunc(ctx, spec)
+	}
+	return &domain.CommandResult{Stdout: []byte{}, Stderr: []byte{}}, nil
+}
+
+// mockSessionArchives keeps archives in memory, with their files keyed by
+// archive name and path
+type mockSessionArchives struct {
+	archives map[string]map[string][]byte
+	removed  []string
+}
+
+func newMockSessionArchives() *mockSessionArchives {
+	return &mockSessionArchives{
+		archives: make(map[string]map[string][]byte),
+	}
+}
+
+func (mock *mockSessionArchives) Create(ctx context.Context, name string) (string, error) {
+	if _, exists := mock.archives[name]; exists {
+		return "", errors.New("already exists")
+	}
+	mock.archives[name] = make(map[string][]byte)
+	return "/exports/" + name, nil
+}
+
+func (mock *mockSessionArchives) Locate(ctx context.Context, name string) (string, error) {
+	if _, exists := mock.archives[name]; !exists {
+		return "", errors.New("not found")
+	}
+	return "/exports/" + name, nil
+}
+
+func (mock *mockSessionArchives) WriteFile(ctx context.Context, name string, path string, data []byte) error {
+	files, exists := mock.archives[name]
+	if !exists {
+		return errors.New("not found")
+	}
+	files[path] = data
+	return nil
+}
+
+func (mock *mockSessionArchives) ReadFile(ctx context.Context, name string, path string) ([]byte, error) {
+	data, exists := mock.archives[name][path]
+	if !exists {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
+
+func (mock *mockSessionArchives) Remove(ctx context.Context, name string) error {
+	mock.removed = append(mock.removed, name)
+	delete(mock.archives, name)
+	return nil
+}
+
 type mockSessionRepository struct {
 	sessions map[string]*domain.Session
 }
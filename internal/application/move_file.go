@@ -0,0 +1,60 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type MoveFileRequest struct {
+	SessionID       string
+	SourcePath      string
+	DestinationPath string
+	Overwrite       bool
+}
+
+type MoveFileResponse struct {
+	SessionID       string `json:"sessionId"`
+	SourcePath      string `json:"sourcePath"`
+	DestinationPath string `json:"destinationPath"`
+}
+
+type MoveFileUseCase struct {
+	worktreeFiles     domain.WorktreeFiles
+	sessionRepository domain.SessionRepository
+}
+
+func NewMoveFileUseCase(
+	worktreeFiles domain.WorktreeFiles,
+	sessionRepository domain.SessionRepository,
+) *MoveFileUseCase {
+	return &MoveFileUseCase{
+		worktreeFiles:     worktreeFiles,
+		sessionRepository: sessionRepository,
+	}
+}
+
+func (moveFileUseCase *MoveFileUseCase) Execute(
+	ctx context.Context,
+	request MoveFileRequest,
+) (*MoveFileResponse, error) {
+	session, err := findSession(ctx, moveFileUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status() == domain.StatusMerged {
+		return nil, fmt.Errorf("session %s is already merged", session.ID())
+	}
+
+	err = moveFileUseCase.worktreeFiles.MoveFile(ctx, session.WorktreePath(), request.SourcePath, request.DestinationPath, request.Overwrite)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move file: %w", err)
+	}
+
+	return &MoveFileResponse{
+		SessionID:       session.ID().String(),
+		SourcePath:      request.SourcePath,
+		DestinationPath: request.DestinationPath,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func TestMoveFileUseCase_Execute_PassesOverwriteThrough(t *testing.T) {
+	// arrange
+	var movedFrom, movedTo string
+	var overwrote bool
+	worktreeFiles := &mockWorktreeFiles{
+		moveFileFunc: func(ctx context.Context, worktreePath string, sourcePath string, destinationPath string, overwrite bool) error {
+			movedFrom, movedTo, overwrote = sourcePath, destinationPath, overwrite
+			return nil
+		},
+	}
+	useCase := NewMoveFileUseCase(worktreeFiles, setupFileSession(t, "test-session", false))
+
+	// act
+	response, err := useCase.Execute(context.Background(), MoveFileRequest{SessionID: "test-session", SourcePath: "a.go", DestinationPath: "pkg/a.go", Overwrite: true})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if movedFrom != "a.go" || movedTo != "pkg/a.go" || !overwrote {
+		t.Errorf("moved %s to %s (overwrite %v), want a.go to pkg/a.go with overwrite", movedFrom, movedTo, overwrote)
+	}
+	if response.DestinationPath != "pkg/a.go" {
+		t.Errorf("response = %+v, want the destination path", response)
+	}
+}
+
+func TestMoveFileUseCase_Execute_ProtectedPath_ReturnsError(t *testing.T) {
+	// arrange
+	worktreeFiles := &mockWorktreeFiles{
+		moveFileFunc: func(ctx context.Context, worktreePath string, sourcePath string, destinationPath string, overwrite bool) error {
+			return domain.ErrProtectedPath
+		},
+	}
+	useCase := NewMoveFileUseCase(worktreeFiles, setupFileSession(t, "test-session", false))
+
+	// act
+	_, err := useCase.Execute(context.Background(), MoveFileRequest{SessionID: "test-session", SourcePath: "a.go", DestinationPath: ".git"})
+
+	// assert
+	if !errors.Is(err, domain.ErrProtectedPath) {
+		t.Errorf("Execute() error = %v, want ErrProtectedPath", err)
+	}
+}
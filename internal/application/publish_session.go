@@ -0,0 +1,187 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type PublishSessionRequest struct {
+	SessionID string
+	// Title and Body describe the pull request; they default to the commit
+	// subject for single-commit sessions and to a list of the commits
+	Title string
+	Body  string
+	Draft bool
+	// Force overwrites the remote branch, e.g. after the session was rebased
+	Force bool
+}
+
+type PullRequestDTO struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+}
+
+type PublishSessionResponse struct {
+	SessionID   string          `json:"sessionId"`
+	Remote      string          `json:"remote"`
+	BranchName  string          `json:"branchName"`
+	BaseRef     string          `json:"baseRef"`
+	HeadCommit  string          `json:"headCommit"`
+	PullRequest *PullRequestDTO `json:"pullRequest,omitempty"`
+	// PullRequestCreated is false when the session already had a pull
+	// request or no forge is configured
+	PullRequestCreated bool `json:"pullRequestCreated"`
+}
+
+type PublishSessionUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+	forge             domain.Forge
+	remote            string
+	baseBranch        string
+}
+
+// NewPublishSessionUseCase creates the use case. forge may be nil, in which
+// case sessions are pushed without opening a pull request.
+func NewPublishSessionUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+	forge domain.Forge,
+	remote string,
+	baseBranch string,
+) *PublishSessionUseCase {
+	return &PublishSessionUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+		forge:             forge,
+		remote:            remote,
+		baseBranch:        baseBranch,
+	}
+}
+
+// Execute pushes the session branch to the configured remote and opens a
+// pull request into the session's base ref, recording it on the session.
+// Publishing again only pushes; the existing pull request picks up the new
+// commits.
+func (publishSessionUseCase *PublishSessionUseCase) Execute(
+	ctx context.Context,
+	request PublishSessionRequest,
+) (*PublishSessionResponse, error) {
+	session, err := findSession(ctx, publishSessionUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status() == domain.StatusMerged {
+		return nil, fmt.Errorf("session %s is already merged", session.ID())
+	}
+
+	baseRef := baseRefFor(session, publishSessionUseCase.baseBranch)
+	commits, err := publishSessionUseCase.gitOperations.GetCommits(ctx, baseRef, session.BranchName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("session %s has no commits to publish", session.ID())
+	}
+	if err := publishSessionUseCase.ensurePullRequestBase(ctx, session, baseRef); err != nil {
+		return nil, err
+	}
+
+	if err := publishSessionUseCase.gitOperations.Push(ctx, publishSessionUseCase.remote, session.BranchName(), request.Force); err != nil {
+		return nil, err
+	}
+
+	response := &PublishSessionResponse{
+		SessionID:  session.ID().String(),
+		Remote:     publishSessionUseCase.remote,
+		BranchName: session.BranchName(),
+		BaseRef:    baseRef,
+		HeadCommit: commits[0].SHA,
+	}
+
+	if existing := session.PullRequest(); existing.Number != 0 {
+		response.PullRequest = buildPullRequestDTO(existing)
+		return response, nil
+	}
+	if publishSessionUseCase.forge == nil {
+		return response, nil
+	}
+
+	pullRequest, err := publishSessionUseCase.forge.CreatePullRequest(ctx, domain.NewPullRequest{
+		Title: pullRequestTitle(request.Title, session, commits),
+		Body:  pullRequestBody(request.Body, session, commits),
+		Head:  session.BranchName(),
+		Base:  baseRef,
+		Draft: request.Draft,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("branch was pushed but opening the pull request failed: %w", err)
+	}
+
+	if err := session.SetPullRequest(*pullRequest); err != nil {
+		return nil, err
+	}
+	if err := publishSessionUseCase.sessionRepository.Save(ctx, session); err != nil {
+		return nil, fmt.Errorf("opened pull request %s but failed to save session: %w", pullRequest.URL, err)
+	}
+
+	response.PullRequest = buildPullRequestDTO(*pullRequest)
+	response.PullRequestCreated = true
+	return response, nil
+}
+
+// ensurePullRequestBase refuses, before anything is pushed, a session whose
+// pull request would target a tag or commit, which forges only accept as a
+// branch. Sessions that will not get a new pull request may have any base.
+func (publishSessionUseCase *PublishSessionUseCase) ensurePullRequestBase(ctx context.Context, session *domain.Session, baseRef string) error {
+	if publishSessionUseCase.forge == nil || session.PullRequest().Number != 0 {
+		return nil
+	}
+
+	isBranch, err := publishSessionUseCase.gitOperations.BranchExists(ctx, baseRef)
+	if err != nil {
+		return fmt.Errorf("failed to check base branch: %w", err)
+	}
+	if !isBranch {
+		return fmt.Errorf("base ref %s is not a local branch and cannot be the base of a pull request", baseRef)
+	}
+	return nil
+}
+
+// pullRequestTitle uses the requested title, the commit subject of a
+// single-commit session, or names the session
+func pullRequestTitle(title string, session *domain.Session, commits []domain.Commit) string {
+	if strings.TrimSpace(title) != "" {
+		return title
+	}
+	if len(commits) == 1 {
+		return commits[0].Subject
+	}
+	return fmt.Sprintf("Session '%s'", session.ID())
+}
+
+// pullRequestBody uses the requested body or lists the session's commits,
+// oldest first, followed by a Session-Id line
+func pullRequestBody(body string, session *domain.Session, commits []domain.Commit) string {
+	if strings.TrimSpace(body) != "" {
+		return body
+	}
+
+	var builder strings.Builder
+	builder.WriteString("Commits:\n")
+	for index := len(commits) - 1; index >= 0; index-- {
+		fmt.Fprintf(&builder, "- %s (%.7s)\n", commits[index].Subject, commits[index].SHA)
+	}
+	fmt.Fprintf(&builder, "\n%s: %s\n", sessionIDTrailerKey, session.ID())
+	return builder.String()
+}
+
+func buildPullRequestDTO(pullRequest domain.PullRequest) *PullRequestDTO {
+	if pullRequest.Number == 0 {
+		return nil
+	}
+	return &PullRequestDTO{Number: pullRequest.Number, URL: pullRequest.URL}
+}
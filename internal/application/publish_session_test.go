@@ -0,0 +1,168 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func setupPublishTest(t *testing.T, gitOperations *mockGitOperations, forge domain.Forge) (*PublishSessionUseCase, *mockSessionRepository) {
+	t.Helper()
+
+	if gitOperations.branchExistsFunc == nil {
+		gitOperations.branchExistsFunc = func(ctx context.Context, branch string) (bool, error) {
+			return true, nil
+		}
+	}
+	if gitOperations.getCommitsFunc == nil {
+		gitOperations.getCommitsFunc = func(ctx context.Context, baseRef string, sessionBranch string) ([]domain.Commit, error) {
+			return []domain.Commit{
+				{SHA: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", Subject: "Add tests"},
+				{SHA: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", Subject: "Add login"},
+			}, nil
+		}
+	}
+
+	sessionRepository := newMockSessionRepository()
+	sessionID, _ := domain.NewSessionID("test-session")
+	session, _ := domain.NewSession(sessionID, "/path/test-session", "")
+	sessionRepository.Save(context.Background(), session)
+
+	return NewPublishSessionUseCase(gitOperations, sessionRepository, forge, "origin", "main"), sessionRepository
+}
+
+func TestPublishSessionUseCase_Execute_PushesAndOpensPullRequest(t *testing.T) {
+	// arrange
+	var pushedBranch string
+	gitOperations := &mockGitOperations{
+		pushFunc: func(ctx context.Context, remote string, branchName string, force bool) error {
+			pushedBranch = remote + " " + branchName
+			return nil
+		},
+	}
+	var receivedRequest domain.NewPullRequest
+	forge := &mockForge{
+		createPullRequestFunc: func(ctx context.Context, request domain.NewPullRequest) (*domain.PullRequest, error) {
+			receivedRequest = request
+			return &domain.PullRequest{Number: 5, URL: "https://forge.example.com/pull/5"}, nil
+		},
+	}
+	useCase, sessionRepository := setupPublishTest(t, gitOperations, forge)
+
+	// act
+	response, err := useCase.Execute(context.Background(), PublishSessionRequest{SessionID: "test-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if pushedBranch != "origin orchestragent-test-session" {
+		t.Errorf("pushed %q, want the session branch to origin", pushedBranch)
+	}
+	if !response.PullRequestCreated || response.PullRequest.Number != 5 {
+		t.Errorf("response = %+v, want created pull request 5", response)
+	}
+	if receivedRequest.Head != "orchestragent-test-session" || receivedRequest.Base != "main" {
+		t.Errorf("pull request = %+v, want session branch into main", receivedRequest)
+	}
+	if !strings.Contains(receivedRequest.Body, "- Add login (aaaaaaa)\n- Add tests (bbbbbbb)") {
+		t.Errorf("body = %q, want commits oldest first", receivedRequest.Body)
+	}
+	if saved := sessionRepository.sessions["test-session"]; saved.PullRequest().Number != 5 {
+		t.Errorf("saved pull request = %+v, want number 5", saved.PullRequest())
+	}
+}
+
+func TestPublishSessionUseCase_Execute_ExistingPullRequest_OnlyPushes(t *testing.T) {
+	// arrange
+	forge := &mockForge{
+		createPullRequestFunc: func(ctx context.Context, request domain.NewPullRequest) (*domain.PullRequest, error) {
+			t.Error("CreatePullRequest() should not run for a published session")
+			return nil, errors.New("unexpected")
+		},
+	}
+	useCase, sessionRepository := setupPublishTest(t, &mockGitOperations{}, forge)
+	sessionRepository.sessions["test-session"].SetPullRequest(domain.PullRequest{Number: 3, URL: "https://forge.example.com/pull/3"})
+
+	// act
+	response, err := useCase.Execute(context.Background(), PublishSessionRequest{SessionID: "test-session", Force: true})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if response.PullRequestCreated || response.PullRequest == nil || response.PullRequest.Number != 3 {
+		t.Errorf("response = %+v, want the existing pull request", response)
+	}
+}
+
+func TestPublishSessionUseCase_Execute_WithoutForge_OnlyPushes(t *testing.T) {
+	// arrange
+	useCase, _ := setupPublishTest(t, &mockGitOperations{}, nil)
+
+	// act
+	response, err := useCase.Execute(context.Background(), PublishSessionRequest{SessionID: "test-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if response.PullRequest != nil || response.HeadCommit != "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb" {
+		t.Errorf("response = %+v, want a push without pull request", response)
+	}
+}
+
+func TestPublishSessionUseCase_Execute_NoCommits_ReturnsError(t *testing.T) {
+	// arrange
+	pushCalled := false
+	gitOperations := &mockGitOperations{
+		getCommitsFunc: func(ctx context.Context, baseRef string, sessionBranch string) ([]domain.Commit, error) {
+			return []domain.Commit{}, nil
+		},
+		pushFunc: func(ctx context.Context, remote string, branchName string, force bool) error {
+			pushCalled = true
+			return nil
+		},
+	}
+	useCase, _ := setupPublishTest(t, gitOperations, &mockForge{})
+
+	// act
+	_, err := useCase.Execute(context.Background(), PublishSessionRequest{SessionID: "test-session"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for a session without commits")
+	}
+	if pushCalled {
+		t.Error("Push() should not run without commits")
+	}
+}
+
+func TestPublishSessionUseCase_Execute_NonBranchBase_RefusesBeforePushing(t *testing.T) {
+	// arrange
+	pushCalled := false
+	gitOperations := &mockGitOperations{
+		branchExistsFunc: func(ctx context.Context, branch string) (bool, error) {
+			return false, nil
+		},
+		pushFunc: func(ctx context.Context, remote string, branchName string, force bool) error {
+			pushCalled = true
+			return nil
+		},
+	}
+	useCase, _ := setupPublishTest(t, gitOperations, &mockForge{})
+
+	// act
+	_, err := useCase.Execute(context.Background(), PublishSessionRequest{SessionID: "test-session"})
+
+	// assert
+	if err == nil || !strings.Contains(err.Error(), "not a local branch") {
+		t.Errorf("Execute() error = %v, want the base ref refused", err)
+	}
+	if pushCalled {
+		t.Error("Push() should not run when the pull request base is not a branch")
+	}
+}
@@ -0,0 +1,96 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+const (
+	DefaultReadFileMaxBytes = 256 * 1024
+	MaxReadFileMaxBytes     = 4 * 1024 * 1024
+
+	FileEncodingUTF8   = "utf-8"
+	FileEncodingBase64 = "base64"
+)
+
+type ReadFileRequest struct {
+	SessionID string
+	Path      string
+	MaxBytes  int
+}
+
+type ReadFileResponse struct {
+	SessionID string `json:"sessionId"`
+	Path      string `json:"path"`
+	Content   string `json:"content"`
+	// Encoding is FileEncodingUTF8 for text and FileEncodingBase64 for
+	// anything else
+	Encoding  string `json:"encoding"`
+	SizeBytes int64  `json:"sizeBytes"`
+	Truncated bool   `json:"truncated"`
+}
+
+type ReadFileUseCase struct {
+	worktreeFiles     domain.WorktreeFiles
+	sessionRepository domain.SessionRepository
+}
+
+func NewReadFileUseCase(
+	worktreeFiles domain.WorktreeFiles,
+	sessionRepository domain.SessionRepository,
+) *ReadFileUseCase {
+	return &ReadFileUseCase{
+		worktreeFiles:     worktreeFiles,
+		sessionRepository: sessionRepository,
+	}
+}
+
+func (readFileUseCase *ReadFileUseCase) Execute(
+	ctx context.Context,
+	request ReadFileRequest,
+) (*ReadFileResponse, error) {
+	session, err := findSession(ctx, readFileUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBytes := clampPositive(request.MaxBytes, DefaultReadFileMaxBytes, MaxReadFileMaxBytes)
+	content, err := readFileUseCase.worktreeFiles.ReadFile(ctx, session.WorktreePath(), request.Path, maxBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	data := content.Data
+	if content.Truncated() {
+		data = trimPartialRune(data)
+	}
+	response := &ReadFileResponse{
+		SessionID: session.ID().String(),
+		Path:      request.Path,
+		Content:   string(data),
+		Encoding:  FileEncodingUTF8,
+		SizeBytes: content.SizeBytes,
+		Truncated: content.Truncated(),
+	}
+	if !utf8.Valid(data) || bytes.IndexByte(data, 0) >= 0 {
+		response.Content = base64.StdEncoding.EncodeToString(content.Data)
+		response.Encoding = FileEncodingBase64
+	}
+	return response, nil
+}
+
+// trimPartialRune drops an incomplete UTF-8 sequence cut off at the end of a
+// truncated read, so truncated text is still returned as text
+func trimPartialRune(data []byte) []byte {
+	for cut := 0; cut < utf8.UTFMax && cut <= len(data); cut++ {
+		if utf8.Valid(data[:len(data)-cut]) {
+			return data[:len(data)-cut]
+		}
+	}
+	return data
+}
@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// setupFileSession saves a session whose worktree is /worktrees/<sessionID>
+func setupFileSession(t *testing.T, sessionID string, merged bool) *mockSessionRepository {
+	t.Helper()
+
+	sessionRepository := newMockSessionRepository()
+	id, _ := domain.NewSessionID(sessionID)
+	session, _ := domain.NewSession(id, "/worktrees/"+sessionID, "")
+	if merged {
+		session.MarkMerged()
+	}
+	sessionRepository.Save(context.Background(), session)
+	return sessionRepository
+}
+
+func TestReadFileUseCase_Execute_TextFile_ReturnsUTF8Content(t *testing.T) {
+	// arrange
+	var readFrom, readPath string
+	var readLimit int
+	worktreeFiles := &mockWorktreeFiles{
+		readFileFunc: func(ctx context.Context, worktreePath string, path string, maxBytes int) (*domain.FileContent, error) {
+			readFrom, readPath, readLimit = worktreePath, path, maxBytes
+			return &domain.FileContent{Data: []byte("package main\n"), SizeBytes: 13}, nil
+		},
+	}
+	useCase := NewReadFileUseCase(worktreeFiles, setupFileSession(t, "test-session", false))
+
+	// act
+	response, err := useCase.Execute(context.Background(), ReadFileRequest{SessionID: "test-session", Path: "main.go"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if readFrom != "/worktrees/test-session" || readPath != "main.go" || readLimit != DefaultReadFileMaxBytes {
+		t.Errorf("read %s in %s up to %d bytes, want main.go in the session worktree with the default limit", readPath, readFrom, readLimit)
+	}
+	if response.Content != "package main\n" || response.Encoding != FileEncodingUTF8 || response.Truncated {
+		t.Errorf("response = %+v, want the untruncated text", response)
+	}
+}
+
+func TestReadFileUseCase_Execute_TruncatedInsideRune_DropsPartialRune(t *testing.T) {
+	// arrange
+	worktreeFiles := &mockWorktreeFiles{
+		readFileFunc: func(ctx context.Context, worktreePath string, path string, maxBytes int) (*domain.FileContent, error) {
+			return &domain.FileContent{Data: []byte("caf\xc3"), SizeBytes: 5}, nil
+		},
+	}
+	useCase := NewReadFileUseCase(worktreeFiles, setupFileSession(t, "test-session", false))
+
+	// act
+	response, err := useCase.Execute(context.Background(), ReadFileRequest{SessionID: "test-session", Path: "menu.txt", MaxBytes: 4})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if response.Content != "caf" || response.Encoding != FileEncodingUTF8 || !response.Truncated {
+		t.Errorf("response = %+v, want truncated text without the partial rune", response)
+	}
+}
+
+func TestReadFileUseCase_Execute_BinaryFile_ReturnsBase64(t *testing.T) {
+	// arrange
+	data := []byte{0x89, 'P', 'N', 'G', 0x00}
+	worktreeFiles := &mockWorktreeFiles{
+		readFileFunc: func(ctx context.Context, worktreePath string, path string, maxBytes int) (*domain.FileContent, error) {
+			return &domain.FileContent{Data: data, SizeBytes: int64(len(data))}, nil
+		},
+	}
+	useCase := NewReadFileUseCase(worktreeFiles, setupFileSession(t, "test-session", false))
+
+	// act
+	response, err := useCase.Execute(context.Background(), ReadFileRequest{SessionID: "test-session", Path: "logo.png"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if response.Encoding != FileEncodingBase64 || response.Content != base64.StdEncoding.EncodeToString(data) {
+		t.Errorf("response = %+v, want base64 content", response)
+	}
+}
+
+func TestReadFileUseCase_Execute_PathOutsideWorktree_ReturnsError(t *testing.T) {
+	// arrange
+	worktreeFiles := &mockWorktreeFiles{
+		readFileFunc: func(ctx context.Context, worktreePath string, path string, maxBytes int) (*domain.FileContent, error) {
+			return nil, domain.ErrPathOutsideWorktree
+		},
+	}
+	useCase := NewReadFileUseCase(worktreeFiles, setupFileSession(t, "test-session", false))
+
+	// act
+	_, err := useCase.Execute(context.Background(), ReadFileRequest{SessionID: "test-session", Path: "../secret"})
+
+	// assert
+	if !errors.Is(err, domain.ErrPathOutsideWorktree) {
+		t.Errorf("Execute() error = %v, want ErrPathOutsideWorktree", err)
+	}
+}
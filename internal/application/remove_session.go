@@ -58,6 +58,9 @@ func (removeSessionUseCase *RemoveSessionUseCase) Execute(
 	}
 
 	if !request.Force {
+		if err := removeSessionUseCase.ensureNoStackedChildren(ctx, session); err != nil {
+			return nil, err
+		}
 		err := removeSessionUseCase.checkForUnmergedWork(ctx, session, response)
 		if err != nil {
 			return nil, err
@@ -67,13 +70,9 @@ func (removeSessionUseCase *RemoveSessionUseCase) Execute(
 		}
 	}
 
-	if err := removeSessionUseCase.removeSession(ctx, session, request.Force); err != nil {
+	if err := teardownSession(ctx, removeSessionUseCase.gitOperations, removeSessionUseCase.sessionRepository, session, request.Force); err != nil {
 		return nil, err
 	}
-	removeSessionUseCase.deleteBranchIfPossible(ctx, session)
-	if err := removeSessionUseCase.sessionRepository.Delete(ctx, session.ID()); err != nil {
-		return nil, fmt.Errorf("failed to delete session: %w", err)
-	}
 
 	response.RemovedAt = time.Now()
 	response.HasUnmergedChanges = false
@@ -96,6 +95,19 @@ func (removeSessionUseCase *RemoveSessionUseCase) fetchSession(ctx context.Conte
 	return session, nil
 }
 
+// ensureNoStackedChildren refuses to remove a session other sessions are
+// stacked on, since removing its branch would leave them without a base
+func (removeSessionUseCase *RemoveSessionUseCase) ensureNoStackedChildren(ctx context.Context, session *domain.Session) error {
+	children, err := findStackedChildren(ctx, removeSessionUseCase.sessionRepository, session)
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		return fmt.Errorf("sessions stacked on %s would be orphaned: %s; restack or remove them first, or call with force=true", session.ID(), sessionIDList(children))
+	}
+	return nil
+}
+
 func (removeSessionUseCase *RemoveSessionUseCase) checkForUnmergedWork(
 	ctx context.Context,
 	session *domain.Session,
@@ -106,7 +118,7 @@ func (removeSessionUseCase *RemoveSessionUseCase) checkForUnmergedWork(
 		return fmt.Errorf("failed to check uncommitted changes: %w", err)
 	}
 
-	unpushedCount, err := removeSessionUseCase.gitOperations.HasUnpushedCommits(ctx, removeSessionUseCase.baseBranch, session.BranchName())
+	unpushedCount, err := removeSessionUseCase.gitOperations.HasUnpushedCommits(ctx, baseRefFor(session, removeSessionUseCase.baseBranch), session.BranchName())
 	if err != nil {
 		return fmt.Errorf("failed to check unpushed commits: %w", err)
 	}
@@ -129,14 +141,3 @@ func (removeSessionUseCase *RemoveSessionUseCase) setUnmergedChanges(response *R
 		fileCount,
 	)
 }
-
-func (removeSessionUseCase *RemoveSessionUseCase) removeSession(ctx context.Context, session *domain.Session, force bool) error {
-	if err := removeSessionUseCase.gitOperations.RemoveWorktree(ctx, session.WorktreePath(), force); err != nil {
-		return fmt.Errorf("failed to remove worktree: %w", err)
-	}
-	return nil
-}
-
-func (removeSessionUseCase *RemoveSessionUseCase) deleteBranchIfPossible(ctx context.Context, session *domain.Session) {
-	removeSessionUseCase.gitOperations.DeleteBranch(ctx, session.BranchName(), true)
-}
@@ -25,6 +25,41 @@ func TestRemoveSessionUseCase_Execute_SessionNotFound(t *testing.T) {
 	}
 }
 
+func TestRemoveSessionUseCase_Execute_StackedChildrenWithoutForce_ReturnsError(t *testing.T) {
+	// arrange
+	removed := false
+	gitOperations := &mockGitOperations{
+		removeWorktreeFunc: func(ctx context.Context, path string, force bool) error {
+			removed = true
+			return nil
+		},
+	}
+	sessionRepository := newMockSessionRepository()
+	removeSessionUseCase := NewRemoveSessionUseCase(gitOperations, sessionRepository, "main")
+
+	parentID, _ := domain.NewSessionID("parent-session")
+	parent, _ := domain.NewSession(parentID, "/path/parent", "")
+	sessionRepository.Save(context.Background(), parent)
+	childID, _ := domain.NewSessionID("child-session")
+	child, _ := domain.NewSession(childID, "/path/child", parent.BranchName())
+	child.SetParentSessionID(parentID)
+	sessionRepository.Save(context.Background(), child)
+
+	request := RemoveSessionRequest{SessionID: "parent-session", Force: false}
+	ctx := context.Background()
+
+	// act
+	_, err := removeSessionUseCase.Execute(ctx, request)
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for a session with stacked children")
+	}
+	if removed {
+		t.Error("Execute() should not remove a session with stacked children")
+	}
+}
+
 func TestRemoveSessionUseCase_Execute_UncommittedChangesWithoutForce(t *testing.T) {
 	// arrange
 	gitOperations := &mockGitOperations{
@@ -39,7 +74,7 @@ func TestRemoveSessionUseCase_Execute_UncommittedChangesWithoutForce(t *testing.
 	removeSessionUseCase := NewRemoveSessionUseCase(gitOperations, sessionRepository, "main")
 
 	sessionID, _ := domain.NewSessionID("test-session")
-	session, _ := domain.NewSession(sessionID, "/path")
+	session, _ := domain.NewSession(sessionID, "/path", "")
 	sessionRepository.Save(context.Background(), session)
 
 	request := RemoveSessionRequest{SessionID: "test-session", Force: false}
@@ -80,7 +115,7 @@ func TestRemoveSessionUseCase_Execute_UnpushedCommitsWithoutForce(t *testing.T)
 	removeSessionUseCase := NewRemoveSessionUseCase(gitOperations, sessionRepository, "main")
 
 	sessionID, _ := domain.NewSessionID("test-session")
-	session, _ := domain.NewSession(sessionID, "/path")
+	session, _ := domain.NewSession(sessionID, "/path", "")
 	sessionRepository.Save(context.Background(), session)
 
 	request := RemoveSessionRequest{SessionID: "test-session", Force: false}
@@ -118,7 +153,7 @@ func TestRemoveSessionUseCase_Execute_CleanWorktree(t *testing.T) {
 	removeSessionUseCase := NewRemoveSessionUseCase(gitOperations, sessionRepository, "main")
 
 	sessionID, _ := domain.NewSessionID("test-session")
-	session, _ := domain.NewSession(sessionID, "/path")
+	session, _ := domain.NewSession(sessionID, "/path", "")
 	sessionRepository.Save(context.Background(), session)
 
 	request := RemoveSessionRequest{SessionID: "test-session", Force: false}
@@ -158,7 +193,7 @@ func TestRemoveSessionUseCase_Execute_ForceRemoveWithChanges(t *testing.T) {
 	removeSessionUseCase := NewRemoveSessionUseCase(gitOperations, sessionRepository, "main")
 
 	sessionID, _ := domain.NewSessionID("test-session")
-	session, _ := domain.NewSession(sessionID, "/path")
+	session, _ := domain.NewSession(sessionID, "/path", "")
 	sessionRepository.Save(context.Background(), session)
 
 	request := RemoveSessionRequest{SessionID: "test-session", Force: true}
@@ -218,7 +253,7 @@ func TestRemoveSessionUseCase_Execute_GitOperationFails(t *testing.T) {
 	removeSessionUseCase := NewRemoveSessionUseCase(gitOperations, sessionRepository, "main")
 
 	sessionID, _ := domain.NewSessionID("test-session")
-	session, _ := domain.NewSession(sessionID, "/path")
+	session, _ := domain.NewSession(sessionID, "/path", "")
 	sessionRepository.Save(context.Background(), session)
 
 	request := RemoveSessionRequest{SessionID: "test-session", Force: false}
@@ -250,7 +285,7 @@ func TestRemoveSessionUseCase_Execute_BranchDeleteFailsContinues(t *testing.T) {
 	removeSessionUseCase := NewRemoveSessionUseCase(gitOperations, sessionRepository, "main")
 
 	sessionID, _ := domain.NewSessionID("test-session")
-	session, _ := domain.NewSession(sessionID, "/path")
+	session, _ := domain.NewSession(sessionID, "/path", "")
 	sessionRepository.Save(context.Background(), session)
 
 	request := RemoveSessionRequest{SessionID: "test-session", Force: false}
@@ -272,3 +307,62 @@ func TestRemoveSessionUseCase_Execute_BranchDeleteFailsContinues(t *testing.T) {
 		t.Error("Execute() expected session to be deleted from repository")
 	}
 }
+
+func TestRemoveSessionUseCase_Execute_ChecksUnmergedWorkAgainstSessionBaseRef(t *testing.T) {
+	// arrange
+	var receivedBaseBranch string
+	gitOperations := &mockGitOperations{
+		hasUnpushedCommitsFunc: func(ctx context.Context, baseBranch string, sessionBranch string) (int, error) {
+			receivedBaseBranch = baseBranch
+			return 0, nil
+		},
+	}
+	sessionRepository := newMockSessionRepository()
+	removeSessionUseCase := NewRemoveSessionUseCase(gitOperations, sessionRepository, "main")
+
+	sessionID, _ := domain.NewSessionID("test-session")
+	session, _ := domain.NewSession(sessionID, "/path", "hotfix/2.1")
+	sessionRepository.Save(context.Background(), session)
+
+	request := RemoveSessionRequest{SessionID: "test-session", Force: false}
+	ctx := context.Background()
+
+	// act
+	_, err := removeSessionUseCase.Execute(ctx, request)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if receivedBaseBranch != "hotfix/2.1" {
+		t.Errorf("HasUnpushedCommits() baseBranch = %q, want %q", receivedBaseBranch, "hotfix/2.1")
+	}
+}
+
+func TestRemoveSessionUseCase_Execute_DeletesCheckpoints(t *testing.T) {
+	// arrange
+	var deletedPrefix string
+	gitOperations := &mockGitOperations{
+		deleteCheckpointsFunc: func(ctx context.Context, refPrefix string) error {
+			deletedPrefix = refPrefix
+			return nil
+		},
+	}
+	sessionRepository := newMockSessionRepository()
+	removeSessionUseCase := NewRemoveSessionUseCase(gitOperations, sessionRepository, "main")
+
+	sessionID, _ := domain.NewSessionID("test-session")
+	session, _ := domain.NewSession(sessionID, "/path", "")
+	sessionRepository.Save(context.Background(), session)
+
+	// act
+	_, err := removeSessionUseCase.Execute(context.Background(), RemoveSessionRequest{SessionID: "test-session", Force: true})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if deletedPrefix != sessionID.CheckpointRefPrefix() {
+		t.Errorf("DeleteCheckpoints() prefix = %q, want %q", deletedPrefix, sessionID.CheckpointRefPrefix())
+	}
+}
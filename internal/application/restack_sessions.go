@@ -0,0 +1,299 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type RestackSessionsRequest struct {
+	// SessionID limits the restack to the sessions stacked above this one;
+	// empty restacks every stack
+	SessionID string
+}
+
+type RestackResultDTO struct {
+	SessionID          string   `json:"sessionId"`
+	ParentSessionID    string   `json:"parentSessionId"`
+	BaseRef            string   `json:"baseRef"`
+	PreviousBaseCommit string   `json:"previousBaseCommit,omitempty"`
+	BaseCommit         string   `json:"baseCommit,omitempty"`
+	HeadCommit         string   `json:"headCommit,omitempty"`
+	Restacked          bool     `json:"restacked"`
+	ConflictedPaths    []string `json:"conflictedPaths,omitempty"`
+	SkippedReason      string   `json:"skippedReason,omitempty"`
+}
+
+type RestackSessionsResponse struct {
+	Sessions  []RestackResultDTO `json:"sessions"`
+	StackTree string             `json:"stackTree,omitempty"`
+}
+
+type RestackSessionsUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+	baseBranch        string
+}
+
+func NewRestackSessionsUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+	baseBranch string,
+) *RestackSessionsUseCase {
+	return &RestackSessionsUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+		baseBranch:        baseBranch,
+	}
+}
+
+// Execute walks each stack from the bottom up and rebases every open session
+// onto the current tip of the session it is stacked on. Children of a merged
+// session move onto that session's base, and are stacked on its parent if it
+// had one. Only commits made after the recorded base commit are replayed.
+// A session that conflicts or has uncommitted changes is left alone together
+// with everything stacked above it.
+func (restackSessionsUseCase *RestackSessionsUseCase) Execute(
+	ctx context.Context,
+	request RestackSessionsRequest,
+) (*RestackSessionsResponse, error) {
+	sessions, err := restackSessionsUseCase.sessionRepository.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	response := &RestackSessionsResponse{Sessions: make([]RestackResultDTO, 0)}
+	roots, err := restackSessionsUseCase.findRoots(ctx, sessions, request.SessionID, response)
+	if err != nil {
+		return nil, err
+	}
+
+	// visited stops the walk on parent links that form a cycle
+	visited := make(map[domain.SessionID]bool)
+	for _, root := range roots {
+		visited[root.ID()] = true
+		if err := restackSessionsUseCase.restackChildren(ctx, sessions, root, response, visited); err != nil {
+			return nil, err
+		}
+	}
+
+	response.StackTree = renderStackTree(sessions, restackSessionsUseCase.baseBranch)
+	return response, nil
+}
+
+// findRoots returns the requested session, or every session that is not
+// stacked on another one. Sessions whose stack parent was removed are
+// reported as skipped.
+func (restackSessionsUseCase *RestackSessionsUseCase) findRoots(
+	ctx context.Context,
+	sessions []*domain.Session,
+	sessionID string,
+	response *RestackSessionsResponse,
+) ([]*domain.Session, error) {
+	if sessionID != "" {
+		root, err := findSession(ctx, restackSessionsUseCase.sessionRepository, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		return []*domain.Session{root}, nil
+	}
+
+	roots := make([]*domain.Session, 0)
+	for _, session := range sessions {
+		if stackParent(sessions, session) != nil {
+			continue
+		}
+		roots = append(roots, session)
+
+		parentID := session.ParentSessionID()
+		if parentID.String() != "" && session.BaseRef() == parentID.BranchName() && session.Status() != domain.StatusMerged {
+			response.Sessions = append(response.Sessions, RestackResultDTO{
+				SessionID:       session.ID().String(),
+				ParentSessionID: parentID.String(),
+				BaseRef:         session.BaseRef(),
+				BaseCommit:      session.BaseCommit(),
+				SkippedReason:   fmt.Sprintf("parent session %s no longer exists", parentID),
+			})
+		}
+	}
+	return roots, nil
+}
+
+func (restackSessionsUseCase *RestackSessionsUseCase) restackChildren(
+	ctx context.Context,
+	sessions []*domain.Session,
+	parent *domain.Session,
+	response *RestackSessionsResponse,
+	visited map[domain.SessionID]bool,
+) error {
+	for _, child := range stackedChildren(sessions, parent) {
+		if visited[child.ID()] {
+			continue
+		}
+		visited[child.ID()] = true
+
+		result, err := restackSessionsUseCase.restackSession(ctx, sessions, child, parent)
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			// merged sessions stay where they are, but their children move
+			if err := restackSessionsUseCase.restackChildren(ctx, sessions, child, response, visited); err != nil {
+				return err
+			}
+			continue
+		}
+
+		response.Sessions = append(response.Sessions, *result)
+		if result.SkippedReason != "" || len(result.ConflictedPaths) > 0 {
+			restackSessionsUseCase.skipChildren(sessions, child, response, visited)
+			continue
+		}
+		if err := restackSessionsUseCase.restackChildren(ctx, sessions, child, response, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restackSession rebases child onto the tip of its new base. It returns nil
+// for merged sessions, which are not moved.
+func (restackSessionsUseCase *RestackSessionsUseCase) restackSession(
+	ctx context.Context,
+	sessions []*domain.Session,
+	child *domain.Session,
+	parent *domain.Session,
+) (*RestackResultDTO, error) {
+	if child.Status() == domain.StatusMerged {
+		return nil, nil
+	}
+
+	newBaseRef := parent.BranchName()
+	newParent := parent
+	passed := map[domain.SessionID]bool{child.ID(): true}
+	for ancestor := parent; ancestor != nil && ancestor.Status() == domain.StatusMerged && !passed[ancestor.ID()]; ancestor = newParent {
+		passed[ancestor.ID()] = true
+		newBaseRef = baseRefFor(ancestor, restackSessionsUseCase.baseBranch)
+		newParent = stackParent(sessions, ancestor)
+	}
+
+	result := &RestackResultDTO{
+		SessionID:          child.ID().String(),
+		ParentSessionID:    parent.ID().String(),
+		BaseRef:            newBaseRef,
+		PreviousBaseCommit: child.BaseCommit(),
+	}
+
+	newBase, err := restackSessionsUseCase.gitOperations.ResolveCommit(ctx, newBaseRef)
+	if err != nil {
+		result.BaseRef = child.BaseRef()
+		result.BaseCommit = child.BaseCommit()
+		result.SkippedReason = fmt.Sprintf("base ref %s not found", newBaseRef)
+		return result, nil
+	}
+	result.BaseCommit = newBase
+
+	if newBase == child.BaseCommit() && newBaseRef == child.BaseRef() {
+		headCommit, err := restackSessionsUseCase.gitOperations.ResolveCommit(ctx, child.BranchName())
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve session head: %w", err)
+		}
+		result.HeadCommit = headCommit
+		return result, nil
+	}
+
+	hasUncommitted, fileCount, err := restackSessionsUseCase.gitOperations.HasUncommittedChanges(ctx, child.WorktreePath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to check uncommitted changes: %w", err)
+	}
+	if hasUncommitted {
+		result.BaseRef = child.BaseRef()
+		result.BaseCommit = child.BaseCommit()
+		result.SkippedReason = fmt.Sprintf("session has %d uncommitted files", fileCount)
+		return result, nil
+	}
+
+	upstream := child.BaseCommit()
+	if upstream == "" {
+		upstream = child.BaseRef()
+	}
+	err = restackSessionsUseCase.gitOperations.RebaseOnto(ctx, child.WorktreePath(), newBase, upstream)
+	var conflictErr *domain.ConflictError
+	if errors.As(err, &conflictErr) {
+		result.BaseRef = child.BaseRef()
+		result.BaseCommit = child.BaseCommit()
+		result.ConflictedPaths = conflictErr.Paths
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restack session %s: %w", child.ID(), err)
+	}
+
+	if err := restackSessionsUseCase.recordNewBase(ctx, child, newBaseRef, newBase, newParent); err != nil {
+		return nil, err
+	}
+
+	headCommit, err := restackSessionsUseCase.gitOperations.ResolveCommit(ctx, child.BranchName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve session head: %w", err)
+	}
+	result.HeadCommit = headCommit
+	result.Restacked = true
+	return result, nil
+}
+
+// recordNewBase saves the base a session was restacked onto. A session whose
+// parent was merged into a base that is not a session keeps the merged
+// parent as its lineage but is no longer stacked.
+func (restackSessionsUseCase *RestackSessionsUseCase) recordNewBase(
+	ctx context.Context,
+	session *domain.Session,
+	baseRef string,
+	baseCommit string,
+	parent *domain.Session,
+) error {
+	if err := session.SetBaseRef(baseRef); err != nil {
+		return err
+	}
+	if err := session.SetBaseCommit(baseCommit); err != nil {
+		return err
+	}
+	if parent != nil {
+		if err := session.SetParentSessionID(parent.ID()); err != nil {
+			return err
+		}
+	}
+	if err := restackSessionsUseCase.sessionRepository.Save(ctx, session); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// skipChildren reports every open session stacked above a session that could
+// not be restacked
+func (restackSessionsUseCase *RestackSessionsUseCase) skipChildren(
+	sessions []*domain.Session,
+	parent *domain.Session,
+	response *RestackSessionsResponse,
+	visited map[domain.SessionID]bool,
+) {
+	for _, child := range stackedChildren(sessions, parent) {
+		if visited[child.ID()] {
+			continue
+		}
+		visited[child.ID()] = true
+
+		if child.Status() != domain.StatusMerged {
+			response.Sessions = append(response.Sessions, RestackResultDTO{
+				SessionID:       child.ID().String(),
+				ParentSessionID: parent.ID().String(),
+				BaseRef:         child.BaseRef(),
+				BaseCommit:      child.BaseCommit(),
+				SkippedReason:   fmt.Sprintf("parent session %s was not restacked", parent.ID()),
+			})
+		}
+		restackSessionsUseCase.skipChildren(sessions, child, response, visited)
+	}
+}
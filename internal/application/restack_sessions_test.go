@@ -0,0 +1,191 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+const (
+	oldParentTip = "1111111111111111111111111111111111111111"
+	newParentTip = "2222222222222222222222222222222222222222"
+	oldChildTip  = "3333333333333333333333333333333333333333"
+	newChildTip  = "4444444444444444444444444444444444444444"
+	mainTip      = "5555555555555555555555555555555555555555"
+)
+
+type rebaseOntoCall struct {
+	worktreePath string
+	newBase      string
+	upstream     string
+}
+
+// saveTestStack stores parent <- child <- grandchild, each stacked on the
+// previous one at its old tip
+func saveTestStack(t *testing.T, sessionRepository *mockSessionRepository) (*domain.Session, *domain.Session, *domain.Session) {
+	t.Helper()
+
+	newTestSession := func(id string, baseRef string, baseCommit string, parent *domain.Session) *domain.Session {
+		sessionID, _ := domain.NewSessionID(id)
+		session, _ := domain.NewSession(sessionID, "/worktrees/"+id, baseRef)
+		session.SetBaseCommit(baseCommit)
+		if parent != nil {
+			session.SetParentSessionID(parent.ID())
+		}
+		sessionRepository.Save(context.Background(), session)
+		return session
+	}
+
+	parent := newTestSession("parent", "main", mainTip, nil)
+	child := newTestSession("child", parent.BranchName(), oldParentTip, parent)
+	grandchild := newTestSession("grandchild", child.BranchName(), oldChildTip, child)
+	return parent, child, grandchild
+}
+
+func newRestackGitOperations(tips map[string]string, calls *[]rebaseOntoCall) *mockGitOperations {
+	return &mockGitOperations{
+		resolveCommitFunc: func(ctx context.Context, ref string) (string, error) {
+			return tips[ref], nil
+		},
+		rebaseOntoFunc: func(ctx context.Context, worktreePath string, newBase string, upstream string) error {
+			*calls = append(*calls, rebaseOntoCall{worktreePath, newBase, upstream})
+			return nil
+		},
+	}
+}
+
+func TestRestackSessionsUseCase_Execute_ParentMoved_RebasesWholeChain(t *testing.T) {
+	// arrange
+	sessionRepository := newMockSessionRepository()
+	parent, child, grandchild := saveTestStack(t, sessionRepository)
+	var calls []rebaseOntoCall
+	tips := map[string]string{
+		"main":                  mainTip,
+		parent.BranchName():     newParentTip,
+		child.BranchName():      newChildTip,
+		grandchild.BranchName(): "6666666666666666666666666666666666666666",
+	}
+	useCase := NewRestackSessionsUseCase(newRestackGitOperations(tips, &calls), sessionRepository, "main")
+
+	// act
+	response, err := useCase.Execute(context.Background(), RestackSessionsRequest{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	expected := []rebaseOntoCall{
+		{child.WorktreePath(), newParentTip, oldParentTip},
+		{grandchild.WorktreePath(), newChildTip, oldChildTip},
+	}
+	if len(calls) != len(expected) || calls[0] != expected[0] || calls[1] != expected[1] {
+		t.Errorf("rebases = %+v, want %+v", calls, expected)
+	}
+	if len(response.Sessions) != 2 || !response.Sessions[0].Restacked || !response.Sessions[1].Restacked {
+		t.Errorf("results = %+v, want both sessions restacked", response.Sessions)
+	}
+	if saved := sessionRepository.sessions["child"]; saved.BaseCommit() != newParentTip || !saved.IsStackedOn(parent) {
+		t.Errorf("saved child base commit = %s, want %s on the parent", saved.BaseCommit(), newParentTip)
+	}
+	if response.StackTree != "parent (on main)\n└── child\n    └── grandchild" {
+		t.Errorf("StackTree = %q", response.StackTree)
+	}
+}
+
+func TestRestackSessionsUseCase_Execute_ParentMerged_MovesChildOntoParentBase(t *testing.T) {
+	// arrange
+	sessionRepository := newMockSessionRepository()
+	parent, child, grandchild := saveTestStack(t, sessionRepository)
+	parent.MarkMerged()
+	var calls []rebaseOntoCall
+	tips := map[string]string{
+		"main":              mainTip,
+		parent.BranchName(): oldParentTip,
+		child.BranchName():  oldChildTip,
+	}
+	useCase := NewRestackSessionsUseCase(newRestackGitOperations(tips, &calls), sessionRepository, "main")
+
+	// act
+	response, err := useCase.Execute(context.Background(), RestackSessionsRequest{SessionID: "parent"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if len(calls) != 1 || calls[0] != (rebaseOntoCall{child.WorktreePath(), mainTip, oldParentTip}) {
+		t.Errorf("rebases = %+v, want only the child moved onto main", calls)
+	}
+	saved := sessionRepository.sessions["child"]
+	if saved.BaseRef() != "main" || saved.IsStackedOn(parent) || saved.ParentSessionID() != parent.ID() {
+		t.Errorf("saved child base %q parent %s, want main with the merged parent kept as lineage", saved.BaseRef(), saved.ParentSessionID())
+	}
+	if len(response.Sessions) != 2 || response.Sessions[1].SessionID != "grandchild" || response.Sessions[1].Restacked {
+		t.Errorf("results = %+v, want the grandchild already up to date", response.Sessions)
+	}
+	if !grandchild.IsStackedOn(child) {
+		t.Error("grandchild should stay stacked on the child")
+	}
+}
+
+func TestRestackSessionsUseCase_Execute_Conflict_SkipsSessionsAbove(t *testing.T) {
+	// arrange
+	sessionRepository := newMockSessionRepository()
+	parent, child, _ := saveTestStack(t, sessionRepository)
+	tips := map[string]string{
+		"main":              mainTip,
+		parent.BranchName(): newParentTip,
+		child.BranchName():  oldChildTip,
+	}
+	gitOperations := &mockGitOperations{
+		resolveCommitFunc: func(ctx context.Context, ref string) (string, error) {
+			return tips[ref], nil
+		},
+		rebaseOntoFunc: func(ctx context.Context, worktreePath string, newBase string, upstream string) error {
+			return &domain.ConflictError{Operation: "rebase", Paths: []string{"shared.go"}}
+		},
+	}
+	useCase := NewRestackSessionsUseCase(gitOperations, sessionRepository, "main")
+
+	// act
+	response, err := useCase.Execute(context.Background(), RestackSessionsRequest{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if len(response.Sessions) != 2 {
+		t.Fatalf("results = %+v, want the child and grandchild", response.Sessions)
+	}
+	if childResult := response.Sessions[0]; childResult.Restacked || len(childResult.ConflictedPaths) != 1 || childResult.BaseCommit != oldParentTip {
+		t.Errorf("child result = %+v, want a conflict on the old base", childResult)
+	}
+	if grandchildResult := response.Sessions[1]; grandchildResult.SkippedReason == "" {
+		t.Errorf("grandchild result = %+v, want it skipped", grandchildResult)
+	}
+	if saved := sessionRepository.sessions["child"]; saved.BaseCommit() != oldParentTip {
+		t.Errorf("saved child base commit = %s, want it unchanged", saved.BaseCommit())
+	}
+}
+
+func TestRestackSessionsUseCase_Execute_Cycle_VisitsEachSessionOnce(t *testing.T) {
+	// arrange
+	sessionRepository := newMockSessionRepository()
+	first, second := newTestCycle(t)
+	sessionRepository.Save(context.Background(), first)
+	sessionRepository.Save(context.Background(), second)
+	var calls []rebaseOntoCall
+	gitOperations := newRestackGitOperations(map[string]string{}, &calls)
+	useCase := NewRestackSessionsUseCase(gitOperations, sessionRepository, "main")
+
+	// act
+	response, err := useCase.Execute(context.Background(), RestackSessionsRequest{SessionID: "first"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if len(response.Sessions) != 1 || response.Sessions[0].SessionID != "second" {
+		t.Errorf("Sessions = %+v, want only second restacked onto first", response.Sessions)
+	}
+}
@@ -0,0 +1,90 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type RestoreCheckpointRequest struct {
+	SessionID string
+	Name      string
+}
+
+type RestoreCheckpointResponse struct {
+	SessionID  string        `json:"sessionId"`
+	Checkpoint CheckpointDTO `json:"checkpoint"`
+	// Backup holds the state the session was in before the restore, so the
+	// restore itself can be undone
+	Backup     CheckpointDTO `json:"backup"`
+	RestoredAt time.Time     `json:"restoredAt"`
+}
+
+type RestoreCheckpointUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+}
+
+func NewRestoreCheckpointUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+) *RestoreCheckpointUseCase {
+	return &RestoreCheckpointUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+	}
+}
+
+// Execute puts the session worktree and branch back to the state recorded in
+// a checkpoint. Anything done since, committed or not, is first saved as a
+// before-restore-<n> checkpoint and then discarded.
+func (restoreCheckpointUseCase *RestoreCheckpointUseCase) Execute(
+	ctx context.Context,
+	request RestoreCheckpointRequest,
+) (*RestoreCheckpointResponse, error) {
+	session, err := findSession(ctx, restoreCheckpointUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status() == domain.StatusMerged {
+		return nil, fmt.Errorf("session %s is already merged", session.ID())
+	}
+
+	checkpointName, err := domain.NewCheckpointName(request.Name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint name: %w", err)
+	}
+	checkpointRef := session.ID().CheckpointRef(checkpointName)
+
+	existing, err := restoreCheckpointUseCase.gitOperations.ListCheckpoints(ctx, session.ID().CheckpointRefPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up checkpoint: %w", err)
+	}
+	var checkpoint *domain.Checkpoint
+	for index := range existing {
+		if existing[index].Ref == checkpointRef {
+			checkpoint = &existing[index]
+		}
+	}
+	if checkpoint == nil {
+		return nil, fmt.Errorf("checkpoint not found: %s", checkpointName)
+	}
+
+	backupName, err := firstFreeCheckpointName(existing, "before-restore", 1)
+	if err != nil {
+		return nil, err
+	}
+	backup, err := restoreCheckpointUseCase.gitOperations.RestoreCheckpoint(ctx, session.WorktreePath(), checkpointRef, session.ID().CheckpointRef(backupName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore checkpoint: %w", err)
+	}
+
+	return &RestoreCheckpointResponse{
+		SessionID:  session.ID().String(),
+		Checkpoint: buildCheckpointDTO(*checkpoint),
+		Backup:     buildCheckpointDTO(*backup),
+		RestoredAt: time.Now(),
+	}, nil
+}
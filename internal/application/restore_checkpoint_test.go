@@ -0,0 +1,68 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func TestRestoreCheckpointUseCase_Execute_RestoresNamedCheckpoint(t *testing.T) {
+	// arrange
+	var restoredRef, restoredPath, backupRef string
+	gitOperations := &mockGitOperations{
+		listCheckpointsFunc: func(ctx context.Context, refPrefix string) ([]domain.Checkpoint, error) {
+			return []domain.Checkpoint{
+				{Name: "before-refactor", Ref: refPrefix + "before-refactor"},
+				{Name: "before-restore-1", Ref: refPrefix + "before-restore-1"},
+			}, nil
+		},
+		restoreCheckpointFunc: func(ctx context.Context, worktreePath string, ref string, backup string) (*domain.Checkpoint, error) {
+			restoredPath = worktreePath
+			restoredRef = ref
+			backupRef = backup
+			return &domain.Checkpoint{Name: "before-restore-2", Ref: backup}, nil
+		},
+	}
+	useCase := NewRestoreCheckpointUseCase(gitOperations, setupCheckpointTest(t))
+
+	// act
+	response, err := useCase.Execute(context.Background(), RestoreCheckpointRequest{SessionID: "test-session", Name: "before-refactor"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if restoredRef != "refs/orchestragent/test-session/checkpoints/before-refactor" || restoredPath != "/path/test-session" {
+		t.Errorf("RestoreCheckpoint() = %q in %q, want the session's before-refactor checkpoint", restoredRef, restoredPath)
+	}
+	if backupRef != "refs/orchestragent/test-session/checkpoints/before-restore-2" || response.Backup.Name != "before-restore-2" {
+		t.Errorf("backup = %q (%+v), want the first free before-restore name", backupRef, response.Backup)
+	}
+	if response.RestoredAt.IsZero() {
+		t.Error("Execute() expected RestoredAt to be set")
+	}
+}
+
+func TestRestoreCheckpointUseCase_Execute_UnknownCheckpoint(t *testing.T) {
+	// arrange
+	restoreCalled := false
+	gitOperations := &mockGitOperations{
+		restoreCheckpointFunc: func(ctx context.Context, worktreePath string, ref string, backupRef string) (*domain.Checkpoint, error) {
+			restoreCalled = true
+			return &domain.Checkpoint{}, nil
+		},
+	}
+	useCase := NewRestoreCheckpointUseCase(gitOperations, setupCheckpointTest(t))
+
+	// act
+	_, err := useCase.Execute(context.Background(), RestoreCheckpointRequest{SessionID: "test-session", Name: "missing"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for an unknown checkpoint")
+	}
+	if restoreCalled {
+		t.Error("RestoreCheckpoint() should not run for an unknown checkpoint")
+	}
+}
@@ -0,0 +1,152 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+const (
+	DefaultSearchContextLines = 0
+	MaxSearchContextLines     = 10
+	DefaultSearchMaxResults   = 100
+	MaxSearchMaxResults       = 1000
+
+	// searchLineMaxBytes caps each returned line so that a match in minified
+	// or generated code does not flood the response
+	searchLineMaxBytes = 1024
+)
+
+type SearchSessionRequest struct {
+	SessionID        string
+	Pattern          string
+	Literal          bool
+	IgnoreCase       bool
+	PathGlobs        []string
+	IncludeUntracked bool
+	// ContextLines is the number of lines shown before and after each match;
+	// nil selects DefaultSearchContextLines
+	ContextLines *int
+	MaxResults   int
+}
+
+type SearchMatchDTO struct {
+	Path          string   `json:"path"`
+	LineNumber    int      `json:"lineNumber"`
+	Column        int      `json:"column"`
+	Line          string   `json:"line"`
+	ContextBefore []string `json:"contextBefore,omitempty"`
+	ContextAfter  []string `json:"contextAfter,omitempty"`
+}
+
+type SearchSessionResponse struct {
+	SessionID string           `json:"sessionId"`
+	Matches   []SearchMatchDTO `json:"matches"`
+	Truncated bool             `json:"truncated"`
+}
+
+type SearchSessionUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+}
+
+func NewSearchSessionUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+) *SearchSessionUseCase {
+	return &SearchSessionUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+	}
+}
+
+func (searchSessionUseCase *SearchSessionUseCase) Execute(
+	ctx context.Context,
+	request SearchSessionRequest,
+) (*SearchSessionResponse, error) {
+	session, err := findSession(ctx, searchSessionUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := buildSearchOptions(request)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := searchSessionUseCase.gitOperations.Search(ctx, session.WorktreePath(), options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search session: %w", err)
+	}
+
+	matches := make([]SearchMatchDTO, 0, len(result.Matches))
+	for _, match := range result.Matches {
+		matches = append(matches, SearchMatchDTO{
+			Path:          match.Path,
+			LineNumber:    match.LineNumber,
+			Column:        match.Column,
+			Line:          truncateSearchLine(match.Line),
+			ContextBefore: truncateSearchLines(match.ContextBefore),
+			ContextAfter:  truncateSearchLines(match.ContextAfter),
+		})
+	}
+
+	return &SearchSessionResponse{
+		SessionID: session.ID().String(),
+		Matches:   matches,
+		Truncated: result.Truncated,
+	}, nil
+}
+
+func buildSearchOptions(request SearchSessionRequest) (domain.SearchOptions, error) {
+	if request.Pattern == "" {
+		return domain.SearchOptions{}, errors.New("search pattern must not be empty")
+	}
+
+	contextLines := DefaultSearchContextLines
+	if request.ContextLines != nil {
+		contextLines = *request.ContextLines
+	}
+	if contextLines < 0 || contextLines > MaxSearchContextLines {
+		return domain.SearchOptions{}, fmt.Errorf("context lines must be between 0 and %d, got %d", MaxSearchContextLines, contextLines)
+	}
+
+	if err := validatePathGlobs(request.PathGlobs); err != nil {
+		return domain.SearchOptions{}, err
+	}
+
+	return domain.SearchOptions{
+		Pattern:          request.Pattern,
+		Literal:          request.Literal,
+		IgnoreCase:       request.IgnoreCase,
+		PathGlobs:        request.PathGlobs,
+		IncludeUntracked: request.IncludeUntracked,
+		ContextLines:     contextLines,
+		MaxResults:       clampPositive(request.MaxResults, DefaultSearchMaxResults, MaxSearchMaxResults),
+	}, nil
+}
+
+func truncateSearchLines(lines []string) []string {
+	truncated := make([]string, 0, len(lines))
+	for _, line := range lines {
+		truncated = append(truncated, truncateSearchLine(line))
+	}
+	return truncated
+}
+
+// truncateSearchLine cuts a line at searchLineMaxBytes without splitting a
+// UTF-8 character and marks the cut with an ellipsis
+func truncateSearchLine(line string) string {
+	if len(line) <= searchLineMaxBytes {
+		return line
+	}
+
+	cut := searchLineMaxBytes
+	for cut > 0 && !utf8.RuneStart(line[cut]) {
+		cut--
+	}
+	return line[:cut] + "…"
+}
@@ -0,0 +1,91 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func TestSearchSessionUseCase_Execute_SearchesSessionWorktreeWithDefaults(t *testing.T) {
+	// arrange
+	var searchedIn string
+	var searchOptions domain.SearchOptions
+	gitOperations := &mockGitOperations{
+		searchFunc: func(ctx context.Context, worktreePath string, options domain.SearchOptions) (*domain.SearchResult, error) {
+			searchedIn, searchOptions = worktreePath, options
+			return &domain.SearchResult{
+				Matches:   []domain.SearchMatch{{Path: "main.go", LineNumber: 3, Column: 6, Line: "func main() {"}},
+				Truncated: true,
+			}, nil
+		},
+	}
+	useCase := NewSearchSessionUseCase(gitOperations, setupFileSession(t, "test-session", false))
+
+	// act
+	response, err := useCase.Execute(context.Background(), SearchSessionRequest{SessionID: "test-session", Pattern: "func main"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if searchedIn != "/worktrees/test-session" {
+		t.Errorf("searched in %q, want the session worktree", searchedIn)
+	}
+	if searchOptions.ContextLines != DefaultSearchContextLines || searchOptions.MaxResults != DefaultSearchMaxResults {
+		t.Errorf("options = %+v, want the default context and result limit", searchOptions)
+	}
+	if len(response.Matches) != 1 || response.Matches[0].Column != 6 || !response.Truncated {
+		t.Errorf("response = %+v, want the truncated match", response)
+	}
+}
+
+func TestSearchSessionUseCase_Execute_LongLine_IsCut(t *testing.T) {
+	// arrange
+	longLine := strings.Repeat("x", searchLineMaxBytes-1) + "é"
+	gitOperations := &mockGitOperations{
+		searchFunc: func(ctx context.Context, worktreePath string, options domain.SearchOptions) (*domain.SearchResult, error) {
+			return &domain.SearchResult{Matches: []domain.SearchMatch{{Path: "bundle.js", LineNumber: 1, Line: longLine}}}, nil
+		},
+	}
+	useCase := NewSearchSessionUseCase(gitOperations, setupFileSession(t, "test-session", false))
+
+	// act
+	response, err := useCase.Execute(context.Background(), SearchSessionRequest{SessionID: "test-session", Pattern: "x"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if line := response.Matches[0].Line; line != strings.Repeat("x", searchLineMaxBytes-1)+"…" {
+		t.Errorf("line ends with %q, want the cut before the split character", line[len(line)-8:])
+	}
+}
+
+func TestSearchSessionUseCase_Execute_RejectsInvalidInput(t *testing.T) {
+	tooManyContextLines := MaxSearchContextLines + 1
+	testCases := []struct {
+		name    string
+		request SearchSessionRequest
+	}{
+		{"empty pattern", SearchSessionRequest{SessionID: "test-session"}},
+		{"too many context lines", SearchSessionRequest{SessionID: "test-session", Pattern: "x", ContextLines: &tooManyContextLines}},
+		{"pathspec magic", SearchSessionRequest{SessionID: "test-session", Pattern: "x", PathGlobs: []string{":(top)*"}}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// arrange
+			useCase := NewSearchSessionUseCase(&mockGitOperations{}, setupFileSession(t, "test-session", false))
+
+			// act
+			_, err := useCase.Execute(context.Background(), testCase.request)
+
+			// assert
+			if err == nil {
+				t.Error("Execute() expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// ExportFormat selects how a session's commits are stored in an export
+type ExportFormat string
+
+const (
+	// ExportFormatBundle stores the session branch as a git bundle, keeping
+	// commit SHAs and merge commits intact
+	ExportFormatBundle ExportFormat = "bundle"
+	// ExportFormatPatches stores one format-patch file per commit, readable
+	// and appliable without git bundles but re-created with new SHAs
+	ExportFormatPatches ExportFormat = "patches"
+)
+
+const (
+	sessionArchiveVersion = 1
+	sessionManifestName   = "manifest.json"
+	sessionBundleName     = "session.bundle"
+	sessionPatchesDirName = "patches"
+)
+
+// sessionManifest describes an exported session. It is written next to the
+// bundle or patch series and is all import_session needs to recreate the
+// session.
+type sessionManifest struct {
+	Version     int             `json:"version"`
+	Format      ExportFormat    `json:"format"`
+	SessionID   string          `json:"sessionId"`
+	BranchName  string          `json:"branchName"`
+	BaseRef     string          `json:"baseRef"`
+	BaseCommit  string          `json:"baseCommit,omitempty"`
+	HeadCommit  string          `json:"headCommit"`
+	Status      string          `json:"status"`
+	PullRequest *PullRequestDTO `json:"pullRequest,omitempty"`
+	Commits     int             `json:"commits"`
+	// Patches lists the patch files relative to the export directory, in
+	// the order they apply
+	Patches    []string  `json:"patches,omitempty"`
+	ExportedAt time.Time `json:"exportedAt"`
+}
+
+func parseExportFormat(value string) (ExportFormat, error) {
+	switch format := ExportFormat(value); format {
+	case "":
+		return ExportFormatBundle, nil
+	case ExportFormatBundle, ExportFormatPatches:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q (expected bundle or patches)", value)
+	}
+}
+
+func writeSessionManifest(ctx context.Context, sessionArchives domain.SessionArchives, archiveName string, manifest sessionManifest) error {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	if err := sessionArchives.WriteFile(ctx, archiveName, sessionManifestName, append(content, '\n')); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+func readSessionManifest(ctx context.Context, sessionArchives domain.SessionArchives, archiveName string) (*sessionManifest, error) {
+	content, err := sessionArchives.ReadFile(ctx, archiveName, sessionManifestName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest sessionManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if manifest.Version != sessionArchiveVersion {
+		return nil, fmt.Errorf("unsupported export version %d (expected %d)", manifest.Version, sessionArchiveVersion)
+	}
+	if _, err := parseExportFormat(string(manifest.Format)); err != nil || manifest.Format == "" {
+		return nil, fmt.Errorf("manifest has unknown format %q", manifest.Format)
+	}
+	return &manifest, nil
+}
@@ -0,0 +1,62 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// findSession validates a raw session ID and loads the matching session
+func findSession(ctx context.Context, sessionRepository domain.SessionRepository, sessionIDString string) (*domain.Session, error) {
+	sessionID, err := domain.NewSessionID(sessionIDString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	session, err := sessionRepository.FindByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	return session, nil
+}
+
+// teardownSession removes a session's worktree, its branch, its checkpoints
+// and its record. The branch and checkpoints are deleted on a best-effort
+// basis.
+func teardownSession(
+	ctx context.Context,
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+	session *domain.Session,
+	force bool,
+) error {
+	if err := gitOperations.RemoveWorktree(ctx, session.WorktreePath(), force); err != nil {
+		return fmt.Errorf("failed to remove worktree: %w", err)
+	}
+	gitOperations.DeleteBranch(ctx, session.BranchName(), true)
+	gitOperations.DeleteCheckpoints(ctx, session.ID().CheckpointRefPrefix())
+	if err := sessionRepository.Delete(ctx, session.ID()); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// baseRefFor returns the ref a session's work is compared against. Sessions
+// created before base refs were recorded fall back to the configured branch.
+func baseRefFor(session *domain.Session, defaultBaseBranch string) string {
+	if session.BaseRef() != "" {
+		return session.BaseRef()
+	}
+	return defaultBaseBranch
+}
+
+// diffBaseFor returns the revision a session's changes are measured from: the
+// commit pinned at creation when known, otherwise its base ref
+func diffBaseFor(session *domain.Session, defaultBaseBranch string) string {
+	if session.BaseCommit() != "" {
+		return session.BaseCommit()
+	}
+	return baseRefFor(session, defaultBaseBranch)
+}
@@ -0,0 +1,123 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// stackedChildren returns the sessions stacked directly on parent, ordered by
+// session ID
+func stackedChildren(sessions []*domain.Session, parent *domain.Session) []*domain.Session {
+	children := make([]*domain.Session, 0)
+	for _, session := range sessions {
+		if session.IsStackedOn(parent) {
+			children = append(children, session)
+		}
+	}
+	sortSessions(children)
+	return children
+}
+
+// stackParent returns the session that session is stacked on, or nil when it
+// is not stacked or its parent no longer exists
+func stackParent(sessions []*domain.Session, session *domain.Session) *domain.Session {
+	for _, candidate := range sessions {
+		if session.IsStackedOn(candidate) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// findStackedChildren loads the sessions stacked directly on session
+func findStackedChildren(ctx context.Context, sessionRepository domain.SessionRepository, session *domain.Session) ([]*domain.Session, error) {
+	sessions, err := sessionRepository.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return stackedChildren(sessions, session), nil
+}
+
+func sessionIDList(sessions []*domain.Session) string {
+	ids := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		ids = append(ids, session.ID().String())
+	}
+	return strings.Join(ids, ", ")
+}
+
+// renderStackTree draws every stack as a tree rooted at the session at the
+// bottom of the stack, labelled with the ref that session is based on. It
+// returns an empty string when no session is stacked. Sessions whose parent
+// links form a cycle, which only a damaged database holds, are drawn from the
+// first of them, and the repeated session is marked instead of followed.
+func renderStackTree(sessions []*domain.Session, defaultBaseBranch string) string {
+	roots := make([]*domain.Session, 0)
+	for _, session := range sessions {
+		if stackParent(sessions, session) == nil && len(stackedChildren(sessions, session)) > 0 {
+			roots = append(roots, session)
+		}
+	}
+	sortSessions(roots)
+
+	var builder strings.Builder
+	visited := make(map[domain.SessionID]bool)
+	writeRoot := func(root *domain.Session) {
+		visited[root.ID()] = true
+		fmt.Fprintf(&builder, "%s (on %s)\n", stackTreeLabel(root), baseRefFor(root, defaultBaseBranch))
+		writeStackBranches(&builder, sessions, root, "", visited)
+	}
+	for _, root := range roots {
+		writeRoot(root)
+	}
+
+	cycled := make([]*domain.Session, 0)
+	for _, session := range sessions {
+		if !visited[session.ID()] && stackParent(sessions, session) != nil {
+			cycled = append(cycled, session)
+		}
+	}
+	sortSessions(cycled)
+	for _, session := range cycled {
+		if !visited[session.ID()] {
+			writeRoot(session)
+		}
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+func writeStackBranches(builder *strings.Builder, sessions []*domain.Session, parent *domain.Session, indent string, visited map[domain.SessionID]bool) {
+	children := stackedChildren(sessions, parent)
+	for index, child := range children {
+		connector, childIndent := "├── ", "│   "
+		if index == len(children)-1 {
+			connector, childIndent = "└── ", "    "
+		}
+		if visited[child.ID()] {
+			builder.WriteString(indent + connector + stackTreeLabel(child) + " (cycle)\n")
+			continue
+		}
+		visited[child.ID()] = true
+		builder.WriteString(indent + connector + stackTreeLabel(child) + "\n")
+		writeStackBranches(builder, sessions, child, indent+childIndent, visited)
+	}
+}
+
+func sortSessions(sessions []*domain.Session) {
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].ID().String() < sessions[j].ID().String()
+	})
+}
+
+// stackTreeLabel marks sessions that are no longer open, since children of a
+// merged session need restacking
+func stackTreeLabel(session *domain.Session) string {
+	if session.Status() == domain.StatusOpen {
+		return session.ID().String()
+	}
+	return fmt.Sprintf("%s [%s]", session.ID(), session.Status())
+}
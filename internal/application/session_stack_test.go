@@ -0,0 +1,34 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// newTestCycle returns two sessions stacked on each other, which only a
+// hand-edited database holds
+func newTestCycle(t *testing.T) (*domain.Session, *domain.Session) {
+	t.Helper()
+
+	firstID, _ := domain.NewSessionID("first")
+	secondID, _ := domain.NewSessionID("second")
+	first, _ := domain.NewSession(firstID, "/worktrees/first", secondID.BranchName())
+	second, _ := domain.NewSession(secondID, "/worktrees/second", firstID.BranchName())
+	first.SetParentSessionID(secondID)
+	second.SetParentSessionID(firstID)
+	return first, second
+}
+
+func TestRenderStackTree_Cycle_StopsAtRepeatedSession(t *testing.T) {
+	// arrange
+	first, second := newTestCycle(t)
+
+	// act
+	tree := renderStackTree([]*domain.Session{second, first}, "main")
+
+	// assert
+	if want := "first (on " + second.BranchName() + ")\n└── second\n    └── first (cycle)"; tree != want {
+		t.Errorf("renderStackTree() = %q, want %q", tree, want)
+	}
+}
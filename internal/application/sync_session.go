@@ -0,0 +1,131 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type SyncSessionRequest struct {
+	SessionID string
+	// Strategy is rebase or merge; empty means rebase
+	Strategy string
+}
+
+type SyncSessionResponse struct {
+	SessionID          string   `json:"sessionId"`
+	BaseRef            string   `json:"baseRef"`
+	Strategy           string   `json:"strategy"`
+	Synced             bool     `json:"synced"`
+	PreviousBaseCommit string   `json:"previousBaseCommit,omitempty"`
+	BaseCommit         string   `json:"baseCommit"`
+	HeadCommit         string   `json:"headCommit,omitempty"`
+	ConflictedPaths    []string `json:"conflictedPaths,omitempty"`
+}
+
+type SyncSessionUseCase struct {
+	gitOperations     domain.GitOperations
+	sessionRepository domain.SessionRepository
+	baseBranch        string
+}
+
+func NewSyncSessionUseCase(
+	gitOperations domain.GitOperations,
+	sessionRepository domain.SessionRepository,
+	baseBranch string,
+) *SyncSessionUseCase {
+	return &SyncSessionUseCase{
+		gitOperations:     gitOperations,
+		sessionRepository: sessionRepository,
+		baseBranch:        baseBranch,
+	}
+}
+
+// Execute brings the session branch up to date with the current tip of its
+// base ref. On success the session's recorded base commit moves to that tip;
+// on conflict the branch is left untouched and the conflicted paths returned.
+func (syncSessionUseCase *SyncSessionUseCase) Execute(
+	ctx context.Context,
+	request SyncSessionRequest,
+) (*SyncSessionResponse, error) {
+	session, err := findSession(ctx, syncSessionUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	strategy, err := syncSessionUseCase.parseStrategy(request.Strategy)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status() == domain.StatusMerged {
+		return nil, fmt.Errorf("session %s is already merged", session.ID())
+	}
+
+	hasUncommitted, fileCount, err := syncSessionUseCase.gitOperations.HasUncommittedChanges(ctx, session.WorktreePath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to check uncommitted changes: %w", err)
+	}
+	if hasUncommitted {
+		return nil, fmt.Errorf("session has %d uncommitted files; commit or discard them before syncing", fileCount)
+	}
+
+	baseRef := baseRefFor(session, syncSessionUseCase.baseBranch)
+	baseCommit, err := syncSessionUseCase.gitOperations.ResolveCommit(ctx, baseRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve base ref %s: %w", baseRef, err)
+	}
+
+	response := &SyncSessionResponse{
+		SessionID:          session.ID().String(),
+		BaseRef:            baseRef,
+		Strategy:           string(strategy),
+		PreviousBaseCommit: session.BaseCommit(),
+		BaseCommit:         baseCommit,
+	}
+
+	options := domain.MergeOptions{Strategy: strategy}
+	if strategy == domain.MergeStrategyMerge {
+		options.Message = fmt.Sprintf("Merge %s into session '%s'", baseRef, session.ID())
+	}
+	err = syncSessionUseCase.gitOperations.Sync(ctx, session.WorktreePath(), baseCommit, options)
+	var conflictErr *domain.ConflictError
+	if errors.As(err, &conflictErr) {
+		response.BaseCommit = session.BaseCommit()
+		response.ConflictedPaths = conflictErr.Paths
+		return response, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sync session: %w", err)
+	}
+
+	if err := session.SetBaseCommit(baseCommit); err != nil {
+		return nil, err
+	}
+	if err := syncSessionUseCase.sessionRepository.Save(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to save session: %w", err)
+	}
+
+	headCommit, err := syncSessionUseCase.gitOperations.ResolveCommit(ctx, session.BranchName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve session head: %w", err)
+	}
+	response.Synced = true
+	response.HeadCommit = headCommit
+
+	return response, nil
+}
+
+func (syncSessionUseCase *SyncSessionUseCase) parseStrategy(value string) (domain.MergeStrategy, error) {
+	if value == "" {
+		return domain.MergeStrategyRebase, nil
+	}
+	strategy, err := domain.ParseMergeStrategy(value)
+	if err != nil {
+		return "", err
+	}
+	if strategy != domain.MergeStrategyRebase && strategy != domain.MergeStrategyMerge {
+		return "", fmt.Errorf("sync strategy must be rebase or merge, got %q", value)
+	}
+	return strategy, nil
+}
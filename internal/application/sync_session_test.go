@@ -0,0 +1,128 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+const syncTestBaseTip = "89abcdef0123456789abcdef0123456789abcdef"
+
+func setupSyncTest(t *testing.T, gitOperations *mockGitOperations) (*SyncSessionUseCase, *mockSessionRepository) {
+	t.Helper()
+
+	if gitOperations.resolveCommitFunc == nil {
+		gitOperations.resolveCommitFunc = func(ctx context.Context, ref string) (string, error) {
+			return syncTestBaseTip, nil
+		}
+	}
+
+	sessionRepository := newMockSessionRepository()
+	sessionID, _ := domain.NewSessionID("test-session")
+	session, _ := domain.NewSession(sessionID, "/path/test-session", "")
+	session.SetBaseCommit("0123456789abcdef0123456789abcdef01234567")
+	sessionRepository.Save(context.Background(), session)
+
+	return NewSyncSessionUseCase(gitOperations, sessionRepository, "main"), sessionRepository
+}
+
+func TestSyncSessionUseCase_Execute_UpdatesBaseCommit(t *testing.T) {
+	// arrange
+	var receivedUpstream string
+	var receivedOptions domain.MergeOptions
+	gitOperations := &mockGitOperations{
+		syncFunc: func(ctx context.Context, worktreePath string, upstream string, options domain.MergeOptions) error {
+			receivedUpstream = upstream
+			receivedOptions = options
+			return nil
+		},
+	}
+	useCase, sessionRepository := setupSyncTest(t, gitOperations)
+
+	// act
+	response, err := useCase.Execute(context.Background(), SyncSessionRequest{SessionID: "test-session"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if !response.Synced {
+		t.Error("Execute() Synced = false, want true")
+	}
+	if receivedUpstream != syncTestBaseTip {
+		t.Errorf("Sync() upstream = %q, want the resolved base tip", receivedUpstream)
+	}
+	if receivedOptions.Strategy != domain.MergeStrategyRebase {
+		t.Errorf("Sync() strategy = %q, want default rebase", receivedOptions.Strategy)
+	}
+	if saved := sessionRepository.sessions["test-session"]; saved.BaseCommit() != syncTestBaseTip {
+		t.Errorf("saved base commit = %q, want %q", saved.BaseCommit(), syncTestBaseTip)
+	}
+}
+
+func TestSyncSessionUseCase_Execute_ConflictKeepsBaseCommit(t *testing.T) {
+	// arrange
+	gitOperations := &mockGitOperations{
+		syncFunc: func(ctx context.Context, worktreePath string, upstream string, options domain.MergeOptions) error {
+			return &domain.ConflictError{Operation: "merge", Paths: []string{"README.md"}}
+		},
+	}
+	useCase, sessionRepository := setupSyncTest(t, gitOperations)
+
+	// act
+	response, err := useCase.Execute(context.Background(), SyncSessionRequest{SessionID: "test-session", Strategy: "merge"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if response.Synced {
+		t.Error("Execute() Synced = true, want false on conflict")
+	}
+	if len(response.ConflictedPaths) != 1 || response.ConflictedPaths[0] != "README.md" {
+		t.Errorf("ConflictedPaths = %v, want [README.md]", response.ConflictedPaths)
+	}
+	if saved := sessionRepository.sessions["test-session"]; saved.BaseCommit() == syncTestBaseTip {
+		t.Error("base commit should not move after a conflict")
+	}
+}
+
+func TestSyncSessionUseCase_Execute_RejectsUncommittedChanges(t *testing.T) {
+	// arrange
+	syncCalled := false
+	gitOperations := &mockGitOperations{
+		hasUncommittedChangesFunc: func(ctx context.Context, worktreePath string) (bool, int, error) {
+			return true, 1, nil
+		},
+		syncFunc: func(ctx context.Context, worktreePath string, upstream string, options domain.MergeOptions) error {
+			syncCalled = true
+			return nil
+		},
+	}
+	useCase, _ := setupSyncTest(t, gitOperations)
+
+	// act
+	_, err := useCase.Execute(context.Background(), SyncSessionRequest{SessionID: "test-session"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for uncommitted changes")
+	}
+	if syncCalled {
+		t.Error("Sync() should not run with uncommitted changes")
+	}
+}
+
+func TestSyncSessionUseCase_Execute_RejectsMergeOnlyStrategies(t *testing.T) {
+	// arrange
+	useCase, _ := setupSyncTest(t, &mockGitOperations{})
+
+	// act
+	_, err := useCase.Execute(context.Background(), SyncSessionRequest{SessionID: "test-session", Strategy: "squash"})
+
+	// assert
+	if err == nil {
+		t.Error("Execute() expected error for squash strategy")
+	}
+}
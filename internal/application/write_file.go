@@ -0,0 +1,84 @@
+package application
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+type WriteFileRequest struct {
+	SessionID string
+	Path      string
+	Content   string
+	// Encoding is how Content is encoded; empty means FileEncodingUTF8
+	Encoding string
+}
+
+type WriteFileResponse struct {
+	SessionID string `json:"sessionId"`
+	Path      string `json:"path"`
+	SizeBytes int    `json:"sizeBytes"`
+	Created   bool   `json:"created"`
+}
+
+type WriteFileUseCase struct {
+	worktreeFiles     domain.WorktreeFiles
+	sessionRepository domain.SessionRepository
+}
+
+func NewWriteFileUseCase(
+	worktreeFiles domain.WorktreeFiles,
+	sessionRepository domain.SessionRepository,
+) *WriteFileUseCase {
+	return &WriteFileUseCase{
+		worktreeFiles:     worktreeFiles,
+		sessionRepository: sessionRepository,
+	}
+}
+
+func (writeFileUseCase *WriteFileUseCase) Execute(
+	ctx context.Context,
+	request WriteFileRequest,
+) (*WriteFileResponse, error) {
+	session, err := findSession(ctx, writeFileUseCase.sessionRepository, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status() == domain.StatusMerged {
+		return nil, fmt.Errorf("session %s is already merged", session.ID())
+	}
+
+	data, err := decodeFileContent(request.Content, request.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := writeFileUseCase.worktreeFiles.WriteFile(ctx, session.WorktreePath(), request.Path, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return &WriteFileResponse{
+		SessionID: session.ID().String(),
+		Path:      request.Path,
+		SizeBytes: len(data),
+		Created:   created,
+	}, nil
+}
+
+func decodeFileContent(content string, encoding string) ([]byte, error) {
+	switch encoding {
+	case "", FileEncodingUTF8:
+		return []byte(content), nil
+	case FileEncodingBase64:
+		data, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 content: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unknown encoding %q, expected %s or %s", encoding, FileEncodingUTF8, FileEncodingBase64)
+	}
+}
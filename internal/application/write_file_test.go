@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWriteFileUseCase_Execute_Base64Content_WritesDecodedBytes(t *testing.T) {
+	// arrange
+	var written []byte
+	worktreeFiles := &mockWorktreeFiles{
+		writeFileFunc: func(ctx context.Context, worktreePath string, path string, data []byte) (bool, error) {
+			written = data
+			return true, nil
+		},
+	}
+	useCase := NewWriteFileUseCase(worktreeFiles, setupFileSession(t, "test-session", false))
+
+	// act
+	response, err := useCase.Execute(context.Background(), WriteFileRequest{SessionID: "test-session", Path: "logo.png", Content: "iVBORwA=", Encoding: FileEncodingBase64})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if string(written) != "\x89PNG\x00" {
+		t.Errorf("written = %q, want the decoded bytes", written)
+	}
+	if !response.Created || response.SizeBytes != 5 {
+		t.Errorf("response = %+v, want a created 5 byte file", response)
+	}
+}
+
+func TestWriteFileUseCase_Execute_UnknownEncoding_ReturnsError(t *testing.T) {
+	// arrange
+	useCase := NewWriteFileUseCase(&mockWorktreeFiles{}, setupFileSession(t, "test-session", false))
+
+	// act
+	_, err := useCase.Execute(context.Background(), WriteFileRequest{SessionID: "test-session", Path: "a.txt", Content: "a", Encoding: "latin-1"})
+
+	// assert
+	if err == nil {
+		t.Fatal("Execute() expected error for an unknown encoding")
+	}
+}
+
+func TestWriteFileUseCase_Execute_MergedSession_ReturnsError(t *testing.T) {
+	// arrange
+	worktreeFiles := &mockWorktreeFiles{
+		writeFileFunc: func(ctx context.Context, worktreePath string, path string, data []byte) (bool, error) {
+			t.Error("WriteFile should not be called for a merged session")
+			return false, nil
+		},
+	}
+	useCase := NewWriteFileUseCase(worktreeFiles, setupFileSession(t, "test-session", true))
+
+	// act
+	_, err := useCase.Execute(context.Background(), WriteFileRequest{SessionID: "test-session", Path: "a.txt", Content: "a"})
+
+	// assert
+	if err == nil {
+		t.Fatal("Execute() expected error for a merged session")
+	}
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// BranchDivergence compares a session branch with its base. Ahead counts the
+// commits only on the session branch, Behind the commits only on the base.
+type BranchDivergence struct {
+	Ahead        int
+	Behind       int
+	LastCommitAt time.Time
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// CheckpointName identifies a checkpoint within its session
+type CheckpointName struct {
+	value string
+}
+
+var checkpointNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+func NewCheckpointName(rawName string) (CheckpointName, error) {
+	normalized := strings.ToLower(strings.TrimSpace(rawName))
+
+	if len(normalized) < 1 || len(normalized) > 64 {
+		return CheckpointName{}, errors.New("checkpoint name must be 1-64 characters")
+	}
+
+	if !checkpointNamePattern.MatchString(normalized) {
+		return CheckpointName{}, errors.New("checkpoint name must contain only lowercase letters, numbers, and hyphens")
+	}
+
+	return CheckpointName{value: normalized}, nil
+}
+
+func (checkpointName CheckpointName) String() string {
+	return checkpointName.value
+}
+
+// CheckpointRefPrefix is the hidden ref namespace holding the session's
+// checkpoints. Refs outside refs/heads and refs/tags are not shown by
+// branch or tag listings and are not pushed by default.
+func (sessionID SessionID) CheckpointRefPrefix() string {
+	return "refs/orchestragent/" + sessionID.value + "/checkpoints/"
+}
+
+func (sessionID SessionID) CheckpointRef(checkpointName CheckpointName) string {
+	return sessionID.CheckpointRefPrefix() + checkpointName.value
+}
+
+// Checkpoint is a snapshot of a worktree: HeadSHA is the commit the branch
+// pointed at, and the snapshot commit SHA records the working tree including
+// uncommitted and untracked files. IndexSHA, when set, records what was
+// staged.
+type Checkpoint struct {
+	Name      string
+	Ref       string
+	SHA       string
+	HeadSHA   string
+	IndexSHA  string
+	Message   string
+	CreatedAt time.Time
+}
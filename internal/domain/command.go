@@ -0,0 +1,173 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrCommandNotAllowed is returned for commands, arguments or environment
+// variables the command policy does not permit
+var ErrCommandNotAllowed = errors.New("command is not allowed")
+
+// protectedEnv holds the environment variables callers may never set, even
+// when AllowedEnv matches them: each makes an allowed binary load, run or
+// look up other code than the one the policy allowed
+var protectedEnv = []string{"LD_*", "DYLD_*", "GOFLAGS", "MAKEFLAGS", "MFLAGS", "BASH_ENV", "ENV", "PATH"}
+
+// CommandRule allows one binary, named as it is looked up on the server's
+// PATH or by absolute path. With Args set, every argument must match one of
+// the patterns in full; without, any arguments are allowed. ExactArgs
+// instead requires exactly one argument per pattern, matching it in order.
+type CommandRule struct {
+	Command   string
+	Args      []*regexp.Regexp
+	ExactArgs bool
+}
+
+// NewCommandRule compiles argPatterns, which are anchored at both ends so
+// that "test" allows the argument "test" and nothing longer
+func NewCommandRule(command string, argPatterns []string) (CommandRule, error) {
+	if command == "" || strings.HasPrefix(command, "-") {
+		return CommandRule{}, fmt.Errorf("command %q must name a binary", command)
+	}
+	if strings.Contains(command, "/") && !path.IsAbs(command) {
+		return CommandRule{}, fmt.Errorf("command %q must be a name on PATH or an absolute path", command)
+	}
+
+	rule := CommandRule{Command: command}
+	for _, pattern := range argPatterns {
+		compiled, err := regexp.Compile(`^(?:` + pattern + `)$`)
+		if err != nil {
+			return CommandRule{}, fmt.Errorf("invalid argument pattern %q for %s: %w", pattern, command, err)
+		}
+		rule.Args = append(rule.Args, compiled)
+	}
+	return rule, nil
+}
+
+func (rule CommandRule) allowsAll(args []string) bool {
+	if rule.ExactArgs {
+		if len(args) != len(rule.Args) {
+			return false
+		}
+		for index, pattern := range rule.Args {
+			if !pattern.MatchString(args[index]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, argument := range args {
+		if !rule.allowsArgument(argument) {
+			return false
+		}
+	}
+	return true
+}
+
+func (rule CommandRule) allowsArgument(argument string) bool {
+	if rule.Args == nil {
+		return true
+	}
+	for _, pattern := range rule.Args {
+		if pattern.MatchString(argument) {
+			return true
+		}
+	}
+	return false
+}
+
+// CommandPolicy decides which commands may run in a session worktree.
+// AllowedEnv holds glob patterns of the environment variable names callers
+// may set; none are allowed while it is empty. DeniedEnv holds glob patterns,
+// such as "*_TOKEN", of names that are removed from the command's environment
+// and may not be set either. Timeout and MaxOutputBytes are upper limits for
+// every run.
+type CommandPolicy struct {
+	Rules          []CommandRule
+	AllowedEnv     []string
+	DeniedEnv      []string
+	Timeout        time.Duration
+	MaxOutputBytes int
+}
+
+// Enabled reports whether any command is allowed at all
+func (policy *CommandPolicy) Enabled() bool {
+	return policy != nil && len(policy.Rules) > 0
+}
+
+// Check returns ErrCommandNotAllowed unless a rule allows command together
+// with all of args
+func (policy *CommandPolicy) Check(command string, args []string) error {
+	if !policy.Enabled() {
+		return fmt.Errorf("%w: no commands are configured", ErrCommandNotAllowed)
+	}
+
+	var commandAllowed bool
+	for _, rule := range policy.Rules {
+		if rule.Command != command {
+			continue
+		}
+		commandAllowed = true
+		if rule.allowsAll(args) {
+			return nil
+		}
+	}
+	if !commandAllowed {
+		return fmt.Errorf("%w: %s", ErrCommandNotAllowed, command)
+	}
+	return fmt.Errorf("%w: %s with arguments %q", ErrCommandNotAllowed, command, args)
+}
+
+// EnvDenied reports whether the environment variable name matches one of
+// the denied patterns
+func (policy *CommandPolicy) EnvDenied(name string) bool {
+	return EnvNameMatches(policy.DeniedEnv, name)
+}
+
+// EnvSettable reports whether a caller may set the environment variable
+// name: AllowedEnv must match it, and neither DeniedEnv nor the variables
+// that change which code runs, such as LD_PRELOAD, GOFLAGS or PATH
+func (policy *CommandPolicy) EnvSettable(name string) bool {
+	return EnvNameMatches(policy.AllowedEnv, name) && !EnvNameMatches(protectedEnv, name) && !policy.EnvDenied(name)
+}
+
+// EnvNameMatches reports whether name matches one of the glob patterns
+func EnvNameMatches(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// CommandSpec describes one run of an allowed command. Env adds to, or
+// replaces, the server's environment after the DeniedEnv variables have been
+// removed from it. Stdout and stderr are each kept up to MaxOutputBytes.
+type CommandSpec struct {
+	WorktreePath   string
+	Command        string
+	Args           []string
+	Env            map[string]string
+	DeniedEnv      []string
+	Timeout        time.Duration
+	MaxOutputBytes int
+}
+
+// CommandResult is the outcome of a command that was started. ExitCode is -1
+// when the command was killed, for example because it timed out.
+type CommandResult struct {
+	ExitCode        int
+	Stdout          []byte
+	Stderr          []byte
+	StdoutTruncated bool
+	StderrTruncated bool
+	TimedOut        bool
+	Duration        time.Duration
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCommandPolicy_Check_MatchesWholeArguments(t *testing.T) {
+	// arrange
+	goRule, err := NewCommandRule("go", []string{"test|vet", `\./\.\.\.`, "-run=.*"})
+	if err != nil {
+		t.Fatalf("NewCommandRule() error: %v", err)
+	}
+	makeRule, _ := NewCommandRule("make", nil)
+	cargoRule, _ := NewCommandRule("cargo", []string{"test", "--all"})
+	cargoRule.ExactArgs = true
+	policy := &CommandPolicy{Rules: []CommandRule{goRule, makeRule, cargoRule}}
+
+	testCases := []struct {
+		name    string
+		command string
+		args    []string
+		allowed bool
+	}{
+		{"allowed arguments", "go", []string{"test", "./...", "-run=TestX"}, true},
+		{"any arguments without patterns", "make", []string{"SHELL=/bin/sh", "all"}, true},
+		{"argument only matching a prefix", "go", []string{"test", "-exec=/bin/sh"}, false},
+		{"pattern is anchored", "go", []string{"testx"}, false},
+		{"unknown command", "sh", []string{"-c", "id"}, false},
+		{"exact arguments in order", "cargo", []string{"test", "--all"}, true},
+		{"exact arguments reordered", "cargo", []string{"--all", "test"}, false},
+		{"exact arguments repeated", "cargo", []string{"test", "test"}, false},
+		{"exact arguments missing", "cargo", []string{"test"}, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// act
+			err := policy.Check(testCase.command, testCase.args)
+
+			// assert
+			if testCase.allowed && err != nil {
+				t.Errorf("Check() unexpected error: %v", err)
+			}
+			if !testCase.allowed && !errors.Is(err, ErrCommandNotAllowed) {
+				t.Errorf("Check() error = %v, want ErrCommandNotAllowed", err)
+			}
+		})
+	}
+}
+
+func TestCommandPolicy_EmptyPolicy_AllowsNothing(t *testing.T) {
+	// act
+	err := (&CommandPolicy{}).Check("go", []string{"version"})
+
+	// assert
+	if !errors.Is(err, ErrCommandNotAllowed) {
+		t.Errorf("Check() error = %v, want ErrCommandNotAllowed", err)
+	}
+}
+
+func TestCommandPolicy_EnvSettable_OnlyAllowedAndUnprotectedNames(t *testing.T) {
+	// arrange
+	policy := &CommandPolicy{AllowedEnv: []string{"*"}, DeniedEnv: []string{"*_TOKEN"}}
+
+	for name, want := range map[string]bool{"CGO_ENABLED": true, "FORGE_TOKEN": false, "LD_PRELOAD": false, "GOFLAGS": false, "MAKEFLAGS": false, "BASH_ENV": false, "ENV": false, "PATH": false} {
+		// act
+		settable := policy.EnvSettable(name)
+
+		// assert
+		if settable != want {
+			t.Errorf("EnvSettable(%q) = %v, want %v", name, settable, want)
+		}
+	}
+	if (&CommandPolicy{}).EnvSettable("CGO_ENABLED") {
+		t.Error("EnvSettable() = true without AllowedEnv, want every name refused")
+	}
+}
+
+func TestCommandPolicy_EnvDenied_MatchesGlobs(t *testing.T) {
+	// arrange
+	policy := &CommandPolicy{DeniedEnv: []string{"*_TOKEN", "AWS_*"}}
+
+	for name, want := range map[string]bool{"FORGE_TOKEN": true, "AWS_SECRET_ACCESS_KEY": true, "GOPATH": false, "TOKEN_FILE": false} {
+		// act
+		denied := policy.EnvDenied(name)
+
+		// assert
+		if denied != want {
+			t.Errorf("EnvDenied(%q) = %v, want %v", name, denied, want)
+		}
+	}
+}
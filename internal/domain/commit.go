@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// CommitTrailer is a "Key: value" line from the end of a commit message, such
+// as "Signed-off-by" or "Co-authored-by"
+type CommitTrailer struct {
+	Key   string
+	Value string
+}
+
+type Commit struct {
+	SHA            string
+	ParentSHAs     []string
+	AuthorName     string
+	AuthorEmail    string
+	AuthoredAt     time.Time
+	CommitterName  string
+	CommitterEmail string
+	CommittedAt    time.Time
+	Subject        string
+	Body           string
+	Trailers       []CommitTrailer
+	Files          []FileChange
+}
+
+// ErrNothingToCommit is returned when a commit would not change anything
+var ErrNothingToCommit = errors.New("nothing to commit")
+
+// CommitAuthor is the identity recorded as both author and committer. An
+// empty identity leaves git to use the repository's configured user.
+type CommitAuthor struct {
+	Name  string
+	Email string
+}
+
+// CommitOptions describes a commit made on behalf of a session. Paths are
+// relative to the worktree; when empty every change, including untracked
+// files, is committed.
+type CommitOptions struct {
+	Paths    []string
+	Message  string
+	Author   CommitAuthor
+	Trailers []CommitTrailer
+}
@@ -0,0 +1,23 @@
+package domain
+
+// DiffOptions narrows a diff to matching paths and controls how much
+// unchanged context surrounds each hunk
+type DiffOptions struct {
+	// PathGlobs are glob patterns relative to the repository root, for
+	// example "internal/**/*.go". An empty list includes every file.
+	PathGlobs    []string
+	ContextLines int
+}
+
+// FileDiff is the unified diff of a single file
+type FileDiff struct {
+	Path  string
+	Patch string
+}
+
+// RefComparison is the direct difference between the trees of two commits,
+// as unified diffs and as per-file line counts
+type RefComparison struct {
+	Diffs []FileDiff
+	Files []FileChange
+}
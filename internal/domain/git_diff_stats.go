@@ -1,6 +1,78 @@
 package domain
 
+type FileChangeType string
+
+const (
+	FileChangeAdded       FileChangeType = "added"
+	FileChangeModified    FileChangeType = "modified"
+	FileChangeDeleted     FileChangeType = "deleted"
+	FileChangeRenamed     FileChangeType = "renamed"
+	FileChangeCopied      FileChangeType = "copied"
+	FileChangeTypeChanged FileChangeType = "type_changed"
+)
+
+// FileChange describes how a single file differs from the base. OldPath is
+// only set for renames and copies. Binary files carry no line counts.
+// Untracked files have never been added to the index.
+type FileChange struct {
+	Path         string
+	OldPath      string
+	ChangeType   FileChangeType
+	LinesAdded   int
+	LinesRemoved int
+	Binary       bool
+	Untracked    bool
+}
+
+// DiffContribution is the size of one layer of a session's work
+type DiffContribution struct {
+	LinesAdded   int
+	LinesRemoved int
+	FilesChanged int
+}
+
+// DiffBreakdown splits a session's work by where it currently lives: commits
+// on the session branch, the index, the working tree, and files git does not
+// track yet. The layers are measured independently, so a line added in a
+// commit and removed again in the working tree shows up in both.
+type DiffBreakdown struct {
+	Committed DiffContribution
+	Staged    DiffContribution
+	Unstaged  DiffContribution
+	Untracked DiffContribution
+}
+
+// GitDiffStats totals the net difference between the base and the worktree,
+// including untracked files
 type GitDiffStats struct {
 	LinesAdded   int
 	LinesRemoved int
+	FilesChanged int
+	Files        []FileChange
+	Breakdown    DiffBreakdown
+}
+
+// NewGitDiffStats totals the per-file changes into a GitDiffStats
+func NewGitDiffStats(files []FileChange) *GitDiffStats {
+	stats := &GitDiffStats{
+		FilesChanged: len(files),
+		Files:        files,
+	}
+
+	for _, file := range files {
+		stats.LinesAdded += file.LinesAdded
+		stats.LinesRemoved += file.LinesRemoved
+	}
+
+	return stats
+}
+
+// NewDiffContribution totals the per-file changes of a single layer
+func NewDiffContribution(files []FileChange) DiffContribution {
+	stats := NewGitDiffStats(files)
+	return DiffContribution{
+		LinesAdded:   stats.LinesAdded,
+		LinesRemoved: stats.LinesRemoved,
+		FilesChanged: stats.FilesChanged,
+	}
 }
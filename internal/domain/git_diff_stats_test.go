@@ -37,3 +37,26 @@ func TestGitDiffStats_ZeroValues(t *testing.T) {
 		t.Errorf("LinesRemoved = %d, want 0", stats.LinesRemoved)
 	}
 }
+
+func TestNewGitDiffStats_TotalsFileChanges(t *testing.T) {
+	// arrange
+	files := []FileChange{
+		{Path: "main.go", ChangeType: FileChangeModified, LinesAdded: 3, LinesRemoved: 1},
+		{Path: "logo.png", ChangeType: FileChangeAdded, Binary: true},
+		{Path: "new.go", OldPath: "old.go", ChangeType: FileChangeRenamed, LinesAdded: 2, LinesRemoved: 2},
+	}
+
+	// act
+	stats := NewGitDiffStats(files)
+
+	// assert
+	if stats.LinesAdded != 5 {
+		t.Errorf("LinesAdded = %d, want 5", stats.LinesAdded)
+	}
+	if stats.LinesRemoved != 3 {
+		t.Errorf("LinesRemoved = %d, want 3", stats.LinesRemoved)
+	}
+	if stats.FilesChanged != 3 {
+		t.Errorf("FilesChanged = %d, want 3", stats.FilesChanged)
+	}
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+type MergeStrategy string
+
+const (
+	// MergeStrategyMerge always records a merge commit
+	MergeStrategyMerge MergeStrategy = "merge"
+	// MergeStrategySquash collapses the session into a single commit on the base
+	MergeStrategySquash MergeStrategy = "squash"
+	// MergeStrategyRebase replays the session onto the base, then fast-forwards
+	MergeStrategyRebase MergeStrategy = "rebase"
+	// MergeStrategyFastForwardOnly fails unless the base can be fast-forwarded
+	MergeStrategyFastForwardOnly MergeStrategy = "ff-only"
+)
+
+func ParseMergeStrategy(value string) (MergeStrategy, error) {
+	switch strategy := MergeStrategy(value); strategy {
+	case MergeStrategyMerge, MergeStrategySquash, MergeStrategyRebase, MergeStrategyFastForwardOnly:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown merge strategy %q (expected merge, squash, rebase or ff-only)", value)
+	}
+}
+
+// MergeOptions controls how a session branch is integrated into its base.
+// Message is used for merge and squash commits and ignored otherwise.
+type MergeOptions struct {
+	Strategy MergeStrategy
+	Message  string
+}
+
+// ConflictError reports that a git operation stopped on conflicts and was
+// rolled back
+type ConflictError struct {
+	Operation string
+	Paths     []string
+}
+
+func (conflictError *ConflictError) Error() string {
+	if len(conflictError.Paths) == 0 {
+		return fmt.Sprintf("%s stopped on conflicts and was aborted", conflictError.Operation)
+	}
+	return fmt.Sprintf("%s stopped on conflicts in %s and was aborted", conflictError.Operation, strings.Join(conflictError.Paths, ", "))
+}
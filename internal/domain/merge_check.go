@@ -0,0 +1,17 @@
+package domain
+
+// MergeConflict describes one path that would conflict. Hunks holds the
+// conflict-marker blocks git would leave in the file; conflicts without
+// content markers, such as modify/delete, have none.
+type MergeConflict struct {
+	Path    string
+	Type    string
+	Message string
+	Hunks   []string
+}
+
+// MergeCheck is the outcome of a dry-run merge
+type MergeCheck struct {
+	Clean     bool
+	Conflicts []MergeConflict
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMergeStrategy_ValidStrategies(t *testing.T) {
+	for _, value := range []string{"merge", "squash", "rebase", "ff-only"} {
+		// act
+		strategy, err := ParseMergeStrategy(value)
+
+		// assert
+		if err != nil {
+			t.Errorf("ParseMergeStrategy(%q) unexpected error: %v", value, err)
+		}
+		if string(strategy) != value {
+			t.Errorf("ParseMergeStrategy(%q) = %q", value, strategy)
+		}
+	}
+}
+
+func TestParseMergeStrategy_UnknownStrategy(t *testing.T) {
+	// act
+	_, err := ParseMergeStrategy("octopus")
+
+	// assert
+	if err == nil {
+		t.Error("ParseMergeStrategy() expected error for unknown strategy")
+	}
+}
+
+func TestConflictError_ListsPaths(t *testing.T) {
+	// arrange
+	conflictErr := &ConflictError{Operation: "merge", Paths: []string{"a.go", "b.go"}}
+
+	// act
+	message := conflictErr.Error()
+
+	// assert
+	if !strings.Contains(message, "a.go, b.go") {
+		t.Errorf("Error() = %q, want it to list the conflicting paths", message)
+	}
+}
@@ -0,0 +1,29 @@
+package domain
+
+// PatchHunk reports whether one hunk of a patch applies to the worktree on
+// its own. Number counts the hunks of a file from 1. Changes without hunks,
+// such as renames, mode changes and binary files, are reported as a single
+// hunk with an empty Header.
+type PatchHunk struct {
+	Path    string
+	Number  int
+	Header  string
+	Applies bool
+}
+
+// PatchResult describes applying, or only checking, a patch against a
+// worktree. A patch that does not apply as is falls back to a three-way merge
+// with the blobs it was made from; ThreeWay is set when that fallback is
+// needed. A three-way merge that would conflict is not attempted, so the
+// worktree is never left with conflict markers.
+type PatchResult struct {
+	Files           []string
+	Hunks           []PatchHunk
+	Applies         bool
+	ThreeWay        bool
+	Applied         bool
+	ConflictedPaths []string
+	// Errors holds git's reasons when the patch applies neither as is nor
+	// with a three-way merge
+	Errors []string
+}
@@ -3,13 +3,134 @@ package domain
 import "context"
 
 type GitOperations interface {
-	CreateWorktree(ctx context.Context, worktreePath string, branchName string) error
+	CreateWorktree(ctx context.Context, worktreePath string, branchName string, baseRef string) error
 	RemoveWorktree(ctx context.Context, worktreePath string, force bool) error
 	BranchExists(ctx context.Context, branchName string) (bool, error)
+	ResolveCommit(ctx context.Context, ref string) (string, error)
 	HasUncommittedChanges(ctx context.Context, worktreePath string) (bool, int, error)
 	HasUnpushedCommits(ctx context.Context, baseBranch string, sessionBranch string) (int, error)
 	DeleteBranch(ctx context.Context, branchName string, force bool) error
-	GetDiffStats(ctx context.Context, worktreePath string, baseBranch string) (*GitDiffStats, error)
+	GetDiffStats(ctx context.Context, worktreePath string, baseRef string) (*GitDiffStats, error)
+	GetDiff(ctx context.Context, worktreePath string, baseRef string, options DiffOptions) ([]FileDiff, error)
+	GetCommits(ctx context.Context, baseRef string, sessionBranch string) ([]Commit, error)
+	// CompareRefs diffs the tree of toRef against the tree of fromRef without
+	// looking at their merge-base or any worktree
+	CompareRefs(ctx context.Context, fromRef string, toRef string, options DiffOptions) (*RefComparison, error)
+	// GetBranchChanges lists the files changed by the commits on branchName
+	// since it diverged from baseRef, limited to paths matching pathGlobs
+	GetBranchChanges(ctx context.Context, baseRef string, branchName string, pathGlobs []string) ([]FileChange, error)
+	// Merge integrates sessionBranch into baseBranch and returns the new tip
+	// of baseBranch. It returns a *ConflictError when the merge was aborted.
+	Merge(ctx context.Context, baseBranch string, sessionBranch string, sessionWorktreePath string, options MergeOptions) (string, error)
+	// Sync brings the branch checked out in worktreePath up to date with
+	// upstream by rebasing onto it or merging it in; only the merge and rebase
+	// strategies apply. It returns a *ConflictError when the sync was aborted.
+	Sync(ctx context.Context, worktreePath string, upstream string, options MergeOptions) error
+	// RebaseOnto replays the commits of the branch checked out in
+	// worktreePath that are not reachable from upstream onto newBase. It
+	// returns a *ConflictError when the rebase was aborted.
+	RebaseOnto(ctx context.Context, worktreePath string, newBase string, upstream string) error
+	// GetDivergence counts the commits sessionBranch and baseRef do not share
+	// and reads the commit time of the session branch tip
+	GetDivergence(ctx context.Context, baseRef string, sessionBranch string) (*BranchDivergence, error)
+	// CreateCheckpoint snapshots the worktree, including uncommitted and
+	// untracked files, into a commit stored under ref without touching the
+	// worktree, its index or its branch. It fails if ref already exists.
+	CreateCheckpoint(ctx context.Context, worktreePath string, ref string, message string) (*Checkpoint, error)
+	ListCheckpoints(ctx context.Context, refPrefix string) ([]Checkpoint, error)
+	// RestoreCheckpoint resets the worktree and its branch to the state
+	// recorded in the checkpoint at ref, after saving the current state as a
+	// checkpoint under backupRef, which it returns. A restore that fails part
+	// way is rolled back to the backup.
+	RestoreCheckpoint(ctx context.Context, worktreePath string, ref string, backupRef string) (*Checkpoint, error)
+	DeleteCheckpoints(ctx context.Context, refPrefix string) error
+	// Commit stages the requested paths in worktreePath and commits them on
+	// the checked out branch. It returns ErrNothingToCommit when nothing is
+	// staged for those paths.
+	Commit(ctx context.Context, worktreePath string, options CommitOptions) (*Commit, error)
+	// Push publishes branchName to the branch of the same name on remote.
+	// force overwrites the remote branch only if it still matches what was
+	// last fetched or pushed.
+	Push(ctx context.Context, remote string, branchName string, force bool) error
+	// CreateBundle writes the commits on branchName that are not reachable
+	// from excludedCommit into a git bundle at bundlePath. An empty
+	// excludedCommit bundles the branch's whole history.
+	CreateBundle(ctx context.Context, bundlePath string, branchName string, excludedCommit string) error
+	// FormatPatches writes baseRef..branchName as a numbered patch series into
+	// outputDirectory and returns the patch file paths in order
+	FormatPatches(ctx context.Context, outputDirectory string, baseRef string, branchName string) ([]string, error)
+	// FetchBundle copies the objects of ref from the bundle at bundlePath into
+	// the repository and returns the commit ref points to
+	FetchBundle(ctx context.Context, bundlePath string, ref string) (string, error)
+	// ApplyPatches commits a patch series onto the branch checked out in
+	// worktreePath. A series that does not apply is aborted.
+	ApplyPatches(ctx context.Context, worktreePath string, patchPaths []string) error
+	// CherryPick applies commits, in order, onto the branch checked out in
+	// worktreePath. It returns a *ConflictError when the picks were aborted.
+	CherryPick(ctx context.Context, worktreePath string, commits []string) error
+	// CopyWorktreeChanges copies the uncommitted and untracked files of one
+	// worktree into another that has the same commit checked out, leaving
+	// the source untouched
+	CopyWorktreeChanges(ctx context.Context, sourceWorktreePath string, targetWorktreePath string) error
+	// Search looks for lines matching a pattern in the tracked files of
+	// worktreePath as they are on disk, skipping binary files
+	Search(ctx context.Context, worktreePath string, options SearchOptions) (*SearchResult, error)
+	// ApplyPatch applies a unified diff to the files of worktreePath, or with
+	// checkOnly only reports whether and how it would apply. Paths outside the
+	// worktree or inside .git are refused before git sees the patch.
+	ApplyPatch(ctx context.Context, worktreePath string, patch string, checkOnly bool) (*PatchResult, error)
+	// CheckMerge performs a dry-run merge of sessionBranch into baseRef
+	// without touching any worktree, index or ref
+	CheckMerge(ctx context.Context, baseRef string, sessionBranch string) (*MergeCheck, error)
+}
+
+// Forge opens pull requests on the service hosting the shared repository
+type Forge interface {
+	CreatePullRequest(ctx context.Context, request NewPullRequest) (*PullRequest, error)
+}
+
+// WorktreeFiles reads and changes files inside a session worktree. Paths are
+// relative to worktreePath; every method fails with ErrPathOutsideWorktree
+// rather than touch anything outside it, and the changing methods fail with
+// ErrProtectedPath rather than touch .git.
+type WorktreeFiles interface {
+	// ReadFile returns up to maxBytes of the file at path
+	ReadFile(ctx context.Context, worktreePath string, path string, maxBytes int) (*FileContent, error)
+	// WriteFile replaces the file at path with data, creating it and any
+	// missing parent directories. It reports whether the file was created.
+	WriteFile(ctx context.Context, worktreePath string, path string, data []byte) (bool, error)
+	// ListDirectory returns the entries of the directory at path sorted by name
+	ListDirectory(ctx context.Context, worktreePath string, path string) ([]DirectoryEntry, error)
+	// DeleteFile removes the file or symlink at path; directories are refused
+	DeleteFile(ctx context.Context, worktreePath string, path string) error
+	// MoveFile renames sourcePath to destinationPath, creating missing parent
+	// directories. An existing destination is only replaced with overwrite.
+	MoveFile(ctx context.Context, worktreePath string, sourcePath string, destinationPath string, overwrite bool) error
+}
+
+// SessionArchives holds exported sessions in the configured export directory.
+// An archive is a directory named by a path relative to it, or by an
+// absolute path inside it; every method fails with
+// ErrPathOutsideExportDirectory rather than touch anything outside.
+type SessionArchives interface {
+	// Create makes the archive directory, which must not exist yet, and
+	// returns its absolute path for bundles and patches to be written into
+	Create(ctx context.Context, name string) (string, error)
+	// Locate returns the absolute path of an existing archive
+	Locate(ctx context.Context, name string) (string, error)
+	// WriteFile stores data as the file at path inside the archive
+	WriteFile(ctx context.Context, name string, path string, data []byte) error
+	// ReadFile returns the file at path inside the archive
+	ReadFile(ctx context.Context, name string, path string) ([]byte, error)
+	// Remove deletes the archive and everything in it
+	Remove(ctx context.Context, name string) error
+}
+
+// CommandRunner runs a command with a session worktree as its working
+// directory. It returns an error only when the command could not be started
+// or the caller gave up; exit codes and timeouts are part of the result.
+type CommandRunner interface {
+	Run(ctx context.Context, spec CommandSpec) (*CommandResult, error)
 }
 
 type SessionRepository interface {
@@ -0,0 +1,17 @@
+package domain
+
+// PullRequest identifies a pull request opened for a session on a forge
+type PullRequest struct {
+	Number int
+	URL    string
+}
+
+// NewPullRequest describes a pull request to open from Head into Base, both
+// branch names on the forge's repository
+type NewPullRequest struct {
+	Title string
+	Body  string
+	Head  string
+	Base  string
+	Draft bool
+}
@@ -0,0 +1,35 @@
+package domain
+
+// SearchOptions describes a content search in a worktree. Pattern is a POSIX
+// extended regular expression unless Literal is set.
+type SearchOptions struct {
+	Pattern    string
+	Literal    bool
+	IgnoreCase bool
+	// PathGlobs are glob patterns relative to the repository root, for
+	// example "internal/**/*.go". An empty list searches every file.
+	PathGlobs []string
+	// IncludeUntracked also searches files git does not track yet, except
+	// ignored ones
+	IncludeUntracked bool
+	ContextLines     int
+	MaxResults       int
+}
+
+// SearchMatch is a single matching line together with the lines around it.
+// Column is the 1-based byte offset of the first match on the line.
+type SearchMatch struct {
+	Path          string
+	LineNumber    int
+	Column        int
+	Line          string
+	ContextBefore []string
+	ContextAfter  []string
+}
+
+// SearchResult holds the matches of a search in the order git reports them.
+// Truncated is set when more matches were found than requested.
+type SearchResult struct {
+	Matches   []SearchMatch
+	Truncated bool
+}
@@ -2,6 +2,9 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -13,26 +16,41 @@ const (
 	StatusMerged   SessionStatus = "merged"
 )
 
+// commitSHAPattern accepts full SHA-1 and SHA-256 object names
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+
 type Session struct {
 	id           SessionID
 	status       SessionStatus
 	worktreePath string
 	branchName   string
+	baseRef      string
+	baseCommit   string
+	pullRequest  PullRequest
+	parentID     SessionID
 	createdAt    time.Time
 	updatedAt    time.Time
 }
 
-func NewSession(sessionID SessionID, worktreePath string) (*Session, error) {
+// NewSession creates an open session. baseRef is the branch, tag or commit the
+// session was created from; an empty baseRef means the session follows the
+// server's configured base branch.
+func NewSession(sessionID SessionID, worktreePath string, baseRef string) (*Session, error) {
 	if worktreePath == "" {
 		return nil, errors.New("worktree path cannot be empty")
 	}
 
+	if strings.HasPrefix(baseRef, "-") {
+		return nil, errors.New("base ref cannot start with '-'")
+	}
+
 	now := time.Now()
 	return &Session{
 		id:           sessionID,
 		status:       StatusOpen,
 		worktreePath: worktreePath,
 		branchName:   sessionID.BranchName(),
+		baseRef:      baseRef,
 		createdAt:    now,
 		updatedAt:    now,
 	}, nil
@@ -54,6 +72,79 @@ func (session *Session) BranchName() string {
 	return session.branchName
 }
 
+func (session *Session) BaseRef() string {
+	return session.baseRef
+}
+
+// SetBaseRef moves the session onto a different base, e.g. when the session
+// it was stacked on has been merged into its own base
+func (session *Session) SetBaseRef(baseRef string) error {
+	if strings.HasPrefix(baseRef, "-") {
+		return errors.New("base ref cannot start with '-'")
+	}
+
+	session.baseRef = baseRef
+	session.updatedAt = time.Now()
+	return nil
+}
+
+// BaseCommit is the commit SHA the session branch was started from. It is
+// empty for sessions created before base commits were recorded.
+func (session *Session) BaseCommit() string {
+	return session.baseCommit
+}
+
+func (session *Session) SetBaseCommit(commitSHA string) error {
+	if !commitSHAPattern.MatchString(commitSHA) {
+		return fmt.Errorf("invalid base commit SHA: %q", commitSHA)
+	}
+
+	session.baseCommit = commitSHA
+	session.updatedAt = time.Now()
+	return nil
+}
+
+// PullRequest is the pull request opened for the session, or the zero value
+// when the session has not been published
+func (session *Session) PullRequest() PullRequest {
+	return session.pullRequest
+}
+
+func (session *Session) SetPullRequest(pullRequest PullRequest) error {
+	if pullRequest.Number <= 0 || pullRequest.URL == "" {
+		return fmt.Errorf("invalid pull request: number %d, url %q", pullRequest.Number, pullRequest.URL)
+	}
+
+	session.pullRequest = pullRequest
+	session.updatedAt = time.Now()
+	return nil
+}
+
+// ParentSessionID is the session this one was forked from, or the zero
+// SessionID when it was created on its own
+func (session *Session) ParentSessionID() SessionID {
+	return session.parentID
+}
+
+func (session *Session) SetParentSessionID(parentID SessionID) error {
+	if parentID.String() == "" {
+		return errors.New("parent session ID cannot be empty")
+	}
+	if parentID == session.id {
+		return fmt.Errorf("session %s cannot be its own parent", session.id)
+	}
+
+	session.parentID = parentID
+	session.updatedAt = time.Now()
+	return nil
+}
+
+// IsStackedOn reports whether the session builds on parent's branch, as
+// opposed to having been forked from parent onto parent's own base
+func (session *Session) IsStackedOn(parent *Session) bool {
+	return session.parentID == parent.id && session.baseRef == parent.branchName
+}
+
 func (session *Session) MarkReviewed() {
 	session.status = StatusReviewed
 	session.updatedAt = time.Now()
@@ -0,0 +1,8 @@
+package domain
+
+import "errors"
+
+// ErrPathOutsideExportDirectory is returned for export names that climb out
+// of the export directory with "..", are absolute paths elsewhere, or
+// resolve through a symlink to outside it
+var ErrPathOutsideExportDirectory = errors.New("path is outside the export directory")
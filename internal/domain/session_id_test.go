@@ -63,3 +63,30 @@ func TestSessionID_BranchName(t *testing.T) {
 		t.Errorf("BranchName() = %q, want %q", result, expectedBranchName)
 	}
 }
+
+func TestNewCheckpointName(t *testing.T) {
+	// arrange
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{"before-refactor", false},
+		{" Step-2 ", false},
+		{"1", false},
+		{"", true},
+		{"-draft", true},
+		{"draft-", true},
+		{"a/b", true},
+		{"a..b", true},
+	}
+
+	for _, testCase := range tests {
+		// act
+		_, err := NewCheckpointName(testCase.input)
+
+		// assert
+		if (err != nil) != testCase.wantErr {
+			t.Errorf("NewCheckpointName(%q) error = %v, wantErr %v", testCase.input, err, testCase.wantErr)
+		}
+	}
+}
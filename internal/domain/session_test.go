@@ -10,7 +10,7 @@ func TestNewSession(t *testing.T) {
 	worktreePath := "/path/to/worktree"
 
 	// act
-	session, err := NewSession(sessionID, worktreePath)
+	session, err := NewSession(sessionID, worktreePath, "")
 
 	// assert
 	if err != nil {
@@ -39,7 +39,7 @@ func TestNewSession_InvalidWorktreePath(t *testing.T) {
 	sessionID, _ := NewSessionID("test-session")
 
 	// act
-	_, err := NewSession(sessionID, "")
+	_, err := NewSession(sessionID, "", "")
 
 	// assert
 	if err == nil {
@@ -47,10 +47,74 @@ func TestNewSession_InvalidWorktreePath(t *testing.T) {
 	}
 }
 
+func TestNewSession_WithBaseRef(t *testing.T) {
+	// arrange
+	sessionID, _ := NewSessionID("test-session")
+
+	// act
+	session, err := NewSession(sessionID, "/path", "release/1.4")
+
+	// assert
+	if err != nil {
+		t.Fatalf("NewSession() unexpected error: %v", err)
+	}
+	if session.BaseRef() != "release/1.4" {
+		t.Errorf("BaseRef() = %q, want %q", session.BaseRef(), "release/1.4")
+	}
+}
+
+func TestNewSession_BaseRefLooksLikeOption(t *testing.T) {
+	// arrange
+	sessionID, _ := NewSessionID("test-session")
+
+	// act
+	_, err := NewSession(sessionID, "/path", "--upload-pack=evil")
+
+	// assert
+	if err == nil {
+		t.Error("NewSession() with option-like base ref expected error, got nil")
+	}
+}
+
+func TestSession_SetBaseCommit(t *testing.T) {
+	// arrange
+	sessionID, _ := NewSessionID("test-session")
+	session, _ := NewSession(sessionID, "/path", "main")
+	commitSHA := "0123456789abcdef0123456789abcdef01234567"
+
+	// act
+	err := session.SetBaseCommit(commitSHA)
+
+	// assert
+	if err != nil {
+		t.Fatalf("SetBaseCommit() unexpected error: %v", err)
+	}
+	if session.BaseCommit() != commitSHA {
+		t.Errorf("BaseCommit() = %q, want %q", session.BaseCommit(), commitSHA)
+	}
+}
+
+func TestSession_SetBaseCommit_InvalidSHA(t *testing.T) {
+	// arrange
+	sessionID, _ := NewSessionID("test-session")
+	session, _ := NewSession(sessionID, "/path", "main")
+
+	// act
+	err := session.SetBaseCommit("main")
+
+	// assert
+	if err == nil {
+		t.Error("SetBaseCommit() with a branch name expected error, got nil")
+	}
+	if session.BaseCommit() != "" {
+		t.Errorf("BaseCommit() = %q, want empty after rejected update", session.BaseCommit())
+	}
+}
+
 func TestSession_MarkReviewed(t *testing.T) {
 	// arrange
 	sessionID, _ := NewSessionID("test-session")
-	session, _ := NewSession(sessionID, "/path")
+	session, _ := NewSession(sessionID, "/path", "")
 
 	// act
 	session.MarkReviewed()
@@ -64,7 +128,7 @@ func TestSession_MarkReviewed(t *testing.T) {
 func TestSession_MarkMerged(t *testing.T) {
 	// arrange
 	sessionID, _ := NewSessionID("test-session")
-	session, _ := NewSession(sessionID, "/path")
+	session, _ := NewSession(sessionID, "/path", "")
 
 	// act
 	session.MarkMerged()
@@ -74,3 +138,74 @@ func TestSession_MarkMerged(t *testing.T) {
 		t.Errorf("Status after MarkMerged() = %q, want %q", session.Status(), StatusMerged)
 	}
 }
+
+func TestSession_SetPullRequest(t *testing.T) {
+	// arrange
+	sessionID, _ := NewSessionID("test-session")
+	session, _ := NewSession(sessionID, "/path/to/worktree", "")
+
+	// act
+	invalidErr := session.SetPullRequest(PullRequest{Number: 0, URL: "https://example.com/pull/0"})
+	validErr := session.SetPullRequest(PullRequest{Number: 7, URL: "https://example.com/pull/7"})
+
+	// assert
+	if invalidErr == nil {
+		t.Error("SetPullRequest() with number 0 expected error, got nil")
+	}
+	if validErr != nil {
+		t.Fatalf("SetPullRequest() unexpected error: %v", validErr)
+	}
+	if pullRequest := session.PullRequest(); pullRequest.Number != 7 || pullRequest.URL != "https://example.com/pull/7" {
+		t.Errorf("PullRequest() = %+v, want number 7", pullRequest)
+	}
+}
+
+func TestSession_SetParentSessionID(t *testing.T) {
+	// arrange
+	sessionID, _ := NewSessionID("test-session")
+	parentID, _ := NewSessionID("parent-session")
+	session, _ := NewSession(sessionID, "/path/to/worktree", "")
+
+	// act
+	selfErr := session.SetParentSessionID(sessionID)
+	emptyErr := session.SetParentSessionID(SessionID{})
+	validErr := session.SetParentSessionID(parentID)
+
+	// assert
+	if selfErr == nil {
+		t.Error("SetParentSessionID() with own ID expected error, got nil")
+	}
+	if emptyErr == nil {
+		t.Error("SetParentSessionID() with empty ID expected error, got nil")
+	}
+	if validErr != nil {
+		t.Fatalf("SetParentSessionID() unexpected error: %v", validErr)
+	}
+	if got := session.ParentSessionID(); got != parentID {
+		t.Errorf("ParentSessionID() = %s, want %s", got, parentID)
+	}
+}
+
+func TestSession_IsStackedOn(t *testing.T) {
+	// arrange
+	parentID, _ := NewSessionID("parent-session")
+	parent, _ := NewSession(parentID, "/path/to/parent", "")
+	stackedID, _ := NewSessionID("stacked-session")
+	stacked, _ := NewSession(stackedID, "/path/to/stacked", parent.BranchName())
+	stacked.SetParentSessionID(parentID)
+	forkedID, _ := NewSessionID("forked-session")
+	forked, _ := NewSession(forkedID, "/path/to/forked", "main")
+	forked.SetParentSessionID(parentID)
+
+	// act
+	stackedResult := stacked.IsStackedOn(parent)
+	forkedResult := forked.IsStackedOn(parent)
+
+	// assert
+	if !stackedResult {
+		t.Error("IsStackedOn() = false for a session based on the parent branch, want true")
+	}
+	if forkedResult {
+		t.Error("IsStackedOn() = true for a fork based on main, want false")
+	}
+}
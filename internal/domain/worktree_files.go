@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrPathOutsideWorktree is returned for paths that are absolute, climb
+	// out with "..", or resolve through a symlink to outside the worktree
+	ErrPathOutsideWorktree = errors.New("path is outside the session worktree")
+	// ErrProtectedPath is returned when a change would touch the worktree's
+	// .git entry
+	ErrProtectedPath = errors.New("path is protected")
+)
+
+type DirectoryEntryType string
+
+const (
+	DirectoryEntryFile      DirectoryEntryType = "file"
+	DirectoryEntryDirectory DirectoryEntryType = "directory"
+	DirectoryEntrySymlink   DirectoryEntryType = "symlink"
+	DirectoryEntryOther     DirectoryEntryType = "other"
+)
+
+// DirectoryEntry describes one entry of a worktree directory. Path is
+// relative to the worktree root and uses forward slashes.
+type DirectoryEntry struct {
+	Name       string
+	Path       string
+	Type       DirectoryEntryType
+	SizeBytes  int64
+	ModifiedAt time.Time
+}
+
+// FileContent holds the first bytes of a file together with its full size
+type FileContent struct {
+	Data      []byte
+	SizeBytes int64
+}
+
+// Truncated reports whether Data stops before the end of the file
+func (content *FileContent) Truncated() bool {
+	return int64(len(content.Data)) < content.SizeBytes
+}
@@ -0,0 +1,493 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DefaultBaseBranch  = "main"
+	DefaultWorktreeDir = ".worktrees"
+	DefaultExportDir   = ".exports"
+	DefaultDatabaseDir = "."
+	// DefaultStaleBehindCommits is how many commits a session may fall behind
+	// its base before it is reported as stale
+	DefaultStaleBehindCommits = 50
+	DefaultRemote             = "origin"
+	DefaultForgeURL           = "https://api.github.com"
+	DefaultExecTimeoutSeconds = 600
+	DefaultExecMaxOutputBytes = 1 << 20
+
+	// SandboxModeAuto isolates commands where Linux namespaces are available
+	// and runs them unisolated elsewhere; SandboxModeNamespaces refuses to
+	// start without namespaces and SandboxModeOff never isolates
+	SandboxModeAuto       = "auto"
+	SandboxModeNamespaces = "namespaces"
+	SandboxModeOff        = "off"
+
+	applicationDirectoryName = "orchestragent-mcp"
+	userConfigFileName       = "config.yaml"
+)
+
+const (
+	EnvConfigPath  = "ORCHESTRAGENT_CONFIG"
+	EnvRepoRoot    = "ORCHESTRAGENT_REPO"
+	EnvBaseBranch  = "ORCHESTRAGENT_BASE_BRANCH"
+	EnvWorktreeDir = "ORCHESTRAGENT_WORKTREE_DIR"
+	EnvExportDir   = "ORCHESTRAGENT_EXPORT_DIR"
+	EnvDatabaseDir = "ORCHESTRAGENT_DB"
+	EnvTestCommand = "ORCHESTRAGENT_TEST_COMMAND"
+
+	EnvStaleBehindCommits = "ORCHESTRAGENT_STALE_BEHIND_COMMITS"
+	EnvCommitAuthorName   = "ORCHESTRAGENT_COMMIT_AUTHOR_NAME"
+	EnvCommitAuthorEmail  = "ORCHESTRAGENT_COMMIT_AUTHOR_EMAIL"
+	EnvRemote             = "ORCHESTRAGENT_REMOTE"
+	EnvForgeURL           = "ORCHESTRAGENT_FORGE_URL"
+	EnvForgeRepository    = "ORCHESTRAGENT_FORGE_REPOSITORY"
+	EnvForgeToken         = "ORCHESTRAGENT_FORGE_TOKEN"
+)
+
+// DefaultSandboxReadOnlyPaths are the toolchain locations sandboxed commands
+// can read by default
+var DefaultSandboxReadOnlyPaths = []string{"/usr", "/bin", "/sbin", "/lib", "/lib32", "/lib64", "/etc", "/opt"}
+
+var repositoryConfigFileNames = []string{".orchestragent-mcp.yaml", ".orchestragent-mcp.yml"}
+
+type Config struct {
+	RepoRoot    string `yaml:"repoRoot"`
+	BaseBranch  string `yaml:"baseBranch"`
+	TestCommand string `yaml:"testCommand"`
+	WorktreeDir string `yaml:"worktreeDir"`
+	// ExportDir is the only directory export_session writes to and
+	// import_session reads from
+	ExportDir   string `yaml:"exportDir"`
+	DatabaseDir string `yaml:"databaseDir"`
+	// StaleBehindCommits marks sessions more than this many commits behind
+	// their base as stale
+	StaleBehindCommits int `yaml:"staleBehindCommits"`
+	// CommitAuthorName and CommitAuthorEmail identify commits made through
+	// commit_session; when empty git's configured user is used
+	CommitAuthorName  string `yaml:"commitAuthorName"`
+	CommitAuthorEmail string `yaml:"commitAuthorEmail"`
+	// Remote is the git remote publish_session pushes session branches to
+	Remote string `yaml:"remote"`
+	// ForgeURL is the REST API root of the GitHub-compatible forge that pull
+	// requests are opened on, and ForgeRepository its "owner/name". Pull
+	// requests are disabled while ForgeRepository is empty.
+	ForgeURL        string `yaml:"forgeUrl"`
+	ForgeRepository string `yaml:"forgeRepository"`
+	ForgeToken      string `yaml:"forgeToken"`
+	// Exec is the policy exec_in_session enforces
+	Exec ExecConfig `yaml:"exec"`
+
+	// LoadedFiles lists the configuration files that contributed to this
+	// configuration, in the order they were applied
+	LoadedFiles []string `yaml:"-"`
+}
+
+// ExecConfig limits the commands exec_in_session may run. No command is
+// allowed while Allow is empty.
+type ExecConfig struct {
+	Allow []ExecRule `yaml:"allow"`
+	// AllowedEnv holds glob patterns of the environment variable names
+	// callers may set; none while it is empty
+	AllowedEnv []string `yaml:"allowedEnv"`
+	// DeniedEnv holds glob patterns of environment variable names that are
+	// removed from the command's environment and may not be set by callers
+	DeniedEnv []string `yaml:"deniedEnv"`
+	// TimeoutSeconds and MaxOutputBytes are upper limits; callers may ask for
+	// a shorter timeout. MaxOutputBytes applies to stdout and stderr each.
+	TimeoutSeconds int `yaml:"timeoutSeconds"`
+	MaxOutputBytes int `yaml:"maxOutputBytes"`
+	// Sandbox decides what of the filesystem commands can see
+	Sandbox SandboxConfig `yaml:"sandbox"`
+}
+
+// SandboxConfig describes the isolation commands run in. Inside the sandbox
+// only the session worktree is writable; ReadOnlyPaths and WritablePaths are
+// the only other host paths visible, everything else is hidden.
+type SandboxConfig struct {
+	Mode          string   `yaml:"mode"`
+	ReadOnlyPaths []string `yaml:"readOnlyPaths"`
+	WritablePaths []string `yaml:"writablePaths"`
+}
+
+// ExecRule allows one binary, by name on PATH or by absolute path. When Args
+// is set, every argument must fully match one of its regular expressions.
+type ExecRule struct {
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+}
+
+// Overrides holds values supplied on the command line. Empty fields are
+// treated as "not provided" and leave the underlying value untouched.
+type Overrides struct {
+	ConfigPath  string
+	RepoRoot    string
+	BaseBranch  string
+	WorktreeDir string
+	ExportDir   string
+	DatabaseDir string
+	TestCommand string
+}
+
+// Load builds the server configuration by layering, from lowest to highest
+// precedence: built-in defaults, the user config file in the XDG config
+// directory, the repository config file, environment variables and finally
+// command line overrides. When an explicit config path is given (flag or
+// ORCHESTRAGENT_CONFIG) only that file is read.
+func Load(overrides Overrides) (*Config, error) {
+	config := defaultConfig()
+
+	discoveryRoot, err := resolveDiscoveryRoot(overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	configFiles, err := discoverConfigFiles(overrides, discoveryRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, configFile := range configFiles {
+		if err := config.mergeFile(configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := config.applyEnvironment(); err != nil {
+		return nil, err
+	}
+	config.applyOverrides(overrides)
+
+	if config.RepoRoot == "" {
+		config.RepoRoot = discoveryRoot
+	}
+
+	if err := config.normalizePaths(); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		BaseBranch:  DefaultBaseBranch,
+		WorktreeDir: DefaultWorktreeDir,
+		ExportDir:   DefaultExportDir,
+		DatabaseDir: DefaultDatabaseDir,
+
+		StaleBehindCommits: DefaultStaleBehindCommits,
+		Remote:             DefaultRemote,
+		ForgeURL:           DefaultForgeURL,
+		Exec: ExecConfig{
+			TimeoutSeconds: DefaultExecTimeoutSeconds,
+			MaxOutputBytes: DefaultExecMaxOutputBytes,
+			Sandbox: SandboxConfig{
+				Mode:          SandboxModeAuto,
+				ReadOnlyPaths: append([]string{}, DefaultSandboxReadOnlyPaths...),
+			},
+		},
+	}
+}
+
+func resolveDiscoveryRoot(overrides Overrides) (string, error) {
+	if overrides.RepoRoot != "" {
+		return overrides.RepoRoot, nil
+	}
+
+	if repoRoot, ok := os.LookupEnv(EnvRepoRoot); ok && repoRoot != "" {
+		return repoRoot, nil
+	}
+
+	currentWorkingDirectory, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve current working directory: %w", err)
+	}
+
+	return currentWorkingDirectory, nil
+}
+
+func discoverConfigFiles(overrides Overrides, discoveryRoot string) ([]string, error) {
+	explicitPath := overrides.ConfigPath
+	if explicitPath == "" {
+		explicitPath = os.Getenv(EnvConfigPath)
+	}
+
+	if explicitPath != "" {
+		if _, err := os.Stat(explicitPath); err != nil {
+			return nil, fmt.Errorf("config file %s is not readable: %w", explicitPath, err)
+		}
+		return []string{explicitPath}, nil
+	}
+
+	configFiles := make([]string, 0, 2)
+
+	if userConfigDirectory, err := os.UserConfigDir(); err == nil {
+		userConfigPath := filepath.Join(userConfigDirectory, applicationDirectoryName, userConfigFileName)
+		if fileExists(userConfigPath) {
+			configFiles = append(configFiles, userConfigPath)
+		}
+	}
+
+	for _, fileName := range repositoryConfigFileNames {
+		repositoryConfigPath := filepath.Join(discoveryRoot, fileName)
+		if fileExists(repositoryConfigPath) {
+			configFiles = append(configFiles, repositoryConfigPath)
+			break
+		}
+	}
+
+	return configFiles, nil
+}
+
+func (config *Config) mergeFile(configPath string) error {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", configPath, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", configPath, err)
+	}
+
+	config.LoadedFiles = append(config.LoadedFiles, configPath)
+	return nil
+}
+
+func (config *Config) applyEnvironment() error {
+	applyEnvironmentValue(EnvRepoRoot, &config.RepoRoot)
+	applyEnvironmentValue(EnvBaseBranch, &config.BaseBranch)
+	applyEnvironmentValue(EnvWorktreeDir, &config.WorktreeDir)
+	applyEnvironmentValue(EnvExportDir, &config.ExportDir)
+	applyEnvironmentValue(EnvDatabaseDir, &config.DatabaseDir)
+	applyEnvironmentValue(EnvTestCommand, &config.TestCommand)
+	applyEnvironmentValue(EnvCommitAuthorName, &config.CommitAuthorName)
+	applyEnvironmentValue(EnvCommitAuthorEmail, &config.CommitAuthorEmail)
+	applyEnvironmentValue(EnvRemote, &config.Remote)
+	applyEnvironmentValue(EnvForgeURL, &config.ForgeURL)
+	applyEnvironmentValue(EnvForgeRepository, &config.ForgeRepository)
+	applyEnvironmentValue(EnvForgeToken, &config.ForgeToken)
+
+	if value, ok := os.LookupEnv(EnvStaleBehindCommits); ok && value != "" {
+		staleBehindCommits, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s must be a whole number, got %q", EnvStaleBehindCommits, value)
+		}
+		config.StaleBehindCommits = staleBehindCommits
+	}
+
+	return nil
+}
+
+func applyEnvironmentValue(name string, target *string) {
+	if value, ok := os.LookupEnv(name); ok && value != "" {
+		*target = value
+	}
+}
+
+func (config *Config) applyOverrides(overrides Overrides) {
+	applyOverrideValue(overrides.RepoRoot, &config.RepoRoot)
+	applyOverrideValue(overrides.BaseBranch, &config.BaseBranch)
+	applyOverrideValue(overrides.WorktreeDir, &config.WorktreeDir)
+	applyOverrideValue(overrides.ExportDir, &config.ExportDir)
+	applyOverrideValue(overrides.DatabaseDir, &config.DatabaseDir)
+	applyOverrideValue(overrides.TestCommand, &config.TestCommand)
+}
+
+func applyOverrideValue(value string, target *string) {
+	if value != "" {
+		*target = value
+	}
+}
+
+// normalizePaths makes the repository root absolute and resolves relative
+// worktree and export directories against it
+func (config *Config) normalizePaths() error {
+	absoluteRepoRoot, err := filepath.Abs(config.RepoRoot)
+	if err != nil {
+		return fmt.Errorf("failed to resolve absolute path for repoRoot %s: %w", config.RepoRoot, err)
+	}
+	config.RepoRoot = absoluteRepoRoot
+
+	if config.WorktreeDir != "" && !filepath.IsAbs(config.WorktreeDir) {
+		config.WorktreeDir = filepath.Join(config.RepoRoot, config.WorktreeDir)
+	}
+	if config.ExportDir != "" && !filepath.IsAbs(config.ExportDir) {
+		config.ExportDir = filepath.Join(config.RepoRoot, config.ExportDir)
+	}
+
+	return nil
+}
+
+// Validate checks the configuration and reports every problem found, not
+// just the first one
+func (config *Config) Validate() error {
+	var problems []error
+
+	if err := validateRepoRoot(config.RepoRoot); err != nil {
+		problems = append(problems, err)
+	}
+
+	if err := validateBaseBranch(config.BaseBranch); err != nil {
+		problems = append(problems, err)
+	}
+
+	if strings.TrimSpace(config.WorktreeDir) == "" {
+		problems = append(problems, errors.New("worktreeDir must not be empty"))
+	} else if filepath.Clean(config.WorktreeDir) == filepath.Clean(config.RepoRoot) {
+		problems = append(problems, errors.New("worktreeDir must not be the repository root itself"))
+	}
+
+	if strings.TrimSpace(config.ExportDir) == "" {
+		problems = append(problems, errors.New("exportDir must not be empty"))
+	} else if filepath.Clean(config.ExportDir) == filepath.Clean(config.RepoRoot) {
+		problems = append(problems, errors.New("exportDir must not be the repository root itself"))
+	}
+
+	if config.StaleBehindCommits < 1 {
+		problems = append(problems, fmt.Errorf("staleBehindCommits must be at least 1, got %d", config.StaleBehindCommits))
+	}
+
+	if strings.ContainsAny(config.CommitAuthorName, "<>\n") {
+		problems = append(problems, fmt.Errorf("commitAuthorName %q must not contain '<', '>' or line breaks", config.CommitAuthorName))
+	}
+	if strings.ContainsAny(config.CommitAuthorEmail, "<>\n") {
+		problems = append(problems, fmt.Errorf("commitAuthorEmail %q must not contain '<', '>' or line breaks", config.CommitAuthorEmail))
+	}
+
+	if config.Remote == "" || strings.HasPrefix(config.Remote, "-") {
+		problems = append(problems, fmt.Errorf("remote %q must be a remote name", config.Remote))
+	}
+	if config.ForgeRepository != "" && strings.Count(config.ForgeRepository, "/") != 1 {
+		problems = append(problems, fmt.Errorf("forgeRepository %q must have the form owner/name", config.ForgeRepository))
+	}
+
+	if fields := strings.Fields(config.TestCommand); len(fields) > 0 {
+		if err := validateCommandName("testCommand", fields[0]); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	problems = append(problems, validateExec(config.Exec)...)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(problems...))
+	}
+
+	return nil
+}
+
+func validateRepoRoot(repoRoot string) error {
+	if strings.TrimSpace(repoRoot) == "" {
+		return errors.New("repoRoot must not be empty")
+	}
+
+	info, err := os.Stat(repoRoot)
+	if err != nil {
+		return fmt.Errorf("repoRoot %s does not exist", repoRoot)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("repoRoot %s is not a directory", repoRoot)
+	}
+
+	if _, err := os.Stat(filepath.Join(repoRoot, ".git")); err != nil {
+		return fmt.Errorf("repoRoot %s is not a git repository (no .git found)", repoRoot)
+	}
+
+	return nil
+}
+
+func validateBaseBranch(baseBranch string) error {
+	if baseBranch == "" {
+		return errors.New("baseBranch must not be empty")
+	}
+	if strings.HasPrefix(baseBranch, "-") {
+		return fmt.Errorf("baseBranch %q must not start with '-'", baseBranch)
+	}
+	if strings.ContainsAny(baseBranch, " \t\n~^:?*[\\") || strings.Contains(baseBranch, "..") {
+		return fmt.Errorf("baseBranch %q is not a valid branch name", baseBranch)
+	}
+	return nil
+}
+
+func validateExec(execConfig ExecConfig) []error {
+	var problems []error
+
+	for _, rule := range execConfig.Allow {
+		if err := validateCommandName("exec.allow command", rule.Command); err != nil {
+			problems = append(problems, err)
+		}
+		for _, pattern := range rule.Args {
+			if _, err := regexp.Compile(pattern); err != nil {
+				problems = append(problems, fmt.Errorf("exec.allow argument pattern %q for %s is invalid: %w", pattern, rule.Command, err))
+			}
+		}
+	}
+	for _, pattern := range execConfig.AllowedEnv {
+		if _, err := path.Match(pattern, ""); err != nil {
+			problems = append(problems, fmt.Errorf("exec.allowedEnv pattern %q is invalid", pattern))
+		}
+	}
+	for _, pattern := range execConfig.DeniedEnv {
+		if _, err := path.Match(pattern, ""); err != nil {
+			problems = append(problems, fmt.Errorf("exec.deniedEnv pattern %q is invalid", pattern))
+		}
+	}
+
+	if execConfig.TimeoutSeconds < 1 {
+		problems = append(problems, fmt.Errorf("exec.timeoutSeconds must be at least 1, got %d", execConfig.TimeoutSeconds))
+	}
+	if execConfig.MaxOutputBytes < 1 {
+		problems = append(problems, fmt.Errorf("exec.maxOutputBytes must be at least 1, got %d", execConfig.MaxOutputBytes))
+	}
+
+	switch execConfig.Sandbox.Mode {
+	case SandboxModeAuto, SandboxModeNamespaces, SandboxModeOff:
+	default:
+		problems = append(problems, fmt.Errorf("exec.sandbox.mode %q must be %s, %s or %s", execConfig.Sandbox.Mode, SandboxModeAuto, SandboxModeNamespaces, SandboxModeOff))
+	}
+	sandboxPaths := append(append([]string{}, execConfig.Sandbox.ReadOnlyPaths...), execConfig.Sandbox.WritablePaths...)
+	for _, sandboxPath := range sandboxPaths {
+		if !filepath.IsAbs(sandboxPath) {
+			problems = append(problems, fmt.Errorf("exec.sandbox path %q must be absolute", sandboxPath))
+		} else if filepath.Clean(sandboxPath) == string(filepath.Separator) {
+			problems = append(problems, errors.New("exec.sandbox paths must not include the filesystem root"))
+		}
+	}
+
+	return problems
+}
+
+func validateCommandName(key string, command string) error {
+	if command == "" || strings.HasPrefix(command, "-") {
+		return fmt.Errorf("%s %q must name a binary", key, command)
+	}
+	if strings.Contains(command, "/") && !filepath.IsAbs(command) {
+		return fmt.Errorf("%s %q must be a name on PATH or an absolute path", key, command)
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,356 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupIsolatedEnvironment(t *testing.T) string {
+	t.Helper()
+
+	userConfigHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", userConfigHome)
+
+	for _, name := range []string{EnvConfigPath, EnvRepoRoot, EnvBaseBranch, EnvWorktreeDir, EnvExportDir, EnvDatabaseDir, EnvTestCommand, EnvStaleBehindCommits, EnvCommitAuthorName, EnvCommitAuthorEmail, EnvRemote, EnvForgeURL, EnvForgeRepository, EnvForgeToken} {
+		t.Setenv(name, "")
+	}
+
+	return userConfigHome
+}
+
+func setupFakeRepository(t *testing.T) string {
+	t.Helper()
+
+	repositoryRoot := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repositoryRoot, ".git"), 0o755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+
+	return repositoryRoot
+}
+
+func writeConfigFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create config directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+}
+
+func TestLoad_WithoutConfigFiles_UsesDefaults(t *testing.T) {
+	// arrange
+	setupIsolatedEnvironment(t)
+	repositoryRoot := setupFakeRepository(t)
+
+	// act
+	config, err := Load(Overrides{RepoRoot: repositoryRoot})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if config.RepoRoot != repositoryRoot {
+		t.Errorf("RepoRoot = %q, want %q", config.RepoRoot, repositoryRoot)
+	}
+	if config.BaseBranch != DefaultBaseBranch {
+		t.Errorf("BaseBranch = %q, want %q", config.BaseBranch, DefaultBaseBranch)
+	}
+	expectedWorktreeDir := filepath.Join(repositoryRoot, DefaultWorktreeDir)
+	if config.WorktreeDir != expectedWorktreeDir {
+		t.Errorf("WorktreeDir = %q, want %q", config.WorktreeDir, expectedWorktreeDir)
+	}
+	if expectedExportDir := filepath.Join(repositoryRoot, DefaultExportDir); config.ExportDir != expectedExportDir {
+		t.Errorf("ExportDir = %q, want %q", config.ExportDir, expectedExportDir)
+	}
+	if config.StaleBehindCommits != DefaultStaleBehindCommits {
+		t.Errorf("StaleBehindCommits = %d, want %d", config.StaleBehindCommits, DefaultStaleBehindCommits)
+	}
+	if len(config.LoadedFiles) != 0 {
+		t.Errorf("LoadedFiles = %v, want none", config.LoadedFiles)
+	}
+}
+
+func TestLoad_RepositoryConfigFile_OverridesUserConfigFile(t *testing.T) {
+	// arrange
+	userConfigHome := setupIsolatedEnvironment(t)
+	repositoryRoot := setupFakeRepository(t)
+
+	writeConfigFile(t, filepath.Join(userConfigHome, applicationDirectoryName, userConfigFileName), "baseBranch: develop\ntestCommand: make test\n")
+	writeConfigFile(t, filepath.Join(repositoryRoot, ".orchestragent-mcp.yaml"), "baseBranch: master\n")
+
+	// act
+	config, err := Load(Overrides{RepoRoot: repositoryRoot})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if config.BaseBranch != "master" {
+		t.Errorf("BaseBranch = %q, want %q", config.BaseBranch, "master")
+	}
+	if config.TestCommand != "make test" {
+		t.Errorf("TestCommand = %q, want %q", config.TestCommand, "make test")
+	}
+	if len(config.LoadedFiles) != 2 {
+		t.Errorf("LoadedFiles = %v, want 2 entries", config.LoadedFiles)
+	}
+}
+
+func TestLoad_EnvironmentAndFlags_OverrideConfigFile(t *testing.T) {
+	// arrange
+	setupIsolatedEnvironment(t)
+	repositoryRoot := setupFakeRepository(t)
+	writeConfigFile(t, filepath.Join(repositoryRoot, ".orchestragent-mcp.yaml"), "baseBranch: master\nworktreeDir: from-file\n")
+	t.Setenv(EnvBaseBranch, "develop")
+	t.Setenv(EnvWorktreeDir, "from-env")
+
+	// act
+	config, err := Load(Overrides{RepoRoot: repositoryRoot, WorktreeDir: "from-flag"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if config.BaseBranch != "develop" {
+		t.Errorf("BaseBranch = %q, want %q", config.BaseBranch, "develop")
+	}
+	expectedWorktreeDir := filepath.Join(repositoryRoot, "from-flag")
+	if config.WorktreeDir != expectedWorktreeDir {
+		t.Errorf("WorktreeDir = %q, want %q", config.WorktreeDir, expectedWorktreeDir)
+	}
+}
+
+func TestLoad_StaleBehindCommits_FromFileAndEnvironment(t *testing.T) {
+	// arrange
+	setupIsolatedEnvironment(t)
+	repositoryRoot := setupFakeRepository(t)
+	writeConfigFile(t, filepath.Join(repositoryRoot, ".orchestragent-mcp.yaml"), "staleBehindCommits: 20\n")
+
+	// act
+	fromFile, fileErr := Load(Overrides{RepoRoot: repositoryRoot})
+	t.Setenv(EnvStaleBehindCommits, "many")
+	_, envErr := Load(Overrides{RepoRoot: repositoryRoot})
+
+	// assert
+	if fileErr != nil {
+		t.Fatalf("Load() error: %v", fileErr)
+	}
+	if fromFile.StaleBehindCommits != 20 {
+		t.Errorf("StaleBehindCommits = %d, want 20", fromFile.StaleBehindCommits)
+	}
+	if envErr == nil || !strings.Contains(envErr.Error(), EnvStaleBehindCommits) {
+		t.Errorf("Load() error = %v, want one naming %s", envErr, EnvStaleBehindCommits)
+	}
+}
+
+func TestLoad_ExplicitConfigPath_IgnoresDiscoveredFiles(t *testing.T) {
+	// arrange
+	setupIsolatedEnvironment(t)
+	repositoryRoot := setupFakeRepository(t)
+	writeConfigFile(t, filepath.Join(repositoryRoot, ".orchestragent-mcp.yaml"), "baseBranch: master\n")
+	explicitConfigPath := filepath.Join(t.TempDir(), "custom.yaml")
+	writeConfigFile(t, explicitConfigPath, "repoRoot: "+repositoryRoot+"\nbaseBranch: release\n")
+
+	// act
+	config, err := Load(Overrides{ConfigPath: explicitConfigPath})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if config.BaseBranch != "release" {
+		t.Errorf("BaseBranch = %q, want %q", config.BaseBranch, "release")
+	}
+	if config.RepoRoot != repositoryRoot {
+		t.Errorf("RepoRoot = %q, want %q", config.RepoRoot, repositoryRoot)
+	}
+}
+
+func TestLoad_MissingExplicitConfigPath_ReturnsError(t *testing.T) {
+	// arrange
+	setupIsolatedEnvironment(t)
+	repositoryRoot := setupFakeRepository(t)
+
+	// act
+	_, err := Load(Overrides{RepoRoot: repositoryRoot, ConfigPath: filepath.Join(repositoryRoot, "missing.yaml")})
+
+	// assert
+	if err == nil {
+		t.Fatal("Load() expected error for missing explicit config file")
+	}
+}
+
+func TestLoad_UnknownField_ReturnsError(t *testing.T) {
+	// arrange
+	setupIsolatedEnvironment(t)
+	repositoryRoot := setupFakeRepository(t)
+	writeConfigFile(t, filepath.Join(repositoryRoot, ".orchestragent-mcp.yaml"), "baseBrnch: master\n")
+
+	// act
+	_, err := Load(Overrides{RepoRoot: repositoryRoot})
+
+	// assert
+	if err == nil {
+		t.Fatal("Load() expected error for unknown config field")
+	}
+	if !strings.Contains(err.Error(), "baseBrnch") {
+		t.Errorf("error %q should name the unknown field", err.Error())
+	}
+}
+
+func TestLoad_RepoRootIsNotGitRepository_ReturnsError(t *testing.T) {
+	// arrange
+	setupIsolatedEnvironment(t)
+	notARepository := t.TempDir()
+
+	// act
+	_, err := Load(Overrides{RepoRoot: notARepository})
+
+	// assert
+	if err == nil {
+		t.Fatal("Load() expected error for directory without .git")
+	}
+}
+
+func TestValidate_ReportsAllProblems(t *testing.T) {
+	// arrange
+	config := &Config{
+		RepoRoot:    filepath.Join(t.TempDir(), "missing"),
+		BaseBranch:  "-bad branch",
+		WorktreeDir: "",
+	}
+
+	// act
+	err := config.Validate()
+
+	// assert
+	if err == nil {
+		t.Fatal("Validate() expected error")
+	}
+	message := err.Error()
+	for _, expected := range []string{"repoRoot", "baseBranch", "worktreeDir"} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("error %q should mention %s", message, expected)
+		}
+	}
+}
+
+func TestLoad_CommitAuthor_FromEnvironmentAndValidated(t *testing.T) {
+	// arrange
+	setupIsolatedEnvironment(t)
+	repositoryRoot := setupFakeRepository(t)
+	t.Setenv(EnvCommitAuthorName, "Session Bot")
+	t.Setenv(EnvCommitAuthorEmail, "bot@example.com")
+
+	// act
+	fromEnvironment, environmentErr := Load(Overrides{RepoRoot: repositoryRoot})
+	t.Setenv(EnvCommitAuthorEmail, "<bot@example.com>")
+	_, invalidErr := Load(Overrides{RepoRoot: repositoryRoot})
+
+	// assert
+	if environmentErr != nil {
+		t.Fatalf("Load() error: %v", environmentErr)
+	}
+	if fromEnvironment.CommitAuthorName != "Session Bot" || fromEnvironment.CommitAuthorEmail != "bot@example.com" {
+		t.Errorf("commit author = %q <%q>, want values from the environment", fromEnvironment.CommitAuthorName, fromEnvironment.CommitAuthorEmail)
+	}
+	if invalidErr == nil {
+		t.Error("Load() expected error for an email with angle brackets")
+	}
+}
+
+func TestLoad_ForgeSettings_DefaultsAndValidation(t *testing.T) {
+	// arrange
+	setupIsolatedEnvironment(t)
+	repositoryRoot := setupFakeRepository(t)
+	writeConfigFile(t, filepath.Join(repositoryRoot, ".orchestragent-mcp.yaml"), "forgeRepository: team/app\n")
+
+	// act
+	fromFile, fileErr := Load(Overrides{RepoRoot: repositoryRoot})
+	t.Setenv(EnvForgeRepository, "team")
+	_, invalidErr := Load(Overrides{RepoRoot: repositoryRoot})
+
+	// assert
+	if fileErr != nil {
+		t.Fatalf("Load() error: %v", fileErr)
+	}
+	if fromFile.Remote != DefaultRemote || fromFile.ForgeURL != DefaultForgeURL || fromFile.ForgeRepository != "team/app" {
+		t.Errorf("forge settings = %q %q %q, want defaults with team/app", fromFile.Remote, fromFile.ForgeURL, fromFile.ForgeRepository)
+	}
+	if invalidErr == nil {
+		t.Error("Load() expected error for a repository without owner")
+	}
+}
+
+func TestLoad_ExecPolicy_KeepsDefaultLimitsAndValidatesPatterns(t *testing.T) {
+	// arrange
+	setupIsolatedEnvironment(t)
+	repositoryRoot := setupFakeRepository(t)
+	configPath := filepath.Join(repositoryRoot, ".orchestragent-mcp.yaml")
+	writeConfigFile(t, configPath, "exec:\n  allow:\n    - command: go\n      args: [\"test|vet\", \"./...\"]\n    - command: make\n  allowedEnv: [CGO_ENABLED]\n  deniedEnv: [\"*_TOKEN\"]\n")
+
+	// act
+	fromFile, fileErr := Load(Overrides{RepoRoot: repositoryRoot})
+	writeConfigFile(t, configPath, "exec:\n  allow:\n    - command: bin/tool\n      args: [\"(\"]\n  allowedEnv: [\"[\"]\n  timeoutSeconds: 0\n")
+	_, invalidErr := Load(Overrides{RepoRoot: repositoryRoot})
+
+	// assert
+	if fileErr != nil {
+		t.Fatalf("Load() error: %v", fileErr)
+	}
+	if len(fromFile.Exec.Allow) != 2 || fromFile.Exec.Allow[0].Command != "go" || len(fromFile.Exec.Allow[0].Args) != 2 || fromFile.Exec.Allow[1].Args != nil {
+		t.Errorf("exec.allow = %+v, want go with two patterns and make without", fromFile.Exec.Allow)
+	}
+	if len(fromFile.Exec.AllowedEnv) != 1 || fromFile.Exec.AllowedEnv[0] != "CGO_ENABLED" {
+		t.Errorf("exec.allowedEnv = %v, want CGO_ENABLED", fromFile.Exec.AllowedEnv)
+	}
+	if fromFile.Exec.TimeoutSeconds != DefaultExecTimeoutSeconds || fromFile.Exec.MaxOutputBytes != DefaultExecMaxOutputBytes {
+		t.Errorf("exec limits = %d s, %d bytes, want the defaults", fromFile.Exec.TimeoutSeconds, fromFile.Exec.MaxOutputBytes)
+	}
+	if invalidErr == nil {
+		t.Fatal("Load() expected error for an invalid exec policy")
+	}
+	for _, expected := range []string{"bin/tool", `"("`, "exec.allowedEnv", "exec.timeoutSeconds"} {
+		if !strings.Contains(invalidErr.Error(), expected) {
+			t.Errorf("error %q should mention %s", invalidErr.Error(), expected)
+		}
+	}
+}
+
+func TestLoad_ExecSandbox_DefaultsAndValidation(t *testing.T) {
+	// arrange
+	setupIsolatedEnvironment(t)
+	repositoryRoot := setupFakeRepository(t)
+	configPath := filepath.Join(repositoryRoot, ".orchestragent-mcp.yaml")
+
+	// act
+	defaults, defaultsErr := Load(Overrides{RepoRoot: repositoryRoot})
+	writeConfigFile(t, configPath, "exec:\n  sandbox:\n    mode: off\n    readOnlyPaths: [/usr]\n    writablePaths: [/var/cache/go]\n")
+	fromFile, fileErr := Load(Overrides{RepoRoot: repositoryRoot})
+	writeConfigFile(t, configPath, "exec:\n  sandbox:\n    mode: strict\n    writablePaths: [cache, /]\n")
+	_, invalidErr := Load(Overrides{RepoRoot: repositoryRoot})
+
+	// assert
+	if defaultsErr != nil || fileErr != nil {
+		t.Fatalf("Load() errors: %v, %v", defaultsErr, fileErr)
+	}
+	if defaults.Exec.Sandbox.Mode != SandboxModeAuto || len(defaults.Exec.Sandbox.ReadOnlyPaths) != len(DefaultSandboxReadOnlyPaths) {
+		t.Errorf("default sandbox = %+v, want auto with the default read-only paths", defaults.Exec.Sandbox)
+	}
+	if fromFile.Exec.Sandbox.Mode != SandboxModeOff || len(fromFile.Exec.Sandbox.ReadOnlyPaths) != 1 || fromFile.Exec.Sandbox.WritablePaths[0] != "/var/cache/go" {
+		t.Errorf("sandbox = %+v, want the configured paths to replace the defaults", fromFile.Exec.Sandbox)
+	}
+	if invalidErr == nil {
+		t.Fatal("Load() expected error for an invalid sandbox")
+	}
+	for _, expected := range []string{`"strict"`, `"cache"`, "filesystem root"} {
+		if !strings.Contains(invalidErr.Error(), expected) {
+			t.Errorf("error %q should mention %s", invalidErr.Error(), expected)
+		}
+	}
+}
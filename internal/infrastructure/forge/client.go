@@ -0,0 +1,136 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// DefaultBaseURL is the GitHub REST API. Gitea and Forgejo serve the same
+// pull request endpoint under https://<host>/api/v1.
+const DefaultBaseURL = "https://api.github.com"
+
+const (
+	requestTimeout = 30 * time.Second
+	// maxErrorBodyBytes caps how much of a failed response is read to find
+	// the forge's error message
+	maxErrorBodyBytes = 64 * 1024
+)
+
+// Client opens pull requests through the GitHub-compatible REST API that
+// GitHub, Gitea and Forgejo share
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	owner      string
+	repository string
+	token      string
+}
+
+// NewClient creates a client for the repository given as "owner/name".
+// baseURL is the API root; httpClient may be nil to use a client with a
+// default timeout.
+func NewClient(baseURL string, repository string, token string, httpClient *http.Client) (*Client, error) {
+	owner, name, found := strings.Cut(repository, "/")
+	if !found || owner == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("forge repository %q must have the form owner/name", repository)
+	}
+
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return nil, fmt.Errorf("forge URL %q must be an absolute http or https URL", baseURL)
+	}
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: requestTimeout}
+	}
+
+	return &Client{
+		httpClient: httpClient,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		owner:      owner,
+		repository: name,
+		token:      token,
+	}, nil
+}
+
+type createPullRequestBody struct {
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Draft bool   `json:"draft,omitempty"`
+}
+
+type pullRequestResponse struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+// CreatePullRequest opens a pull request and returns its number and web URL
+func (client *Client) CreatePullRequest(ctx context.Context, request domain.NewPullRequest) (*domain.PullRequest, error) {
+	payload, err := json.Marshal(createPullRequestBody{
+		Title: request.Title,
+		Body:  request.Body,
+		Head:  request.Head,
+		Base:  request.Base,
+		Draft: request.Draft,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode pull request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/pulls", client.baseURL, url.PathEscape(client.owner), url.PathEscape(client.repository))
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build pull request request: %w", err)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Accept", "application/json")
+	if client.token != "" {
+		httpRequest.Header.Set("Authorization", "token "+client.token)
+	}
+
+	httpResponse, err := client.httpClient.Do(httpRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach forge: %w", err)
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode != http.StatusCreated && httpResponse.StatusCode != http.StatusOK {
+		return nil, readErrorResponse(httpResponse)
+	}
+
+	var created pullRequestResponse
+	if err := json.NewDecoder(httpResponse.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode pull request response: %w", err)
+	}
+	if created.Number <= 0 || created.HTMLURL == "" {
+		return nil, errors.New("forge response is missing the pull request number or URL")
+	}
+
+	return &domain.PullRequest{Number: created.Number, URL: created.HTMLURL}, nil
+}
+
+func readErrorResponse(httpResponse *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(httpResponse.Body, maxErrorBodyBytes))
+
+	var decoded errorResponse
+	if json.Unmarshal(body, &decoded) == nil && decoded.Message != "" {
+		return fmt.Errorf("forge rejected pull request (%s): %s", httpResponse.Status, decoded.Message)
+	}
+	return fmt.Errorf("forge rejected pull request (%s)", httpResponse.Status)
+}
@@ -0,0 +1,87 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func TestClient_CreatePullRequest_PostsToPullsEndpoint(t *testing.T) {
+	// arrange
+	var receivedPath, receivedAuthorization string
+	var receivedBody createPullRequestBody
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedPath = request.Method + " " + request.URL.Path
+		receivedAuthorization = request.Header.Get("Authorization")
+		json.NewDecoder(request.Body).Decode(&receivedBody)
+		writer.WriteHeader(http.StatusCreated)
+		writer.Write([]byte(`{"number": 12, "html_url": "https://forge.example.com/team/app/pull/12"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL+"/api/v1/", "team/app", "secret", server.Client())
+	if err != nil {
+		t.Fatalf("NewClient() error: %v", err)
+	}
+
+	// act
+	pullRequest, err := client.CreatePullRequest(context.Background(), domain.NewPullRequest{
+		Title: "Add login",
+		Head:  "orchestragent-login",
+		Base:  "main",
+		Draft: true,
+	})
+
+	// assert
+	if err != nil {
+		t.Fatalf("CreatePullRequest() error: %v", err)
+	}
+	if pullRequest.Number != 12 || pullRequest.URL != "https://forge.example.com/team/app/pull/12" {
+		t.Errorf("pull request = %+v, want number 12", pullRequest)
+	}
+	if receivedPath != "POST /api/v1/repos/team/app/pulls" {
+		t.Errorf("request = %q, want POST to the pulls endpoint", receivedPath)
+	}
+	if receivedAuthorization != "token secret" {
+		t.Errorf("Authorization = %q, want the token", receivedAuthorization)
+	}
+	if receivedBody.Head != "orchestragent-login" || receivedBody.Base != "main" || !receivedBody.Draft {
+		t.Errorf("body = %+v, want head, base and draft", receivedBody)
+	}
+}
+
+func TestClient_CreatePullRequest_RejectedRequest_ReturnsForgeMessage(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusUnprocessableEntity)
+		writer.Write([]byte(`{"message": "A pull request already exists"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, "team/app", "", server.Client())
+
+	// act
+	_, err := client.CreatePullRequest(context.Background(), domain.NewPullRequest{Title: "Add login", Head: "feature", Base: "main"})
+
+	// assert
+	if err == nil {
+		t.Fatal("CreatePullRequest() expected error")
+	}
+	if expected := "forge rejected pull request (422 Unprocessable Entity): A pull request already exists"; err.Error() != expected {
+		t.Errorf("error = %q, want %q", err.Error(), expected)
+	}
+}
+
+func TestNewClient_InvalidRepository_ReturnsError(t *testing.T) {
+	// act
+	_, err := NewClient(DefaultBaseURL, "just-a-name", "", nil)
+
+	// assert
+	if err == nil {
+		t.Error("NewClient() expected error for a repository without owner")
+	}
+}
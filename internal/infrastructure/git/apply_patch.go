@@ -0,0 +1,451 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// patchFile is the part of a patch that changes one file: the header lines
+// up to its first hunk, and the hunks themselves
+type patchFile struct {
+	path     string
+	header   string
+	hasNames bool
+	hunks    []patchHunk
+}
+
+type patchHunk struct {
+	header string
+	text   string
+}
+
+// ApplyPatch applies patch with "git apply", which on its own refuses paths
+// that leave the worktree or run through a symlink. Every attempt is dry-run
+// first, so the patch is either applied in full or the worktree is left
+// untouched. Hunks are only checked one by one when the patch as a whole does
+// not apply cleanly. Neither a plain nor a three-way apply changes the index.
+func (gitClient *GitClient) ApplyPatch(ctx context.Context, worktreePath string, patch string, checkOnly bool) (*domain.PatchResult, error) {
+	files, err := parsePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	temporaryDirectory, err := os.MkdirTemp("", "orchestragent-patch-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary patch directory: %w", err)
+	}
+	defer os.RemoveAll(temporaryDirectory)
+
+	patchPath, err := writePatchFile(temporaryDirectory, "patch.diff", patch)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.PatchResult{Files: make([]string, 0, len(files)), Hunks: patchHunks(files)}
+	for _, file := range files {
+		result.Files = append(result.Files, file.path)
+	}
+
+	applyOutput, applies, err := gitClient.checkApply(ctx, worktreePath, patchPath)
+	if err != nil {
+		return nil, err
+	}
+	if !applies {
+		if err := gitClient.checkHunks(ctx, worktreePath, temporaryDirectory, files, result.Hunks); err != nil {
+			return nil, err
+		}
+
+		merge, err := gitClient.mergePatch(ctx, worktreePath, temporaryDirectory, patchPath)
+		if err != nil {
+			return nil, err
+		}
+		if !merge.applies {
+			result.Errors = applyErrors(applyOutput, merge.output)
+			return result, nil
+		}
+		result.ConflictedPaths = threeWayConflicts(merge.output)
+		if len(result.ConflictedPaths) > 0 {
+			return result, nil
+		}
+		result.ThreeWay = true
+
+		// the merged result goes to the worktree as a plain patch of its own
+		if patchPath, err = gitClient.mergedPatch(ctx, worktreePath, temporaryDirectory, merge); err != nil {
+			return nil, err
+		}
+	}
+	result.Applies = true
+
+	if checkOnly {
+		return result, nil
+	}
+
+	if patchPath != "" {
+		if _, err := gitClient.executeGitCommand(ctx, "-C", worktreePath, "apply", patchPath); err != nil {
+			return nil, fmt.Errorf("failed to apply patch: %w", err)
+		}
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// patchMerge is the outcome of a three-way merge in a temporary index: git's
+// messages, whether the merge was possible, and the trees before and after
+// it when it went through without conflicts
+type patchMerge struct {
+	output     string
+	applies    bool
+	treeBefore string
+	treeAfter  string
+}
+
+// mergePatch three-way merges patch into a temporary copy of the index that
+// holds the worktree's tracked files as they are, uncommitted changes
+// included. "git apply --3way" on the worktree itself would refuse files
+// that differ from the index and stage the files it merges.
+func (gitClient *GitClient) mergePatch(ctx context.Context, worktreePath string, directory string, patchPath string) (patchMerge, error) {
+	indexFile := filepath.Join(directory, "index")
+	if err := gitClient.copyIndex(ctx, worktreePath, indexFile); err != nil {
+		return patchMerge{}, err
+	}
+	if _, err := gitClient.executeGitCommandWithIndex(ctx, indexFile, "-C", worktreePath, "add", "--update"); err != nil {
+		return patchMerge{}, fmt.Errorf("failed to read uncommitted changes: %w", err)
+	}
+	treeBefore, err := gitClient.executeGitCommandWithIndex(ctx, indexFile, "-C", worktreePath, "write-tree")
+	if err != nil {
+		return patchMerge{}, fmt.Errorf("failed to record the worktree: %w", err)
+	}
+
+	commandOutput, err := gitClient.executeGitCommandWithIndexCombined(ctx, indexFile, "-C", worktreePath, "apply", "--3way", "--cached", patchPath)
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return patchMerge{}, fmt.Errorf("failed to merge patch: %w", err)
+	}
+	merge := patchMerge{output: string(commandOutput), treeBefore: strings.TrimSpace(string(treeBefore))}
+	if len(threeWayConflicts(merge.output)) > 0 {
+		merge.applies = true
+		return merge, nil
+	}
+	if err != nil {
+		return merge, nil
+	}
+
+	treeAfter, err := gitClient.executeGitCommandWithIndex(ctx, indexFile, "-C", worktreePath, "write-tree")
+	if err != nil {
+		return patchMerge{}, fmt.Errorf("failed to record the merged patch: %w", err)
+	}
+	merge.applies = true
+	merge.treeAfter = strings.TrimSpace(string(treeAfter))
+	return merge, nil
+}
+
+// mergedPatch writes what a clean merge changed as a patch that applies to
+// the worktree as it is, or returns "" when the merge changed nothing
+func (gitClient *GitClient) mergedPatch(ctx context.Context, worktreePath string, directory string, merge patchMerge) (string, error) {
+	if merge.treeBefore == merge.treeAfter {
+		return "", nil
+	}
+	diff, err := gitClient.executeGitCommandWithOutput(ctx, "-C", worktreePath, "diff", "--binary", "--no-renames", merge.treeBefore, merge.treeAfter)
+	if err != nil {
+		return "", fmt.Errorf("failed to diff the merged patch: %w", err)
+	}
+	return writePatchFile(directory, "merged.diff", string(diff))
+}
+
+// copyIndex copies the worktree's index to indexFile
+func (gitClient *GitClient) copyIndex(ctx context.Context, worktreePath string, indexFile string) error {
+	worktreeIndex, err := gitClient.indexPath(ctx, worktreePath)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(worktreeIndex)
+	if err != nil {
+		return fmt.Errorf("failed to read the index: %w", err)
+	}
+	if err := os.WriteFile(indexFile, content, 0o600); err != nil {
+		return fmt.Errorf("failed to copy the index: %w", err)
+	}
+	return nil
+}
+
+// checkApply dry-runs "git apply" and returns git's messages and whether the
+// patch would apply
+func (gitClient *GitClient) checkApply(ctx context.Context, worktreePath string, args ...string) (string, bool, error) {
+	commandOutput, err := gitClient.executeGitCommand(ctx, append([]string{"-C", worktreePath, "apply", "--check"}, args...)...)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return string(commandOutput), false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to check patch: %w", err)
+	}
+	return string(commandOutput), true, nil
+}
+
+// checkHunks dry-runs each hunk as a patch of its own and records whether it
+// applies. hunks is in the order patchHunks returns.
+func (gitClient *GitClient) checkHunks(ctx context.Context, worktreePath string, directory string, files []patchFile, hunks []domain.PatchHunk) error {
+	next := 0
+	check := func(text string) error {
+		hunkPath, err := writePatchFile(directory, "hunk.diff", text)
+		if err != nil {
+			return err
+		}
+		_, applies, err := gitClient.checkApply(ctx, worktreePath, hunkPath)
+		if err != nil {
+			return err
+		}
+		hunks[next].Applies = applies
+		next++
+		return nil
+	}
+
+	for _, file := range files {
+		if len(file.hunks) == 0 {
+			if err := check(file.header); err != nil {
+				return err
+			}
+		}
+		for _, hunk := range file.hunks {
+			if err := check(file.header + hunk.text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// patchHunks lists the hunks of files, all marked as applying
+func patchHunks(files []patchFile) []domain.PatchHunk {
+	hunks := make([]domain.PatchHunk, 0)
+	for _, file := range files {
+		if len(file.hunks) == 0 {
+			hunks = append(hunks, domain.PatchHunk{Path: file.path, Number: 1, Applies: true})
+		}
+		for index, hunk := range file.hunks {
+			hunks = append(hunks, domain.PatchHunk{Path: file.path, Number: index + 1, Header: hunk.header, Applies: true})
+		}
+	}
+	return hunks
+}
+
+func writePatchFile(directory string, name string, text string) (string, error) {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	patchPath := filepath.Join(directory, name)
+	if err := os.WriteFile(patchPath, []byte(text), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write patch file: %w", err)
+	}
+	return patchPath, nil
+}
+
+// threeWayConflicts returns the paths a "git apply --3way" run reported as
+// applied with conflicts
+func threeWayConflicts(output string) []string {
+	paths := make([]string, 0)
+	for _, line := range strings.Split(output, "\n") {
+		path, found := strings.CutPrefix(line, "Applied patch to '")
+		if !found {
+			continue
+		}
+		if path, found = strings.CutSuffix(path, "' with conflicts."); found {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// applyErrors collects the distinct error lines of "git apply" outputs
+func applyErrors(outputs ...string) []string {
+	seen := make(map[string]bool)
+	messages := make([]string, 0)
+	for _, output := range outputs {
+		for _, line := range strings.Split(output, "\n") {
+			message, found := strings.CutPrefix(line, "error: ")
+			if !found {
+				message, found = strings.CutPrefix(line, "fatal: ")
+			}
+			if found && !seen[message] {
+				seen[message] = true
+				messages = append(messages, message)
+			}
+		}
+	}
+	return messages
+}
+
+// parsePatch splits a unified diff, with or without git's extended headers,
+// into files and hunks, and refuses paths that leave the worktree or touch
+// .git. Text before the first file or after the hunks of a file, such as a
+// commit message or a mail signature, is skipped the way git apply does.
+func parsePatch(patch string) ([]patchFile, error) {
+	lines := strings.SplitAfter(patch, "\n")
+	files := make([]patchFile, 0)
+	var current *patchFile
+
+	for index := 0; index < len(lines); index++ {
+		line := lines[index]
+		switch {
+		case strings.HasPrefix(line, diffHeaderPrefix):
+			files = append(files, patchFile{header: line})
+			current = &files[len(files)-1]
+			if err := current.setPath(parseDiffHeaderPath(strings.TrimSuffix(line, "\n"))); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(line, "--- ") && index+1 < len(lines) && strings.HasPrefix(lines[index+1], "+++ "):
+			if current == nil || current.hasNames || len(current.hunks) > 0 {
+				files = append(files, patchFile{})
+				current = &files[len(files)-1]
+			}
+			current.header += line + lines[index+1]
+			current.hasNames = true
+			for _, name := range []string{line, lines[index+1]} {
+				if err := current.setPath(patchNamePath(name[len("--- "):])); err != nil {
+					return nil, err
+				}
+			}
+			index++
+		case strings.HasPrefix(line, "@@ ") && current != nil:
+			hunk, last, err := readHunk(lines, index)
+			if err != nil {
+				return nil, fmt.Errorf("invalid patch for %s: %w", current.path, err)
+			}
+			current.hunks = append(current.hunks, hunk)
+			index = last
+		case current != nil && len(current.hunks) == 0:
+			current.header += line
+			for _, prefix := range []string{"rename from ", "rename to ", "copy from ", "copy to "} {
+				if name, found := strings.CutPrefix(line, prefix); found {
+					if err := current.setPath(unquoteGitPath(strings.TrimSuffix(name, "\n"))); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, errors.New("patch contains no file changes")
+	}
+	return files, nil
+}
+
+// setPath validates a path named by the file's header and makes it the
+// file's path. Later names win, so the new path of a rename is reported.
+func (file *patchFile) setPath(path string) error {
+	if path == "" {
+		return nil
+	}
+	if strings.HasPrefix(path, "/") || filepath.IsAbs(path) {
+		return fmt.Errorf("%w: %s", domain.ErrPathOutsideWorktree, path)
+	}
+	for _, component := range strings.Split(path, "/") {
+		if component == ".." {
+			return fmt.Errorf("%w: %s", domain.ErrPathOutsideWorktree, path)
+		}
+		if strings.EqualFold(component, ".git") {
+			return fmt.Errorf("%w: %s", domain.ErrProtectedPath, path)
+		}
+	}
+	file.path = path
+	return nil
+}
+
+// patchNamePath returns the path of a "---" or "+++" name with its leading
+// directory, usually a/ or b/, stripped the way "git apply -p1" does. It
+// returns "" for /dev/null.
+func patchNamePath(name string) string {
+	name = strings.TrimSuffix(name, "\n")
+	if strings.HasPrefix(name, `"`) {
+		name = unquoteGitPath(name)
+	} else if tab := strings.IndexByte(name, '\t'); tab >= 0 {
+		name = name[:tab]
+	}
+
+	if name == "/dev/null" {
+		return ""
+	}
+	if _, path, found := strings.Cut(name, "/"); found {
+		return path
+	}
+	return name
+}
+
+// readHunk reads the hunk whose header is lines[start], using the line counts
+// of the header to find its end, and returns the index of its last line
+func readHunk(lines []string, start int) (patchHunk, int, error) {
+	header := strings.TrimSuffix(lines[start], "\n")
+	oldCount, newCount, err := parseHunkCounts(header)
+	if err != nil {
+		return patchHunk{}, 0, err
+	}
+
+	hunk := patchHunk{header: header, text: lines[start]}
+	index := start
+	for oldCount > 0 || newCount > 0 {
+		index++
+		if index >= len(lines) || lines[index] == "" {
+			return patchHunk{}, 0, fmt.Errorf("hunk %q is truncated", header)
+		}
+		switch lines[index][0] {
+		case ' ', '\n':
+			oldCount--
+			newCount--
+		case '-':
+			oldCount--
+		case '+':
+			newCount--
+		case '\\':
+		default:
+			return patchHunk{}, 0, fmt.Errorf("hunk %q is truncated", header)
+		}
+		hunk.text += lines[index]
+	}
+	// a "\ No newline at end of file" marker after the last line
+	if index+1 < len(lines) && strings.HasPrefix(lines[index+1], `\`) {
+		index++
+		hunk.text += lines[index]
+	}
+	return hunk, index, nil
+}
+
+// parseHunkCounts returns the old and new line counts of a hunk header such
+// as "@@ -12,7 +12,8 @@ func main() {"
+func parseHunkCounts(header string) (int, int, error) {
+	fields := strings.Fields(header)
+	if len(fields) < 4 || fields[3] != "@@" {
+		return 0, 0, fmt.Errorf("malformed hunk header %q", header)
+	}
+	oldCount, oldErr := hunkRangeCount(fields[1], "-")
+	newCount, newErr := hunkRangeCount(fields[2], "+")
+	if oldErr != nil || newErr != nil {
+		return 0, 0, fmt.Errorf("malformed hunk header %q", header)
+	}
+	return oldCount, newCount, nil
+}
+
+// hunkRangeCount returns the line count of a "-start,count" or "+start,count"
+// range, where a missing count means one line
+func hunkRangeCount(field string, sign string) (int, error) {
+	rangeSpec, found := strings.CutPrefix(field, sign)
+	if !found {
+		return 0, fmt.Errorf("range %q does not start with %s", field, sign)
+	}
+	start, count, hasCount := strings.Cut(rangeSpec, ",")
+	if _, err := strconv.Atoi(start); err != nil {
+		return 0, err
+	}
+	if !hasCount {
+		return 1, nil
+	}
+	return strconv.Atoi(count)
+}
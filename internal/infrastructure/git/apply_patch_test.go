@@ -0,0 +1,189 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// numberedLines returns lines "1" to "count", one per line, with the lines
+// in replacements swapped in
+func numberedLines(count int, replacements map[int]string) string {
+	var builder strings.Builder
+	for number := 1; number <= count; number++ {
+		line, replaced := replacements[number]
+		if !replaced {
+			line = fmt.Sprint(number)
+		}
+		builder.WriteString(line + "\n")
+	}
+	return builder.String()
+}
+
+// makePatch returns the diff that turns the committed fileName into content,
+// leaving the worktree as it was
+func makePatch(t *testing.T, worktreePath string, fileName string, content string) string {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(worktreePath, fileName), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", fileName, err)
+	}
+	patch := runGit(t, worktreePath, "diff")
+	runGit(t, worktreePath, "checkout", "--", fileName)
+	return patch
+}
+
+func TestGitClient_ApplyPatch_CheckLeavesWorktreeUntouched(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "numbers.txt", numberedLines(9, nil), "Add numbers")
+	patch := makePatch(t, setup.worktreePath, "numbers.txt", numberedLines(9, map[int]string{2: "two"}))
+
+	// act
+	checked, checkErr := setup.gitClient.ApplyPatch(setup.ctx, setup.worktreePath, patch, true)
+	unchanged, _ := os.ReadFile(filepath.Join(setup.worktreePath, "numbers.txt"))
+	applied, applyErr := setup.gitClient.ApplyPatch(setup.ctx, setup.worktreePath, patch, false)
+	changed, _ := os.ReadFile(filepath.Join(setup.worktreePath, "numbers.txt"))
+
+	// assert
+	if checkErr != nil || applyErr != nil {
+		t.Fatalf("ApplyPatch() errors: %v, %v", checkErr, applyErr)
+	}
+	if !checked.Applies || checked.Applied || string(unchanged) != numberedLines(9, nil) {
+		t.Errorf("check = %+v, file = %q; want an applicable patch and an untouched file", checked, unchanged)
+	}
+	if !applied.Applied || applied.ThreeWay || string(changed) != numberedLines(9, map[int]string{2: "two"}) {
+		t.Errorf("apply = %+v, file = %q; want the patch applied as is", applied, changed)
+	}
+	if len(applied.Hunks) != 1 || applied.Hunks[0].Path != "numbers.txt" || !applied.Hunks[0].Applies || !strings.HasPrefix(applied.Hunks[0].Header, "@@ -1,5 +1,5 @@") {
+		t.Errorf("hunks = %+v, want one applying hunk in numbers.txt", applied.Hunks)
+	}
+}
+
+func TestGitClient_ApplyPatch_FallsBackToThreeWayMerge(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "numbers.txt", numberedLines(9, nil), "Add numbers")
+	patch := makePatch(t, setup.worktreePath, "numbers.txt", numberedLines(9, map[int]string{2: "two"}))
+	commitFile(t, setup.worktreePath, "numbers.txt", numberedLines(9, map[int]string{5: "five"}), "Change context")
+
+	// act
+	result, err := setup.gitClient.ApplyPatch(setup.ctx, setup.worktreePath, patch, false)
+
+	// assert
+	if err != nil {
+		t.Fatalf("ApplyPatch() error: %v", err)
+	}
+	if !result.Applied || !result.ThreeWay || len(result.Hunks) != 1 || result.Hunks[0].Applies {
+		t.Errorf("result = %+v, want a three-way apply of a hunk that does not apply as is", result)
+	}
+	if content, _ := os.ReadFile(filepath.Join(setup.worktreePath, "numbers.txt")); string(content) != numberedLines(9, map[int]string{2: "two", 5: "five"}) {
+		t.Errorf("content = %q, want both changes", content)
+	}
+	if staged := runGit(t, setup.worktreePath, "diff", "--cached", "--name-only"); staged != "" {
+		t.Errorf("staged = %q, want the index left alone as with a plain apply", staged)
+	}
+}
+
+func TestGitClient_ApplyPatch_ThreeWayMergeKeepsUncommittedChanges(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "numbers.txt", numberedLines(20, nil), "Add numbers")
+	patch := makePatch(t, setup.worktreePath, "numbers.txt", numberedLines(20, map[int]string{2: "two"}))
+	commitFile(t, setup.worktreePath, "numbers.txt", numberedLines(20, map[int]string{4: "four"}), "Change context")
+	if err := os.WriteFile(filepath.Join(setup.worktreePath, "numbers.txt"), []byte(numberedLines(20, map[int]string{4: "four", 18: "dirty"})), 0644); err != nil {
+		t.Fatalf("failed to write numbers.txt: %v", err)
+	}
+
+	// act
+	checked, checkErr := setup.gitClient.ApplyPatch(setup.ctx, setup.worktreePath, patch, true)
+	result, err := setup.gitClient.ApplyPatch(setup.ctx, setup.worktreePath, patch, false)
+
+	// assert
+	if checkErr != nil || err != nil {
+		t.Fatalf("ApplyPatch() errors: %v, %v", checkErr, err)
+	}
+	if !checked.Applies || !checked.ThreeWay || checked.Applied {
+		t.Errorf("check = %+v, want a three-way merge that would apply", checked)
+	}
+	if !result.Applied || !result.ThreeWay {
+		t.Errorf("result = %+v, want a three-way apply over the uncommitted change", result)
+	}
+	if content, _ := os.ReadFile(filepath.Join(setup.worktreePath, "numbers.txt")); string(content) != numberedLines(20, map[int]string{2: "two", 4: "four", 18: "dirty"}) {
+		t.Errorf("content = %q, want the patch merged and the uncommitted change kept", content)
+	}
+	if status := runGit(t, setup.worktreePath, "status", "--porcelain"); status != "M numbers.txt" {
+		t.Errorf("status = %q, want numbers.txt modified but not staged", status)
+	}
+}
+
+func TestGitClient_ApplyPatch_ConflictsAreReportedWithoutChanges(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "numbers.txt", numberedLines(20, nil), "Add numbers")
+	patch := makePatch(t, setup.worktreePath, "numbers.txt", numberedLines(20, map[int]string{2: "two", 18: "eighteen"}))
+	committed := numberedLines(20, map[int]string{18: "XVIII"})
+	commitFile(t, setup.worktreePath, "numbers.txt", committed, "Change line 18")
+
+	// act
+	result, err := setup.gitClient.ApplyPatch(setup.ctx, setup.worktreePath, patch, false)
+
+	// assert
+	if err != nil {
+		t.Fatalf("ApplyPatch() error: %v", err)
+	}
+	if result.Applies || result.Applied || strings.Join(result.ConflictedPaths, ",") != "numbers.txt" {
+		t.Errorf("result = %+v, want a conflict in numbers.txt", result)
+	}
+	if len(result.Hunks) != 2 || !result.Hunks[0].Applies || result.Hunks[1].Applies {
+		t.Errorf("hunks = %+v, want the first to apply and the second to fail", result.Hunks)
+	}
+	if content, _ := os.ReadFile(filepath.Join(setup.worktreePath, "numbers.txt")); string(content) != committed {
+		t.Errorf("content = %q, want the file untouched", content)
+	}
+	if status := runGit(t, setup.worktreePath, "status", "--porcelain"); status != "" {
+		t.Errorf("status = %q, want a clean worktree", status)
+	}
+}
+
+func TestGitClient_ApplyPatch_RefusesPathsOutsideWorktree(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	testCases := []struct {
+		name  string
+		patch string
+		want  error
+	}{
+		{"parent directory", "--- a/../outside.txt\n+++ b/../outside.txt\n@@ -0,0 +1 @@\n+escaped\n", domain.ErrPathOutsideWorktree},
+		{"absolute path", "--- /dev/null\n+++ b//etc/outside.txt\n@@ -0,0 +1 @@\n+escaped\n", domain.ErrPathOutsideWorktree},
+		{"git directory", "diff --git a/.git/hooks/pre-commit b/.git/hooks/pre-commit\nnew file mode 100755\n--- /dev/null\n+++ b/.git/hooks/pre-commit\n@@ -0,0 +1 @@\n+exit 0\n", domain.ErrProtectedPath},
+		{"renamed into git directory", "diff --git a/README.md b/README.md\nsimilarity index 100%\nrename from README.md\nrename to .GIT/README.md\n", domain.ErrProtectedPath},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// act
+			_, err := setup.gitClient.ApplyPatch(setup.ctx, setup.worktreePath, testCase.patch, false)
+
+			// assert
+			if !errors.Is(err, testCase.want) {
+				t.Errorf("ApplyPatch() error = %v, want %v", err, testCase.want)
+			}
+		})
+	}
+}
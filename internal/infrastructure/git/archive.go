@@ -0,0 +1,81 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CreateBundle writes branchName, minus everything reachable from
+// excludedCommit, into a bundle. Recipients need excludedCommit in their
+// repository to unpack it.
+func (gitClient *GitClient) CreateBundle(ctx context.Context, bundlePath string, branchName string, excludedCommit string) error {
+	args := []string{"bundle", "create", "--quiet", bundlePath, "refs/heads/" + branchName}
+	if excludedCommit != "" {
+		args = append(args, "^"+excludedCommit)
+	}
+
+	if _, err := gitClient.executeGitCommand(ctx, args...); err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	return nil
+}
+
+// FormatPatches writes one mbox patch per commit in baseRef..branchName and
+// returns the paths git prints. Merge commits have no patch and are skipped.
+func (gitClient *GitClient) FormatPatches(ctx context.Context, outputDirectory string, baseRef string, branchName string) ([]string, error) {
+	revRange := fmt.Sprintf("%s..%s", baseRef, branchName)
+	commandOutput, err := gitClient.executeGitCommandWithOutput(ctx,
+		"format-patch", "--no-signature", "--no-stat", "-o", outputDirectory,
+		"--end-of-options", revRange,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format patches: %w", err)
+	}
+
+	patchPaths := make([]string, 0)
+	for _, line := range strings.Split(string(commandOutput), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			patchPaths = append(patchPaths, line)
+		}
+	}
+	return patchPaths, nil
+}
+
+// FetchBundle verifies the bundle against the repository, which fails when
+// its prerequisite commits are missing, and fetches ref without creating any
+// local ref
+func (gitClient *GitClient) FetchBundle(ctx context.Context, bundlePath string, ref string) (string, error) {
+	if _, err := gitClient.executeGitCommand(ctx, "bundle", "verify", "--quiet", bundlePath); err != nil {
+		return "", fmt.Errorf("bundle %s cannot be applied to this repository: %w", bundlePath, err)
+	}
+
+	commandOutput, err := gitClient.executeGitCommandWithOutput(ctx, "bundle", "list-heads", bundlePath, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to read bundle heads: %w", err)
+	}
+	commitSHA, _, found := strings.Cut(strings.TrimSpace(string(commandOutput)), " ")
+	if !found || commitSHA == "" {
+		return "", fmt.Errorf("bundle %s does not contain %s", bundlePath, ref)
+	}
+
+	if _, err := gitClient.executeGitCommand(ctx, "fetch", "--quiet", "--no-tags", "--no-write-fetch-head", bundlePath, ref); err != nil {
+		return "", fmt.Errorf("failed to fetch %s from bundle: %w", ref, err)
+	}
+	return commitSHA, nil
+}
+
+// ApplyPatches runs git am with a three-way fallback and aborts the whole
+// series if any patch fails
+func (gitClient *GitClient) ApplyPatches(ctx context.Context, worktreePath string, patchPaths []string) error {
+	if len(patchPaths) == 0 {
+		return nil
+	}
+
+	args := append([]string{"-C", worktreePath, "am", "--quiet", "--3way", "--"}, patchPaths...)
+	if _, err := gitClient.executeGitCommand(ctx, args...); err != nil {
+		gitClient.executeGitCommand(ctx, "-C", worktreePath, "am", "--abort")
+		return fmt.Errorf("failed to apply patches: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitClient_CreateBundle_FetchBundle_RoundTrip(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	baseCommit := runGit(t, setup.repositoryRoot, "rev-parse", "master")
+	commitFile(t, setup.worktreePath, "feature.txt", "feature\n", "Add feature")
+	headCommit := runGit(t, setup.worktreePath, "rev-parse", "HEAD")
+	bundlePath := filepath.Join(t.TempDir(), "session.bundle")
+
+	if err := setup.gitClient.CreateBundle(setup.ctx, bundlePath, setup.branchName, baseCommit); err != nil {
+		t.Fatalf("CreateBundle() error: %v", err)
+	}
+	if err := setup.gitClient.RemoveWorktree(setup.ctx, setup.worktreePath, true); err != nil {
+		t.Fatalf("RemoveWorktree() error: %v", err)
+	}
+	if err := setup.gitClient.DeleteBranch(setup.ctx, setup.branchName, true); err != nil {
+		t.Fatalf("DeleteBranch() error: %v", err)
+	}
+
+	// act
+	fetchedCommit, err := setup.gitClient.FetchBundle(setup.ctx, bundlePath, "refs/heads/"+setup.branchName)
+
+	// assert
+	if err != nil {
+		t.Fatalf("FetchBundle() error: %v", err)
+	}
+	if fetchedCommit != headCommit {
+		t.Errorf("FetchBundle() = %s, want %s", fetchedCommit, headCommit)
+	}
+	if content := runGit(t, setup.repositoryRoot, "show", fetchedCommit+":feature.txt"); content != "feature" {
+		t.Errorf("feature.txt = %q, want the bundled content", content)
+	}
+}
+
+func TestGitClient_FetchBundle_MissingRef_ReturnsError(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "feature.txt", "feature\n", "Add feature")
+	bundlePath := filepath.Join(t.TempDir(), "session.bundle")
+	if err := setup.gitClient.CreateBundle(setup.ctx, bundlePath, setup.branchName, ""); err != nil {
+		t.Fatalf("CreateBundle() error: %v", err)
+	}
+
+	// act
+	_, err := setup.gitClient.FetchBundle(setup.ctx, bundlePath, "refs/heads/other")
+
+	// assert
+	if err == nil {
+		t.Error("FetchBundle() expected error for a ref the bundle does not contain")
+	}
+}
+
+func TestGitClient_FormatPatches_ApplyPatches_RecreatesCommits(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	baseCommit := runGit(t, setup.repositoryRoot, "rev-parse", "master")
+	commitFile(t, setup.worktreePath, "one.txt", "one\n", "Add one")
+	commitFile(t, setup.worktreePath, "two.txt", "two\n", "Add two")
+	patchDirectory := t.TempDir()
+
+	patchPaths, err := setup.gitClient.FormatPatches(setup.ctx, patchDirectory, baseCommit, setup.branchName)
+	if err != nil {
+		t.Fatalf("FormatPatches() error: %v", err)
+	}
+	if len(patchPaths) != 2 {
+		t.Fatalf("FormatPatches() = %v, want 2 patches", patchPaths)
+	}
+
+	importedPath := filepath.Join(setup.repositoryRoot, ".worktrees", "imported")
+	if err := setup.gitClient.CreateWorktree(setup.ctx, importedPath, "imported", baseCommit); err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+
+	// act
+	err = setup.gitClient.ApplyPatches(setup.ctx, importedPath, patchPaths)
+
+	// assert
+	if err != nil {
+		t.Fatalf("ApplyPatches() error: %v", err)
+	}
+	if subjects := runGit(t, importedPath, "log", "--format=%s", baseCommit+"..HEAD"); subjects != "Add two\nAdd one" {
+		t.Errorf("log = %q, want both commits", subjects)
+	}
+	if _, err := os.Stat(filepath.Join(importedPath, "two.txt")); err != nil {
+		t.Errorf("two.txt missing after applying patches: %v", err)
+	}
+}
+
+func TestGitClient_ApplyPatches_Conflict_AbortsSeries(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	baseCommit := runGit(t, setup.repositoryRoot, "rev-parse", "master")
+	commitFile(t, setup.worktreePath, "README.md", "# From session\n", "Edit readme")
+	patchPaths, err := setup.gitClient.FormatPatches(setup.ctx, t.TempDir(), baseCommit, setup.branchName)
+	if err != nil {
+		t.Fatalf("FormatPatches() error: %v", err)
+	}
+
+	importedPath := filepath.Join(setup.repositoryRoot, ".worktrees", "imported")
+	if err := setup.gitClient.CreateWorktree(setup.ctx, importedPath, "imported", baseCommit); err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	commitFile(t, importedPath, "README.md", "# Diverged\n", "Diverge readme")
+	headBefore := runGit(t, importedPath, "rev-parse", "HEAD")
+
+	// act
+	err = setup.gitClient.ApplyPatches(setup.ctx, importedPath, patchPaths)
+
+	// assert
+	if err == nil {
+		t.Fatal("ApplyPatches() expected error for a conflicting patch")
+	}
+	if head := runGit(t, importedPath, "rev-parse", "HEAD"); head != headBefore {
+		t.Error("HEAD should not move when the series is aborted")
+	}
+	if status := runGit(t, importedPath, "status", "--porcelain"); status != "" {
+		t.Errorf("status = %q, want a clean worktree after abort", status)
+	}
+}
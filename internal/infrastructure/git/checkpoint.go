@@ -0,0 +1,297 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+const (
+	checkpointFieldSeparator  = "\x1f"
+	checkpointRecordSeparator = "\x1e"
+)
+
+// CreateCheckpoint snapshots the worktree into a commit stored under ref. The
+// snapshot is built in a temporary index so the worktree, its index and its
+// branch are left untouched. The commit's first parent is HEAD and its
+// second, when the index can be written as a tree, records what was staged.
+// An existing ref is never overwritten.
+func (gitClient *GitClient) CreateCheckpoint(ctx context.Context, worktreePath string, ref string, message string) (*domain.Checkpoint, error) {
+	headSHA, err := gitClient.objectName(ctx, worktreePath, "rev-parse", "--verify", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+
+	parents := []string{"-p", headSHA}
+	if indexTree, err := gitClient.objectName(ctx, worktreePath, "write-tree"); err == nil {
+		indexSHA, err := gitClient.objectName(ctx, worktreePath, "commit-tree", indexTree, "-p", headSHA, "-m", "index of "+message)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record staged changes: %w", err)
+		}
+		parents = append(parents, "-p", indexSHA)
+	}
+
+	worktreeTree, err := gitClient.writeWorktreeTree(ctx, worktreePath)
+	if err != nil {
+		return nil, err
+	}
+
+	commitArgs := append([]string{"commit-tree", worktreeTree}, parents...)
+	commitArgs = append(commitArgs, "-m", message)
+	checkpointSHA, err := gitClient.objectName(ctx, worktreePath, commitArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record checkpoint commit: %w", err)
+	}
+
+	if _, err := gitClient.executeGitCommand(ctx, "update-ref", "-m", "checkpoint", ref, checkpointSHA, ""); err != nil {
+		return nil, fmt.Errorf("failed to store checkpoint %s: %w", ref, err)
+	}
+
+	checkpoints, err := gitClient.ListCheckpoints(ctx, ref)
+	if err != nil || len(checkpoints) == 0 {
+		return nil, fmt.Errorf("failed to read back checkpoint %s: %w", ref, err)
+	}
+	return &checkpoints[0], nil
+}
+
+// writeWorktreeTree writes the full working tree, including untracked files
+// that are not ignored, as a tree object by staging it into a temporary index
+func (gitClient *GitClient) writeWorktreeTree(ctx context.Context, worktreePath string) (string, error) {
+	temporaryDirectory, err := os.MkdirTemp("", "orchestragent-checkpoint-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary index directory: %w", err)
+	}
+	defer os.RemoveAll(temporaryDirectory)
+	indexFile := filepath.Join(temporaryDirectory, "index")
+
+	if _, err := gitClient.executeGitCommandWithIndex(ctx, indexFile, "-C", worktreePath, "read-tree", "HEAD"); err != nil {
+		return "", fmt.Errorf("failed to seed checkpoint index: %w", err)
+	}
+	if _, err := gitClient.executeGitCommandWithIndex(ctx, indexFile, "-C", worktreePath, "add", "--all"); err != nil {
+		return "", fmt.Errorf("failed to stage worktree for checkpoint: %w", err)
+	}
+	commandOutput, err := gitClient.executeGitCommandWithIndex(ctx, indexFile, "-C", worktreePath, "write-tree")
+	if err != nil {
+		return "", fmt.Errorf("failed to write checkpoint tree: %w", err)
+	}
+	return strings.TrimSpace(string(commandOutput)), nil
+}
+
+// ListCheckpoints returns the checkpoints stored under refPrefix, oldest
+// first. refPrefix may also name a single checkpoint ref.
+func (gitClient *GitClient) ListCheckpoints(ctx context.Context, refPrefix string) ([]domain.Checkpoint, error) {
+	format := strings.Join([]string{
+		"%(refname)",
+		"%(objectname)",
+		"%(parent)",
+		"%(creatordate:iso-strict)",
+		"%(contents)",
+	}, "%1f") + "%1e"
+
+	commandOutput, err := gitClient.executeGitCommandWithOutput(ctx, "for-each-ref", "--sort=creatordate", "--format="+format, refPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	return parseCheckpointRefs(string(commandOutput))
+}
+
+func parseCheckpointRefs(output string) ([]domain.Checkpoint, error) {
+	checkpoints := make([]domain.Checkpoint, 0)
+	for _, record := range strings.Split(output, checkpointRecordSeparator) {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		fields := strings.SplitN(record, checkpointFieldSeparator, 5)
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("unexpected checkpoint record %q", record)
+		}
+
+		createdAt, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse checkpoint time %q: %w", fields[3], err)
+		}
+
+		checkpoint := domain.Checkpoint{
+			Name:      fields[0][strings.LastIndex(fields[0], "/")+1:],
+			Ref:       fields[0],
+			SHA:       fields[1],
+			Message:   strings.TrimRight(fields[4], "\n"),
+			CreatedAt: createdAt,
+		}
+		parents := strings.Fields(fields[2])
+		if len(parents) > 0 {
+			checkpoint.HeadSHA = parents[0]
+		}
+		if len(parents) > 1 {
+			checkpoint.IndexSHA = parents[1]
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, nil
+}
+
+// RestoreCheckpoint resets the worktree's branch to the commit the checkpoint
+// was taken on and brings back its working tree and staged changes. Files
+// created since the checkpoint are removed unless they are ignored.
+//
+// The checkpoint is checked and its index built in a temporary index file
+// before anything changes. The current state is then saved as a checkpoint
+// under backupRef, and if a later step fails the worktree is rolled back to
+// it, so a restore either completes or leaves the worktree as it was.
+func (gitClient *GitClient) RestoreCheckpoint(ctx context.Context, worktreePath string, ref string, backupRef string) (*domain.Checkpoint, error) {
+	checkpoint, err := gitClient.findCheckpoint(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	restore, err := gitClient.prepareRestore(ctx, worktreePath, checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint %s cannot be restored: %w", ref, err)
+	}
+	defer restore.discard()
+
+	backup, err := gitClient.CreateCheckpoint(ctx, worktreePath, backupRef, "Before restoring "+checkpoint.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save the current state before restoring: %w", err)
+	}
+
+	restoreErr := gitClient.applyRestore(ctx, worktreePath, restore)
+	if restoreErr == nil {
+		return backup, nil
+	}
+
+	rollback, err := gitClient.prepareRestore(ctx, worktreePath, *backup)
+	if err == nil {
+		defer rollback.discard()
+		err = gitClient.applyRestore(ctx, worktreePath, rollback)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore checkpoint %s: %w; rolling back to %s also failed: %v", ref, restoreErr, backupRef, err)
+	}
+	return nil, fmt.Errorf("failed to restore checkpoint %s, rolled back to %s: %w", ref, backupRef, restoreErr)
+}
+
+func (gitClient *GitClient) findCheckpoint(ctx context.Context, ref string) (domain.Checkpoint, error) {
+	checkpoints, err := gitClient.ListCheckpoints(ctx, ref)
+	if err != nil {
+		return domain.Checkpoint{}, err
+	}
+	if len(checkpoints) != 1 || checkpoints[0].Ref != ref {
+		return domain.Checkpoint{}, fmt.Errorf("checkpoint %s does not exist", ref)
+	}
+	return checkpoints[0], nil
+}
+
+// preparedRestore is a checkpoint whose objects were checked, together with
+// its staged changes written to an index file next to the worktree's own
+type preparedRestore struct {
+	checkpoint domain.Checkpoint
+	indexFile  string
+	targetFile string
+}
+
+func (restore preparedRestore) discard() {
+	os.Remove(restore.indexFile)
+}
+
+// prepareRestore changes nothing in the worktree: it checks that the
+// checkpoint's commits exist and its files can be checked out over the
+// current ones, and writes its index into a temporary file
+func (gitClient *GitClient) prepareRestore(ctx context.Context, worktreePath string, checkpoint domain.Checkpoint) (preparedRestore, error) {
+	indexCommit := checkpoint.HeadSHA
+	if checkpoint.IndexSHA != "" {
+		indexCommit = checkpoint.IndexSHA
+	}
+	for _, object := range []string{checkpoint.SHA, checkpoint.HeadSHA, indexCommit} {
+		if _, err := gitClient.objectName(ctx, worktreePath, "rev-parse", "--verify", "--quiet", object+"^{commit}"); err != nil {
+			return preparedRestore{}, fmt.Errorf("commit %s is missing", object)
+		}
+	}
+	if _, err := gitClient.executeGitCommand(ctx, "-C", worktreePath, "read-tree", "--reset", "-u", "-n", checkpoint.SHA); err != nil {
+		return preparedRestore{}, fmt.Errorf("files cannot be checked out: %w", err)
+	}
+
+	targetFile, err := gitClient.indexPath(ctx, worktreePath)
+	if err != nil {
+		return preparedRestore{}, err
+	}
+
+	restore := preparedRestore{
+		checkpoint: checkpoint,
+		indexFile:  targetFile + ".orchestragent-restore",
+		targetFile: targetFile,
+	}
+	restore.discard()
+	if _, err := gitClient.executeGitCommandWithIndex(ctx, restore.indexFile, "-C", worktreePath, "read-tree", indexCommit); err != nil {
+		restore.discard()
+		return preparedRestore{}, fmt.Errorf("failed to build the restored index: %w", err)
+	}
+	return restore, nil
+}
+
+// applyRestore checks out the checkpoint's files, removes the rest, moves the
+// branch and finally swaps in the prepared index
+func (gitClient *GitClient) applyRestore(ctx context.Context, worktreePath string, restore preparedRestore) error {
+	checkpoint := restore.checkpoint
+	if _, err := gitClient.executeGitCommand(ctx, "-C", worktreePath, "read-tree", "--reset", "-u", checkpoint.SHA); err != nil {
+		return fmt.Errorf("failed to restore checkpoint files: %w", err)
+	}
+	if _, err := gitClient.executeGitCommand(ctx, "-C", worktreePath, "clean", "-fdq"); err != nil {
+		return fmt.Errorf("failed to remove files created after the checkpoint: %w", err)
+	}
+	if _, err := gitClient.executeGitCommand(ctx, "-C", worktreePath, "update-ref", "-m", "restore checkpoint "+checkpoint.Name, "HEAD", checkpoint.HeadSHA); err != nil {
+		return fmt.Errorf("failed to reset branch to checkpoint: %w", err)
+	}
+	if err := os.Rename(restore.indexFile, restore.targetFile); err != nil {
+		return fmt.Errorf("failed to restore staged changes: %w", err)
+	}
+	// the prepared index has no file timestamps yet; refreshing records them
+	// so unchanged files do not show up as modified. It exits non-zero when
+	// files differ from the index, which is expected for unstaged work.
+	_, _ = gitClient.executeGitCommand(ctx, "-C", worktreePath, "update-index", "-q", "--refresh")
+	return nil
+}
+
+// DeleteCheckpoints deletes every checkpoint ref under refPrefix
+func (gitClient *GitClient) DeleteCheckpoints(ctx context.Context, refPrefix string) error {
+	checkpoints, err := gitClient.ListCheckpoints(ctx, refPrefix)
+	if err != nil {
+		return err
+	}
+
+	for _, checkpoint := range checkpoints {
+		if _, err := gitClient.executeGitCommand(ctx, "update-ref", "-d", checkpoint.Ref); err != nil {
+			return fmt.Errorf("failed to delete checkpoint %s: %w", checkpoint.Ref, err)
+		}
+	}
+	return nil
+}
+
+// indexPath returns the absolute path of the worktree's index file
+func (gitClient *GitClient) indexPath(ctx context.Context, worktreePath string) (string, error) {
+	indexFile, err := gitClient.objectName(ctx, worktreePath, "rev-parse", "--git-path", "index")
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the index: %w", err)
+	}
+	if !filepath.IsAbs(indexFile) {
+		indexFile = filepath.Join(worktreePath, indexFile)
+	}
+	return indexFile, nil
+}
+
+// objectName runs a plumbing command in worktreePath that prints a single
+// object name
+func (gitClient *GitClient) objectName(ctx context.Context, worktreePath string, args ...string) (string, error) {
+	commandOutput, err := gitClient.executeGitCommandWithOutput(ctx, append([]string{"-C", worktreePath}, args...)...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(commandOutput)), nil
+}
@@ -0,0 +1,192 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testCheckpointPrefix = "refs/orchestragent/test/checkpoints/"
+
+func TestGitClient_CreateCheckpoint_LeavesWorktreeUntouched(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	os.WriteFile(filepath.Join(setup.worktreePath, "README.md"), []byte("# Edited\n"), 0644)
+	os.WriteFile(filepath.Join(setup.worktreePath, "untracked.txt"), []byte("new\n"), 0644)
+	statusBefore := runGit(t, setup.worktreePath, "status", "--porcelain")
+	headBefore := runGit(t, setup.worktreePath, "rev-parse", "HEAD")
+
+	// act
+	checkpoint, err := setup.gitClient.CreateCheckpoint(setup.ctx, setup.worktreePath, testCheckpointPrefix+"first", "First checkpoint")
+
+	// assert
+	if err != nil {
+		t.Fatalf("CreateCheckpoint() error: %v", err)
+	}
+	if checkpoint.Name != "first" || checkpoint.HeadSHA != headBefore || checkpoint.Message != "First checkpoint" {
+		t.Errorf("checkpoint = %+v, want name first on HEAD %s", checkpoint, headBefore)
+	}
+	if status := runGit(t, setup.worktreePath, "status", "--porcelain"); status != statusBefore {
+		t.Errorf("status = %q, want unchanged %q", status, statusBefore)
+	}
+	if head := runGit(t, setup.worktreePath, "rev-parse", "HEAD"); head != headBefore {
+		t.Error("HEAD should not move when a checkpoint is created")
+	}
+	if content := runGit(t, setup.repositoryRoot, "show", checkpoint.SHA+":untracked.txt"); content != "new" {
+		t.Errorf("checkpoint untracked.txt = %q, want %q", content, "new")
+	}
+}
+
+func TestGitClient_CreateCheckpoint_ExistingName_ReturnsError(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	if _, err := setup.gitClient.CreateCheckpoint(setup.ctx, setup.worktreePath, testCheckpointPrefix+"same", "One"); err != nil {
+		t.Fatalf("CreateCheckpoint() error: %v", err)
+	}
+
+	// act
+	_, err := setup.gitClient.CreateCheckpoint(setup.ctx, setup.worktreePath, testCheckpointPrefix+"same", "Two")
+
+	// assert
+	if err == nil {
+		t.Error("CreateCheckpoint() expected error for an existing checkpoint")
+	}
+}
+
+func TestGitClient_RestoreCheckpoint_RestoresBranchFilesAndIndex(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	headBefore := runGit(t, setup.worktreePath, "rev-parse", "HEAD")
+	os.WriteFile(filepath.Join(setup.worktreePath, "staged.txt"), []byte("staged\n"), 0644)
+	runGit(t, setup.worktreePath, "add", "staged.txt")
+	os.WriteFile(filepath.Join(setup.worktreePath, "README.md"), []byte("# Unstaged\n"), 0644)
+	os.WriteFile(filepath.Join(setup.worktreePath, "untracked.txt"), []byte("untracked\n"), 0644)
+	statusAtCheckpoint := runGit(t, setup.worktreePath, "status", "--porcelain")
+
+	checkpointRef := testCheckpointPrefix + "before-wreck"
+	if _, err := setup.gitClient.CreateCheckpoint(setup.ctx, setup.worktreePath, checkpointRef, "Before wreck"); err != nil {
+		t.Fatalf("CreateCheckpoint() error: %v", err)
+	}
+
+	os.Remove(filepath.Join(setup.worktreePath, "untracked.txt"))
+	os.WriteFile(filepath.Join(setup.worktreePath, "README.md"), []byte("wrecked\n"), 0644)
+	commitFile(t, setup.worktreePath, "wreck.txt", "wreck\n", "Wreck")
+	os.WriteFile(filepath.Join(setup.worktreePath, "junk.txt"), []byte("junk\n"), 0644)
+
+	wreckedHead := runGit(t, setup.worktreePath, "rev-parse", "HEAD")
+
+	// act
+	backup, err := setup.gitClient.RestoreCheckpoint(setup.ctx, setup.worktreePath, checkpointRef, testCheckpointPrefix+"backup")
+
+	// assert
+	if err != nil {
+		t.Fatalf("RestoreCheckpoint() error: %v", err)
+	}
+	if backup.Name != "backup" || backup.HeadSHA != wreckedHead {
+		t.Errorf("backup = %+v, want the state before the restore on HEAD %s", backup, wreckedHead)
+	}
+	if content := runGit(t, setup.repositoryRoot, "show", backup.SHA+":junk.txt"); content != "junk" {
+		t.Errorf("backup junk.txt = %q, want the untracked file saved", content)
+	}
+	if head := runGit(t, setup.worktreePath, "rev-parse", "HEAD"); head != headBefore {
+		t.Errorf("HEAD = %s, want %s", head, headBefore)
+	}
+	if status := runGit(t, setup.worktreePath, "status", "--porcelain"); status != statusAtCheckpoint {
+		t.Errorf("status = %q, want %q", status, statusAtCheckpoint)
+	}
+	if content, _ := os.ReadFile(filepath.Join(setup.worktreePath, "README.md")); string(content) != "# Unstaged\n" {
+		t.Errorf("README.md = %q, want the checkpointed content", content)
+	}
+}
+
+func TestGitClient_RestoreCheckpoint_UnknownRef_ReturnsError(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	// act
+	_, err := setup.gitClient.RestoreCheckpoint(setup.ctx, setup.worktreePath, testCheckpointPrefix+"missing", testCheckpointPrefix+"backup")
+
+	// assert
+	if err == nil {
+		t.Error("RestoreCheckpoint() expected error for an unknown checkpoint")
+	}
+	checkpoints, _ := setup.gitClient.ListCheckpoints(setup.ctx, testCheckpointPrefix)
+	if len(checkpoints) != 0 {
+		t.Errorf("ListCheckpoints() = %+v, want no backup for a restore that never started", checkpoints)
+	}
+}
+
+func TestGitClient_RestoreCheckpoint_FailureAfterChanges_RollsBack(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	checkpointRef := testCheckpointPrefix + "old"
+	checkpoint, err := setup.gitClient.CreateCheckpoint(setup.ctx, setup.worktreePath, checkpointRef, "Old")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint() error: %v", err)
+	}
+	commitFile(t, setup.worktreePath, "later.txt", "later\n", "Later")
+	os.WriteFile(filepath.Join(setup.worktreePath, "README.md"), []byte("# Uncommitted\n"), 0644)
+	headBefore := runGit(t, setup.worktreePath, "rev-parse", "HEAD")
+	statusBefore := runGit(t, setup.worktreePath, "status", "--porcelain")
+
+	// fail the branch move to the checkpoint, which runs after the files changed
+	hook := "#!/bin/sh\n[ \"$1\" = prepared ] && grep -q " + checkpoint.HeadSHA + " && exit 1\nexit 0\n"
+	hookPath := filepath.Join(setup.repositoryRoot, ".git", "hooks", "reference-transaction")
+	if err := os.WriteFile(hookPath, []byte(hook), 0755); err != nil {
+		t.Fatalf("failed to write hook: %v", err)
+	}
+
+	// act
+	_, err = setup.gitClient.RestoreCheckpoint(setup.ctx, setup.worktreePath, checkpointRef, testCheckpointPrefix+"backup")
+
+	// assert
+	if err == nil || !strings.Contains(err.Error(), "rolled back") {
+		t.Fatalf("RestoreCheckpoint() error = %v, want a rolled back failure", err)
+	}
+	if head := runGit(t, setup.worktreePath, "rev-parse", "HEAD"); head != headBefore {
+		t.Errorf("HEAD = %s, want %s", head, headBefore)
+	}
+	if status := runGit(t, setup.worktreePath, "status", "--porcelain"); status != statusBefore {
+		t.Errorf("status = %q, want %q", status, statusBefore)
+	}
+	if content, _ := os.ReadFile(filepath.Join(setup.worktreePath, "README.md")); string(content) != "# Uncommitted\n" {
+		t.Errorf("README.md = %q, want the uncommitted edit back", content)
+	}
+}
+
+func TestGitClient_DeleteCheckpoints_RemovesAllRefs(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	for _, name := range []string{"one", "two"} {
+		if _, err := setup.gitClient.CreateCheckpoint(setup.ctx, setup.worktreePath, testCheckpointPrefix+name, name); err != nil {
+			t.Fatalf("CreateCheckpoint() error: %v", err)
+		}
+	}
+
+	// act
+	err := setup.gitClient.DeleteCheckpoints(setup.ctx, testCheckpointPrefix)
+
+	// assert
+	if err != nil {
+		t.Fatalf("DeleteCheckpoints() error: %v", err)
+	}
+	checkpoints, err := setup.gitClient.ListCheckpoints(setup.ctx, testCheckpointPrefix)
+	if err != nil {
+		t.Fatalf("ListCheckpoints() error: %v", err)
+	}
+	if len(checkpoints) != 0 {
+		t.Errorf("ListCheckpoints() = %d checkpoints, want 0", len(checkpoints))
+	}
+}
@@ -0,0 +1,27 @@
+package git
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// CherryPick applies commits, in order, onto the branch checked out in
+// worktreePath. Each new commit records the commit it was picked from. If
+// any pick stops, the whole sequence is aborted so the branch is left as it
+// was.
+func (gitClient *GitClient) CherryPick(ctx context.Context, worktreePath string, commits []string) error {
+	args := append([]string{"-C", worktreePath, "cherry-pick", "-x", "--end-of-options"}, commits...)
+	if _, err := gitClient.executeGitCommand(ctx, args...); err != nil {
+		pickErr := gitClient.abortOnConflict(ctx, worktreePath, "cherry-pick", err, "cherry-pick", "--abort")
+		var conflictErr *domain.ConflictError
+		if !errors.As(pickErr, &conflictErr) {
+			// a pick can also stop without conflicts, e.g. on a commit whose
+			// changes are already applied, and still leave the sequence open
+			gitClient.executeGitCommand(ctx, "-C", worktreePath, "cherry-pick", "--abort")
+		}
+		return pickErr
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func TestGitClient_CherryPick_AppliesCommitsInOrder(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "first.txt", "first\n", "First change")
+	first := runGit(t, setup.worktreePath, "rev-parse", "HEAD")
+	commitFile(t, setup.worktreePath, "second.txt", "second\n", "Second change")
+	second := runGit(t, setup.worktreePath, "rev-parse", "HEAD")
+
+	targetPath := filepath.Join(setup.repositoryRoot, ".worktrees", "target")
+	if err := setup.gitClient.CreateWorktree(setup.ctx, targetPath, "session-target", "master"); err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+
+	// act
+	err := setup.gitClient.CherryPick(setup.ctx, targetPath, []string{first, second})
+
+	// assert
+	if err != nil {
+		t.Fatalf("CherryPick() error: %v", err)
+	}
+	if subjects := runGit(t, targetPath, "log", "--format=%s", "master..HEAD"); subjects != "Second change\nFirst change" {
+		t.Errorf("picked commits = %q, want both in order", subjects)
+	}
+	if body := runGit(t, targetPath, "log", "--format=%b", "-1"); !strings.Contains(body, second) {
+		t.Errorf("commit body = %q, want a reference to %s", body, second)
+	}
+}
+
+func TestGitClient_CherryPick_ConflictAbortsWholeSequence(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "clean.txt", "clean\n", "Clean change")
+	clean := runGit(t, setup.worktreePath, "rev-parse", "HEAD")
+	commitFile(t, setup.worktreePath, "README.md", "# Source\n", "Conflicting change")
+	conflicting := runGit(t, setup.worktreePath, "rev-parse", "HEAD")
+
+	targetPath := filepath.Join(setup.repositoryRoot, ".worktrees", "target")
+	if err := setup.gitClient.CreateWorktree(setup.ctx, targetPath, "session-target", "master"); err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	commitFile(t, targetPath, "README.md", "# Target\n", "Target change")
+	headBefore := runGit(t, targetPath, "rev-parse", "HEAD")
+
+	// act
+	err := setup.gitClient.CherryPick(setup.ctx, targetPath, []string{clean, conflicting})
+
+	// assert
+	var conflictErr *domain.ConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("CherryPick() error = %v, want ConflictError", err)
+	}
+	if len(conflictErr.Paths) != 1 || conflictErr.Paths[0] != "README.md" {
+		t.Errorf("conflict paths = %v, want [README.md]", conflictErr.Paths)
+	}
+	if headAfter := runGit(t, targetPath, "rev-parse", "HEAD"); headAfter != headBefore {
+		t.Error("target branch should be back where it was after the abort")
+	}
+	if _, err := os.Stat(filepath.Join(targetPath, "clean.txt")); !os.IsNotExist(err) {
+		t.Error("clean.txt from the aborted sequence should not remain")
+	}
+}
@@ -0,0 +1,62 @@
+package git
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// Commit stages options.Paths, or every change when none are given, and
+// commits them on the branch checked out in worktreePath. Only the requested
+// paths are committed even if other changes were already staged. Trailers
+// are appended with git's own trailer handling, so they merge with any the
+// message already carries.
+func (gitClient *GitClient) Commit(ctx context.Context, worktreePath string, options domain.CommitOptions) (*domain.Commit, error) {
+	pathspec := append([]string{"--"}, options.Paths...)
+
+	addArgs := append([]string{"-C", worktreePath, "add", "--all"}, pathspec...)
+	if _, err := gitClient.executeGitCommand(ctx, addArgs...); err != nil {
+		return nil, fmt.Errorf("failed to stage changes: %w", err)
+	}
+
+	diffArgs := append([]string{"-C", worktreePath, "diff", "--cached", "--quiet"}, pathspec...)
+	_, exitCode, err := gitClient.executeGitCommandWithExitCode(ctx, diffArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check staged changes: %w", err)
+	}
+	if exitCode == 0 {
+		return nil, domain.ErrNothingToCommit
+	}
+
+	commitArgs := []string{"-C", worktreePath}
+	if options.Author.Name != "" {
+		commitArgs = append(commitArgs, "-c", "user.name="+options.Author.Name)
+	}
+	if options.Author.Email != "" {
+		commitArgs = append(commitArgs, "-c", "user.email="+options.Author.Email)
+	}
+	commitArgs = append(commitArgs, "commit", "--quiet", "-m", options.Message)
+	for _, trailer := range options.Trailers {
+		commitArgs = append(commitArgs, "--trailer", trailer.Key+": "+trailer.Value)
+	}
+	if len(options.Paths) > 0 {
+		commitArgs = append(commitArgs, pathspec...)
+	}
+	if _, err := gitClient.executeGitCommand(ctx, commitArgs...); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	headSHA, err := gitClient.objectName(ctx, worktreePath, "rev-parse", "--verify", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve new commit: %w", err)
+	}
+	commits, err := gitClient.readCommitLog(ctx, "-1", "--end-of-options", headSHA)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) != 1 {
+		return nil, fmt.Errorf("failed to read back commit %s", headSHA)
+	}
+	return &commits[0], nil
+}
@@ -0,0 +1,118 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+const (
+	commitRecordSeparator = "\x1e"
+	commitFieldSeparator  = "\x1f"
+)
+
+// commitLogFields lists the pretty-format placeholders emitted for every
+// commit, in the order parseCommitRecord reads them back
+var commitLogFields = []string{
+	"%H",  // commit SHA
+	"%P",  // parent SHAs
+	"%an", // author name
+	"%ae", // author email
+	"%aI", // author date, strict ISO 8601
+	"%cn", // committer name
+	"%ce", // committer email
+	"%cI", // committer date, strict ISO 8601
+	"%s",  // subject
+	"%b",  // body
+	"%(trailers:only,unfold)",
+}
+
+// GetCommits returns the commits reachable from sessionBranch but not from
+// baseRef, newest first, each with its per-file line counts
+func (gitClient *GitClient) GetCommits(ctx context.Context, baseRef string, sessionBranch string) ([]domain.Commit, error) {
+	revRange := fmt.Sprintf("%s..%s", baseRef, sessionBranch)
+	return gitClient.readCommitLog(ctx, "--end-of-options", revRange)
+}
+
+// readCommitLog runs git log with the given revision arguments appended and
+// parses every commit it prints
+func (gitClient *GitClient) readCommitLog(ctx context.Context, revisionArgs ...string) ([]domain.Commit, error) {
+	format := commitRecordSeparator + strings.Join(commitLogFields, commitFieldSeparator) + commitFieldSeparator
+	args := []string{"log", "--no-color", "--numstat", "-z", "-M", "--format=" + format}
+
+	commandOutput, err := gitClient.executeGitCommandWithOutput(ctx, append(args, revisionArgs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit log: %w", err)
+	}
+
+	return parseCommitLog(string(commandOutput))
+}
+
+func parseCommitLog(output string) ([]domain.Commit, error) {
+	commits := make([]domain.Commit, 0)
+
+	for _, record := range strings.Split(output, commitRecordSeparator) {
+		if strings.Trim(record, "\x00\n") == "" {
+			continue
+		}
+
+		commit, err := parseCommitRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, commit)
+	}
+
+	return commits, nil
+}
+
+func parseCommitRecord(record string) (domain.Commit, error) {
+	fields := strings.SplitN(record, commitFieldSeparator, len(commitLogFields)+1)
+	if len(fields) != len(commitLogFields)+1 {
+		return domain.Commit{}, fmt.Errorf("malformed commit log record: %q", record)
+	}
+
+	authoredAt, err := time.Parse(time.RFC3339, fields[4])
+	if err != nil {
+		return domain.Commit{}, fmt.Errorf("invalid author date for %s: %w", fields[0], err)
+	}
+	committedAt, err := time.Parse(time.RFC3339, fields[7])
+	if err != nil {
+		return domain.Commit{}, fmt.Errorf("invalid committer date for %s: %w", fields[0], err)
+	}
+
+	return domain.Commit{
+		SHA:            fields[0],
+		ParentSHAs:     strings.Fields(fields[1]),
+		AuthorName:     fields[2],
+		AuthorEmail:    fields[3],
+		AuthoredAt:     authoredAt,
+		CommitterName:  fields[5],
+		CommitterEmail: fields[6],
+		CommittedAt:    committedAt,
+		Subject:        fields[8],
+		Body:           strings.TrimRight(fields[9], "\n"),
+		Trailers:       parseCommitTrailers(fields[10]),
+		Files:          parseDiffNumstatOutput(strings.TrimLeft(fields[11], "\x00\n")),
+	}, nil
+}
+
+func parseCommitTrailers(output string) []domain.CommitTrailer {
+	trailers := make([]domain.CommitTrailer, 0)
+
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found || strings.TrimSpace(key) == "" {
+			continue
+		}
+		trailers = append(trailers, domain.CommitTrailer{
+			Key:   strings.TrimSpace(key),
+			Value: strings.TrimSpace(value),
+		})
+	}
+
+	return trailers
+}
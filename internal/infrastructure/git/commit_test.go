@@ -0,0 +1,94 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func TestGitClient_Commit_AllChanges_CommitsWithTrailersAndAuthor(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	os.WriteFile(filepath.Join(setup.worktreePath, "README.md"), []byte("# Edited\nline\n"), 0644)
+	os.WriteFile(filepath.Join(setup.worktreePath, "new.txt"), []byte("new\n"), 0644)
+
+	options := domain.CommitOptions{
+		Message:  "Update readme",
+		Author:   domain.CommitAuthor{Name: "Session Bot", Email: "bot@example.com"},
+		Trailers: []domain.CommitTrailer{{Key: "Session-Id", Value: "test"}, {Key: "Agent", Value: "tester"}},
+	}
+
+	// act
+	commit, err := setup.gitClient.Commit(setup.ctx, setup.worktreePath, options)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Commit() error: %v", err)
+	}
+	if head := runGit(t, setup.worktreePath, "rev-parse", "HEAD"); commit.SHA != head {
+		t.Errorf("SHA = %s, want HEAD %s", commit.SHA, head)
+	}
+	if commit.AuthorName != "Session Bot" || commit.CommitterEmail != "bot@example.com" {
+		t.Errorf("identity = %s <%s>, want the configured author", commit.AuthorName, commit.CommitterEmail)
+	}
+	if len(commit.Trailers) != 2 || commit.Trailers[0].Key != "Session-Id" || commit.Trailers[1].Value != "tester" {
+		t.Errorf("Trailers = %+v, want Session-Id and Agent", commit.Trailers)
+	}
+	if len(commit.Files) != 2 {
+		t.Errorf("Files = %+v, want README.md and new.txt", commit.Files)
+	}
+	if status := runGit(t, setup.worktreePath, "status", "--porcelain"); status != "" {
+		t.Errorf("status = %q, want a clean worktree", status)
+	}
+}
+
+func TestGitClient_Commit_SelectedPaths_LeavesOtherChanges(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	os.WriteFile(filepath.Join(setup.worktreePath, "selected.txt"), []byte("selected\n"), 0644)
+	os.WriteFile(filepath.Join(setup.worktreePath, "staged.txt"), []byte("staged\n"), 0644)
+	runGit(t, setup.worktreePath, "add", "staged.txt")
+
+	// act
+	commit, err := setup.gitClient.Commit(setup.ctx, setup.worktreePath, domain.CommitOptions{
+		Paths:   []string{"selected.txt"},
+		Message: "Add selected",
+	})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Commit() error: %v", err)
+	}
+	if len(commit.Files) != 1 || commit.Files[0].Path != "selected.txt" || commit.Files[0].LinesAdded != 1 {
+		t.Errorf("Files = %+v, want only selected.txt", commit.Files)
+	}
+	if status := runGit(t, setup.worktreePath, "status", "--porcelain"); status != "A  staged.txt" {
+		t.Errorf("status = %q, want staged.txt still staged", status)
+	}
+}
+
+func TestGitClient_Commit_NoChanges_ReturnsErrNothingToCommit(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	headBefore := runGit(t, setup.worktreePath, "rev-parse", "HEAD")
+
+	// act
+	_, err := setup.gitClient.Commit(setup.ctx, setup.worktreePath, domain.CommitOptions{Message: "Empty"})
+
+	// assert
+	if !errors.Is(err, domain.ErrNothingToCommit) {
+		t.Errorf("Commit() error = %v, want ErrNothingToCommit", err)
+	}
+	if head := runGit(t, setup.worktreePath, "rev-parse", "HEAD"); head != headBefore {
+		t.Error("HEAD should not move without changes")
+	}
+}
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// CompareRefs resolves both refs first so that neither can be mistaken for
+// an option, then diffs the two commits from the repository root
+func (gitClient *GitClient) CompareRefs(ctx context.Context, fromRef string, toRef string, options domain.DiffOptions) (*domain.RefComparison, error) {
+	fromCommit, err := gitClient.ResolveCommit(ctx, fromRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare refs: %w", err)
+	}
+	toCommit, err := gitClient.ResolveCommit(ctx, toRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare refs: %w", err)
+	}
+
+	args := []string{
+		"-c", "core.quotePath=false",
+		"diff", "--no-color", "--no-ext-diff", "-M",
+		fmt.Sprintf("--unified=%d", options.ContextLines),
+		fromCommit, toCommit,
+	}
+	commandOutput, err := gitClient.executeGitCommandWithOutput(ctx, append(args, globPathspecs(options.PathGlobs)...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s with %s: %w", fromRef, toRef, err)
+	}
+
+	files, err := gitClient.diffFileChanges(ctx, gitClient.repositoryRoot, append([]string{fromCommit, toCommit}, globPathspecs(options.PathGlobs)...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count changes between %s and %s: %w", fromRef, toRef, err)
+	}
+
+	return &domain.RefComparison{
+		Diffs: splitUnifiedDiff(string(commandOutput)),
+		Files: files,
+	}, nil
+}
+
+// GetBranchChanges diffs the branch tip against its merge-base with baseRef,
+// so commits that landed on the base afterwards are not attributed to the
+// branch
+func (gitClient *GitClient) GetBranchChanges(ctx context.Context, baseRef string, branchName string, pathGlobs []string) ([]domain.FileChange, error) {
+	branchCommit, err := gitClient.ResolveCommit(ctx, branchName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branch changes: %w", err)
+	}
+	mergeBase, err := gitClient.mergeBase(ctx, gitClient.repositoryRoot, baseRef, branchCommit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branch changes: %w", err)
+	}
+
+	files, err := gitClient.diffFileChanges(ctx, gitClient.repositoryRoot, append([]string{mergeBase, branchCommit}, globPathspecs(pathGlobs)...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changes of %s: %w", branchName, err)
+	}
+	return files, nil
+}
+
+// globPathspecs turns path globs into the pathspec arguments that follow the
+// revisions of a "git diff"
+func globPathspecs(pathGlobs []string) []string {
+	pathspecs := []string{"--"}
+	for _, pathGlob := range pathGlobs {
+		pathspecs = append(pathspecs, ":(glob)"+pathGlob)
+	}
+	return pathspecs
+}
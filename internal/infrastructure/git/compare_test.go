@@ -0,0 +1,68 @@
+package git
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func TestGitClient_CompareRefs_DiffsBranchTipsDirectly(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "shared.txt", "left\n", "Left change")
+	commitFile(t, setup.worktreePath, "left.txt", "only left\n", "Left only")
+
+	otherPath := filepath.Join(setup.repositoryRoot, ".worktrees", "other")
+	if err := setup.gitClient.CreateWorktree(setup.ctx, otherPath, "session-other", "master"); err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	commitFile(t, otherPath, "shared.txt", "right\nsecond\n", "Right change")
+
+	// act
+	comparison, err := setup.gitClient.CompareRefs(setup.ctx, setup.branchName, "session-other", domain.DiffOptions{ContextLines: 3})
+
+	// assert
+	if err != nil {
+		t.Fatalf("CompareRefs() error: %v", err)
+	}
+	if len(comparison.Diffs) != 2 || comparison.Diffs[0].Path != "left.txt" || comparison.Diffs[1].Path != "shared.txt" {
+		t.Fatalf("diffs = %+v, want left.txt and shared.txt", comparison.Diffs)
+	}
+	if !strings.Contains(comparison.Diffs[1].Patch, "-left\n+right\n+second\n") {
+		t.Errorf("shared.txt patch = %q, want left replaced by right", comparison.Diffs[1].Patch)
+	}
+	if len(comparison.Files) != 2 {
+		t.Fatalf("files = %+v, want 2", comparison.Files)
+	}
+	if shared := comparison.Files[1]; shared.LinesAdded != 2 || shared.LinesRemoved != 1 {
+		t.Errorf("shared.txt counts = +%d -%d, want +2 -1", shared.LinesAdded, shared.LinesRemoved)
+	}
+	if left := comparison.Files[0]; left.ChangeType != domain.FileChangeDeleted {
+		t.Errorf("left.txt change type = %s, want deleted", left.ChangeType)
+	}
+}
+
+func TestGitClient_GetBranchChanges_IgnoresBaseMovingOn(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "session.go", "package session\n", "Session change")
+	commitFile(t, setup.worktreePath, "notes.txt", "notes\n", "Session notes")
+	commitFile(t, setup.repositoryRoot, "base.txt", "base\n", "Base change")
+
+	// act
+	files, err := setup.gitClient.GetBranchChanges(setup.ctx, "master", setup.branchName, []string{"*.go"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("GetBranchChanges() error: %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "session.go" {
+		t.Errorf("files = %+v, want only session.go", files)
+	}
+}
@@ -0,0 +1,26 @@
+package git
+
+import (
+	"context"
+	"fmt"
+)
+
+// CopyWorktreeChanges carries the uncommitted and untracked files of
+// sourceWorktreePath over to targetWorktreePath, which must be clean and have
+// the same commit checked out. The source is snapshotted through a temporary
+// index, like a checkpoint, so it is left untouched; the copied changes
+// arrive unstaged in the target.
+func (gitClient *GitClient) CopyWorktreeChanges(ctx context.Context, sourceWorktreePath string, targetWorktreePath string) error {
+	sourceTree, err := gitClient.writeWorktreeTree(ctx, sourceWorktreePath)
+	if err != nil {
+		return err
+	}
+
+	if _, err := gitClient.executeGitCommand(ctx, "-C", targetWorktreePath, "read-tree", "--reset", "-u", sourceTree); err != nil {
+		return fmt.Errorf("failed to copy worktree changes: %w", err)
+	}
+	if _, err := gitClient.executeGitCommand(ctx, "-C", targetWorktreePath, "reset", "--quiet"); err != nil {
+		return fmt.Errorf("failed to unstage copied changes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitClient_CopyWorktreeChanges_CopiesEditsDeletionsAndUntrackedFiles(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "obsolete.txt", "old\n", "Add obsolete file")
+	os.WriteFile(filepath.Join(setup.worktreePath, "README.md"), []byte("# Edited\n"), 0644)
+	os.WriteFile(filepath.Join(setup.worktreePath, "untracked.txt"), []byte("new\n"), 0644)
+	os.Remove(filepath.Join(setup.worktreePath, "obsolete.txt"))
+	sourceStatus := runGit(t, setup.worktreePath, "status", "--porcelain")
+
+	forkPath := filepath.Join(setup.repositoryRoot, ".worktrees", "fork")
+	if err := setup.gitClient.CreateWorktree(setup.ctx, forkPath, "session-fork", setup.branchName); err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+
+	// act
+	err := setup.gitClient.CopyWorktreeChanges(setup.ctx, setup.worktreePath, forkPath)
+
+	// assert
+	if err != nil {
+		t.Fatalf("CopyWorktreeChanges() error: %v", err)
+	}
+	if status := runGit(t, forkPath, "status", "--porcelain"); status != sourceStatus {
+		t.Errorf("fork status = %q, want %q", status, sourceStatus)
+	}
+	if content, _ := os.ReadFile(filepath.Join(forkPath, "untracked.txt")); string(content) != "new\n" {
+		t.Errorf("fork untracked.txt = %q, want %q", content, "new\n")
+	}
+	if status := runGit(t, setup.worktreePath, "status", "--porcelain"); status != sourceStatus {
+		t.Errorf("source status = %q, want unchanged %q", status, sourceStatus)
+	}
+}
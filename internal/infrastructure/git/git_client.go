@@ -1,10 +1,16 @@
 package git
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/tzDel/orchestragent-mcp/internal/domain"
 )
@@ -45,8 +51,67 @@ func (gitClient *GitClient) executeGitCommandWithOutput(ctx context.Context, arg
 	return commandOutput, nil
 }
 
-func (gitClient *GitClient) CreateWorktree(ctx context.Context, worktreePath string, branchName string) error {
-	_, err := gitClient.executeGitCommand(ctx, "worktree", "add", "-b", branchName, worktreePath)
+// executeGitCommandWithExitCode executes a git command and returns stdout
+// together with the exit code, for commands that report results through a
+// non-zero exit status. The error is only set when git could not be run.
+func (gitClient *GitClient) executeGitCommandWithExitCode(ctx context.Context, args ...string) ([]byte, int, error) {
+	gitCommand := exec.CommandContext(ctx, "git", args...)
+	gitCommand.Dir = gitClient.repositoryRoot
+
+	commandOutput, err := gitCommand.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return commandOutput, exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return nil, -1, fmt.Errorf("git command failed: %w", err)
+	}
+
+	return commandOutput, 0, nil
+}
+
+// executeGitCommandWithIndex executes a git command against a separate index
+// file instead of the worktree's own and returns stdout
+func (gitClient *GitClient) executeGitCommandWithIndex(ctx context.Context, indexFile string, args ...string) ([]byte, error) {
+	gitCommand := exec.CommandContext(ctx, "git", args...)
+	gitCommand.Dir = gitClient.repositoryRoot
+	gitCommand.Env = append(os.Environ(), "GIT_INDEX_FILE="+indexFile)
+
+	var stderr bytes.Buffer
+	gitCommand.Stderr = &stderr
+	commandOutput, err := gitCommand.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git command failed: %w (output: %s)", err, stderr.String())
+	}
+
+	return commandOutput, nil
+}
+
+// executeGitCommandWithIndexCombined executes a git command against a
+// separate index file and returns stdout and stderr together, for commands
+// that report what they did on stderr
+func (gitClient *GitClient) executeGitCommandWithIndexCombined(ctx context.Context, indexFile string, args ...string) ([]byte, error) {
+	gitCommand := exec.CommandContext(ctx, "git", args...)
+	gitCommand.Dir = gitClient.repositoryRoot
+	gitCommand.Env = append(os.Environ(), "GIT_INDEX_FILE="+indexFile)
+
+	commandOutput, err := gitCommand.CombinedOutput()
+	if err != nil {
+		return commandOutput, fmt.Errorf("git command failed: %w (output: %s)", err, string(commandOutput))
+	}
+
+	return commandOutput, nil
+}
+
+// CreateWorktree creates a new branch and worktree starting at baseRef, or at
+// the currently checked out HEAD when baseRef is empty
+func (gitClient *GitClient) CreateWorktree(ctx context.Context, worktreePath string, branchName string, baseRef string) error {
+	args := []string{"worktree", "add", "-b", branchName, worktreePath}
+	if baseRef != "" {
+		args = append(args, baseRef)
+	}
+
+	_, err := gitClient.executeGitCommand(ctx, args...)
 	if err != nil {
 		return fmt.Errorf("failed to create worktree: %w", err)
 	}
@@ -77,6 +142,17 @@ func (gitClient *GitClient) BranchExists(ctx context.Context, branchName string)
 	return strings.TrimSpace(string(commandOutput)) != "", nil
 }
 
+// ResolveCommit resolves a branch, tag or commit-ish to the full SHA of the
+// commit it points at
+func (gitClient *GitClient) ResolveCommit(ctx context.Context, ref string) (string, error) {
+	commandOutput, err := gitClient.executeGitCommandWithOutput(ctx, "rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %q to a commit: %w", ref, err)
+	}
+
+	return strings.TrimSpace(string(commandOutput)), nil
+}
+
 func (gitClient *GitClient) HasUncommittedChanges(ctx context.Context, worktreePath string) (bool, int, error) {
 	commandOutput, err := gitClient.executeGitCommandWithOutput(ctx, "-C", worktreePath, "status", "--porcelain")
 	if err != nil {
@@ -109,6 +185,31 @@ func (gitClient *GitClient) HasUnpushedCommits(ctx context.Context, baseBranch s
 	return count, nil
 }
 
+func (gitClient *GitClient) GetDivergence(ctx context.Context, baseRef string, sessionBranch string) (*domain.BranchDivergence, error) {
+	symmetricRange := fmt.Sprintf("%s...%s", baseRef, sessionBranch)
+	commandOutput, err := gitClient.executeGitCommandWithOutput(ctx, "rev-list", "--left-right", "--count", "--end-of-options", symmetricRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count diverging commits: %w", err)
+	}
+
+	divergence := &domain.BranchDivergence{}
+	if _, err := fmt.Sscanf(strings.TrimSpace(string(commandOutput)), "%d\t%d", &divergence.Behind, &divergence.Ahead); err != nil {
+		return nil, fmt.Errorf("failed to parse diverging commit counts: %w", err)
+	}
+
+	commandOutput, err = gitClient.executeGitCommandWithOutput(ctx, "log", "-1", "--format=%cI", "--end-of-options", sessionBranch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last commit time: %w", err)
+	}
+	lastCommitAt, err := time.Parse(time.RFC3339, strings.TrimSpace(string(commandOutput)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse last commit time: %w", err)
+	}
+	divergence.LastCommitAt = lastCommitAt
+
+	return divergence, nil
+}
+
 func (gitClient *GitClient) DeleteBranch(ctx context.Context, branchName string, force bool) error {
 	flag := "-d"
 	if force {
@@ -123,48 +224,277 @@ func (gitClient *GitClient) DeleteBranch(ctx context.Context, branchName string,
 	return nil
 }
 
-func (gitClient *GitClient) GetDiffStats(ctx context.Context, worktreePath string, baseBranch string) (*domain.GitDiffStats, error) {
-	commandOutput, err := gitClient.executeGitCommandWithOutput(ctx, "-C", worktreePath, "diff", "--numstat", baseBranch)
+// GetDiffStats measures the worktree against the merge-base of baseRef and
+// the worktree's HEAD, so commits that land on the base after the session
+// started are not counted as session changes. Untracked files count as added
+// lines, and the total is broken down into committed, staged, unstaged and
+// untracked work.
+func (gitClient *GitClient) GetDiffStats(ctx context.Context, worktreePath string, baseRef string) (*domain.GitDiffStats, error) {
+	mergeBase, err := gitClient.mergeBase(ctx, worktreePath, baseRef, "HEAD")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get diff stats: %w", err)
 	}
 
-	stats := parseDiffNumstatOutput(string(commandOutput))
+	trackedFiles, err := gitClient.diffFileChanges(ctx, worktreePath, mergeBase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff stats: %w", err)
+	}
+	committedFiles, err := gitClient.diffFileChanges(ctx, worktreePath, mergeBase, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get committed diff stats: %w", err)
+	}
+	stagedFiles, err := gitClient.diffFileChanges(ctx, worktreePath, "--cached")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staged diff stats: %w", err)
+	}
+	unstagedFiles, err := gitClient.diffFileChanges(ctx, worktreePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unstaged diff stats: %w", err)
+	}
+	untrackedFiles, err := gitClient.untrackedFileChanges(ctx, worktreePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get untracked diff stats: %w", err)
+	}
+
+	stats := domain.NewGitDiffStats(append(trackedFiles, untrackedFiles...))
+	stats.Breakdown = domain.DiffBreakdown{
+		Committed: domain.NewDiffContribution(committedFiles),
+		Staged:    domain.NewDiffContribution(stagedFiles),
+		Unstaged:  domain.NewDiffContribution(unstagedFiles),
+		Untracked: domain.NewDiffContribution(untrackedFiles),
+	}
 	return stats, nil
 }
 
-func parseDiffNumstatOutput(output string) *domain.GitDiffStats {
-	stats := &domain.GitDiffStats{
-		LinesAdded:   0,
-		LinesRemoved: 0,
+// diffFileChanges runs "git diff" with the given revisions and returns the
+// per-file line counts together with each file's change type
+func (gitClient *GitClient) diffFileChanges(ctx context.Context, worktreePath string, revisions ...string) ([]domain.FileChange, error) {
+	nameStatusArgs := append([]string{"-C", worktreePath, "diff", "--name-status", "-z", "-M"}, revisions...)
+	nameStatusOutput, err := gitClient.executeGitCommandWithOutput(ctx, nameStatusArgs...)
+	if err != nil {
+		return nil, err
 	}
 
-	outputString := strings.TrimSpace(output)
-	if outputString == "" {
-		return stats
+	numstatArgs := append([]string{"-C", worktreePath, "diff", "--numstat", "-z", "-M"}, revisions...)
+	numstatOutput, err := gitClient.executeGitCommandWithOutput(ctx, numstatArgs...)
+	if err != nil {
+		return nil, err
 	}
 
-	lines := strings.Split(outputString, "\n")
-	for _, line := range lines {
-		fields := strings.Fields(line)
-		if len(fields) < 2 {
+	files := parseDiffNumstatOutput(string(numstatOutput))
+	applyChangeTypes(files, parseDiffNameStatusOutput(string(nameStatusOutput)))
+	return files, nil
+}
+
+// untrackedFileChanges lists files that are neither tracked nor ignored and
+// counts their lines the way git would once they are added
+func (gitClient *GitClient) untrackedFileChanges(ctx context.Context, worktreePath string) ([]domain.FileChange, error) {
+	commandOutput, err := gitClient.executeGitCommandWithOutput(ctx, "-C", worktreePath, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]domain.FileChange, 0)
+	for _, path := range strings.Split(string(commandOutput), "\x00") {
+		if path == "" {
+			continue
+		}
+
+		lineCount, binary, err := countFileLines(filepath.Join(worktreePath, filepath.FromSlash(path)))
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, domain.FileChange{
+			Path:       path,
+			ChangeType: domain.FileChangeAdded,
+			LinesAdded: lineCount,
+			Binary:     binary,
+			Untracked:  true,
+		})
+	}
+
+	return files, nil
+}
+
+func (gitClient *GitClient) mergeBase(ctx context.Context, worktreePath string, firstRef string, secondRef string) (string, error) {
+	commandOutput, err := gitClient.executeGitCommandWithOutput(ctx, "-C", worktreePath, "merge-base", "--end-of-options", firstRef, secondRef)
+	if err != nil {
+		return "", fmt.Errorf("failed to find merge-base of %s and %s: %w", firstRef, secondRef, err)
+	}
+
+	return strings.TrimSpace(string(commandOutput)), nil
+}
+
+// parseDiffNumstatOutput parses "git diff --numstat -z" output. Renamed and
+// copied files have an empty path field followed by the old and new paths as
+// separate NUL-terminated fields; binary files report "-" for both counts.
+func parseDiffNumstatOutput(output string) []domain.FileChange {
+	files := make([]domain.FileChange, 0)
+	fields := strings.Split(output, "\x00")
+
+	for index := 0; index < len(fields); index++ {
+		counts := strings.SplitN(fields[index], "\t", 3)
+		if len(counts) < 3 {
 			continue
 		}
 
-		added := 0
-		removed := 0
+		file := domain.FileChange{
+			Path:       counts[2],
+			ChangeType: domain.FileChangeModified,
+		}
+		if counts[2] == "" && index+2 < len(fields) {
+			file.OldPath = fields[index+1]
+			file.Path = fields[index+2]
+			index += 2
+		}
+
+		if counts[0] == "-" && counts[1] == "-" {
+			file.Binary = true
+		} else {
+			file.LinesAdded, _ = strconv.Atoi(counts[0])
+			file.LinesRemoved, _ = strconv.Atoi(counts[1])
+		}
+
+		files = append(files, file)
+	}
+
+	return files
+}
+
+// parseDiffNameStatusOutput parses "git diff --name-status -z" output into a
+// map from each file's new path to its change type
+func parseDiffNameStatusOutput(output string) map[string]domain.FileChangeType {
+	changeTypes := make(map[string]domain.FileChangeType)
+	fields := strings.Split(output, "\x00")
 
-		if fields[0] != "-" {
-			fmt.Sscanf(fields[0], "%d", &added)
+	for index := 0; index+1 < len(fields); index++ {
+		status := fields[index]
+		if status == "" {
+			continue
 		}
 
-		if fields[1] != "-" {
-			fmt.Sscanf(fields[1], "%d", &removed)
+		path := fields[index+1]
+		index++
+		if (status[0] == 'R' || status[0] == 'C') && index+1 < len(fields) {
+			path = fields[index+1]
+			index++
 		}
 
-		stats.LinesAdded += added
-		stats.LinesRemoved += removed
+		changeTypes[path] = changeTypeFromStatus(status[0])
 	}
 
-	return stats
+	return changeTypes
+}
+
+func changeTypeFromStatus(status byte) domain.FileChangeType {
+	switch status {
+	case 'A':
+		return domain.FileChangeAdded
+	case 'D':
+		return domain.FileChangeDeleted
+	case 'R':
+		return domain.FileChangeRenamed
+	case 'C':
+		return domain.FileChangeCopied
+	case 'T':
+		return domain.FileChangeTypeChanged
+	default:
+		return domain.FileChangeModified
+	}
+}
+
+func applyChangeTypes(files []domain.FileChange, changeTypes map[string]domain.FileChangeType) {
+	for index := range files {
+		if changeType, exists := changeTypes[files[index].Path]; exists {
+			files[index].ChangeType = changeType
+		}
+	}
+}
+
+// GetDiff returns the unified diff of the worktree against the merge-base of
+// baseRef and HEAD, split into one entry per file
+func (gitClient *GitClient) GetDiff(ctx context.Context, worktreePath string, baseRef string, options domain.DiffOptions) ([]domain.FileDiff, error) {
+	mergeBase, err := gitClient.mergeBase(ctx, worktreePath, baseRef, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff: %w", err)
+	}
+
+	args := []string{
+		"-C", worktreePath,
+		"-c", "core.quotePath=false",
+		"diff", "--no-color", "--no-ext-diff",
+		fmt.Sprintf("--unified=%d", options.ContextLines),
+		mergeBase,
+	}
+
+	commandOutput, err := gitClient.executeGitCommandWithOutput(ctx, append(args, globPathspecs(options.PathGlobs)...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff: %w", err)
+	}
+
+	return splitUnifiedDiff(string(commandOutput)), nil
+}
+
+const diffHeaderPrefix = "diff --git "
+
+func splitUnifiedDiff(output string) []domain.FileDiff {
+	fileDiffs := make([]domain.FileDiff, 0)
+	if output == "" {
+		return fileDiffs
+	}
+
+	lines := strings.SplitAfter(output, "\n")
+	var current *strings.Builder
+	var currentPath string
+
+	flush := func() {
+		if current != nil {
+			fileDiffs = append(fileDiffs, domain.FileDiff{Path: currentPath, Patch: current.String()})
+		}
+	}
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, diffHeaderPrefix) {
+			flush()
+			current = &strings.Builder{}
+			currentPath = parseDiffHeaderPath(strings.TrimRight(line, "\n"))
+		} else if current != nil && strings.HasPrefix(line, "rename to ") {
+			currentPath = strings.TrimRight(strings.TrimPrefix(line, "rename to "), "\n")
+		}
+
+		if current != nil {
+			current.WriteString(line)
+		}
+	}
+	flush()
+
+	return fileDiffs
+}
+
+// parseDiffHeaderPath extracts the post-image path from a "diff --git a/x b/x"
+// header. Both sides are identical unless the file was renamed, in which case
+// the caller picks up the "rename to" line instead.
+func parseDiffHeaderPath(header string) string {
+	paths := strings.TrimPrefix(header, diffHeaderPrefix)
+
+	if strings.HasPrefix(paths, `"`) {
+		if separator := strings.Index(paths, `" `); separator >= 0 {
+			return strings.TrimPrefix(unquoteGitPath(paths[separator+2:]), "b/")
+		}
+	}
+
+	pathLength := (len(paths) - len("a/ b/")) / 2
+	if pathLength > 0 && len(paths) >= 2+pathLength {
+		return paths[2 : 2+pathLength]
+	}
+
+	return paths
+}
+
+func unquoteGitPath(path string) string {
+	if unquoted, err := strconv.Unquote(path); err == nil {
+		return unquoted
+	}
+	return path
 }
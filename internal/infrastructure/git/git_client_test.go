@@ -5,7 +5,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
 )
 
 func setupTestRepo(t *testing.T) (string, func()) {
@@ -51,6 +55,29 @@ func setupTestRepo(t *testing.T) (string, func()) {
 	return temporaryDirectory, cleanup
 }
 
+func commitFile(t *testing.T, directory string, fileName string, content string, message string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(directory, fileName)), 0755); err != nil {
+		t.Fatalf("Failed to create directory for %s: %v", fileName, err)
+	}
+	if err := os.WriteFile(filepath.Join(directory, fileName), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", fileName, err)
+	}
+
+	gitAddCommand := exec.Command("git", "add", fileName)
+	gitAddCommand.Dir = directory
+	if output, err := gitAddCommand.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to add %s: %v (%s)", fileName, err, output)
+	}
+
+	gitCommitCommand := exec.Command("git", "commit", "-m", message)
+	gitCommitCommand.Dir = directory
+	if output, err := gitCommitCommand.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to commit %s: %v (%s)", fileName, err, output)
+	}
+}
+
 type testRepoWithWorktree struct {
 	repositoryRoot string
 	gitClient      *GitClient
@@ -69,7 +96,7 @@ func setupTestRepoWithWorktree(t *testing.T) *testRepoWithWorktree {
 	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "test-session")
 	branchName := "session-test"
 
-	if err := gitClient.CreateWorktree(ctx, worktreePath, branchName); err != nil {
+	if err := gitClient.CreateWorktree(ctx, worktreePath, branchName, ""); err != nil {
 		cleanup()
 		t.Fatalf("Failed to create worktree: %v", err)
 	}
@@ -95,7 +122,7 @@ func TestGitClient_CreateWorktree(t *testing.T) {
 	branchName := "session-test"
 
 	// act
-	err := gitClient.CreateWorktree(ctx, worktreePath, branchName)
+	err := gitClient.CreateWorktree(ctx, worktreePath, branchName, "")
 
 	// assert
 	if err != nil {
@@ -115,6 +142,78 @@ func TestGitClient_CreateWorktree(t *testing.T) {
 	}
 }
 
+func TestGitClient_CreateWorktree_FromBaseRef(t *testing.T) {
+	// arrange
+	repositoryRoot, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	gitClient := NewGitClient(repositoryRoot)
+	ctx := context.Background()
+
+	tagCommand := exec.Command("git", "tag", "v1.0.0")
+	tagCommand.Dir = repositoryRoot
+	if err := tagCommand.Run(); err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+	taggedCommit, _ := gitClient.ResolveCommit(ctx, "v1.0.0")
+
+	os.WriteFile(filepath.Join(repositoryRoot, "later.txt"), []byte("later"), 0644)
+	addCommand := exec.Command("git", "add", "later.txt")
+	addCommand.Dir = repositoryRoot
+	addCommand.Run()
+	commitCommand := exec.Command("git", "commit", "-m", "Later commit")
+	commitCommand.Dir = repositoryRoot
+	commitCommand.Run()
+
+	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "hotfix")
+
+	// act
+	err := gitClient.CreateWorktree(ctx, worktreePath, "session-hotfix", "v1.0.0")
+
+	// assert
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	branchCommit, err := gitClient.ResolveCommit(ctx, "session-hotfix")
+	if err != nil {
+		t.Fatalf("ResolveCommit() error: %v", err)
+	}
+	if branchCommit != taggedCommit {
+		t.Errorf("branch starts at %s, want tagged commit %s", branchCommit, taggedCommit)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "later.txt")); !os.IsNotExist(err) {
+		t.Error("worktree should not contain files committed after the base ref")
+	}
+}
+
+func TestGitClient_ResolveCommit(t *testing.T) {
+	// arrange
+	repositoryRoot, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	gitClient := NewGitClient(repositoryRoot)
+	ctx := context.Background()
+
+	// act
+	commitSHA, err := gitClient.ResolveCommit(ctx, "master")
+
+	// assert
+	if err != nil {
+		t.Fatalf("ResolveCommit() error: %v", err)
+	}
+	if len(commitSHA) != 40 {
+		t.Errorf("ResolveCommit() = %q, want a 40 character SHA", commitSHA)
+	}
+
+	// act
+	_, err = gitClient.ResolveCommit(ctx, "does-not-exist")
+
+	// assert
+	if err == nil {
+		t.Error("ResolveCommit() expected error for unknown ref")
+	}
+}
+
 func TestGitClient_RemoveWorktree(t *testing.T) {
 	// arrange
 	setup := setupTestRepoWithWorktree(t)
@@ -176,7 +275,7 @@ func TestGitClient_BranchExists(t *testing.T) {
 	// arrange
 	worktreePath := filepath.Join(repositoryRoot, ".worktrees", "test-session")
 	branchName := "session-test"
-	gitClient.CreateWorktree(ctx, worktreePath, branchName)
+	gitClient.CreateWorktree(ctx, worktreePath, branchName, "")
 
 	// act
 	exists, err = gitClient.BranchExists(ctx, branchName)
@@ -301,6 +400,30 @@ func TestGitClient_HasUnpushedCommits_WithUnpushedCommits(t *testing.T) {
 	}
 }
 
+func TestGitClient_GetDivergence_CountsAheadAndBehind(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "feature.txt", "feature\n", "Add feature")
+	commitFile(t, setup.repositoryRoot, "upstream-1.txt", "one\n", "Upstream one")
+	commitFile(t, setup.repositoryRoot, "upstream-2.txt", "two\n", "Upstream two")
+
+	// act
+	divergence, err := setup.gitClient.GetDivergence(setup.ctx, "master", setup.branchName)
+
+	// assert
+	if err != nil {
+		t.Fatalf("GetDivergence() error: %v", err)
+	}
+	if divergence.Ahead != 1 || divergence.Behind != 2 {
+		t.Errorf("GetDivergence() = ahead %d behind %d, want ahead 1 behind 2", divergence.Ahead, divergence.Behind)
+	}
+	if divergence.LastCommitAt.IsZero() || time.Since(divergence.LastCommitAt) > time.Hour {
+		t.Errorf("LastCommitAt = %v, want the recent session commit", divergence.LastCommitAt)
+	}
+}
+
 func TestGitClient_DeleteBranch_SafeDelete(t *testing.T) {
 	// arrange
 	setup := setupTestRepoWithWorktree(t)
@@ -435,3 +558,313 @@ func TestGitClient_GetDiffStats_WithModifications(t *testing.T) {
 		t.Error("GetDiffStats() LinesRemoved should be > 0 for modifications")
 	}
 }
+
+func TestGitClient_GetDiffStats_IgnoresCommitsAddedToBaseAfterSessionStart(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "session.txt", "session line\n", "Session work")
+	commitFile(t, setup.repositoryRoot, "upstream.txt", "upstream 1\nupstream 2\nupstream 3\n", "Upstream work")
+
+	// act
+	stats, err := setup.gitClient.GetDiffStats(setup.ctx, setup.worktreePath, "master")
+
+	// assert
+	if err != nil {
+		t.Fatalf("GetDiffStats() error: %v", err)
+	}
+	if stats.LinesAdded != 1 {
+		t.Errorf("GetDiffStats() LinesAdded = %d, want 1 (upstream lines must not be counted)", stats.LinesAdded)
+	}
+	if stats.LinesRemoved != 0 {
+		t.Errorf("GetDiffStats() LinesRemoved = %d, want 0", stats.LinesRemoved)
+	}
+}
+
+func TestGitClient_GetDiff_SplitsFilesAndFiltersByGlob(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "internal/app/service.go", "package app\n", "Add service")
+	commitFile(t, setup.worktreePath, "docs/notes with space.md", "notes\n", "Add notes")
+
+	// act
+	allFiles, err := setup.gitClient.GetDiff(setup.ctx, setup.worktreePath, "master", domain.DiffOptions{ContextLines: 3})
+	if err != nil {
+		t.Fatalf("GetDiff() error: %v", err)
+	}
+	goFiles, err := setup.gitClient.GetDiff(setup.ctx, setup.worktreePath, "master", domain.DiffOptions{PathGlobs: []string{"internal/**/*.go"}, ContextLines: 3})
+	if err != nil {
+		t.Fatalf("GetDiff() with glob error: %v", err)
+	}
+
+	// assert
+	if len(allFiles) != 2 {
+		t.Fatalf("GetDiff() returned %d files, want 2", len(allFiles))
+	}
+	if allFiles[0].Path != "docs/notes with space.md" {
+		t.Errorf("first path = %q, want %q", allFiles[0].Path, "docs/notes with space.md")
+	}
+	if len(goFiles) != 1 || goFiles[0].Path != "internal/app/service.go" {
+		t.Fatalf("GetDiff() with glob = %+v, want only internal/app/service.go", goFiles)
+	}
+	if !strings.Contains(goFiles[0].Patch, "+package app") {
+		t.Errorf("patch %q should contain the added line", goFiles[0].Patch)
+	}
+}
+
+func TestGitClient_GetDiff_IncludesUncommittedChanges(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	os.WriteFile(filepath.Join(setup.worktreePath, "README.md"), []byte("# Changed\n"), 0644)
+
+	// act
+	fileDiffs, err := setup.gitClient.GetDiff(setup.ctx, setup.worktreePath, "master", domain.DiffOptions{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("GetDiff() error: %v", err)
+	}
+	if len(fileDiffs) != 1 || fileDiffs[0].Path != "README.md" {
+		t.Fatalf("GetDiff() = %+v, want README.md", fileDiffs)
+	}
+	if !strings.Contains(fileDiffs[0].Patch, "+# Changed") {
+		t.Errorf("patch %q should contain the uncommitted change", fileDiffs[0].Patch)
+	}
+}
+
+func TestParseDiffHeaderPath(t *testing.T) {
+	testCases := []struct {
+		header string
+		want   string
+	}{
+		{`diff --git a/main.go b/main.go`, "main.go"},
+		{`diff --git a/dir/a b/c.txt b/dir/a b/c.txt`, "dir/a b/c.txt"},
+		{`diff --git "a/tab\there.txt" "b/tab\there.txt"`, "tab\there.txt"},
+	}
+
+	for _, testCase := range testCases {
+		// act
+		got := parseDiffHeaderPath(testCase.header)
+
+		// assert
+		if got != testCase.want {
+			t.Errorf("parseDiffHeaderPath(%q) = %q, want %q", testCase.header, got, testCase.want)
+		}
+	}
+}
+
+func TestGitClient_GetDiffStats_ReportsPerFileChanges(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.repositoryRoot, "docs/guide.md", "one\ntwo\nthree\nfour\nfive\n", "Add guide")
+	gitMergeCommand := exec.Command("git", "merge", "--ff-only", "master")
+	gitMergeCommand.Dir = setup.worktreePath
+	if output, err := gitMergeCommand.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to fast-forward session: %v (%s)", err, output)
+	}
+
+	gitMoveCommand := exec.Command("git", "mv", "docs/guide.md", "docs/manual.md")
+	gitMoveCommand.Dir = setup.worktreePath
+	if output, err := gitMoveCommand.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to rename file: %v (%s)", err, output)
+	}
+	commitFile(t, setup.worktreePath, "logo.bin", "\x00\x01\x02binary", "Add binary")
+	os.Remove(filepath.Join(setup.worktreePath, "README.md"))
+
+	// act
+	stats, err := setup.gitClient.GetDiffStats(setup.ctx, setup.worktreePath, "HEAD~1")
+
+	// assert
+	if err != nil {
+		t.Fatalf("GetDiffStats() error: %v", err)
+	}
+	if stats.FilesChanged != 3 {
+		t.Fatalf("GetDiffStats() FilesChanged = %d, want 3 (%+v)", stats.FilesChanged, stats.Files)
+	}
+
+	filesByPath := make(map[string]domain.FileChange)
+	for _, file := range stats.Files {
+		filesByPath[file.Path] = file
+	}
+
+	renamed := filesByPath["docs/manual.md"]
+	if renamed.ChangeType != domain.FileChangeRenamed || renamed.OldPath != "docs/guide.md" {
+		t.Errorf("renamed file = %+v, want rename from docs/guide.md", renamed)
+	}
+	binary := filesByPath["logo.bin"]
+	if !binary.Binary || binary.ChangeType != domain.FileChangeAdded {
+		t.Errorf("binary file = %+v, want added binary", binary)
+	}
+	deleted := filesByPath["README.md"]
+	if deleted.ChangeType != domain.FileChangeDeleted || deleted.LinesRemoved != 1 {
+		t.Errorf("deleted file = %+v, want deleted with 1 line removed", deleted)
+	}
+}
+
+func TestParseDiffNumstatOutput(t *testing.T) {
+	// arrange
+	output := "3\t1\tmain.go\x00-\t-\timage.png\x000\t0\t\x00old name.txt\x00new name.txt\x00"
+
+	// act
+	files := parseDiffNumstatOutput(output)
+
+	// assert
+	if len(files) != 3 {
+		t.Fatalf("parseDiffNumstatOutput() returned %d files, want 3", len(files))
+	}
+	if files[0].Path != "main.go" || files[0].LinesAdded != 3 || files[0].LinesRemoved != 1 {
+		t.Errorf("files[0] = %+v, want main.go +3 -1", files[0])
+	}
+	if !files[1].Binary {
+		t.Errorf("files[1] = %+v, want binary", files[1])
+	}
+	if files[2].Path != "new name.txt" || files[2].OldPath != "old name.txt" {
+		t.Errorf("files[2] = %+v, want rename from 'old name.txt' to 'new name.txt'", files[2])
+	}
+}
+
+func TestGitClient_GetDiffStats_BreaksDownCommittedStagedUnstagedAndUntracked(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "committed.txt", "a\nb\n", "Add committed file")
+
+	os.WriteFile(filepath.Join(setup.worktreePath, "staged.txt"), []byte("staged\n"), 0644)
+	gitAddCommand := exec.Command("git", "add", "staged.txt")
+	gitAddCommand.Dir = setup.worktreePath
+	if output, err := gitAddCommand.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to stage file: %v (%s)", err, output)
+	}
+
+	os.WriteFile(filepath.Join(setup.worktreePath, "README.md"), []byte("# Test Repo\nunstaged line\n"), 0644)
+	os.WriteFile(filepath.Join(setup.worktreePath, "untracked.txt"), []byte("one\ntwo\nthree"), 0644)
+	os.WriteFile(filepath.Join(setup.worktreePath, "untracked.bin"), []byte("\x00\x01"), 0644)
+
+	// act
+	stats, err := setup.gitClient.GetDiffStats(setup.ctx, setup.worktreePath, "master")
+
+	// assert
+	if err != nil {
+		t.Fatalf("GetDiffStats() error: %v", err)
+	}
+	breakdown := stats.Breakdown
+	if breakdown.Committed.LinesAdded != 2 || breakdown.Committed.FilesChanged != 1 {
+		t.Errorf("Committed = %+v, want 2 lines in 1 file", breakdown.Committed)
+	}
+	if breakdown.Staged.LinesAdded != 1 || breakdown.Staged.FilesChanged != 1 {
+		t.Errorf("Staged = %+v, want 1 line in 1 file", breakdown.Staged)
+	}
+	if breakdown.Unstaged.LinesAdded != 2 || breakdown.Unstaged.LinesRemoved != 1 {
+		t.Errorf("Unstaged = %+v, want +2 -1", breakdown.Unstaged)
+	}
+	if breakdown.Untracked.LinesAdded != 3 || breakdown.Untracked.FilesChanged != 2 {
+		t.Errorf("Untracked = %+v, want 3 lines in 2 files", breakdown.Untracked)
+	}
+	if stats.LinesAdded != 8 || stats.LinesRemoved != 1 {
+		t.Errorf("total = +%d -%d, want +8 -1", stats.LinesAdded, stats.LinesRemoved)
+	}
+	if stats.FilesChanged != 5 {
+		t.Errorf("FilesChanged = %d, want 5", stats.FilesChanged)
+	}
+}
+
+func TestCountFileLines(t *testing.T) {
+	// arrange
+	directory := t.TempDir()
+	testCases := []struct {
+		name       string
+		content    string
+		wantLines  int
+		wantBinary bool
+	}{
+		{"empty.txt", "", 0, false},
+		{"trailing-newline.txt", "a\nb\n", 2, false},
+		{"no-trailing-newline.txt", "a\nb", 2, false},
+		{"binary.bin", "text\x00more", 0, true},
+	}
+
+	for _, testCase := range testCases {
+		path := filepath.Join(directory, testCase.name)
+		os.WriteFile(path, []byte(testCase.content), 0644)
+
+		// act
+		lines, binary, err := countFileLines(path)
+
+		// assert
+		if err != nil {
+			t.Fatalf("countFileLines(%s) error: %v", testCase.name, err)
+		}
+		if lines != testCase.wantLines || binary != testCase.wantBinary {
+			t.Errorf("countFileLines(%s) = (%d, %v), want (%d, %v)", testCase.name, lines, binary, testCase.wantLines, testCase.wantBinary)
+		}
+	}
+}
+
+func TestGitClient_GetCommits_ReturnsSessionCommitsWithDetails(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "first.txt", "one\n", "Add first file")
+	commitFile(t, setup.worktreePath, "second.txt", "a\nb\n", "Add second file\n\nLonger explanation.\n\nSession-Id: test-session")
+	commitFile(t, setup.repositoryRoot, "upstream.txt", "upstream\n", "Upstream work")
+
+	// act
+	commits, err := setup.gitClient.GetCommits(setup.ctx, "master", setup.branchName)
+
+	// assert
+	if err != nil {
+		t.Fatalf("GetCommits() error: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("GetCommits() returned %d commits, want 2", len(commits))
+	}
+
+	latest := commits[0]
+	if latest.Subject != "Add second file" {
+		t.Errorf("Subject = %q, want %q", latest.Subject, "Add second file")
+	}
+	if !strings.HasPrefix(latest.Body, "Longer explanation.") {
+		t.Errorf("Body = %q, want it to start with the explanation", latest.Body)
+	}
+	if len(latest.Trailers) != 1 || latest.Trailers[0].Key != "Session-Id" || latest.Trailers[0].Value != "test-session" {
+		t.Errorf("Trailers = %+v, want Session-Id: test-session", latest.Trailers)
+	}
+	if latest.AuthorEmail != "test@example.com" {
+		t.Errorf("AuthorEmail = %q, want %q", latest.AuthorEmail, "test@example.com")
+	}
+	if latest.AuthoredAt.IsZero() {
+		t.Error("AuthoredAt should be set")
+	}
+	if len(latest.ParentSHAs) != 1 || latest.ParentSHAs[0] != commits[1].SHA {
+		t.Errorf("ParentSHAs = %v, want [%s]", latest.ParentSHAs, commits[1].SHA)
+	}
+	if len(latest.Files) != 1 || latest.Files[0].Path != "second.txt" || latest.Files[0].LinesAdded != 2 {
+		t.Errorf("Files = %+v, want second.txt +2", latest.Files)
+	}
+}
+
+func TestGitClient_GetCommits_NoCommits(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	// act
+	commits, err := setup.gitClient.GetCommits(setup.ctx, "master", setup.branchName)
+
+	// assert
+	if err != nil {
+		t.Fatalf("GetCommits() error: %v", err)
+	}
+	if len(commits) != 0 {
+		t.Errorf("GetCommits() returned %d commits, want 0", len(commits))
+	}
+}
@@ -0,0 +1,67 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// binaryDetectionBytes mirrors git's heuristic: a file is binary when a NUL
+// byte appears within its first 8000 bytes
+const binaryDetectionBytes = 8000
+
+// countFileLines counts lines the way "git diff --numstat" would for a newly
+// added file: a trailing line without a newline still counts. Symlinks count
+// as a single line holding the link target.
+func countFileLines(path string) (int, bool, error) {
+	fileInfo, err := os.Lstat(path)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if fileInfo.Mode()&os.ModeSymlink != 0 {
+		return 1, false, nil
+	}
+	if !fileInfo.Mode().IsRegular() {
+		return 0, false, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, 32*1024)
+	head, err := reader.Peek(binaryDetectionBytes)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return 0, false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return 0, true, nil
+	}
+
+	lineCount := 0
+	endsWithNewline := true
+	buffer := make([]byte, 32*1024)
+	for {
+		readCount, err := reader.Read(buffer)
+		if readCount > 0 {
+			lineCount += bytes.Count(buffer[:readCount], []byte{'\n'})
+			endsWithNewline = buffer[readCount-1] == '\n'
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+
+	if !endsWithNewline {
+		lineCount++
+	}
+	return lineCount, false, nil
+}
@@ -0,0 +1,230 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// Merge integrates sessionBranch into baseBranch using the requested strategy.
+// The work happens in the worktree that already has baseBranch checked out,
+// or in a temporary worktree when none does. Conflicts are rolled back and
+// reported as a *domain.ConflictError.
+func (gitClient *GitClient) Merge(ctx context.Context, baseBranch string, sessionBranch string, sessionWorktreePath string, options domain.MergeOptions) (string, error) {
+	targetWorktree, release, err := gitClient.checkoutBranchForUpdate(ctx, baseBranch)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	switch options.Strategy {
+	case domain.MergeStrategyMerge:
+		err = gitClient.mergeCommit(ctx, targetWorktree, sessionBranch, options.Message)
+	case domain.MergeStrategySquash:
+		err = gitClient.squashMerge(ctx, targetWorktree, sessionBranch, options.Message)
+	case domain.MergeStrategyRebase:
+		err = gitClient.rebaseAndFastForward(ctx, targetWorktree, baseBranch, sessionBranch, sessionWorktreePath)
+	case domain.MergeStrategyFastForwardOnly:
+		err = gitClient.fastForward(ctx, targetWorktree, sessionBranch)
+	default:
+		err = fmt.Errorf("unsupported merge strategy %q", options.Strategy)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	commandOutput, err := gitClient.executeGitCommandWithOutput(ctx, "-C", targetWorktree, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to read merged head: %w", err)
+	}
+	return strings.TrimSpace(string(commandOutput)), nil
+}
+
+// Sync updates the branch checked out in worktreePath with upstream, in
+// place. Conflicts are rolled back and reported as a *domain.ConflictError.
+func (gitClient *GitClient) Sync(ctx context.Context, worktreePath string, upstream string, options domain.MergeOptions) error {
+	switch options.Strategy {
+	case domain.MergeStrategyRebase:
+		if _, err := gitClient.executeGitCommand(ctx, "-C", worktreePath, "rebase", "--end-of-options", upstream); err != nil {
+			return gitClient.abortOnConflict(ctx, worktreePath, "rebase", err, "rebase", "--abort")
+		}
+		return nil
+	case domain.MergeStrategyMerge:
+		args := []string{"-C", worktreePath, "merge", "--no-edit"}
+		if options.Message != "" {
+			args = append(args, "-m", options.Message)
+		}
+		args = append(args, "--end-of-options", upstream)
+		if _, err := gitClient.executeGitCommand(ctx, args...); err != nil {
+			return gitClient.abortOnConflict(ctx, worktreePath, "merge", err, "merge", "--abort")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported sync strategy %q", options.Strategy)
+	}
+}
+
+// RebaseOnto moves the commits after upstream onto newBase. Unlike Sync it
+// does not look for a merge base, so commits of a parent branch that was
+// rewritten or squashed are left behind rather than replayed.
+func (gitClient *GitClient) RebaseOnto(ctx context.Context, worktreePath string, newBase string, upstream string) error {
+	if _, err := gitClient.executeGitCommand(ctx, "-C", worktreePath, "rebase", "--onto", newBase, "--end-of-options", upstream); err != nil {
+		return gitClient.abortOnConflict(ctx, worktreePath, "rebase", err, "rebase", "--abort")
+	}
+	return nil
+}
+
+func (gitClient *GitClient) mergeCommit(ctx context.Context, targetWorktree string, sessionBranch string, message string) error {
+	args := []string{"-C", targetWorktree, "merge", "--no-ff", "--no-edit"}
+	if message != "" {
+		args = append(args, "-m", message)
+	}
+	args = append(args, "--end-of-options", sessionBranch)
+
+	if _, err := gitClient.executeGitCommand(ctx, args...); err != nil {
+		return gitClient.abortOnConflict(ctx, targetWorktree, "merge", err, "merge", "--abort")
+	}
+	return nil
+}
+
+func (gitClient *GitClient) squashMerge(ctx context.Context, targetWorktree string, sessionBranch string, message string) error {
+	if _, err := gitClient.executeGitCommand(ctx, "-C", targetWorktree, "merge", "--squash", "--end-of-options", sessionBranch); err != nil {
+		return gitClient.abortOnConflict(ctx, targetWorktree, "squash merge", err, "reset", "--merge")
+	}
+
+	hasStagedChanges, err := gitClient.hasStagedChanges(ctx, targetWorktree)
+	if err != nil {
+		return err
+	}
+	if !hasStagedChanges {
+		return nil
+	}
+
+	args := []string{"-C", targetWorktree, "commit", "--no-verify"}
+	if message != "" {
+		args = append(args, "-m", message)
+	} else {
+		args = append(args, "--no-edit")
+	}
+	if _, err := gitClient.executeGitCommand(ctx, args...); err != nil {
+		gitClient.executeGitCommand(ctx, "-C", targetWorktree, "reset", "--merge")
+		return fmt.Errorf("failed to commit squash merge: %w", err)
+	}
+	return nil
+}
+
+func (gitClient *GitClient) rebaseAndFastForward(ctx context.Context, targetWorktree string, baseBranch string, sessionBranch string, sessionWorktreePath string) error {
+	if _, err := gitClient.executeGitCommand(ctx, "-C", sessionWorktreePath, "rebase", "--end-of-options", baseBranch); err != nil {
+		return gitClient.abortOnConflict(ctx, sessionWorktreePath, "rebase", err, "rebase", "--abort")
+	}
+	return gitClient.fastForward(ctx, targetWorktree, sessionBranch)
+}
+
+func (gitClient *GitClient) fastForward(ctx context.Context, targetWorktree string, sessionBranch string) error {
+	if _, err := gitClient.executeGitCommand(ctx, "-C", targetWorktree, "merge", "--ff-only", "--end-of-options", sessionBranch); err != nil {
+		return fmt.Errorf("cannot fast-forward to %s; the base has diverged: %w", sessionBranch, err)
+	}
+	return nil
+}
+
+// abortOnConflict rolls back a failed operation. When the failure left
+// unmerged paths behind it returns a *domain.ConflictError naming them,
+// otherwise it returns the original error.
+func (gitClient *GitClient) abortOnConflict(ctx context.Context, worktreePath string, operation string, operationErr error, abortArgs ...string) error {
+	conflictedPaths, err := gitClient.conflictedPaths(ctx, worktreePath)
+	if err != nil || len(conflictedPaths) == 0 {
+		return fmt.Errorf("%s failed: %w", operation, operationErr)
+	}
+
+	if _, err := gitClient.executeGitCommand(ctx, append([]string{"-C", worktreePath}, abortArgs...)...); err != nil {
+		return fmt.Errorf("%s stopped on conflicts and could not be aborted: %w", operation, err)
+	}
+
+	return &domain.ConflictError{Operation: operation, Paths: conflictedPaths}
+}
+
+func (gitClient *GitClient) conflictedPaths(ctx context.Context, worktreePath string) ([]string, error) {
+	commandOutput, err := gitClient.executeGitCommandWithOutput(ctx, "-C", worktreePath, "diff", "--name-only", "--diff-filter=U", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0)
+	for _, path := range strings.Split(string(commandOutput), "\x00") {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+func (gitClient *GitClient) hasStagedChanges(ctx context.Context, worktreePath string) (bool, error) {
+	_, exitCode, err := gitClient.executeGitCommandWithExitCode(ctx, "-C", worktreePath, "diff", "--cached", "--quiet")
+	if err != nil {
+		return false, fmt.Errorf("failed to check staged changes: %w", err)
+	}
+	if exitCode > 1 {
+		return false, fmt.Errorf("failed to check staged changes: git diff exited with status %d", exitCode)
+	}
+	return exitCode == 1, nil
+}
+
+// checkoutBranchForUpdate returns a worktree with branchName checked out. An
+// existing checkout is reused when it has no uncommitted changes to tracked
+// files; otherwise a temporary worktree is created and removed on release.
+func (gitClient *GitClient) checkoutBranchForUpdate(ctx context.Context, branchName string) (string, func(), error) {
+	existingWorktree, err := gitClient.findWorktreeForBranch(ctx, branchName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if existingWorktree != "" {
+		commandOutput, err := gitClient.executeGitCommandWithOutput(ctx, "-C", existingWorktree, "status", "--porcelain", "--untracked-files=no")
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to check status of %s: %w", existingWorktree, err)
+		}
+		if strings.TrimSpace(string(commandOutput)) != "" {
+			return "", nil, fmt.Errorf("branch %s is checked out at %s with uncommitted changes; commit or stash them first", branchName, existingWorktree)
+		}
+		return existingWorktree, func() {}, nil
+	}
+
+	temporaryDirectory, err := os.MkdirTemp("", "orchestragent-merge-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary worktree directory: %w", err)
+	}
+	if _, err := gitClient.executeGitCommand(ctx, "worktree", "add", temporaryDirectory, branchName); err != nil {
+		os.RemoveAll(temporaryDirectory)
+		return "", nil, fmt.Errorf("failed to check out %s: %w", branchName, err)
+	}
+
+	release := func() {
+		gitClient.executeGitCommand(context.WithoutCancel(ctx), "worktree", "remove", "--force", temporaryDirectory)
+		os.RemoveAll(temporaryDirectory)
+	}
+	return temporaryDirectory, release, nil
+}
+
+// findWorktreeForBranch returns the path of the worktree that has branchName
+// checked out, or "" when no worktree does
+func (gitClient *GitClient) findWorktreeForBranch(ctx context.Context, branchName string) (string, error) {
+	commandOutput, err := gitClient.executeGitCommandWithOutput(ctx, "worktree", "list", "--porcelain", "-z")
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	branchRef := "refs/heads/" + branchName
+	currentWorktree := ""
+	for _, line := range strings.Split(string(commandOutput), "\x00") {
+		if path, found := strings.CutPrefix(line, "worktree "); found {
+			currentWorktree = path
+		} else if line == "branch "+branchRef {
+			return currentWorktree, nil
+		}
+	}
+
+	return "", nil
+}
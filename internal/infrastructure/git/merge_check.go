@@ -0,0 +1,120 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+const (
+	conflictMarkerStart = "<<<<<<< "
+	conflictMarkerEnd   = ">>>>>>> "
+)
+
+// CheckMerge runs "git merge-tree --write-tree", which merges in memory and
+// only writes objects to the object store, so no worktree, index or ref is
+// changed
+func (gitClient *GitClient) CheckMerge(ctx context.Context, baseRef string, sessionBranch string) (*domain.MergeCheck, error) {
+	commandOutput, exitCode, err := gitClient.executeGitCommandWithExitCode(ctx,
+		"merge-tree", "--write-tree", "--name-only", "-z", "--end-of-options", baseRef, sessionBranch,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check merge: %w", err)
+	}
+	if exitCode > 1 {
+		return nil, fmt.Errorf("failed to check merge of %s into %s: git merge-tree exited with status %d", sessionBranch, baseRef, exitCode)
+	}
+
+	resultTree, conflicts := parseMergeTreeOutput(string(commandOutput))
+	if exitCode == 0 {
+		return &domain.MergeCheck{Clean: true, Conflicts: []domain.MergeConflict{}}, nil
+	}
+
+	for index := range conflicts {
+		hunks, err := gitClient.conflictHunks(ctx, resultTree, conflicts[index].Path)
+		if err != nil {
+			return nil, err
+		}
+		conflicts[index].Hunks = hunks
+	}
+
+	return &domain.MergeCheck{Clean: false, Conflicts: conflicts}, nil
+}
+
+// parseMergeTreeOutput parses the -z --name-only output of merge-tree: the
+// result tree, the conflicted paths, an empty field, then messages encoded as
+// "<path count> NUL <paths...> NUL <type> NUL <message> NUL"
+func parseMergeTreeOutput(output string) (string, []domain.MergeConflict) {
+	fields := strings.Split(output, "\x00")
+	conflicts := make([]domain.MergeConflict, 0)
+	if len(fields) == 0 {
+		return "", conflicts
+	}
+
+	resultTree := fields[0]
+	conflictIndex := make(map[string]int)
+	index := 1
+	for ; index < len(fields) && fields[index] != ""; index++ {
+		conflictIndex[fields[index]] = len(conflicts)
+		conflicts = append(conflicts, domain.MergeConflict{Path: fields[index]})
+	}
+
+	for index++; index < len(fields); {
+		pathCount, err := strconv.Atoi(fields[index])
+		if err != nil || index+pathCount+2 >= len(fields) {
+			break
+		}
+		paths := fields[index+1 : index+1+pathCount]
+		conflictType := fields[index+1+pathCount]
+		message := strings.TrimSpace(fields[index+2+pathCount])
+		index += pathCount + 3
+
+		if !strings.HasPrefix(conflictType, "CONFLICT") {
+			continue
+		}
+		for _, path := range paths {
+			if position, exists := conflictIndex[path]; exists && conflicts[position].Type == "" {
+				conflicts[position].Type = conflictType
+				conflicts[position].Message = message
+			}
+		}
+	}
+
+	return resultTree, conflicts
+}
+
+// conflictHunks extracts the conflict-marker blocks that merge-tree wrote into
+// the file in the result tree
+func (gitClient *GitClient) conflictHunks(ctx context.Context, resultTree string, path string) ([]string, error) {
+	hunks := make([]string, 0)
+
+	commandOutput, exitCode, err := gitClient.executeGitCommandWithExitCode(ctx, "cat-file", "blob", resultTree+":"+path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read conflicted file %s: %w", path, err)
+	}
+	if exitCode != 0 {
+		// the path does not exist in the result, e.g. it was deleted on one side
+		return hunks, nil
+	}
+
+	var current *strings.Builder
+	for _, line := range strings.SplitAfter(string(commandOutput), "\n") {
+		if current == nil && strings.HasPrefix(line, conflictMarkerStart) {
+			current = &strings.Builder{}
+		}
+		if current == nil {
+			continue
+		}
+
+		current.WriteString(line)
+		if strings.HasPrefix(line, conflictMarkerEnd) {
+			hunks = append(hunks, current.String())
+			current = nil
+		}
+	}
+
+	return hunks, nil
+}
@@ -0,0 +1,78 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGitClient_CheckMerge_Clean(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "feature.txt", "feature\n", "Add feature")
+	commitFile(t, setup.repositoryRoot, "upstream.txt", "upstream\n", "Upstream work")
+
+	// act
+	mergeCheck, err := setup.gitClient.CheckMerge(setup.ctx, "master", setup.branchName)
+
+	// assert
+	if err != nil {
+		t.Fatalf("CheckMerge() error: %v", err)
+	}
+	if !mergeCheck.Clean {
+		t.Errorf("CheckMerge() Clean = false, conflicts %+v", mergeCheck.Conflicts)
+	}
+}
+
+func TestGitClient_CheckMerge_ReportsConflictsWithoutTouchingWorktrees(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.repositoryRoot, "notes.txt", "shared\n", "Add notes")
+	runGit(t, setup.worktreePath, "merge", "--ff-only", "master")
+	commitFile(t, setup.worktreePath, "README.md", "# Session\n", "Session edit")
+	runGit(t, setup.worktreePath, "rm", "-q", "notes.txt")
+	runGit(t, setup.worktreePath, "commit", "-q", "-m", "Remove notes")
+	commitFile(t, setup.repositoryRoot, "README.md", "# Upstream\n", "Upstream edit")
+	commitFile(t, setup.repositoryRoot, "notes.txt", "changed upstream\n", "Edit notes")
+	masterBefore := runGit(t, setup.repositoryRoot, "rev-parse", "master")
+	sessionBefore := runGit(t, setup.repositoryRoot, "rev-parse", setup.branchName)
+
+	// act
+	mergeCheck, err := setup.gitClient.CheckMerge(setup.ctx, "master", setup.branchName)
+
+	// assert
+	if err != nil {
+		t.Fatalf("CheckMerge() error: %v", err)
+	}
+	if mergeCheck.Clean {
+		t.Fatal("CheckMerge() Clean = true, want conflicts")
+	}
+	if len(mergeCheck.Conflicts) != 2 {
+		t.Fatalf("CheckMerge() returned %d conflicts, want 2: %+v", len(mergeCheck.Conflicts), mergeCheck.Conflicts)
+	}
+
+	readme := mergeCheck.Conflicts[0]
+	if readme.Path != "README.md" || !strings.Contains(readme.Type, "content") {
+		t.Errorf("first conflict = %+v, want content conflict in README.md", readme)
+	}
+	if len(readme.Hunks) != 1 || !strings.Contains(readme.Hunks[0], "# Upstream") || !strings.Contains(readme.Hunks[0], "# Session") {
+		t.Errorf("README.md hunks = %q, want one hunk with both sides", readme.Hunks)
+	}
+	notes := mergeCheck.Conflicts[1]
+	if notes.Path != "notes.txt" || !strings.Contains(notes.Type, "modify/delete") {
+		t.Errorf("second conflict = %+v, want modify/delete on notes.txt", notes)
+	}
+
+	if status := runGit(t, setup.repositoryRoot, "status", "--porcelain", "--untracked-files=no"); status != "" {
+		t.Errorf("repository checkout changed: %q", status)
+	}
+	if status := runGit(t, setup.worktreePath, "status", "--porcelain"); status != "" {
+		t.Errorf("session worktree changed: %q", status)
+	}
+	if runGit(t, setup.repositoryRoot, "rev-parse", "master") != masterBefore || runGit(t, setup.repositoryRoot, "rev-parse", setup.branchName) != sessionBefore {
+		t.Error("CheckMerge() must not move any branch")
+	}
+}
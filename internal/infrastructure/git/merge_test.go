@@ -0,0 +1,249 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func runGit(t *testing.T, directory string, args ...string) string {
+	t.Helper()
+
+	gitCommand := exec.Command("git", args...)
+	gitCommand.Dir = directory
+	output, err := gitCommand.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v (%s)", args, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func TestGitClient_Merge_Strategies(t *testing.T) {
+	testCases := []struct {
+		strategy          domain.MergeStrategy
+		wantParents       int
+		wantSessionCommit bool
+	}{
+		{domain.MergeStrategyMerge, 2, true},
+		{domain.MergeStrategySquash, 1, false},
+		{domain.MergeStrategyRebase, 1, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.strategy), func(t *testing.T) {
+			// arrange
+			setup := setupTestRepoWithWorktree(t)
+			defer setup.cleanup()
+
+			commitFile(t, setup.worktreePath, "feature.txt", "feature\n", "Add feature")
+			commitFile(t, setup.repositoryRoot, "upstream.txt", "upstream\n", "Upstream work")
+
+			// act
+			mergedCommit, err := setup.gitClient.Merge(setup.ctx, "master", setup.branchName, setup.worktreePath, domain.MergeOptions{
+				Strategy: testCase.strategy,
+				Message:  "Integrate session",
+			})
+
+			// assert
+			if err != nil {
+				t.Fatalf("Merge() error: %v", err)
+			}
+			if head := runGit(t, setup.repositoryRoot, "rev-parse", "master"); head != mergedCommit {
+				t.Errorf("master = %s, want merged commit %s", head, mergedCommit)
+			}
+			if _, err := os.Stat(filepath.Join(setup.repositoryRoot, "feature.txt")); err != nil {
+				t.Errorf("feature.txt should be checked out on master: %v", err)
+			}
+			parents := strings.Fields(runGit(t, setup.repositoryRoot, "log", "-1", "--format=%P", "master"))
+			if len(parents) != testCase.wantParents {
+				t.Errorf("merged commit has %d parents, want %d", len(parents), testCase.wantParents)
+			}
+			sessionIsAncestor := exec.Command("git", "merge-base", "--is-ancestor", setup.branchName, "master")
+			sessionIsAncestor.Dir = setup.repositoryRoot
+			if isAncestor := sessionIsAncestor.Run() == nil; isAncestor != testCase.wantSessionCommit {
+				t.Errorf("session branch ancestor of master = %v, want %v", isAncestor, testCase.wantSessionCommit)
+			}
+		})
+	}
+}
+
+func TestGitClient_Merge_FastForwardOnly_FailsWhenDiverged(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "feature.txt", "feature\n", "Add feature")
+	commitFile(t, setup.repositoryRoot, "upstream.txt", "upstream\n", "Upstream work")
+	headBefore := runGit(t, setup.repositoryRoot, "rev-parse", "master")
+
+	// act
+	_, err := setup.gitClient.Merge(setup.ctx, "master", setup.branchName, setup.worktreePath, domain.MergeOptions{Strategy: domain.MergeStrategyFastForwardOnly})
+
+	// assert
+	if err == nil {
+		t.Fatal("Merge() expected error when master has diverged")
+	}
+	if headAfter := runGit(t, setup.repositoryRoot, "rev-parse", "master"); headAfter != headBefore {
+		t.Error("master should not move when fast-forward fails")
+	}
+}
+
+func TestGitClient_Merge_ConflictIsAbortedAndReported(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "README.md", "# Session\n", "Session edit")
+	commitFile(t, setup.repositoryRoot, "README.md", "# Upstream\n", "Upstream edit")
+	headBefore := runGit(t, setup.repositoryRoot, "rev-parse", "master")
+
+	// act
+	_, err := setup.gitClient.Merge(setup.ctx, "master", setup.branchName, setup.worktreePath, domain.MergeOptions{Strategy: domain.MergeStrategyMerge})
+
+	// assert
+	var conflictErr *domain.ConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("Merge() error = %v, want ConflictError", err)
+	}
+	if len(conflictErr.Paths) != 1 || conflictErr.Paths[0] != "README.md" {
+		t.Errorf("conflict paths = %v, want [README.md]", conflictErr.Paths)
+	}
+	if status := runGit(t, setup.repositoryRoot, "status", "--porcelain", "--untracked-files=no"); status != "" {
+		t.Errorf("repository should be clean after abort, got status %q", status)
+	}
+	if headAfter := runGit(t, setup.repositoryRoot, "rev-parse", "master"); headAfter != headBefore {
+		t.Error("master should not move when the merge conflicts")
+	}
+}
+
+func TestGitClient_Merge_BaseNotCheckedOut_UsesTemporaryWorktree(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	runGit(t, setup.repositoryRoot, "branch", "release", "master")
+	commitFile(t, setup.worktreePath, "feature.txt", "feature\n", "Add feature")
+	worktreesBefore := runGit(t, setup.repositoryRoot, "worktree", "list")
+
+	// act
+	mergedCommit, err := setup.gitClient.Merge(setup.ctx, "release", setup.branchName, setup.worktreePath, domain.MergeOptions{Strategy: domain.MergeStrategyFastForwardOnly})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Merge() error: %v", err)
+	}
+	if head := runGit(t, setup.repositoryRoot, "rev-parse", "release"); head != mergedCommit {
+		t.Errorf("release = %s, want %s", head, mergedCommit)
+	}
+	if worktreesAfter := runGit(t, setup.repositoryRoot, "worktree", "list"); worktreesAfter != worktreesBefore {
+		t.Errorf("temporary worktree was not cleaned up:\n%s", worktreesAfter)
+	}
+}
+
+func TestGitClient_Merge_DirtyBaseCheckout_ReturnsError(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "feature.txt", "feature\n", "Add feature")
+	os.WriteFile(filepath.Join(setup.repositoryRoot, "README.md"), []byte("local edit\n"), 0644)
+
+	// act
+	_, err := setup.gitClient.Merge(setup.ctx, "master", setup.branchName, setup.worktreePath, domain.MergeOptions{Strategy: domain.MergeStrategyMerge})
+
+	// assert
+	if err == nil {
+		t.Fatal("Merge() expected error when the base checkout has uncommitted changes")
+	}
+}
+
+func TestGitClient_Sync_BringsBranchUpToDate(t *testing.T) {
+	for _, strategy := range []domain.MergeStrategy{domain.MergeStrategyRebase, domain.MergeStrategyMerge} {
+		t.Run(string(strategy), func(t *testing.T) {
+			// arrange
+			setup := setupTestRepoWithWorktree(t)
+			defer setup.cleanup()
+
+			commitFile(t, setup.worktreePath, "feature.txt", "feature\n", "Add feature")
+			commitFile(t, setup.repositoryRoot, "upstream.txt", "upstream\n", "Upstream work")
+			baseTip := runGit(t, setup.repositoryRoot, "rev-parse", "master")
+
+			// act
+			err := setup.gitClient.Sync(setup.ctx, setup.worktreePath, baseTip, domain.MergeOptions{Strategy: strategy})
+
+			// assert
+			if err != nil {
+				t.Fatalf("Sync() error: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(setup.worktreePath, "upstream.txt")); err != nil {
+				t.Errorf("upstream.txt should be present in the session worktree: %v", err)
+			}
+			if mergeBase := runGit(t, setup.repositoryRoot, "merge-base", "master", setup.branchName); mergeBase != baseTip {
+				t.Errorf("merge-base = %s, want base tip %s", mergeBase, baseTip)
+			}
+		})
+	}
+}
+
+func TestGitClient_RebaseOnto_LeavesSquashedParentCommitsBehind(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "parent.txt", "parent\n", "Parent work")
+	parentTip := runGit(t, setup.worktreePath, "rev-parse", "HEAD")
+	childPath := filepath.Join(setup.repositoryRoot, ".worktrees", "child")
+	if err := setup.gitClient.CreateWorktree(setup.ctx, childPath, "session-child", parentTip); err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	commitFile(t, childPath, "child.txt", "child\n", "Child work")
+	commitFile(t, setup.repositoryRoot, "parent.txt", "parent\n", "Squashed parent work")
+	baseTip := runGit(t, setup.repositoryRoot, "rev-parse", "master")
+
+	// act
+	err := setup.gitClient.RebaseOnto(setup.ctx, childPath, baseTip, parentTip)
+
+	// assert
+	if err != nil {
+		t.Fatalf("RebaseOnto() error: %v", err)
+	}
+	if parent := runGit(t, childPath, "rev-parse", "HEAD~1"); parent != baseTip {
+		t.Errorf("child commit parent = %s, want base tip %s", parent, baseTip)
+	}
+	if subjects := runGit(t, setup.repositoryRoot, "log", "--format=%s", "master..session-child"); subjects != "Child work" {
+		t.Errorf("commits after base = %q, want only the child commit", subjects)
+	}
+}
+
+func TestGitClient_Sync_ConflictIsAbortedAndReported(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "README.md", "# Session\n", "Session edit")
+	commitFile(t, setup.repositoryRoot, "README.md", "# Upstream\n", "Upstream edit")
+	headBefore := runGit(t, setup.worktreePath, "rev-parse", "HEAD")
+
+	// act
+	err := setup.gitClient.Sync(setup.ctx, setup.worktreePath, "master", domain.MergeOptions{Strategy: domain.MergeStrategyRebase})
+
+	// assert
+	var conflictErr *domain.ConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("Sync() error = %v, want ConflictError", err)
+	}
+	if len(conflictErr.Paths) != 1 || conflictErr.Paths[0] != "README.md" {
+		t.Errorf("conflict paths = %v, want [README.md]", conflictErr.Paths)
+	}
+	if status := runGit(t, setup.worktreePath, "status", "--porcelain", "--untracked-files=no"); status != "" {
+		t.Errorf("worktree should be clean after abort, got status %q", status)
+	}
+	if headAfter := runGit(t, setup.worktreePath, "rev-parse", "HEAD"); headAfter != headBefore {
+		t.Error("session branch should not move when the sync conflicts")
+	}
+}
@@ -0,0 +1,23 @@
+package git
+
+import (
+	"context"
+	"fmt"
+)
+
+// Push publishes branchName to the branch of the same name on remote. A
+// forced push uses --force-with-lease, so it fails instead of overwriting
+// commits someone else pushed in the meantime.
+func (gitClient *GitClient) Push(ctx context.Context, remote string, branchName string, force bool) error {
+	args := []string{"push", "--quiet"}
+	if force {
+		args = append(args, "--force-with-lease")
+	}
+	refspec := fmt.Sprintf("refs/heads/%s:refs/heads/%s", branchName, branchName)
+	args = append(args, "--end-of-options", remote, refspec)
+
+	if _, err := gitClient.executeGitCommand(ctx, args...); err != nil {
+		return fmt.Errorf("failed to push %s to %s: %w", branchName, remote, err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func setupBareRemote(t *testing.T, repositoryRoot string) string {
+	t.Helper()
+
+	remotePath := filepath.Join(t.TempDir(), "remote.git")
+	if output, err := exec.Command("git", "init", "--bare", remotePath).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare failed: %v (%s)", err, output)
+	}
+	runGit(t, repositoryRoot, "remote", "add", "origin", remotePath)
+	return remotePath
+}
+
+func TestGitClient_Push_PublishesBranch(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	remotePath := setupBareRemote(t, setup.repositoryRoot)
+	commitFile(t, setup.worktreePath, "feature.txt", "feature\n", "Add feature")
+
+	// act
+	err := setup.gitClient.Push(setup.ctx, "origin", setup.branchName, false)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Push() error: %v", err)
+	}
+	localHead := runGit(t, setup.worktreePath, "rev-parse", "HEAD")
+	if remoteHead := runGit(t, remotePath, "rev-parse", setup.branchName); remoteHead != localHead {
+		t.Errorf("remote %s = %s, want %s", setup.branchName, remoteHead, localHead)
+	}
+}
+
+func TestGitClient_Push_RewrittenBranch_RequiresForce(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	setupBareRemote(t, setup.repositoryRoot)
+	commitFile(t, setup.worktreePath, "feature.txt", "feature\n", "Add feature")
+	if err := setup.gitClient.Push(setup.ctx, "origin", setup.branchName, false); err != nil {
+		t.Fatalf("Push() error: %v", err)
+	}
+	runGit(t, setup.worktreePath, "commit", "--amend", "-m", "Reworded feature")
+
+	// act
+	plainErr := setup.gitClient.Push(setup.ctx, "origin", setup.branchName, false)
+	forceErr := setup.gitClient.Push(setup.ctx, "origin", setup.branchName, true)
+
+	// assert
+	if plainErr == nil {
+		t.Error("Push() expected error for a non-fast-forward update")
+	}
+	if forceErr != nil {
+		t.Errorf("Push() with force error: %v", forceErr)
+	}
+}
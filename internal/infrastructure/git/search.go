@@ -0,0 +1,141 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// grepLine is one line of "git grep --null --column" output. Matching lines
+// carry a column field, context lines do not.
+type grepLine struct {
+	path   string
+	number int
+	column int
+	text   string
+	match  bool
+}
+
+// Search runs "git grep" on the working tree files. git grep honours
+// .gitignore for untracked files and skips binary files with -I. Each file
+// stops after MaxResults+1 matches, which is enough to tell whether the
+// overall result was truncated.
+func (gitClient *GitClient) Search(ctx context.Context, worktreePath string, options domain.SearchOptions) (*domain.SearchResult, error) {
+	args := []string{
+		"-C", worktreePath,
+		"grep", "--null", "--line-number", "--column", "-I", "--no-color",
+		fmt.Sprintf("--max-count=%d", options.MaxResults+1),
+		fmt.Sprintf("--context=%d", options.ContextLines),
+	}
+	if options.Literal {
+		args = append(args, "--fixed-strings")
+	} else {
+		args = append(args, "--extended-regexp")
+	}
+	if options.IgnoreCase {
+		args = append(args, "--ignore-case")
+	}
+	if options.IncludeUntracked {
+		args = append(args, "--untracked")
+	}
+	args = append(args, "-e", options.Pattern)
+
+	commandOutput, exitCode, err := gitClient.executeGitCommandWithExitCode(ctx, append(args, globPathspecs(options.PathGlobs)...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	// git grep exits with 1 when nothing matched
+	if exitCode > 1 {
+		return nil, fmt.Errorf("failed to search: git grep exited with status %d, the pattern may be invalid", exitCode)
+	}
+
+	return collectSearchMatches(parseGrepOutput(string(commandOutput)), options.ContextLines, options.MaxResults), nil
+}
+
+func parseGrepOutput(output string) []grepLine {
+	lines := make([]grepLine, 0)
+	for _, rawLine := range strings.Split(output, "\n") {
+		fields := strings.SplitN(rawLine, "\x00", 4)
+		if len(fields) < 3 {
+			// group separators and the trailing empty line
+			continue
+		}
+
+		number, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		line := grepLine{path: fields[0], number: number, text: fields[2]}
+		if len(fields) == 4 {
+			line.column, _ = strconv.Atoi(fields[2])
+			line.text = fields[3]
+			line.match = true
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// collectSearchMatches turns grep lines into matches that each carry their
+// full context, so a line near two matches shows up in the context of both.
+// Lines after the last kept match are still read to complete its context.
+func collectSearchMatches(lines []grepLine, contextLines int, maxResults int) *domain.SearchResult {
+	result := &domain.SearchResult{Matches: make([]domain.SearchMatch, 0)}
+	recent := make([]grepLine, 0, contextLines)
+	openMatches := make([]int, 0)
+	currentPath := ""
+
+	for _, line := range lines {
+		if line.path != currentPath {
+			currentPath = line.path
+			recent = recent[:0]
+			openMatches = openMatches[:0]
+		}
+		if result.Truncated && len(openMatches) == 0 {
+			break
+		}
+
+		stillOpen := openMatches[:0]
+		for _, index := range openMatches {
+			if line.number <= result.Matches[index].LineNumber+contextLines {
+				result.Matches[index].ContextAfter = append(result.Matches[index].ContextAfter, line.text)
+				stillOpen = append(stillOpen, index)
+			}
+		}
+		openMatches = stillOpen
+
+		if line.match {
+			if len(result.Matches) == maxResults {
+				result.Truncated = true
+			} else {
+				match := domain.SearchMatch{
+					Path:          line.path,
+					LineNumber:    line.number,
+					Column:        line.column,
+					Line:          line.text,
+					ContextBefore: make([]string, 0, len(recent)),
+					ContextAfter:  make([]string, 0, contextLines),
+				}
+				for _, previous := range recent {
+					if previous.number >= line.number-contextLines {
+						match.ContextBefore = append(match.ContextBefore, previous.text)
+					}
+				}
+				result.Matches = append(result.Matches, match)
+				openMatches = append(openMatches, len(result.Matches)-1)
+			}
+		}
+
+		if contextLines > 0 {
+			if len(recent) == contextLines {
+				recent = append(recent[:0], recent[1:]...)
+			}
+			recent = append(recent, line)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,122 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func TestGitClient_Search_ReturnsMatchesWithContext(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "main.go", "package main\n\nfunc main() {\n\tstart()\n\tstart()\n}\n", "Add main")
+
+	// act
+	result, err := setup.gitClient.Search(setup.ctx, setup.worktreePath, domain.SearchOptions{Pattern: `start\(`, ContextLines: 1, MaxResults: 10})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Search() error: %v", err)
+	}
+	if len(result.Matches) != 2 || result.Truncated {
+		t.Fatalf("matches = %+v, want 2", result.Matches)
+	}
+	first, second := result.Matches[0], result.Matches[1]
+	if first.Path != "main.go" || first.LineNumber != 4 || first.Column != 2 || first.Line != "\tstart()" {
+		t.Errorf("first match = %+v, want main.go:4:2", first)
+	}
+	if strings.Join(first.ContextBefore, "|") != "func main() {" || strings.Join(first.ContextAfter, "|") != "\tstart()" {
+		t.Errorf("first context = %q / %q", first.ContextBefore, first.ContextAfter)
+	}
+	if strings.Join(second.ContextBefore, "|") != "\tstart()" || strings.Join(second.ContextAfter, "|") != "}" {
+		t.Errorf("second context = %q / %q", second.ContextBefore, second.ContextAfter)
+	}
+}
+
+func TestGitClient_Search_UntrackedFilesRespectGitignore(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, ".gitignore", "build/\n", "Ignore build output")
+	files := map[string]string{
+		"notes.txt":         "needle in untracked\n",
+		"build/output.txt":  "needle in ignored\n",
+		"internal/find.go":  "// needle in go\n",
+		"internal/skip.txt": "needle in txt\n",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(setup.worktreePath, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	// act
+	trackedOnly, trackedErr := setup.gitClient.Search(setup.ctx, setup.worktreePath, domain.SearchOptions{Pattern: "needle", Literal: true, MaxResults: 10})
+	withUntracked, untrackedErr := setup.gitClient.Search(setup.ctx, setup.worktreePath, domain.SearchOptions{Pattern: "NEEDLE", Literal: true, IgnoreCase: true, IncludeUntracked: true, MaxResults: 10})
+	globbed, globErr := setup.gitClient.Search(setup.ctx, setup.worktreePath, domain.SearchOptions{Pattern: "needle", IncludeUntracked: true, PathGlobs: []string{"internal/**/*.go"}, MaxResults: 10})
+
+	// assert
+	if trackedErr != nil || untrackedErr != nil || globErr != nil {
+		t.Fatalf("Search() errors: %v, %v, %v", trackedErr, untrackedErr, globErr)
+	}
+	if len(trackedOnly.Matches) != 0 {
+		t.Errorf("tracked-only matches = %+v, want none", trackedOnly.Matches)
+	}
+	paths := make([]string, 0)
+	for _, match := range withUntracked.Matches {
+		paths = append(paths, match.Path)
+	}
+	if strings.Join(paths, ",") != "internal/find.go,internal/skip.txt,notes.txt" {
+		t.Errorf("untracked matches = %v, want every file except the ignored one", paths)
+	}
+	if len(globbed.Matches) != 1 || globbed.Matches[0].Path != "internal/find.go" {
+		t.Errorf("globbed matches = %+v, want internal/find.go", globbed.Matches)
+	}
+}
+
+func TestGitClient_Search_MaxResults_TruncatesAcrossFiles(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	commitFile(t, setup.worktreePath, "a.txt", "todo\ntodo\n", "Add a")
+	commitFile(t, setup.worktreePath, "b.txt", "todo\n", "Add b")
+
+	// act
+	result, err := setup.gitClient.Search(setup.ctx, setup.worktreePath, domain.SearchOptions{Pattern: "todo", MaxResults: 2})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Search() error: %v", err)
+	}
+	if len(result.Matches) != 2 || !result.Truncated || result.Matches[1].Path != "a.txt" {
+		t.Errorf("result = %+v, want the two a.txt matches and truncated", result)
+	}
+}
+
+func TestGitClient_Search_NoMatches_ReturnsEmptyResult(t *testing.T) {
+	// arrange
+	setup := setupTestRepoWithWorktree(t)
+	defer setup.cleanup()
+
+	// act
+	result, err := setup.gitClient.Search(setup.ctx, setup.worktreePath, domain.SearchOptions{Pattern: "-absent-", MaxResults: 10})
+
+	// assert
+	if err != nil {
+		t.Fatalf("Search() error: %v", err)
+	}
+	if len(result.Matches) != 0 || result.Truncated {
+		t.Errorf("result = %+v, want no matches", result)
+	}
+}
@@ -12,7 +12,7 @@ func TestInMemoryRepository_SaveAndFind(t *testing.T) {
 	repository := NewInMemorySessionRepository()
 	ctx := context.Background()
 	sessionID, _ := domain.NewSessionID("test-session")
-	session, _ := domain.NewSession(sessionID, "/path/to/worktree")
+	session, _ := domain.NewSession(sessionID, "/path/to/worktree", "")
 
 	// act
 	err := repository.Save(ctx, session)
@@ -65,7 +65,7 @@ func TestInMemoryRepository_Exists(t *testing.T) {
 	}
 
 	// arrange
-	session, _ := domain.NewSession(sessionID, "/path")
+	session, _ := domain.NewSession(sessionID, "/path", "")
 	repository.Save(ctx, session)
 
 	// act
@@ -103,13 +103,13 @@ func TestInMemoryRepository_FindAll_MultipleSessions(t *testing.T) {
 	ctx := context.Background()
 
 	sessionID1, _ := domain.NewSessionID("session-one")
-	session1, _ := domain.NewSession(sessionID1, "/path/one")
+	session1, _ := domain.NewSession(sessionID1, "/path/one", "")
 
 	sessionID2, _ := domain.NewSessionID("session-two")
-	session2, _ := domain.NewSession(sessionID2, "/path/two")
+	session2, _ := domain.NewSession(sessionID2, "/path/two", "")
 
 	sessionID3, _ := domain.NewSessionID("session-three")
-	session3, _ := domain.NewSession(sessionID3, "/path/three")
+	session3, _ := domain.NewSession(sessionID3, "/path/three", "")
 
 	repository.Save(ctx, session1)
 	repository.Save(ctx, session2)
@@ -20,11 +20,64 @@ CREATE TABLE IF NOT EXISTS sessions (
     status TEXT NOT NULL CHECK(status IN ('open', 'reviewed', 'merged')),
     worktree_path TEXT NOT NULL,
     branch_name TEXT NOT NULL,
+    base_ref TEXT NOT NULL DEFAULT '',
+    base_commit TEXT NOT NULL DEFAULT '',
+    pull_request_number INTEGER NOT NULL DEFAULT 0,
+    pull_request_url TEXT NOT NULL DEFAULT '',
+    parent_session_id TEXT NOT NULL DEFAULT '',
     created_at INTEGER NOT NULL,
     updated_at INTEGER NOT NULL
 );
 `
 
+// columnMigration adds a column that was introduced after the sessions table
+// was first created, so databases from older versions keep working
+type columnMigration struct {
+	column     string
+	definition string
+}
+
+var sessionColumnMigrations = []columnMigration{
+	{column: "base_ref", definition: "TEXT NOT NULL DEFAULT ''"},
+	{column: "base_commit", definition: "TEXT NOT NULL DEFAULT ''"},
+	{column: "pull_request_number", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{column: "pull_request_url", definition: "TEXT NOT NULL DEFAULT ''"},
+	{column: "parent_session_id", definition: "TEXT NOT NULL DEFAULT ''"},
+}
+
+const sessionSelectColumns = "id, status, worktree_path, branch_name, base_ref, base_commit, pull_request_number, pull_request_url, parent_session_id, created_at, updated_at"
+
+// sessionRow mirrors the columns listed in sessionSelectColumns
+type sessionRow struct {
+	id                string
+	status            string
+	worktreePath      string
+	branchName        string
+	baseRef           string
+	baseCommit        string
+	pullRequestNumber int
+	pullRequestURL    string
+	parentSessionID   string
+	createdAt         int64
+	updatedAt         int64
+}
+
+func (row *sessionRow) scanTargets() []any {
+	return []any{
+		&row.id,
+		&row.status,
+		&row.worktreePath,
+		&row.branchName,
+		&row.baseRef,
+		&row.baseCommit,
+		&row.pullRequestNumber,
+		&row.pullRequestURL,
+		&row.parentSessionID,
+		&row.createdAt,
+		&row.updatedAt,
+	}
+}
+
 func NewSQLiteSessionRepository(databasePath string) (*SQLiteSessionRepository, error) {
 	database, err := sql.Open("sqlite", databasePath)
 	if err != nil {
@@ -51,17 +104,61 @@ func (repository *SQLiteSessionRepository) initializeSchema() error {
 	if err != nil {
 		return fmt.Errorf("failed to create sessions table: %w", err)
 	}
+	return repository.migrateColumns()
+}
+
+func (repository *SQLiteSessionRepository) migrateColumns() error {
+	existingColumns, err := repository.sessionColumns()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range sessionColumnMigrations {
+		if existingColumns[migration.column] {
+			continue
+		}
+
+		statement := fmt.Sprintf("ALTER TABLE sessions ADD COLUMN %s %s", migration.column, migration.definition)
+		if _, err := repository.database.Exec(statement); err != nil {
+			return fmt.Errorf("failed to add column %s: %w", migration.column, err)
+		}
+	}
+
 	return nil
 }
 
+func (repository *SQLiteSessionRepository) sessionColumns() (map[string]bool, error) {
+	rows, err := repository.database.Query(`SELECT name FROM pragma_table_info('sessions')`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sessions table columns: %w", err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan column name: %w", err)
+		}
+		columns[name] = true
+	}
+
+	return columns, rows.Err()
+}
+
 func (repository *SQLiteSessionRepository) Save(ctx context.Context, session *domain.Session) error {
 	query := `
-		INSERT INTO sessions (id, status, worktree_path, branch_name, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO sessions (id, status, worktree_path, branch_name, base_ref, base_commit, pull_request_number, pull_request_url, parent_session_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			status = excluded.status,
 			worktree_path = excluded.worktree_path,
 			branch_name = excluded.branch_name,
+			base_ref = excluded.base_ref,
+			base_commit = excluded.base_commit,
+			pull_request_number = excluded.pull_request_number,
+			pull_request_url = excluded.pull_request_url,
+			parent_session_id = excluded.parent_session_id,
 			updated_at = excluded.updated_at
 	`
 
@@ -75,6 +172,11 @@ func (repository *SQLiteSessionRepository) Save(ctx context.Context, session *do
 		string(session.Status()),
 		session.WorktreePath(),
 		session.BranchName(),
+		session.BaseRef(),
+		session.BaseCommit(),
+		session.PullRequest().Number,
+		session.PullRequest().URL,
+		session.ParentSessionID().String(),
 		createdAt,
 		updatedAt,
 	)
@@ -88,22 +190,13 @@ func (repository *SQLiteSessionRepository) Save(ctx context.Context, session *do
 
 func (repository *SQLiteSessionRepository) FindByID(ctx context.Context, sessionID domain.SessionID) (*domain.Session, error) {
 	query := `
-		SELECT id, status, worktree_path, branch_name, created_at, updated_at
+		SELECT ` + sessionSelectColumns + `
 		FROM sessions
 		WHERE id = ?
 	`
 
-	var id, status, worktreePath, branchName string
-	var createdAt, updatedAt int64
-
-	err := repository.database.QueryRowContext(ctx, query, sessionID.String()).Scan(
-		&id,
-		&status,
-		&worktreePath,
-		&branchName,
-		&createdAt,
-		&updatedAt,
-	)
+	var row sessionRow
+	err := repository.database.QueryRowContext(ctx, query, sessionID.String()).Scan(row.scanTargets()...)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("session not found: %s", sessionID.String())
@@ -113,12 +206,12 @@ func (repository *SQLiteSessionRepository) FindByID(ctx context.Context, session
 		return nil, fmt.Errorf("failed to query session %s: %w", sessionID.String(), err)
 	}
 
-	return repository.reconstructSession(id, status, worktreePath)
+	return repository.reconstructSession(row)
 }
 
 func (repository *SQLiteSessionRepository) FindAll(ctx context.Context) ([]*domain.Session, error) {
 	query := `
-		SELECT id, status, worktree_path, branch_name, created_at, updated_at
+		SELECT ` + sessionSelectColumns + `
 		FROM sessions
 		ORDER BY created_at ASC
 	`
@@ -187,15 +280,14 @@ func (repository *SQLiteSessionRepository) Close() error {
 }
 
 func (repository *SQLiteSessionRepository) scanRowIntoSession(rows *sql.Rows) (*domain.Session, error) {
-	var id, status, worktreePath, branchName string
-	var createdAt, updatedAt int64
+	var row sessionRow
 
-	err := rows.Scan(&id, &status, &worktreePath, &branchName, &createdAt, &updatedAt)
+	err := rows.Scan(row.scanTargets()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan session row: %w", err)
 	}
 
-	session, err := repository.reconstructSession(id, status, worktreePath)
+	session, err := repository.reconstructSession(row)
 	if err != nil {
 		return nil, err
 	}
@@ -203,18 +295,41 @@ func (repository *SQLiteSessionRepository) scanRowIntoSession(rows *sql.Rows) (*
 	return session, nil
 }
 
-func (repository *SQLiteSessionRepository) reconstructSession(id, status, worktreePath string) (*domain.Session, error) {
-	sessionID, err := domain.NewSessionID(id)
+func (repository *SQLiteSessionRepository) reconstructSession(row sessionRow) (*domain.Session, error) {
+	sessionID, err := domain.NewSessionID(row.id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to reconstruct session ID: %w", err)
 	}
 
-	session, err := domain.NewSession(sessionID, worktreePath)
+	session, err := domain.NewSession(sessionID, row.worktreePath, row.baseRef)
 	if err != nil {
 		return nil, fmt.Errorf("failed to reconstruct session: %w", err)
 	}
 
-	switch domain.SessionStatus(status) {
+	if row.baseCommit != "" {
+		if err := session.SetBaseCommit(row.baseCommit); err != nil {
+			return nil, fmt.Errorf("failed to reconstruct session: %w", err)
+		}
+	}
+
+	if row.pullRequestNumber != 0 {
+		pullRequest := domain.PullRequest{Number: row.pullRequestNumber, URL: row.pullRequestURL}
+		if err := session.SetPullRequest(pullRequest); err != nil {
+			return nil, fmt.Errorf("failed to reconstruct session: %w", err)
+		}
+	}
+
+	if row.parentSessionID != "" {
+		parentID, err := domain.NewSessionID(row.parentSessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconstruct parent session ID: %w", err)
+		}
+		if err := session.SetParentSessionID(parentID); err != nil {
+			return nil, fmt.Errorf("failed to reconstruct session: %w", err)
+		}
+	}
+
+	switch domain.SessionStatus(row.status) {
 	case domain.StatusReviewed:
 		session.MarkReviewed()
 	case domain.StatusMerged:
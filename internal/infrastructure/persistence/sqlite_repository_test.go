@@ -2,6 +2,7 @@ package persistence
 
 import (
 	"context"
+	"database/sql"
 	"os"
 	"path/filepath"
 	"testing"
@@ -15,7 +16,7 @@ func TestSQLiteSessionRepository_Save_PersistsSessionSuccessfully(t *testing.T)
 	defer cleanup()
 
 	sessionID, _ := domain.NewSessionID("test-session")
-	session, _ := domain.NewSession(sessionID, "/path/to/worktree")
+	session, _ := domain.NewSession(sessionID, "/path/to/worktree", "")
 	ctx := context.Background()
 
 	// act
@@ -40,7 +41,7 @@ func TestSQLiteSessionRepository_Save_UpdatesExistingSession(t *testing.T) {
 	defer cleanup()
 
 	sessionID, _ := domain.NewSessionID("test-session")
-	session, _ := domain.NewSession(sessionID, "/path/to/worktree")
+	session, _ := domain.NewSession(sessionID, "/path/to/worktree", "")
 	ctx := context.Background()
 
 	repository.Save(ctx, session)
@@ -60,6 +61,115 @@ func TestSQLiteSessionRepository_Save_UpdatesExistingSession(t *testing.T) {
 	}
 }
 
+func TestSQLiteSessionRepository_Save_PersistsBaseRefAndBaseCommit(t *testing.T) {
+	// arrange
+	repository, cleanup := setupTestRepository(t)
+	defer cleanup()
+
+	sessionID, _ := domain.NewSessionID("test-session")
+	session, _ := domain.NewSession(sessionID, "/path/to/worktree", "release/2.0")
+	session.SetBaseCommit("0123456789abcdef0123456789abcdef01234567")
+	ctx := context.Background()
+
+	// act
+	err := repository.Save(ctx, session)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	retrieved, _ := repository.FindByID(ctx, sessionID)
+	assertSessionEquals(t, session, retrieved)
+}
+
+func TestSQLiteSessionRepository_Save_PersistsPullRequest(t *testing.T) {
+	// arrange
+	repository, cleanup := setupTestRepository(t)
+	defer cleanup()
+
+	sessionID, _ := domain.NewSessionID("test-session")
+	session, _ := domain.NewSession(sessionID, "/path/to/worktree", "")
+	session.SetPullRequest(domain.PullRequest{Number: 42, URL: "https://forge.example.com/team/repo/pull/42"})
+	ctx := context.Background()
+
+	// act
+	err := repository.Save(ctx, session)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	retrieved, _ := repository.FindByID(ctx, sessionID)
+	assertSessionEquals(t, session, retrieved)
+}
+
+func TestSQLiteSessionRepository_Save_PersistsParentSessionID(t *testing.T) {
+	// arrange
+	repository, cleanup := setupTestRepository(t)
+	defer cleanup()
+
+	sessionID, _ := domain.NewSessionID("test-session")
+	parentID, _ := domain.NewSessionID("parent-session")
+	session, _ := domain.NewSession(sessionID, "/path/to/worktree", "")
+	session.SetParentSessionID(parentID)
+	ctx := context.Background()
+
+	// act
+	err := repository.Save(ctx, session)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	retrieved, _ := repository.FindByID(ctx, sessionID)
+	assertSessionEquals(t, session, retrieved)
+}
+
+func TestNewSQLiteSessionRepository_MigratesLegacySchema(t *testing.T) {
+	// arrange
+	dbPath := filepath.Join(t.TempDir(), "legacy.db")
+	legacyDatabase, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("failed to open legacy database: %v", err)
+	}
+	_, err = legacyDatabase.Exec(`
+		CREATE TABLE sessions (
+			id TEXT PRIMARY KEY,
+			status TEXT NOT NULL CHECK(status IN ('open', 'reviewed', 'merged')),
+			worktree_path TEXT NOT NULL,
+			branch_name TEXT NOT NULL,
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL
+		);
+		INSERT INTO sessions VALUES ('legacy', 'open', '/path/legacy', 'orchestragent-legacy', 0, 0);
+	`)
+	legacyDatabase.Close()
+	if err != nil {
+		t.Fatalf("failed to create legacy schema: %v", err)
+	}
+
+	// act
+	repository, err := NewSQLiteSessionRepository(dbPath)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected legacy database to be migrated, got: %v", err)
+	}
+	defer repository.Close()
+
+	sessionID, _ := domain.NewSessionID("legacy")
+	retrieved, err := repository.FindByID(context.Background(), sessionID)
+	if err != nil {
+		t.Fatalf("expected to find legacy session, got error: %v", err)
+	}
+	if retrieved.BaseRef() != "" {
+		t.Errorf("expected empty base ref for legacy session, got %s", retrieved.BaseRef())
+	}
+}
+
 func TestSQLiteSessionRepository_FindByID_ReturnsErrorWhenNotFound(t *testing.T) {
 	// arrange
 	repository, cleanup := setupTestRepository(t)
@@ -84,8 +194,8 @@ func TestSQLiteSessionRepository_FindAll_ReturnsAllSessions(t *testing.T) {
 
 	sessionID1, _ := domain.NewSessionID("session-01")
 	sessionID2, _ := domain.NewSessionID("session-02")
-	session1, _ := domain.NewSession(sessionID1, "/path/1")
-	session2, _ := domain.NewSession(sessionID2, "/path/2")
+	session1, _ := domain.NewSession(sessionID1, "/path/1", "")
+	session2, _ := domain.NewSession(sessionID2, "/path/2", "")
 	ctx := context.Background()
 
 	repository.Save(ctx, session1)
@@ -129,7 +239,7 @@ func TestSQLiteSessionRepository_Exists_ReturnsTrueWhenSessionExists(t *testing.
 	defer cleanup()
 
 	sessionID, _ := domain.NewSessionID("test-session")
-	session, _ := domain.NewSession(sessionID, "/path/to/worktree")
+	session, _ := domain.NewSession(sessionID, "/path/to/worktree", "")
 	ctx := context.Background()
 
 	repository.Save(ctx, session)
@@ -174,7 +284,7 @@ func TestSQLiteSessionRepository_Delete_RemovesSessionSuccessfully(t *testing.T)
 	defer cleanup()
 
 	sessionID, _ := domain.NewSessionID("test-session")
-	session, _ := domain.NewSession(sessionID, "/path/to/worktree")
+	session, _ := domain.NewSession(sessionID, "/path/to/worktree", "")
 	ctx := context.Background()
 
 	repository.Save(ctx, session)
@@ -249,4 +359,20 @@ func assertSessionEquals(t *testing.T, expected, actual *domain.Session) {
 	if expected.BranchName() != actual.BranchName() {
 		t.Errorf("expected branch name %s, got %s", expected.BranchName(), actual.BranchName())
 	}
+
+	if expected.BaseRef() != actual.BaseRef() {
+		t.Errorf("expected base ref %s, got %s", expected.BaseRef(), actual.BaseRef())
+	}
+
+	if expected.BaseCommit() != actual.BaseCommit() {
+		t.Errorf("expected base commit %s, got %s", expected.BaseCommit(), actual.BaseCommit())
+	}
+
+	if expected.PullRequest() != actual.PullRequest() {
+		t.Errorf("expected pull request %+v, got %+v", expected.PullRequest(), actual.PullRequest())
+	}
+
+	if expected.ParentSessionID() != actual.ParentSessionID() {
+		t.Errorf("expected parent session %s, got %s", expected.ParentSessionID(), actual.ParentSessionID())
+	}
 }
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package process
+
+import "os/exec"
+
+// killProcessGroupOnCancel leaves the default of killing only the command
+// itself where process groups are not available
+func killProcessGroupOnCancel(command *exec.Cmd) {}
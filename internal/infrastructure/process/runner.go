@@ -0,0 +1,121 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// waitDelay bounds how long Run waits for output once the command has exited
+// or was killed, in case something it started still holds stdout or stderr
+const waitDelay = 5 * time.Second
+
+// Runner starts commands directly, without a shell, so arguments reach the
+// binary exactly as given. The binary is looked up on the server's PATH, not
+// the one passed in the environment, and never in the worktree itself. Every
+// command goes through the sandbox before it starts.
+type Runner struct {
+	sandbox Sandbox
+}
+
+func NewRunner(sandbox Sandbox) *Runner {
+	return &Runner{sandbox: sandbox}
+}
+
+func (runner *Runner) Run(ctx context.Context, spec domain.CommandSpec) (*domain.CommandResult, error) {
+	binaryPath, err := exec.LookPath(spec.Command)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s: %w", spec.Command, err)
+	}
+
+	runContext, cancel := context.WithTimeout(ctx, spec.Timeout)
+	defer cancel()
+
+	stdout := &cappedBuffer{limit: spec.MaxOutputBytes}
+	stderr := &cappedBuffer{limit: spec.MaxOutputBytes}
+	command := exec.CommandContext(runContext, binaryPath, spec.Args...)
+	command.Dir = spec.WorktreePath
+	command.Env = buildEnvironment(os.Environ(), spec.DeniedEnv, spec.Env)
+	command.Stdout = stdout
+	command.Stderr = stderr
+	command.WaitDelay = waitDelay
+	killProcessGroupOnCancel(command)
+
+	finishSandbox, err := runner.sandbox.Wrap(command, spec.WorktreePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to isolate %s: %w", spec.Command, err)
+	}
+
+	startedAt := time.Now()
+	runErr := command.Run()
+	duration := time.Since(startedAt)
+
+	if err := finishSandbox(); err != nil {
+		return nil, fmt.Errorf("failed to isolate %s: %w", spec.Command, err)
+	}
+
+	if command.ProcessState == nil {
+		return nil, fmt.Errorf("failed to run %s: %w", spec.Command, runErr)
+	}
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("%s was cancelled: %w", spec.Command, ctx.Err())
+	}
+
+	return &domain.CommandResult{
+		ExitCode:        command.ProcessState.ExitCode(),
+		Stdout:          stdout.data,
+		Stderr:          stderr.data,
+		StdoutTruncated: stdout.truncated,
+		StderrTruncated: stderr.truncated,
+		TimedOut:        runContext.Err() == context.DeadlineExceeded,
+		Duration:        duration,
+	}, nil
+}
+
+// buildEnvironment removes the variables matching deniedPatterns from base
+// and then sets extra, returning the result sorted by name
+func buildEnvironment(base []string, deniedPatterns []string, extra map[string]string) []string {
+	values := make(map[string]string, len(base)+len(extra))
+	for _, entry := range base {
+		name, value, found := strings.Cut(entry, "=")
+		if found && !domain.EnvNameMatches(deniedPatterns, name) {
+			values[name] = value
+		}
+	}
+	for name, value := range extra {
+		values[name] = value
+	}
+
+	environment := make([]string, 0, len(values))
+	for name, value := range values {
+		environment = append(environment, name+"="+value)
+	}
+	sort.Strings(environment)
+	return environment
+}
+
+// cappedBuffer keeps the first limit bytes written to it and drops the rest,
+// noting that it did. Writes never fail, so the command is not stopped by a
+// broken pipe when its output runs over.
+type cappedBuffer struct {
+	limit     int
+	data      []byte
+	truncated bool
+}
+
+func (buffer *cappedBuffer) Write(chunk []byte) (int, error) {
+	room := max(buffer.limit-len(buffer.data), 0)
+	if len(chunk) > room {
+		buffer.data = append(buffer.data, chunk[:room]...)
+		buffer.truncated = true
+		return len(chunk), nil
+	}
+	buffer.data = append(buffer.data, chunk...)
+	return len(chunk), nil
+}
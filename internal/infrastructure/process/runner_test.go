@@ -0,0 +1,101 @@
+//go:build unix
+
+package process
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func shellSpec(t *testing.T, script string) domain.CommandSpec {
+	t.Helper()
+
+	return domain.CommandSpec{
+		WorktreePath:   t.TempDir(),
+		Command:        "sh",
+		Args:           []string{"-c", script},
+		Timeout:        10 * time.Second,
+		MaxOutputBytes: 1024,
+	}
+}
+
+func TestRunner_Run_CapturesOutputAndExitCodeInWorktree(t *testing.T) {
+	// arrange
+	spec := shellSpec(t, "pwd; echo failing >&2; exit 3")
+
+	// act
+	result, err := NewRunner(NoSandbox{}).Run(context.Background(), spec)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	realWorktree, _ := filepath.EvalSymlinks(spec.WorktreePath)
+	if strings.TrimSpace(string(result.Stdout)) != realWorktree {
+		t.Errorf("stdout = %q, want the worktree path %s", result.Stdout, realWorktree)
+	}
+	if string(result.Stderr) != "failing\n" || result.ExitCode != 3 || result.TimedOut {
+		t.Errorf("result = %+v, want stderr and exit code 3", result)
+	}
+}
+
+func TestRunner_Run_CapsOutput(t *testing.T) {
+	// arrange
+	spec := shellSpec(t, "i=0; while [ $i -lt 200 ]; do echo 0123456789; i=$((i+1)); done")
+
+	// act
+	result, err := NewRunner(NoSandbox{}).Run(context.Background(), spec)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if len(result.Stdout) != 1024 || !result.StdoutTruncated || result.ExitCode != 0 {
+		t.Errorf("got %d bytes, truncated %v, exit code %d; want the first 1024 bytes of a successful run", len(result.Stdout), result.StdoutTruncated, result.ExitCode)
+	}
+}
+
+func TestRunner_Run_TimeoutKillsChildrenToo(t *testing.T) {
+	// arrange
+	spec := shellSpec(t, "sleep 30 & sleep 30")
+	spec.Timeout = 200 * time.Millisecond
+
+	// act
+	result, err := NewRunner(NoSandbox{}).Run(context.Background(), spec)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if !result.TimedOut || result.ExitCode != -1 {
+		t.Errorf("result = %+v, want a killed run", result)
+	}
+	if result.Duration > waitDelay {
+		t.Errorf("run took %v, want the background sleep killed with the group", result.Duration)
+	}
+}
+
+func TestRunner_Run_RemovesDeniedEnvironment(t *testing.T) {
+	// arrange
+	t.Setenv("RUNNER_TEST_SECRET", "hunter2")
+	t.Setenv("RUNNER_TEST_PLAIN", "visible")
+	spec := shellSpec(t, `echo "$RUNNER_TEST_SECRET|$RUNNER_TEST_PLAIN|$RUNNER_TEST_EXTRA"`)
+	spec.DeniedEnv = []string{"*_SECRET"}
+	spec.Env = map[string]string{"RUNNER_TEST_EXTRA": "added"}
+
+	// act
+	result, err := NewRunner(NoSandbox{}).Run(context.Background(), spec)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if string(result.Stdout) != "|visible|added\n" {
+		t.Errorf("stdout = %q, want the secret removed and the extra variable set", result.Stdout)
+	}
+}
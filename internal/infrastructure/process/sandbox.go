@@ -0,0 +1,26 @@
+package process
+
+import "os/exec"
+
+// Sandbox isolates a command from the rest of the filesystem. Wrap is called
+// once every other field of command is set and may rewrite it entirely; the
+// returned function is called after the command finished and reports
+// whether setting up the isolation failed.
+type Sandbox interface {
+	Wrap(command *exec.Cmd, worktreePath string) (func() error, error)
+}
+
+// SandboxOptions lists what a sandboxed command sees besides its worktree.
+// Paths are absolute; those that do not exist are skipped.
+type SandboxOptions struct {
+	ReadOnlyPaths []string
+	WritablePaths []string
+}
+
+// NoSandbox runs commands with the same view of the filesystem as the server,
+// for platforms without namespaces and for when isolation is switched off
+type NoSandbox struct{}
+
+func (NoSandbox) Wrap(command *exec.Cmd, worktreePath string) (func() error, error) {
+	return func() error { return nil }, nil
+}
@@ -0,0 +1,544 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const (
+	// sandboxInitName is the argv[0] under which the server binary re-executes
+	// itself to set up the namespaces before starting the actual command
+	sandboxInitName = "orchestragent-sandbox-init"
+	// sandboxInitFailed is the exit status of an init that could not set up
+	// the sandbox; the reason is written to sandboxErrorFD
+	sandboxInitFailed = 125
+	sandboxErrorFD    = 3
+	probeTimeout      = 10 * time.Second
+
+	prSetNoNewPrivs   = 0x26
+	sysMountSetattr   = 442
+	mountAttrReadOnly = 0x1
+	atRecursive       = 0x8000
+)
+
+// atFDCWD is a variable because the negative constant cannot be converted
+// to a system call argument
+var atFDCWD = -100
+
+// sandboxDevices are the device nodes bound into every sandbox
+var sandboxDevices = []string{"null", "zero", "full", "random", "urandom", "tty"}
+
+// NamespaceSandbox runs every command in fresh user, mount, PID, IPC and UTS
+// namespaces whose root holds nothing but the worktree, read-write, and the
+// configured paths. The worktree's .git is read-only so that commands cannot
+// redirect the server's own git operations.
+type NamespaceSandbox struct {
+	mounts   []sandboxMount
+	symlinks []sandboxSymlink
+}
+
+type sandboxMount struct {
+	Path     string `json:"path"`
+	Writable bool   `json:"writable"`
+}
+
+type sandboxSymlink struct {
+	Path   string `json:"path"`
+	Target string `json:"target"`
+}
+
+// sandboxSpec is handed to the init as its only argument
+type sandboxSpec struct {
+	Mounts   []sandboxMount   `json:"mounts"`
+	Symlinks []sandboxSymlink `json:"symlinks"`
+	Worktree string           `json:"worktree"`
+	Binary   string           `json:"binary"`
+	Args     []string         `json:"args"`
+}
+
+// NewNamespaceSandbox resolves the configured paths and starts an empty
+// sandbox once, so that a kernel without unprivileged user namespaces is
+// reported here rather than on the first command. RunSandboxInit must be the
+// first thing the binary's main function does.
+func NewNamespaceSandbox(options SandboxOptions) (Sandbox, error) {
+	sandbox := &NamespaceSandbox{}
+	for _, readOnlyPath := range options.ReadOnlyPaths {
+		if err := sandbox.addPath(readOnlyPath, false); err != nil {
+			return nil, err
+		}
+	}
+	for _, writablePath := range options.WritablePaths {
+		if err := sandbox.addPath(writablePath, true); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := sandbox.probe(); err != nil {
+		return nil, fmt.Errorf("namespaces are not available: %w", err)
+	}
+	return sandbox, nil
+}
+
+// addPath records a path that exists; a symbolic link such as /bin on merged
+// /usr systems is recreated in the sandbox and its target is bound instead
+func (sandbox *NamespaceSandbox) addPath(path string, writable bool) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("sandbox path %q must be absolute", path)
+	}
+	path = filepath.Clean(path)
+
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect sandbox path %s: %w", path, err)
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return fmt.Errorf("failed to read sandbox path %s: %w", path, err)
+		}
+		sandbox.symlinks = append(sandbox.symlinks, sandboxSymlink{Path: path, Target: target})
+	}
+
+	realPath, err := filepath.EvalSymlinks(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve sandbox path %s: %w", path, err)
+	}
+	if realPath == "/" {
+		return fmt.Errorf("sandbox path %s must not be the filesystem root", path)
+	}
+	sandbox.mounts = append(sandbox.mounts, sandboxMount{Path: realPath, Writable: writable})
+	return nil
+}
+
+// probe runs the init without a command to prove the namespaces can be set up
+func (sandbox *NamespaceSandbox) probe() error {
+	probeContext, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	command := exec.CommandContext(probeContext, "/proc/self/exe")
+	finish, err := sandbox.wrap(command, sandboxSpec{
+		Mounts:   append([]sandboxMount{}, sandbox.mounts...),
+		Symlinks: sandbox.symlinks,
+	})
+	if err != nil {
+		return err
+	}
+	output, runErr := command.CombinedOutput()
+	if err := finish(); err != nil {
+		return err
+	}
+	if runErr != nil {
+		return fmt.Errorf("%w: %s", runErr, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func (sandbox *NamespaceSandbox) Wrap(command *exec.Cmd, worktreePath string) (func() error, error) {
+	realWorktree, err := filepath.EvalSymlinks(worktreePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve worktree %s: %w", worktreePath, err)
+	}
+
+	if command.Env == nil {
+		command.Env = os.Environ()
+	}
+	command.Env = append(command.Env, sandbox.goCacheEnv(command.Env)...)
+
+	mounts := append([]sandboxMount{}, sandbox.mounts...)
+	mounts = append(mounts, sandboxMount{Path: realWorktree, Writable: true})
+	if _, err := os.Lstat(filepath.Join(realWorktree, ".git")); err == nil {
+		mounts = append(mounts, sandboxMount{Path: filepath.Join(realWorktree, ".git")})
+	}
+
+	return sandbox.wrap(command, sandboxSpec{
+		Mounts:   mounts,
+		Symlinks: sandbox.symlinks,
+		Worktree: realWorktree,
+		Binary:   command.Path,
+		Args:     command.Args,
+	})
+}
+
+// goCacheEnv moves the go command's build and module caches to the
+// sandbox's private /tmp unless the writable paths hold them. Both default to
+// directories below $HOME, which the sandbox hides, and go refuses to build
+// without them. Caches moved to /tmp start empty on every run.
+func (sandbox *NamespaceSandbox) goCacheEnv(environment []string) []string {
+	home := lookupEnv(environment, "HOME")
+	cacheHome := lookupEnv(environment, "XDG_CACHE_HOME")
+	if cacheHome == "" && home != "" {
+		cacheHome = filepath.Join(home, ".cache")
+	}
+	goPath := ""
+	if goPaths := filepath.SplitList(lookupEnv(environment, "GOPATH")); len(goPaths) > 0 {
+		goPath = goPaths[0]
+	} else if home != "" {
+		goPath = filepath.Join(home, "go")
+	}
+
+	caches := []struct {
+		name     string
+		fallback string
+		location string
+	}{
+		{"GOCACHE", "/tmp/go-build", joinIfSet(cacheHome, "go-build")},
+		{"GOMODCACHE", "/tmp/go-mod", joinIfSet(goPath, "pkg", "mod")},
+	}
+	overrides := make([]string, 0, len(caches))
+	for _, cache := range caches {
+		location := lookupEnv(environment, cache.name)
+		if location == "off" {
+			continue
+		}
+		if location == "" {
+			location = cache.location
+		}
+		if location == "" || !sandbox.writable(location) {
+			overrides = append(overrides, cache.name+"="+cache.fallback)
+		}
+	}
+	return overrides
+}
+
+// writable reports whether path lies in one of the writable paths
+func (sandbox *NamespaceSandbox) writable(path string) bool {
+	if !filepath.IsAbs(path) {
+		return false
+	}
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		realPath = filepath.Clean(path)
+	}
+	for _, mount := range sandbox.mounts {
+		if mount.Writable && (realPath == mount.Path || strings.HasPrefix(realPath, mount.Path+string(filepath.Separator))) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupEnv returns the value environment gives name, where later entries
+// win as they do for exec.Cmd
+func lookupEnv(environment []string, name string) string {
+	for index := len(environment) - 1; index >= 0; index-- {
+		if value, found := strings.CutPrefix(environment[index], name+"="); found {
+			return value
+		}
+	}
+	return ""
+}
+
+func joinIfSet(directory string, elements ...string) string {
+	if directory == "" {
+		return ""
+	}
+	return filepath.Join(append([]string{directory}, elements...)...)
+}
+
+// wrap turns command into a run of the init, which sets up the namespaces
+// described by spec and then executes the original binary in them
+func (sandbox *NamespaceSandbox) wrap(command *exec.Cmd, spec sandboxSpec) (func() error, error) {
+	// parents are mounted before their children; for equal paths the later
+	// entry wins, which keeps .git read-only inside a writable worktree
+	sort.SliceStable(spec.Mounts, func(left, right int) bool {
+		return spec.Mounts[left].Path < spec.Mounts[right].Path
+	})
+	encodedSpec, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode sandbox: %w", err)
+	}
+
+	errorReader, errorWriter, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sandbox error pipe: %w", err)
+	}
+
+	command.Path = "/proc/self/exe"
+	command.Args = []string{sandboxInitName, string(encodedSpec)}
+	command.ExtraFiles = []*os.File{errorWriter}
+	if command.SysProcAttr == nil {
+		command.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	command.SysProcAttr.Cloneflags |= syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID |
+		syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS
+	command.SysProcAttr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}}
+	command.SysProcAttr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}}
+	command.SysProcAttr.GidMappingsEnableSetgroups = false
+
+	finish := func() error {
+		errorWriter.Close()
+		defer errorReader.Close()
+
+		message, err := io.ReadAll(io.LimitReader(errorReader, 4096))
+		if err != nil {
+			return fmt.Errorf("failed to read sandbox error pipe: %w", err)
+		}
+		if len(message) > 0 {
+			return errors.New(string(message))
+		}
+		return nil
+	}
+	return finish, nil
+}
+
+// RunSandboxInit takes over the process when it was started as the init of a
+// namespace sandbox and never returns in that case. It must be called before
+// anything else in main, and in TestMain of tests that use the sandbox.
+func RunSandboxInit() {
+	if len(os.Args) != 2 || os.Args[0] != sandboxInitName {
+		return
+	}
+
+	errorPipe := os.NewFile(sandboxErrorFD, "sandbox-error")
+	var spec sandboxSpec
+	if err := json.Unmarshal([]byte(os.Args[1]), &spec); err != nil {
+		failSandboxInit(errorPipe, fmt.Errorf("failed to decode sandbox: %w", err))
+	}
+
+	// capabilities belong to the thread, so the one that drops them must be
+	// the one that executes the command
+	runtime.LockOSThread()
+	if err := enterSandbox(spec); err != nil {
+		failSandboxInit(errorPipe, err)
+	}
+	if spec.Binary == "" {
+		os.Exit(0)
+	}
+
+	syscall.CloseOnExec(sandboxErrorFD)
+	err := syscall.Exec(spec.Binary, spec.Args, os.Environ())
+	failSandboxInit(errorPipe, fmt.Errorf("failed to start %s in the sandbox: %w", spec.Binary, err))
+}
+
+func failSandboxInit(errorPipe *os.File, err error) {
+	errorPipe.WriteString(err.Error())
+	os.Exit(sandboxInitFailed)
+}
+
+// enterSandbox builds the new root on a tmpfs, following bubblewrap: the old
+// root is moved aside with pivot_root, the allowed paths are bound from it,
+// and it is detached before pivoting into the new root
+func enterSandbox(spec sandboxSpec) error {
+	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("failed to make mounts private: %w", err)
+	}
+	if err := syscall.Mount("tmpfs", "/tmp", "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "mode=0755"); err != nil {
+		return fmt.Errorf("failed to mount staging tmpfs: %w", err)
+	}
+	if err := os.Mkdir("/tmp/newroot", 0o755); err != nil {
+		return err
+	}
+	if err := os.Mkdir("/tmp/oldroot", 0o755); err != nil {
+		return err
+	}
+	if err := syscall.PivotRoot("/tmp", "/tmp/oldroot"); err != nil {
+		return fmt.Errorf("failed to move the old root aside: %w", err)
+	}
+	if err := syscall.Chdir("/"); err != nil {
+		return err
+	}
+	if err := syscall.Mount("tmpfs", "/newroot", "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "mode=0755"); err != nil {
+		return fmt.Errorf("failed to mount the new root: %w", err)
+	}
+
+	if err := populateDevices(); err != nil {
+		return err
+	}
+	// a fresh proc only shows the sandbox's own processes; without one the
+	// command runs without /proc rather than seeing the server's
+	if err := os.Mkdir("/newroot/proc", 0o755); err != nil {
+		return err
+	}
+	_ = syscall.Mount("proc", "/newroot/proc", "proc", syscall.MS_NOSUID|syscall.MS_NODEV|syscall.MS_NOEXEC, "")
+	if err := os.Mkdir("/newroot/tmp", 0o755); err != nil {
+		return err
+	}
+	if err := syscall.Mount("tmpfs", "/newroot/tmp", "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "mode=1777"); err != nil {
+		return fmt.Errorf("failed to mount /tmp: %w", err)
+	}
+
+	for _, mount := range spec.Mounts {
+		if err := bindIntoSandbox(mount); err != nil {
+			return err
+		}
+	}
+	for _, symlink := range spec.Symlinks {
+		if err := os.MkdirAll(filepath.Dir("/newroot"+symlink.Path), 0o755); err != nil {
+			return fmt.Errorf("failed to link %s: %w", symlink.Path, err)
+		}
+		if err := os.Symlink(symlink.Target, "/newroot"+symlink.Path); err != nil && !errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("failed to link %s: %w", symlink.Path, err)
+		}
+	}
+
+	if err := syscall.Unmount("/oldroot", syscall.MNT_DETACH); err != nil {
+		return fmt.Errorf("failed to detach the old root: %w", err)
+	}
+	if err := syscall.Chdir("/newroot"); err != nil {
+		return err
+	}
+	if err := syscall.PivotRoot(".", "."); err != nil {
+		return fmt.Errorf("failed to enter the new root: %w", err)
+	}
+	if err := syscall.Unmount(".", syscall.MNT_DETACH); err != nil {
+		return fmt.Errorf("failed to detach the staging root: %w", err)
+	}
+	if err := syscall.Mount("", "/", "", syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY|syscall.MS_NOSUID|syscall.MS_NODEV, ""); err != nil {
+		return fmt.Errorf("failed to make the root read-only: %w", err)
+	}
+
+	workingDirectory := spec.Worktree
+	if workingDirectory == "" {
+		workingDirectory = "/"
+	}
+	if err := syscall.Chdir(workingDirectory); err != nil {
+		return fmt.Errorf("failed to enter the worktree: %w", err)
+	}
+
+	return dropPrivileges()
+}
+
+func populateDevices() error {
+	if err := os.Mkdir("/newroot/dev", 0o755); err != nil {
+		return err
+	}
+	for _, device := range sandboxDevices {
+		source := "/oldroot/dev/" + device
+		if _, err := os.Stat(source); err != nil {
+			continue
+		}
+		target := "/newroot/dev/" + device
+		if err := os.WriteFile(target, nil, 0o644); err != nil {
+			return err
+		}
+		if err := syscall.Mount(source, target, "", syscall.MS_BIND, ""); err != nil {
+			return fmt.Errorf("failed to bind /dev/%s: %w", device, err)
+		}
+	}
+	for name, target := range map[string]string{
+		"fd":     "/proc/self/fd",
+		"stdin":  "/proc/self/fd/0",
+		"stdout": "/proc/self/fd/1",
+		"stderr": "/proc/self/fd/2",
+	} {
+		if err := os.Symlink(target, "/newroot/dev/"+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindIntoSandbox binds a host path to the same place in the new root,
+// creating the mount point where the path's parents do not already provide it
+func bindIntoSandbox(mount sandboxMount) error {
+	source := "/oldroot" + mount.Path
+	target := "/newroot" + mount.Path
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", mount.Path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to create mount point for %s: %w", mount.Path, err)
+	}
+	if info.IsDir() {
+		err = os.MkdirAll(target, 0o755)
+	} else if _, statErr := os.Stat(target); statErr != nil {
+		err = os.WriteFile(target, nil, 0o644)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create mount point for %s: %w", mount.Path, err)
+	}
+
+	if err := syscall.Mount(source, target, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+		return fmt.Errorf("failed to bind %s: %w", mount.Path, err)
+	}
+	if mount.Writable {
+		return nil
+	}
+	if err := makeReadOnly(target); err != nil {
+		return fmt.Errorf("failed to make %s read-only: %w", mount.Path, err)
+	}
+	return nil
+}
+
+// makeReadOnly marks target and every mount below it read-only. Kernels
+// before mount_setattr (5.12) only get the top mount remounted, keeping the
+// flags the user namespace is not allowed to clear.
+func makeReadOnly(target string) error {
+	targetBytes, err := syscall.BytePtrFromString(target)
+	if err != nil {
+		return err
+	}
+	attributes := struct {
+		set         uint64
+		clear       uint64
+		propagation uint64
+		userNS      uint64
+	}{set: mountAttrReadOnly}
+
+	_, _, errno := syscall.Syscall6(sysMountSetattr, uintptr(atFDCWD), uintptr(unsafe.Pointer(targetBytes)),
+		atRecursive, uintptr(unsafe.Pointer(&attributes)), unsafe.Sizeof(attributes), 0)
+	if errno == 0 {
+		return nil
+	}
+	if errno != syscall.ENOSYS {
+		return errno
+	}
+
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(target, &stat); err != nil {
+		return err
+	}
+	flags := uintptr(syscall.MS_BIND | syscall.MS_REMOUNT | syscall.MS_RDONLY)
+	for statFlag, mountFlag := range map[int64]uintptr{
+		0x2:    syscall.MS_NOSUID,
+		0x4:    syscall.MS_NODEV,
+		0x8:    syscall.MS_NOEXEC,
+		0x400:  syscall.MS_NOATIME,
+		0x800:  syscall.MS_NODIRATIME,
+		0x1000: syscall.MS_RELATIME,
+	} {
+		if stat.Flags&statFlag != 0 {
+			flags |= mountFlag
+		}
+	}
+	return syscall.Mount("", target, "", flags, "")
+}
+
+// dropPrivileges empties the capability bounding set, so that the command
+// starts without the capabilities the init held in its user namespace, and
+// keeps it from gaining any through setuid binaries
+func dropPrivileges() error {
+	for capability := 0; ; capability++ {
+		_, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, syscall.PR_CAPBSET_DROP, uintptr(capability), 0)
+		if errno == syscall.EINVAL {
+			break
+		}
+		if errno != 0 {
+			return fmt.Errorf("failed to drop capability %d: %w", capability, errno)
+		}
+	}
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, prSetNoNewPrivs, 1, 0); errno != 0 {
+		return fmt.Errorf("failed to set no_new_privs: %w", errno)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package process
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func TestMain(m *testing.M) {
+	RunSandboxInit()
+	os.Exit(m.Run())
+}
+
+func newTestNamespaceSandbox(t *testing.T, readOnlyPaths ...string) Sandbox {
+	t.Helper()
+
+	sandbox, err := NewNamespaceSandbox(SandboxOptions{ReadOnlyPaths: readOnlyPaths})
+	if err != nil {
+		t.Skipf("namespace sandbox unavailable: %v", err)
+	}
+	return sandbox
+}
+
+func TestNamespaceSandbox_ConfinesCommandToWorktree(t *testing.T) {
+	// arrange
+	sandbox := newTestNamespaceSandbox(t, "/usr", "/bin", "/lib", "/lib64", "/etc")
+	outside := filepath.Join(t.TempDir(), "outside.txt")
+	if err := os.WriteFile(outside, []byte("secret"), 0o644); err != nil {
+		t.Fatalf("failed to write outside file: %v", err)
+	}
+	spec := shellSpec(t, strings.Join([]string{
+		"echo written > inside.txt",
+		"test -e " + outside + " && echo outside-visible",
+		"touch /usr/sandbox-test 2>/dev/null && echo usr-writable",
+		"echo tampered >> .git 2>/dev/null && echo git-writable",
+		"echo done",
+	}, "\n"))
+	if err := os.WriteFile(filepath.Join(spec.WorktreePath, ".git"), []byte("gitdir: /elsewhere\n"), 0o644); err != nil {
+		t.Fatalf("failed to write .git: %v", err)
+	}
+
+	// act
+	result, err := NewRunner(sandbox).Run(context.Background(), spec)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if string(result.Stdout) != "done\n" || result.ExitCode != 0 {
+		t.Errorf("result = %+v with stdout %q, want only the worktree writable and nothing else visible", result, result.Stdout)
+	}
+	if content, err := os.ReadFile(filepath.Join(spec.WorktreePath, "inside.txt")); err != nil || string(content) != "written\n" {
+		t.Errorf("inside.txt = %q, %v; want the write to reach the worktree", content, err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(spec.WorktreePath, ".git")); string(content) != "gitdir: /elsewhere\n" {
+		t.Errorf(".git = %q, want it unchanged", content)
+	}
+}
+
+func TestNamespaceSandbox_ReportsBinaryOutsideSandbox(t *testing.T) {
+	// arrange
+	sandbox := newTestNamespaceSandbox(t, "/etc")
+	spec := shellSpec(t, "true")
+
+	// act
+	_, err := NewRunner(sandbox).Run(context.Background(), spec)
+
+	// assert
+	if err == nil || !strings.Contains(err.Error(), "failed to isolate sh") {
+		t.Errorf("Run() error = %v, want the sandbox failure", err)
+	}
+}
+
+func TestNamespaceSandbox_RunsGoWithCachesBelowHiddenHome(t *testing.T) {
+	// arrange
+	goRoot := runtime.GOROOT()
+	goBinary := filepath.Join(goRoot, "bin", "go")
+	if _, err := os.Stat(goBinary); err != nil {
+		t.Skipf("go toolchain unavailable: %v", err)
+	}
+	sandbox := newTestNamespaceSandbox(t, "/usr", "/bin", "/lib", "/lib64", "/etc", goRoot)
+	worktreePath := t.TempDir()
+	files := map[string]string{
+		"go.mod":     "module example.com/sandboxed\n\ngo 1.21\n",
+		"sandbox.go": "package sandboxed\n\nfunc Answer() int { return 42 }\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(worktreePath, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	spec := domain.CommandSpec{
+		WorktreePath: worktreePath,
+		Command:      goBinary,
+		Args:         []string{"build", "./..."},
+		Env: map[string]string{
+			"HOME":           "/home/sandboxed",
+			"GOCACHE":        "",
+			"GOMODCACHE":     "",
+			"GOPATH":         "",
+			"XDG_CACHE_HOME": "",
+			"GOFLAGS":        "",
+			"GOPROXY":        "off",
+			"GOTOOLCHAIN":    "local",
+		},
+		Timeout:        time.Minute,
+		MaxOutputBytes: 4096,
+	}
+
+	// act
+	result, err := NewRunner(sandbox).Run(context.Background(), spec)
+
+	// assert
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if result.ExitCode != 0 {
+		t.Errorf("go build exit code = %d with stderr %q, want the caches moved somewhere writable", result.ExitCode, result.Stderr)
+	}
+}
+
+func TestNamespaceSandbox_GoCacheEnv_KeepsCachesInWritablePaths(t *testing.T) {
+	// arrange
+	sandbox := &NamespaceSandbox{mounts: []sandboxMount{
+		{Path: "/home/me/.cache", Writable: true},
+		{Path: "/home/me/go", Writable: false},
+	}}
+
+	testCases := []struct {
+		name        string
+		environment []string
+		expected    []string
+	}{
+		{"defaults below home", []string{"HOME=/home/me"}, []string{"GOMODCACHE=/tmp/go-mod"}},
+		{"explicit locations", []string{"HOME=/elsewhere", "GOCACHE=/home/me/.cache/build", "GOMODCACHE=/home/me/.cache/mod"}, []string{}},
+		{"gopath outside", []string{"HOME=/home/me", "GOPATH=/opt/go:/home/me/.cache"}, []string{"GOMODCACHE=/tmp/go-mod"}},
+		{"cache switched off", []string{"GOCACHE=off"}, []string{"GOMODCACHE=/tmp/go-mod"}},
+		{"later entries win", []string{"HOME=/home/me", "XDG_CACHE_HOME=/nowhere", "GOCACHE=/nowhere", "GOCACHE=/home/me/.cache/go-build"}, []string{"GOMODCACHE=/tmp/go-mod"}},
+		{"no home", []string{}, []string{"GOCACHE=/tmp/go-build", "GOMODCACHE=/tmp/go-mod"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// act
+			overrides := sandbox.goCacheEnv(testCase.environment)
+
+			// assert
+			if strings.Join(overrides, " ") != strings.Join(testCase.expected, " ") {
+				t.Errorf("goCacheEnv() = %q, want %q", overrides, testCase.expected)
+			}
+		})
+	}
+}
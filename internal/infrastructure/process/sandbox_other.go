@@ -0,0 +1,13 @@
+//go:build !linux
+
+package process
+
+import "errors"
+
+// NewNamespaceSandbox always fails outside Linux, which has no namespaces
+func NewNamespaceSandbox(options SandboxOptions) (Sandbox, error) {
+	return nil, errors.New("namespaces are only available on Linux")
+}
+
+// RunSandboxInit does nothing outside Linux
+func RunSandboxInit() {}
@@ -0,0 +1,370 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+const (
+	newFilePermissions      = 0o644
+	newDirectoryPermissions = 0o755
+)
+
+// FileSystem confines file operations to a session worktree. Paths are
+// checked twice: lexically, and after resolving symlinks against the real
+// worktree root. The operations themselves then go through an os.Root, which
+// refuses to follow anything out of the worktree if it changed in between.
+type FileSystem struct{}
+
+func NewFileSystem() *FileSystem {
+	return &FileSystem{}
+}
+
+func (fileSystem *FileSystem) ReadFile(ctx context.Context, worktreePath string, path string, maxBytes int) (*domain.FileContent, error) {
+	relativePath, err := localPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := resolveRealPath(worktreePath, relativePath, true); err != nil {
+		return nil, err
+	}
+
+	root, err := os.OpenRoot(worktreePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open worktree: %w", err)
+	}
+	defer root.Close()
+
+	file, err := root.Open(relativePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("failed to read %s: is a directory", path)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, int64(maxBytes)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return &domain.FileContent{Data: data, SizeBytes: info.Size()}, nil
+}
+
+func (fileSystem *FileSystem) WriteFile(ctx context.Context, worktreePath string, path string, data []byte) (bool, error) {
+	relativePath, err := localPath(path)
+	if err != nil {
+		return false, err
+	}
+	if err := checkChangeable(worktreePath, relativePath, true); err != nil {
+		return false, err
+	}
+
+	root, err := os.OpenRoot(worktreePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to open worktree: %w", err)
+	}
+	defer root.Close()
+
+	if err := makeDirectories(root, filepath.Dir(relativePath)); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	info, err := root.Stat(relativePath)
+	created := errors.Is(err, fs.ErrNotExist)
+	if err != nil && !created {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err == nil && info.IsDir() {
+		return false, fmt.Errorf("failed to write %s: is a directory", path)
+	}
+
+	file, err := root.OpenFile(relativePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, newFilePermissions)
+	if err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return created, nil
+}
+
+func (fileSystem *FileSystem) ListDirectory(ctx context.Context, worktreePath string, path string) ([]domain.DirectoryEntry, error) {
+	relativePath, err := localPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := resolveRealPath(worktreePath, relativePath, true); err != nil {
+		return nil, err
+	}
+
+	root, err := os.OpenRoot(worktreePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open worktree: %w", err)
+	}
+	defer root.Close()
+
+	directory, err := root.Open(relativePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", path, err)
+	}
+	defer directory.Close()
+
+	dirEntries, err := directory.ReadDir(-1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", path, err)
+	}
+
+	entries := make([]domain.DirectoryEntry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		info, err := dirEntry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", path, err)
+		}
+
+		entry := domain.DirectoryEntry{
+			Name:       dirEntry.Name(),
+			Path:       filepath.ToSlash(filepath.Join(relativePath, dirEntry.Name())),
+			Type:       entryType(info.Mode()),
+			ModifiedAt: info.ModTime(),
+		}
+		if entry.Type == domain.DirectoryEntryFile {
+			entry.SizeBytes = info.Size()
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+func (fileSystem *FileSystem) DeleteFile(ctx context.Context, worktreePath string, path string) error {
+	relativePath, err := localPath(path)
+	if err != nil {
+		return err
+	}
+	if err := checkChangeable(worktreePath, relativePath, false); err != nil {
+		return err
+	}
+
+	root, err := os.OpenRoot(worktreePath)
+	if err != nil {
+		return fmt.Errorf("failed to open worktree: %w", err)
+	}
+	defer root.Close()
+
+	info, err := root.Lstat(relativePath)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("failed to delete %s: is a directory", path)
+	}
+
+	if err := root.Remove(relativePath); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", path, err)
+	}
+	return nil
+}
+
+// MoveFile renames between the real paths of source and destination, since
+// os.Root offers no rename. Only the parent directories are resolved, so a
+// symlink is moved rather than the file it points to.
+func (fileSystem *FileSystem) MoveFile(ctx context.Context, worktreePath string, sourcePath string, destinationPath string, overwrite bool) error {
+	source, err := localPath(sourcePath)
+	if err != nil {
+		return err
+	}
+	destination, err := localPath(destinationPath)
+	if err != nil {
+		return err
+	}
+	if err := checkChangeable(worktreePath, source, false); err != nil {
+		return err
+	}
+	if err := checkChangeable(worktreePath, destination, false); err != nil {
+		return err
+	}
+
+	root, err := os.OpenRoot(worktreePath)
+	if err != nil {
+		return fmt.Errorf("failed to open worktree: %w", err)
+	}
+	defer root.Close()
+
+	if _, err := root.Lstat(source); err != nil {
+		return fmt.Errorf("failed to move %s: %w", sourcePath, err)
+	}
+	info, err := root.Lstat(destination)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to move %s to %s: %w", sourcePath, destinationPath, err)
+	}
+	if err == nil && (!overwrite || info.IsDir()) {
+		return fmt.Errorf("failed to move %s: %s already exists", sourcePath, destinationPath)
+	}
+
+	if err := makeDirectories(root, filepath.Dir(destination)); err != nil {
+		return fmt.Errorf("failed to move %s to %s: %w", sourcePath, destinationPath, err)
+	}
+
+	realRoot, err := filepath.EvalSymlinks(worktreePath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve worktree: %w", err)
+	}
+	realSource, err := resolveRealPath(worktreePath, source, false)
+	if err != nil {
+		return err
+	}
+	realDestination, err := resolveRealPath(worktreePath, destination, false)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(filepath.Join(realRoot, realSource), filepath.Join(realRoot, realDestination)); err != nil {
+		return fmt.Errorf("failed to move %s to %s: %w", sourcePath, destinationPath, err)
+	}
+	return nil
+}
+
+// localPath validates a slash-separated path relative to the worktree root
+// and returns it in the host's form. An empty path is the root itself.
+func localPath(path string) (string, error) {
+	hostPath := filepath.FromSlash(path)
+	for _, component := range strings.Split(hostPath, string(filepath.Separator)) {
+		if component == ".." {
+			return "", fmt.Errorf("%w: %s", domain.ErrPathOutsideWorktree, path)
+		}
+	}
+
+	cleaned := filepath.Clean(hostPath)
+	if cleaned == "." {
+		return cleaned, nil
+	}
+	if !filepath.IsLocal(cleaned) {
+		return "", fmt.Errorf("%w: %s", domain.ErrPathOutsideWorktree, path)
+	}
+	return cleaned, nil
+}
+
+// checkChangeable refuses changes to the worktree root and to .git, whether
+// named directly or reached through a symlink
+func checkChangeable(worktreePath string, relativePath string, followFinal bool) error {
+	if relativePath == "." || touchesGitDirectory(relativePath) {
+		return fmt.Errorf("%w: %s", domain.ErrProtectedPath, filepath.ToSlash(relativePath))
+	}
+
+	realPath, err := resolveRealPath(worktreePath, relativePath, followFinal)
+	if err != nil {
+		return err
+	}
+	if realPath == "." || touchesGitDirectory(realPath) {
+		return fmt.Errorf("%w: %s resolves to %s", domain.ErrProtectedPath, filepath.ToSlash(relativePath), filepath.ToSlash(realPath))
+	}
+	return nil
+}
+
+func touchesGitDirectory(relativePath string) bool {
+	for _, component := range strings.Split(relativePath, string(filepath.Separator)) {
+		if strings.EqualFold(component, ".git") {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveRealPath follows the symlinks along relativePath and returns where
+// it really points, relative to the real worktree root. Components that do
+// not exist yet are kept as given, and the final component is only followed
+// with followFinal.
+func resolveRealPath(worktreePath string, relativePath string, followFinal bool) (string, error) {
+	realRoot, err := filepath.EvalSymlinks(worktreePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve worktree: %w", err)
+	}
+
+	existing := relativePath
+	missing := make([]string, 0)
+	if !followFinal && relativePath != "." {
+		existing = filepath.Dir(relativePath)
+		missing = append(missing, filepath.Base(relativePath))
+	}
+	for existing != "." {
+		_, err := os.Lstat(filepath.Join(realRoot, existing))
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("failed to resolve %s: %w", filepath.ToSlash(relativePath), err)
+		}
+		missing = append([]string{filepath.Base(existing)}, missing...)
+		existing = filepath.Dir(existing)
+	}
+
+	realPath, err := filepath.EvalSymlinks(filepath.Join(realRoot, existing))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", filepath.ToSlash(relativePath), err)
+	}
+	realRelative, err := filepath.Rel(realRoot, realPath)
+	if err != nil || (realRelative != "." && !filepath.IsLocal(realRelative)) {
+		return "", fmt.Errorf("%w: %s", domain.ErrPathOutsideWorktree, filepath.ToSlash(relativePath))
+	}
+
+	return filepath.Join(append([]string{realRelative}, missing...)...), nil
+}
+
+// makeDirectories creates directory and its missing parents inside root
+func makeDirectories(root *os.Root, directory string) error {
+	if directory == "." {
+		return nil
+	}
+	if err := makeDirectories(root, filepath.Dir(directory)); err != nil {
+		return err
+	}
+
+	err := root.Mkdir(directory, newDirectoryPermissions)
+	if err == nil || !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	info, err := root.Stat(directory)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", filepath.ToSlash(directory))
+	}
+	return nil
+}
+
+func entryType(mode fs.FileMode) domain.DirectoryEntryType {
+	switch {
+	case mode.IsRegular():
+		return domain.DirectoryEntryFile
+	case mode.IsDir():
+		return domain.DirectoryEntryDirectory
+	case mode&fs.ModeSymlink != 0:
+		return domain.DirectoryEntrySymlink
+	default:
+		return domain.DirectoryEntryOther
+	}
+}
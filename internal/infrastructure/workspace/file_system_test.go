@@ -0,0 +1,202 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// setupWorktree creates a worktree-like directory next to a sibling
+// directory holding a secret, and returns the worktree path and the secret's
+func setupWorktree(t *testing.T) (string, string) {
+	t.Helper()
+
+	baseDirectory := t.TempDir()
+	worktreePath := filepath.Join(baseDirectory, "worktree")
+	outsidePath := filepath.Join(baseDirectory, "outside")
+	for _, directory := range []string{filepath.Join(worktreePath, "src"), outsidePath} {
+		if err := os.MkdirAll(directory, 0o755); err != nil {
+			t.Fatalf("failed to create %s: %v", directory, err)
+		}
+	}
+
+	files := map[string]string{
+		filepath.Join(worktreePath, ".git"):        "gitdir: /repo/.git/worktrees/worktree\n",
+		filepath.Join(worktreePath, "src", "a.go"): "package src\n",
+		filepath.Join(outsidePath, "secret.txt"):   "secret\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	return worktreePath, filepath.Join(outsidePath, "secret.txt")
+}
+
+func TestFileSystem_ReadFile_TruncatesAtMaxBytes(t *testing.T) {
+	// arrange
+	worktreePath, _ := setupWorktree(t)
+	fileSystem := NewFileSystem()
+
+	// act
+	content, err := fileSystem.ReadFile(context.Background(), worktreePath, "src/a.go", 7)
+
+	// assert
+	if err != nil {
+		t.Fatalf("ReadFile() error: %v", err)
+	}
+	if string(content.Data) != "package" || content.SizeBytes != 12 || !content.Truncated() {
+		t.Errorf("content = %q of %d bytes, want the first 7 of 12 bytes", content.Data, content.SizeBytes)
+	}
+}
+
+func TestFileSystem_PathsOutsideWorktree_AreRejected(t *testing.T) {
+	// arrange
+	worktreePath, secretPath := setupWorktree(t)
+	if err := os.Symlink(filepath.Dir(secretPath), filepath.Join(worktreePath, "escape")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	fileSystem := NewFileSystem()
+	ctx := context.Background()
+
+	for _, path := range []string{"../outside/secret.txt", "src/../../outside/secret.txt", secretPath, "escape/secret.txt"} {
+		// act
+		_, readErr := fileSystem.ReadFile(ctx, worktreePath, path, 1024)
+		_, writeErr := fileSystem.WriteFile(ctx, worktreePath, path, []byte("overwritten\n"))
+		_, listErr := fileSystem.ListDirectory(ctx, worktreePath, filepath.Dir(path))
+
+		// assert
+		if !errors.Is(readErr, domain.ErrPathOutsideWorktree) {
+			t.Errorf("ReadFile(%q) error = %v, want ErrPathOutsideWorktree", path, readErr)
+		}
+		if !errors.Is(writeErr, domain.ErrPathOutsideWorktree) {
+			t.Errorf("WriteFile(%q) error = %v, want ErrPathOutsideWorktree", path, writeErr)
+		}
+		if !errors.Is(listErr, domain.ErrPathOutsideWorktree) {
+			t.Errorf("ListDirectory(%q) error = %v, want ErrPathOutsideWorktree", filepath.Dir(path), listErr)
+		}
+	}
+	if content, _ := os.ReadFile(secretPath); string(content) != "secret\n" {
+		t.Errorf("secret was changed to %q", content)
+	}
+}
+
+func TestFileSystem_ChangesToGit_AreRejected(t *testing.T) {
+	// arrange
+	worktreePath, _ := setupWorktree(t)
+	if err := os.Symlink(".git", filepath.Join(worktreePath, "git-link")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	fileSystem := NewFileSystem()
+	ctx := context.Background()
+
+	// act
+	_, writeErr := fileSystem.WriteFile(ctx, worktreePath, ".git", []byte("gitdir: /elsewhere\n"))
+	_, linkWriteErr := fileSystem.WriteFile(ctx, worktreePath, "git-link", []byte("gitdir: /elsewhere\n"))
+	deleteErr := fileSystem.DeleteFile(ctx, worktreePath, ".git")
+	moveErr := fileSystem.MoveFile(ctx, worktreePath, "src/a.go", ".GIT", true)
+
+	// assert
+	for name, err := range map[string]error{"write": writeErr, "write through symlink": linkWriteErr, "delete": deleteErr, "move": moveErr} {
+		if !errors.Is(err, domain.ErrProtectedPath) {
+			t.Errorf("%s error = %v, want ErrProtectedPath", name, err)
+		}
+	}
+	if content, _ := os.ReadFile(filepath.Join(worktreePath, ".git")); string(content) != "gitdir: /repo/.git/worktrees/worktree\n" {
+		t.Errorf(".git was changed to %q", content)
+	}
+}
+
+func TestFileSystem_WriteFile_CreatesParentDirectories(t *testing.T) {
+	// arrange
+	worktreePath, _ := setupWorktree(t)
+	fileSystem := NewFileSystem()
+	ctx := context.Background()
+
+	// act
+	created, err := fileSystem.WriteFile(ctx, worktreePath, "internal/new/b.go", []byte("package new\n"))
+	rewritten, rewriteErr := fileSystem.WriteFile(ctx, worktreePath, "internal/new/b.go", []byte("package b\n"))
+
+	// assert
+	if err != nil || rewriteErr != nil {
+		t.Fatalf("WriteFile() errors: %v, %v", err, rewriteErr)
+	}
+	if !created || rewritten {
+		t.Errorf("created = %v, rewritten = %v, want only the first write to create the file", created, rewritten)
+	}
+	if content, _ := os.ReadFile(filepath.Join(worktreePath, "internal", "new", "b.go")); string(content) != "package b\n" {
+		t.Errorf("content = %q, want the second write", content)
+	}
+}
+
+func TestFileSystem_ListDirectory_ReturnsSortedEntries(t *testing.T) {
+	// arrange
+	worktreePath, _ := setupWorktree(t)
+	fileSystem := NewFileSystem()
+
+	// act
+	entries, err := fileSystem.ListDirectory(context.Background(), worktreePath, "")
+
+	// assert
+	if err != nil {
+		t.Fatalf("ListDirectory() error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name != ".git" || entries[1].Path != "src" || entries[1].Type != domain.DirectoryEntryDirectory {
+		t.Errorf("entries = %+v, want .git and the src directory", entries)
+	}
+}
+
+func TestFileSystem_DeleteFile_RefusesDirectories(t *testing.T) {
+	// arrange
+	worktreePath, _ := setupWorktree(t)
+	fileSystem := NewFileSystem()
+	ctx := context.Background()
+
+	// act
+	directoryErr := fileSystem.DeleteFile(ctx, worktreePath, "src")
+	fileErr := fileSystem.DeleteFile(ctx, worktreePath, "src/a.go")
+
+	// assert
+	if directoryErr == nil {
+		t.Error("DeleteFile() expected error for a directory")
+	}
+	if fileErr != nil {
+		t.Fatalf("DeleteFile() error: %v", fileErr)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "src", "a.go")); !os.IsNotExist(err) {
+		t.Error("src/a.go should be deleted")
+	}
+}
+
+func TestFileSystem_MoveFile_RequiresOverwriteForExistingDestination(t *testing.T) {
+	// arrange
+	worktreePath, _ := setupWorktree(t)
+	if err := os.WriteFile(filepath.Join(worktreePath, "b.go"), []byte("package b\n"), 0o644); err != nil {
+		t.Fatalf("failed to write b.go: %v", err)
+	}
+	fileSystem := NewFileSystem()
+	ctx := context.Background()
+
+	// act
+	refusedErr := fileSystem.MoveFile(ctx, worktreePath, "src/a.go", "b.go", false)
+	movedErr := fileSystem.MoveFile(ctx, worktreePath, "src/a.go", "pkg/b.go", false)
+
+	// assert
+	if refusedErr == nil {
+		t.Error("MoveFile() expected error for an existing destination")
+	}
+	if movedErr != nil {
+		t.Fatalf("MoveFile() error: %v", movedErr)
+	}
+	if content, _ := os.ReadFile(filepath.Join(worktreePath, "pkg", "b.go")); string(content) != "package src\n" {
+		t.Errorf("moved content = %q, want src/a.go", content)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "src", "a.go")); !os.IsNotExist(err) {
+		t.Error("src/a.go should no longer exist")
+	}
+}
@@ -0,0 +1,162 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+// SessionArchiveStore keeps exported sessions below one directory. Archive
+// names are checked the same way worktree paths are, and the files inside
+// are written through an os.Root of the export directory.
+type SessionArchiveStore struct {
+	directory string
+}
+
+func NewSessionArchiveStore(directory string) *SessionArchiveStore {
+	return &SessionArchiveStore{directory: directory}
+}
+
+func (store *SessionArchiveStore) Create(ctx context.Context, name string) (string, error) {
+	if err := os.MkdirAll(store.directory, newDirectoryPermissions); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+	relativePath, err := store.archivePath(name, false)
+	if err != nil {
+		return "", err
+	}
+
+	root, err := os.OpenRoot(store.directory)
+	if err != nil {
+		return "", fmt.Errorf("failed to open export directory: %w", err)
+	}
+	defer root.Close()
+
+	if err := makeDirectories(root, filepath.Dir(relativePath)); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	if err := root.Mkdir(relativePath, newDirectoryPermissions); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return "", fmt.Errorf("export %s already exists", name)
+		}
+		return "", fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	return filepath.Join(store.directory, relativePath), nil
+}
+
+func (store *SessionArchiveStore) Locate(ctx context.Context, name string) (string, error) {
+	relativePath, err := store.archivePath(name, true)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(filepath.Join(store.directory, relativePath))
+	if err != nil {
+		return "", fmt.Errorf("export %s not found: %w", name, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("export %s is not a directory", name)
+	}
+	return filepath.Join(store.directory, relativePath), nil
+}
+
+func (store *SessionArchiveStore) WriteFile(ctx context.Context, name string, path string, data []byte) error {
+	filePath, err := store.filePath(name, path)
+	if err != nil {
+		return err
+	}
+
+	root, err := os.OpenRoot(store.directory)
+	if err != nil {
+		return fmt.Errorf("failed to open export directory: %w", err)
+	}
+	defer root.Close()
+
+	file, err := root.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, newFilePermissions)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func (store *SessionArchiveStore) ReadFile(ctx context.Context, name string, path string) ([]byte, error) {
+	filePath, err := store.filePath(name, path)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := os.OpenRoot(store.directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open export directory: %w", err)
+	}
+	defer root.Close()
+
+	file, err := root.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+func (store *SessionArchiveStore) Remove(ctx context.Context, name string) error {
+	relativePath, err := store.archivePath(name, false)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(filepath.Join(store.directory, relativePath)); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", name, err)
+	}
+	return nil
+}
+
+// archivePath returns where the archive called name really lies, relative
+// to the export directory. Absolute names are accepted when they point into
+// it; the export directory itself is never an archive.
+func (store *SessionArchiveStore) archivePath(name string, followFinal bool) (string, error) {
+	relativeName := name
+	if filepath.IsAbs(name) {
+		if relative, err := filepath.Rel(store.directory, name); err == nil {
+			relativeName = filepath.ToSlash(relative)
+		}
+	}
+
+	relativePath, err := localPath(relativeName)
+	if err == nil && relativePath != "." {
+		relativePath, err = resolveRealPath(store.directory, relativePath, followFinal)
+	}
+	if errors.Is(err, domain.ErrPathOutsideWorktree) || (err == nil && relativePath == ".") {
+		return "", fmt.Errorf("%w: %s", domain.ErrPathOutsideExportDirectory, name)
+	}
+	return relativePath, err
+}
+
+func (store *SessionArchiveStore) filePath(name string, path string) (string, error) {
+	relativePath, err := store.archivePath(name, true)
+	if err != nil {
+		return "", err
+	}
+	localFilePath, err := localPath(path)
+	if err != nil || localFilePath == "." {
+		return "", fmt.Errorf("%w: %s", domain.ErrPathOutsideExportDirectory, path)
+	}
+	return filepath.Join(relativePath, localFilePath), nil
+}
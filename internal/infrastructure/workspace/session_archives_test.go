@@ -0,0 +1,108 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tzDel/orchestragent-mcp/internal/domain"
+)
+
+func TestSessionArchiveStore_CreateWriteRead_StaysInExportDirectory(t *testing.T) {
+	// arrange
+	exportDirectory := filepath.Join(t.TempDir(), "exports")
+	store := NewSessionArchiveStore(exportDirectory)
+	ctx := context.Background()
+
+	// act
+	archivePath, err := store.Create(ctx, "team/abc-123")
+	if err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	writeErr := store.WriteFile(ctx, "team/abc-123", "manifest.json", []byte("{}\n"))
+	content, readErr := store.ReadFile(ctx, archivePath, "manifest.json")
+
+	// assert
+	if archivePath != filepath.Join(exportDirectory, "team", "abc-123") {
+		t.Errorf("Create() = %q, want the archive inside the export directory", archivePath)
+	}
+	if writeErr != nil || readErr != nil || string(content) != "{}\n" {
+		t.Errorf("ReadFile() = %q, %v after WriteFile() error %v; want the manifest back by its absolute path", content, readErr, writeErr)
+	}
+	if _, err := store.Create(ctx, "team/abc-123"); err == nil {
+		t.Error("Create() expected error for an existing archive")
+	}
+}
+
+func TestSessionArchiveStore_PathsOutsideExportDirectory_AreRejected(t *testing.T) {
+	// arrange
+	baseDirectory := t.TempDir()
+	exportDirectory := filepath.Join(baseDirectory, "exports")
+	outsidePath := filepath.Join(baseDirectory, "outside")
+	for _, directory := range []string{exportDirectory, outsidePath} {
+		if err := os.MkdirAll(directory, 0o755); err != nil {
+			t.Fatalf("failed to create %s: %v", directory, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(outsidePath, "manifest.json"), []byte("{}\n"), 0o644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+	if err := os.Symlink(outsidePath, filepath.Join(exportDirectory, "link")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	store := NewSessionArchiveStore(exportDirectory)
+	ctx := context.Background()
+
+	testCases := []struct {
+		name string
+		call func() error
+	}{
+		{"create with absolute path elsewhere", func() error { _, err := store.Create(ctx, filepath.Join(outsidePath, "new")); return err }},
+		{"create climbing out", func() error { _, err := store.Create(ctx, "../escape"); return err }},
+		{"create through symlink", func() error { _, err := store.Create(ctx, "link/new"); return err }},
+		{"create export directory itself", func() error { _, err := store.Create(ctx, exportDirectory); return err }},
+		{"locate absolute path elsewhere", func() error { _, err := store.Locate(ctx, outsidePath); return err }},
+		{"read through symlink", func() error { _, err := store.ReadFile(ctx, "link", "manifest.json"); return err }},
+		{"read file climbing out", func() error { _, err := store.ReadFile(ctx, "link/..", "../outside/manifest.json"); return err }},
+		{"remove climbing out", func() error { return store.Remove(ctx, "../outside") }},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// act
+			err := testCase.call()
+
+			// assert
+			if !errors.Is(err, domain.ErrPathOutsideExportDirectory) {
+				t.Errorf("error = %v, want ErrPathOutsideExportDirectory", err)
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(outsidePath, "manifest.json")); err != nil {
+		t.Errorf("outside file should be untouched: %v", err)
+	}
+}
+
+func TestSessionArchiveStore_Remove_DeletesArchive(t *testing.T) {
+	// arrange
+	store := NewSessionArchiveStore(filepath.Join(t.TempDir(), "exports"))
+	ctx := context.Background()
+	archivePath, err := store.Create(ctx, "abc-123")
+	if err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	store.WriteFile(ctx, "abc-123", "manifest.json", []byte("{}\n"))
+
+	// act
+	err = store.Remove(ctx, "abc-123")
+
+	// assert
+	if err != nil {
+		t.Fatalf("Remove() error: %v", err)
+	}
+	if _, err := os.Stat(archivePath); !os.IsNotExist(err) {
+		t.Errorf("archive should be removed, stat error: %v", err)
+	}
+}